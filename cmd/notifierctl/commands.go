@@ -0,0 +1,589 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/ilindan-dev/delayed-notifier/pkg/client"
+	"strings"
+	"time"
+)
+
+func runCreate(ctx context.Context, app *cli, fs *flag.FlagSet, args []string) error {
+	channel := fs.String("channel", "email", "delivery channel: email or telegram")
+	to := fs.String("to", "", "recipient: an email address or a Telegram chat ID")
+	subject := fs.String("subject", "", "subject")
+	message := fs.String("message", "", "message body")
+	callbackURL := fs.String("callback-url", "", "URL receiving a signed POST once the notification is final")
+	idempotencyKey := fs.String("idempotency-key", "", "key making the create safe to repeat")
+	priority := fs.String("priority", "", "low, normal, high or critical (server default normal)")
+	sendNow := fs.Bool("now", false, "send immediately, skipping the delay queue")
+	labels := labelFlags(fs, "a `TAG` of the notification, repeatable", "metadata entry `KEY=VALUE` of the notification, repeatable")
+	when := scheduleFlags(fs)
+	window := windowFlags(fs)
+	if _, err := parse(fs, args, 0); err != nil {
+		return err
+	}
+	if *to == "" || *subject == "" {
+		return usageError(fs, "-to and -subject are required")
+	}
+	var scheduledAt time.Time
+	var err error
+	switch {
+	case *sendNow && when.given() > 0:
+		return usageError(fs, "-now cannot be combined with -at, -in and -local")
+	case !*sendNow:
+		if scheduledAt, err = when.resolve(); err != nil {
+			return usageError(fs, err.Error())
+		}
+	}
+	// The time zone of the window defaults to -tz on the server.
+	deliveryWindow, err := window.resolve("")
+	if err != nil {
+		return usageError(fs, err.Error())
+	}
+
+	req := client.CreateRequest{
+		Recipient:      *to,
+		Channel:        client.Channel(*channel),
+		Subject:        *subject,
+		Message:        *message,
+		ScheduledAt:    scheduledAt,
+		LocalTime:      when.local,
+		Timezone:       when.tz,
+		SendNow:        *sendNow,
+		Priority:       client.Priority(*priority),
+		Tags:           labels.tags,
+		Metadata:       labels.metadata,
+		DeliveryWindow: deliveryWindow,
+		IdempotencyKey: *idempotencyKey,
+	}
+	if when.in != 0 {
+		// The server resolves the delay on its own clock.
+		req.ScheduledAt, req.Delay = time.Time{}, client.ISODuration(when.in)
+	}
+	if *callbackURL != "" {
+		req.CallbackURL = callbackURL
+	}
+
+	n, err := app.client.Create(ctx, req)
+	if err != nil {
+		return err
+	}
+	return app.printNotifications(*n)
+}
+
+func runGet(ctx context.Context, app *cli, fs *flag.FlagSet, args []string) error {
+	positional, err := parse(fs, args, 1)
+	if err != nil {
+		return err
+	}
+	id, err := parseID(fs, positional[0])
+	if err != nil {
+		return err
+	}
+
+	n, err := app.client.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+	return app.printNotifications(*n)
+}
+
+func runList(ctx context.Context, app *cli, fs *flag.FlagSet, args []string) error {
+	status := fs.String("status", "", "only notifications with this status")
+	channel := fs.String("channel", "", "only notifications of this channel")
+	author := fs.String("author", "", "only notifications of this author (admins only)")
+	search := fs.String("search", "", "only notifications whose subject or recipient contains this text")
+	labels := labelFlags(fs, "only notifications with this `TAG`, repeatable", "only notifications with metadata entry `KEY=VALUE`, repeatable")
+	limit := fs.Int("limit", 0, "page size (server default if zero)")
+	pageToken := fs.String("page-token", "", "continue from this page token")
+	all := fs.Bool("all", false, "follow page tokens until the last page")
+	if _, err := parse(fs, args, 0); err != nil {
+		return err
+	}
+
+	opts := client.ListOptions{
+		Status:    client.Status(*status),
+		Channel:   client.Channel(*channel),
+		AuthorID:  *author,
+		Search:    *search,
+		Tags:      labels.tags,
+		Metadata:  labels.metadata,
+		Limit:     *limit,
+		PageToken: *pageToken,
+	}
+	result := client.ListResult{}
+	for {
+		page, err := app.client.List(ctx, opts)
+		if err != nil {
+			return err
+		}
+		result.Notifications = append(result.Notifications, page.Notifications...)
+		result.NextPageToken = page.NextPageToken
+		if !*all || page.NextPageToken == "" {
+			break
+		}
+		opts.PageToken = page.NextPageToken
+	}
+
+	if app.json {
+		return app.printJSON(result)
+	}
+	if err := app.printNotifications(result.Notifications...); err != nil {
+		return err
+	}
+	if result.NextPageToken != "" {
+		_, _ = fmt.Fprintf(app.out, "\nmore results: -page-token %s\n", result.NextPageToken)
+	}
+	return nil
+}
+
+func runCancel(ctx context.Context, app *cli, fs *flag.FlagSet, args []string) error {
+	ifVersion := fs.Int("if-version", 0, "only cancel while the notification has this `VERSION`")
+	positional, err := parse(fs, args, 1)
+	if err != nil {
+		return err
+	}
+	id, err := parseID(fs, positional[0])
+	if err != nil {
+		return err
+	}
+
+	if *ifVersion > 0 {
+		err = app.client.CancelIfVersion(ctx, id, *ifVersion)
+	} else {
+		err = app.client.Cancel(ctx, id)
+	}
+	if err != nil {
+		return err
+	}
+	if !app.json {
+		_, _ = fmt.Fprintf(app.out, "cancelled %s\n", id)
+	}
+	return nil
+}
+
+func runReschedule(ctx context.Context, app *cli, fs *flag.FlagSet, args []string) error {
+	when := scheduleFlags(fs)
+	positional, err := parse(fs, args, 1)
+	if err != nil {
+		return err
+	}
+	id, err := parseID(fs, positional[0])
+	if err != nil {
+		return err
+	}
+	scheduledAt, err := when.resolve()
+	if err != nil {
+		return usageError(fs, err.Error())
+	}
+
+	var n *client.Notification
+	switch {
+	case when.local != "":
+		n, err = app.client.RescheduleLocal(ctx, id, when.local, when.tz)
+	case when.tz != "":
+		return usageError(fs, "-tz requires -local")
+	default:
+		n, err = app.client.Reschedule(ctx, id, scheduledAt)
+	}
+	if err != nil {
+		return err
+	}
+	return app.printNotifications(*n)
+}
+
+func runBulkCancel(ctx context.Context, app *cli, fs *flag.FlagSet, args []string) error {
+	filter := filterFlags(fs)
+	dryRun := fs.Bool("dry-run", false, "only count the matching notifications")
+	wait := fs.Bool("wait", false, "wait until the job has finished")
+	if _, err := parse(fs, args, 0); err != nil {
+		return err
+	}
+	f, err := filter.resolve()
+	if err != nil {
+		return usageError(fs, err.Error())
+	}
+
+	if *dryRun {
+		return app.countMatching(ctx, f)
+	}
+	job, err := app.client.BulkCancel(ctx, f)
+	if err != nil {
+		return err
+	}
+	return app.followJob(ctx, job, *wait)
+}
+
+func runBulkReschedule(ctx context.Context, app *cli, fs *flag.FlagSet, args []string) error {
+	when := scheduleFlags(fs)
+	filter := filterFlags(fs)
+	dryRun := fs.Bool("dry-run", false, "only count the matching notifications")
+	wait := fs.Bool("wait", false, "wait until the job has finished")
+	if _, err := parse(fs, args, 0); err != nil {
+		return err
+	}
+	f, err := filter.resolve()
+	if err != nil {
+		return usageError(fs, err.Error())
+	}
+	scheduledAt, err := when.resolve()
+	if err != nil {
+		return usageError(fs, err.Error())
+	}
+	if when.tz != "" && when.local == "" {
+		return usageError(fs, "-tz requires -local")
+	}
+
+	if *dryRun {
+		return app.countMatching(ctx, f)
+	}
+	job, err := app.client.BulkReschedule(ctx, client.BulkRescheduleRequest{
+		Filter:      f,
+		ScheduledAt: scheduledAt,
+		LocalTime:   when.local,
+		Timezone:    when.tz,
+	})
+	if err != nil {
+		return err
+	}
+	return app.followJob(ctx, job, *wait)
+}
+
+func runJob(ctx context.Context, app *cli, fs *flag.FlagSet, args []string) error {
+	wait := fs.Bool("wait", false, "wait until the job has finished")
+	positional, err := parse(fs, args, 1)
+	if err != nil {
+		return err
+	}
+	id, err := uuid.Parse(positional[0])
+	if err != nil {
+		return usageError(fs, "invalid job ID: "+positional[0])
+	}
+
+	job, err := app.client.GetJob(ctx, id)
+	if err != nil {
+		return err
+	}
+	return app.followJob(ctx, job, *wait)
+}
+
+// countMatching prints the number of notifications a bulk job with filter f would change.
+func (app *cli) countMatching(ctx context.Context, f client.Filter) error {
+	matched, err := app.client.CountMatching(ctx, f)
+	if err != nil {
+		return err
+	}
+	if app.json {
+		return app.printJSON(map[string]int{"matched": matched})
+	}
+	_, err = fmt.Fprintf(app.out, "%d scheduled notification(s) match\n", matched)
+	return err
+}
+
+// followJob prints job, first waiting until it has finished if wait is set.
+func (app *cli) followJob(ctx context.Context, job *client.Job, wait bool) error {
+	if wait && !job.Done() {
+		var err error
+		if job, err = app.client.WaitJob(ctx, job.ID, jobPollInterval); err != nil {
+			return err
+		}
+	}
+	return app.printJob(*job)
+}
+
+func runWindow(ctx context.Context, app *cli, fs *flag.FlagSet, args []string) error {
+	tz := fs.String("tz", "", "IANA time zone of the window, e.g. Europe/Berlin")
+	clear := fs.Bool("clear", false, "remove the delivery window")
+	window := windowFlags(fs)
+	positional, err := parse(fs, args, 2)
+	if err != nil {
+		return err
+	}
+	channel, recipient := client.Channel(positional[0]), positional[1]
+	deliveryWindow, err := window.resolve(*tz)
+	if err != nil {
+		return usageError(fs, err.Error())
+	}
+
+	var w *client.DeliveryWindow
+	switch {
+	case *clear && deliveryWindow != nil:
+		return usageError(fs, "-clear cannot be combined with -days, -hours and -quiet")
+	case *clear:
+		if err := app.client.DeleteRecipientWindow(ctx, channel, recipient); err != nil {
+			return err
+		}
+		if !app.json {
+			_, _ = fmt.Fprintf(app.out, "cleared the delivery window of %s\n", recipient)
+		}
+		return nil
+	case deliveryWindow != nil:
+		w, err = app.client.SetRecipientWindow(ctx, channel, recipient, *deliveryWindow)
+	case *tz != "":
+		return usageError(fs, "-tz requires -days, -hours or -quiet")
+	default:
+		w, err = app.client.GetRecipientWindow(ctx, channel, recipient)
+	}
+	if err != nil {
+		return err
+	}
+	return app.printWindow(*w)
+}
+
+func runEvents(ctx context.Context, app *cli, fs *flag.FlagSet, args []string) error {
+	notificationID := fs.String("id", "", "only events of this notification")
+	since := fs.String("since", "", "replay events after this event ID")
+	if _, err := parse(fs, args, 0); err != nil {
+		return err
+	}
+
+	opts := client.StreamOptions{LastEventID: *since}
+	if *notificationID != "" {
+		id, err := uuid.Parse(*notificationID)
+		if err != nil {
+			return usageError(fs, "invalid notification ID: "+*notificationID)
+		}
+		opts.NotificationID = &id
+	}
+
+	err := app.client.StreamEvents(ctx, opts, app.printEvent)
+	if errors.Is(err, context.Canceled) {
+		// Interrupted by the user.
+		return nil
+	}
+	return err
+}
+
+func runQueues(ctx context.Context, app *cli, fs *flag.FlagSet, args []string) error {
+	if _, err := parse(fs, args, 0); err != nil {
+		return err
+	}
+
+	stats, err := app.client.Queues(ctx)
+	if err != nil {
+		return err
+	}
+	return app.printQueues(stats)
+}
+
+func runDLQ(ctx context.Context, app *cli, fs *flag.FlagSet, args []string) error {
+	limit := fs.Int("limit", 0, "number of messages (server default if zero)")
+	if _, err := parse(fs, args, 0); err != nil {
+		return err
+	}
+
+	letters, err := app.client.DeadLetters(ctx, *limit)
+	if err != nil {
+		return err
+	}
+	return app.printDeadLetters(letters)
+}
+
+// schedule holds the mutually exclusive -at and -in flags.
+type schedule struct {
+	at    string
+	in    time.Duration
+	local string
+	tz    string
+}
+
+func scheduleFlags(fs *flag.FlagSet) *schedule {
+	s := &schedule{}
+	fs.StringVar(&s.at, "at", "", "send time in RFC 3339, e.g. 2025-01-02T15:04:05Z")
+	fs.DurationVar(&s.in, "in", 0, "send after this duration, e.g. 90m")
+	fs.StringVar(&s.local, "local", "", "local send time in -tz, e.g. 2025-01-02T09:00, or 09:00 for the next occurrence")
+	fs.StringVar(&s.tz, "tz", "", "IANA time zone of -local, e.g. Europe/Berlin")
+	return s
+}
+
+// resolve returns the send time given by exactly one of -at, -in and -local.
+// The time is zero for -local, which the server resolves in -tz.
+func (s *schedule) resolve() (time.Time, error) {
+	switch {
+	case s.given() > 1:
+		return time.Time{}, errors.New("-at, -in and -local are mutually exclusive")
+	case s.local != "":
+		return time.Time{}, nil
+	case s.at != "":
+		t, err := time.Parse(time.RFC3339, s.at)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid -at: %w", err)
+		}
+		return t, nil
+	case s.in > 0:
+		return time.Now().Add(s.in), nil
+	default:
+		return time.Time{}, errors.New("one of -at, -in and -local is required")
+	}
+}
+
+// labels holds the repeatable -tag and -meta flags.
+type labels struct {
+	tags     []string
+	metadata map[string]any
+}
+
+func labelFlags(fs *flag.FlagSet, tagUsage, metaUsage string) *labels {
+	l := &labels{}
+	fs.Func("tag", tagUsage, func(tag string) error {
+		l.tags = append(l.tags, tag)
+		return nil
+	})
+	fs.Func("meta", metaUsage+"; values are strings", func(entry string) error {
+		key, value, ok := strings.Cut(entry, "=")
+		if !ok || key == "" {
+			return errors.New("must look like KEY=VALUE")
+		}
+		if l.metadata == nil {
+			l.metadata = map[string]any{}
+		}
+		l.metadata[key] = value
+		return nil
+	})
+	return l
+}
+
+// filter holds the flags selecting the notifications of a bulk job.
+type filter struct {
+	channel string
+	author  string
+	search  string
+	from    string
+	to      string
+	labels  *labels
+}
+
+func filterFlags(fs *flag.FlagSet) *filter {
+	f := &filter{}
+	fs.StringVar(&f.channel, "channel", "", "only notifications of this channel")
+	fs.StringVar(&f.author, "author", "", "only notifications of this author (admins only)")
+	fs.StringVar(&f.search, "search", "", "only notifications whose subject or recipient contains this text")
+	fs.StringVar(&f.from, "from", "", "only notifications scheduled at or after this time in RFC 3339")
+	fs.StringVar(&f.to, "to", "", "only notifications scheduled before this time in RFC 3339")
+	f.labels = labelFlags(fs, "only notifications with this `TAG`, repeatable", "only notifications with metadata entry `KEY=VALUE`, repeatable")
+	return f
+}
+
+// resolve returns the filter given by the flags. The server rejects an empty one.
+func (f *filter) resolve() (client.Filter, error) {
+	result := client.Filter{
+		Channel:  client.Channel(f.channel),
+		AuthorID: f.author,
+		Search:   f.search,
+		Tags:     f.labels.tags,
+		Metadata: f.labels.metadata,
+	}
+	for _, bound := range []struct {
+		name string
+		raw  string
+		dst  **time.Time
+	}{{"-from", f.from, &result.ScheduledFrom}, {"-to", f.to, &result.ScheduledTo}} {
+		if bound.raw == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, bound.raw)
+		if err != nil {
+			return client.Filter{}, fmt.Errorf("invalid %s: %w", bound.name, err)
+		}
+		*bound.dst = &t
+	}
+	return result, nil
+}
+
+// window holds the flags describing a delivery window.
+type window struct {
+	days  string
+	hours string
+	quiet string
+}
+
+func windowFlags(fs *flag.FlagSet) *window {
+	w := &window{}
+	fs.StringVar(&w.days, "days", "", "deliver only on these weekdays, e.g. mon,tue,wed,thu,fri")
+	fs.StringVar(&w.hours, "hours", "", "deliver only between these times of day, e.g. 09:00-18:00")
+	fs.StringVar(&w.quiet, "quiet", "", "never deliver between these times of day, e.g. 22:00-07:00")
+	return w
+}
+
+// resolve returns the delivery window in the time zone tz given by the flags,
+// or nil if none of them is set.
+func (w *window) resolve(tz string) (*client.DeliveryWindow, error) {
+	if w.days == "" && w.hours == "" && w.quiet == "" {
+		return nil, nil
+	}
+	result := &client.DeliveryWindow{Timezone: tz}
+	if w.days != "" {
+		result.Days = strings.Split(w.days, ",")
+	}
+	var err error
+	if result.Hours, err = parseClockRange("-hours", w.hours); err != nil {
+		return nil, err
+	}
+	if result.QuietHours, err = parseClockRange("-quiet", w.quiet); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// parseClockRange parses a range of times of day such as 09:00-18:00, or returns nil if raw is empty.
+func parseClockRange(name, raw string) (*client.ClockRange, error) {
+	if raw == "" {
+		return nil, nil
+	}
+	start, end, ok := strings.Cut(raw, "-")
+	if !ok {
+		return nil, fmt.Errorf("invalid %s %q, must look like 09:00-18:00", name, raw)
+	}
+	return &client.ClockRange{Start: start, End: end}, nil
+}
+
+// given returns how many of -at, -in and -local are set.
+func (s *schedule) given() int {
+	n := 0
+	for _, set := range []bool{s.at != "", s.in != 0, s.local != ""} {
+		if set {
+			n++
+		}
+	}
+	return n
+}
+
+// parse parses the command's flags, which may come before or after the positional
+// arguments, and checks that there are exactly nargs positional arguments.
+func parse(fs *flag.FlagSet, args []string, nargs int) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, errUsage
+		}
+		if fs.NArg() == 0 {
+			break
+		}
+		positional = append(positional, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+	if len(positional) != nargs {
+		return nil, usageError(fs, fmt.Sprintf("expected %d argument(s), got %d", nargs, len(positional)))
+	}
+	return positional, nil
+}
+
+// parseID parses a notification ID argument.
+func parseID(fs *flag.FlagSet, raw string) (uuid.UUID, error) {
+	id, err := uuid.Parse(raw)
+	if err != nil {
+		return uuid.Nil, usageError(fs, "invalid notification ID: "+raw)
+	}
+	return id, nil
+}
+
+// usageError prints msg and the command's usage and returns errUsage.
+func usageError(fs *flag.FlagSet, msg string) error {
+	_, _ = fmt.Fprintf(fs.Output(), "notifierctl %s: %s\n", fs.Name(), msg)
+	fs.Usage()
+	return errUsage
+}
@@ -0,0 +1,165 @@
+// Command notifierctl is the command-line client of the delayed-notifier API.
+//
+// Connection settings come from flags, falling back to environment variables:
+//
+//	-url      NOTIFIER_URL      API address (default http://localhost:8080)
+//	-api-key  NOTIFIER_API_KEY  API key
+//	-token    NOTIFIER_TOKEN    bearer JWT, instead of an API key
+//	-output   NOTIFIER_OUTPUT   table or json (default table)
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/ilindan-dev/delayed-notifier/pkg/client"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+const defaultURL = "http://localhost:8080"
+
+// jobPollInterval is how often -wait polls the progress of a bulk job.
+const jobPollInterval = time.Second
+
+// errUsage is returned by commands called with invalid arguments; the usage has been printed.
+var errUsage = errors.New("invalid usage")
+
+// command is a notifierctl subcommand.
+type command struct {
+	name    string
+	args    string
+	summary string
+	// run defines the command's flags on fs, parses args with it and executes the command.
+	run func(ctx context.Context, app *cli, fs *flag.FlagSet, args []string) error
+}
+
+var commands = []command{
+	{"create", "-channel CHANNEL -to RECIPIENT -subject SUBJECT (-at TIME | -in DURATION | -local TIME [-tz ZONE] | -now) [flags]", "schedule a notification", runCreate},
+	{"get", "ID", "show a notification", runGet},
+	{"list", "[flags]", "list notifications, newest first", runList},
+	{"cancel", "ID [-if-version VERSION]", "cancel a scheduled notification", runCancel},
+	{"reschedule", "ID (-at TIME | -in DURATION | -local TIME [-tz ZONE])", "move a scheduled notification to a new time", runReschedule},
+	{"bulk-cancel", "(-channel | -author | -search | -from | -to | -tag | -meta)... [-dry-run] [-wait]", "cancel every scheduled notification matching a filter", runBulkCancel},
+	{"bulk-reschedule", "(-at TIME | -in DURATION | -local TIME [-tz ZONE]) (-channel | -author | -search | -from | -to | -tag | -meta)... [-dry-run] [-wait]", "move every scheduled notification matching a filter to a new time", runBulkReschedule},
+	{"job", "ID [-wait]", "show the progress of a bulk job", runJob},
+	{"window", "CHANNEL RECIPIENT [-days DAYS] [-hours RANGE] [-quiet RANGE] [-tz ZONE] [-clear]", "show, set or clear the delivery window of a recipient", runWindow},
+	{"events", "[-id ID] [-since EVENT_ID]", "tail status changes until interrupted", runEvents},
+	{"queues", "", "show queue depths (operators only)", runQueues},
+	{"dlq", "[-limit N]", "show the oldest dead letters (operators only)", runDLQ},
+}
+
+// cli holds what every command needs: the API client and the output settings.
+type cli struct {
+	client *client.Client
+	out    io.Writer
+	json   bool
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run executes notifierctl with the given arguments and returns the exit code.
+func run(args []string, stdout, stderr io.Writer) int {
+	global := flag.NewFlagSet("notifierctl", flag.ContinueOnError)
+	global.SetOutput(stderr)
+	baseURL := global.String("url", envOr("NOTIFIER_URL", defaultURL), "API address")
+	apiKey := global.String("api-key", os.Getenv("NOTIFIER_API_KEY"), "API key")
+	token := global.String("token", os.Getenv("NOTIFIER_TOKEN"), "bearer JWT, used instead of an API key")
+	output := global.String("output", envOr("NOTIFIER_OUTPUT", "table"), "output format: table or json")
+	timeout := global.Duration("timeout", 30*time.Second, "timeout of each request")
+	global.Usage = func() { printUsage(global) }
+
+	if err := global.Parse(args); err != nil {
+		return 2
+	}
+	if *output != "table" && *output != "json" {
+		_, _ = fmt.Fprintf(stderr, "notifierctl: unknown output format %q\n", *output)
+		return 2
+	}
+	if global.NArg() == 0 {
+		global.Usage()
+		return 2
+	}
+
+	name, cmdArgs := global.Arg(0), global.Args()[1:]
+	var cmd *command
+	for i := range commands {
+		if commands[i].name == name {
+			cmd = &commands[i]
+		}
+	}
+	if cmd == nil {
+		_, _ = fmt.Fprintf(stderr, "notifierctl: unknown command %q\n", name)
+		global.Usage()
+		return 2
+	}
+
+	opts := []client.Option{client.WithTimeout(*timeout)}
+	if *apiKey != "" {
+		opts = append(opts, client.WithAPIKey(*apiKey))
+	}
+	if *token != "" {
+		opts = append(opts, client.WithBearerToken(*token))
+	}
+	c, err := client.New(*baseURL, opts...)
+	if err != nil {
+		_, _ = fmt.Fprintf(stderr, "notifierctl: %v\n", err)
+		return 2
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	app := &cli{client: c, out: stdout, json: *output == "json"}
+	if err := cmd.run(ctx, app, newFlagSet(cmd, stderr), cmdArgs); err != nil {
+		if errors.Is(err, errUsage) {
+			return 2
+		}
+		_, _ = fmt.Fprintf(stderr, "notifierctl: %s: %v\n", cmd.name, err)
+		var apiErr *client.APIError
+		if errors.As(err, &apiErr) {
+			for _, fe := range apiErr.Fields {
+				_, _ = fmt.Fprintf(stderr, "  %s: %s\n", fe.Field, fe.Message)
+			}
+		}
+		return 1
+	}
+	return 0
+}
+
+func printUsage(global *flag.FlagSet) {
+	w := global.Output()
+	_, _ = fmt.Fprintln(w, "Usage: notifierctl [global flags] COMMAND [flags] [args]")
+	_, _ = fmt.Fprintln(w, "\nCommands:")
+	for _, cmd := range commands {
+		_, _ = fmt.Fprintf(w, "  %-15s %s\n", cmd.name, cmd.summary)
+	}
+	_, _ = fmt.Fprintln(w, "\nGlobal flags:")
+	global.PrintDefaults()
+	_, _ = fmt.Fprintln(w, "\nRun 'notifierctl COMMAND -h' for the flags of a command.")
+}
+
+// newFlagSet creates the flag set of a command, which prints the command's usage on errors.
+func newFlagSet(cmd *command, stderr io.Writer) *flag.FlagSet {
+	fs := flag.NewFlagSet(cmd.name, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		_, _ = fmt.Fprintf(stderr, "Usage: notifierctl %s %s\n\nTo %s.\n", cmd.name, cmd.args, cmd.summary)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+// envOr returns the value of the environment variable key, or fallback if it is empty.
+func envOr(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
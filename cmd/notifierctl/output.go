@@ -0,0 +1,132 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/ilindan-dev/delayed-notifier/pkg/client"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// printNotifications prints notifications as a table, or as JSON: an object for a
+// single notification and an array otherwise.
+func (app *cli) printNotifications(notifications ...client.Notification) error {
+	if app.json {
+		if len(notifications) == 1 {
+			return app.printJSON(notifications[0])
+		}
+		return app.printJSON(notifications)
+	}
+
+	w := tabwriter.NewWriter(app.out, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "ID\tSTATUS\tCHANNEL\tPRIORITY\tRECIPIENT\tATTEMPTS\tVERSION\tSCHEDULED AT\tCREATED AT\tSUBJECT")
+	for _, n := range notifications {
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%d\t%d\t%s\t%s\t%s\n",
+			n.ID, n.Status, n.Channel, n.Priority, n.Recipient, n.Attempts, n.Version, formatTime(n.ScheduledAt), formatTime(n.CreatedAt), n.Subject)
+	}
+	return w.Flush()
+}
+
+// printEvent prints a status event as a line of text, or as a line of JSON so that
+// the stream can be piped into line-oriented tools.
+func (app *cli) printEvent(e client.StatusEvent) error {
+	if app.json {
+		data, err := json.Marshal(e)
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(app.out, "%s\n", data)
+		return err
+	}
+	_, err := fmt.Fprintf(app.out, "%s  %s  %-9s  attempts=%d  event=%s\n",
+		formatTime(e.OccurredAt), e.NotificationID, e.Status, e.Attempts, e.ID)
+	return err
+}
+
+// printWindow prints a delivery window as one line of text, or as JSON.
+func (app *cli) printWindow(w client.DeliveryWindow) error {
+	if app.json {
+		return app.printJSON(w)
+	}
+	days := "every day"
+	if len(w.Days) > 0 {
+		days = strings.Join(w.Days, ",")
+	}
+	hours := "all day"
+	if w.Hours != nil {
+		hours = w.Hours.Start + "-" + w.Hours.End
+	}
+	quiet := "none"
+	if w.QuietHours != nil {
+		quiet = w.QuietHours.Start + "-" + w.QuietHours.End
+	}
+	_, err := fmt.Fprintf(app.out, "timezone=%s  days=%s  hours=%s  quiet=%s\n", w.Timezone, days, hours, quiet)
+	return err
+}
+
+// printJob prints the progress of a bulk job as one line of text, or as JSON.
+func (app *cli) printJob(j client.Job) error {
+	if app.json {
+		return app.printJSON(j)
+	}
+	line := fmt.Sprintf("job %s  %s  %s  processed=%d/%d  failed=%d", j.ID, j.Action, j.Status, j.Processed, j.Total, j.Failed)
+	if j.ScheduledAt != nil {
+		line += "  scheduled_at=" + formatTime(*j.ScheduledAt)
+	}
+	if j.Error != "" {
+		line += "  error=" + strconv.Quote(j.Error)
+	}
+	_, err := fmt.Fprintln(app.out, line)
+	return err
+}
+
+func (app *cli) printQueues(stats []client.QueueStats) error {
+	if app.json {
+		return app.printJSON(stats)
+	}
+
+	w := tabwriter.NewWriter(app.out, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "QUEUE\tMESSAGES\tCONSUMERS")
+	for _, s := range stats {
+		_, _ = fmt.Fprintf(w, "%s\t%d\t%d\n", s.Name, s.Messages, s.Consumers)
+	}
+	return w.Flush()
+}
+
+func (app *cli) printDeadLetters(letters []client.DeadLetter) error {
+	if app.json {
+		return app.printJSON(letters)
+	}
+
+	w := tabwriter.NewWriter(app.out, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "DEAD AT\tMESSAGE ID\tREASON\tBODY")
+	for _, l := range letters {
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", formatTime(l.DeadAt), l.MessageID, l.Reason, truncate(l.Body, 80))
+	}
+	return w.Flush()
+}
+
+func (app *cli) printJSON(v any) error {
+	enc := json.NewEncoder(app.out)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// formatTime formats a timestamp in the local time zone.
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.Local().Format(time.DateTime)
+}
+
+// truncate shortens s to at most n runes for table cells.
+func truncate(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n-1]) + "…"
+}
@@ -2,8 +2,10 @@ package app
 
 import (
 	"context"
+	"github.com/ilindan-dev/delayed-notifier/internal/auth"
 	"github.com/ilindan-dev/delayed-notifier/internal/config"
 	"github.com/ilindan-dev/delayed-notifier/internal/consumer"
+	deliveryGRPC "github.com/ilindan-dev/delayed-notifier/internal/delivery/grpc"
 	deliveryHTTP "github.com/ilindan-dev/delayed-notifier/internal/delivery/http"
 	repo "github.com/ilindan-dev/delayed-notifier/internal/domain/repository"
 	"github.com/ilindan-dev/delayed-notifier/internal/logger"
@@ -29,8 +31,19 @@ var CommonModule = fx.Options(
 		redis.NewClient,
 		rabbitmq.NewConnection,
 		redis.NewNotificationCache,
+		fx.Annotate(redis.NewQuotaStore, fx.As(new(repo.QuotaStore))),
+		fx.Annotate(redis.NewStatusEventStream, fx.As(new(repo.StatusEventStream))),
+		fx.Annotate(redis.NewBulkJobStore, fx.As(new(repo.BulkJobStore))),
 		postgres.NewNotificationRepository,
-		rabbitmq.NewRabbitMQQueue,
+		fx.Annotate(postgres.NewCallbackDeliveryRepository, fx.As(new(repo.CallbackDeliveryRepository))),
+		fx.Annotate(postgres.NewDeliveryWindowRepository, fx.As(new(repo.DeliveryWindowRepository))),
+		fx.Annotate(
+			rabbitmq.NewRabbitMQQueue,
+			fx.As(new(repo.NotificationQueue)),
+			fx.As(new(repo.CallbackQueue)),
+			fx.As(new(repo.DeadLetterQueue)),
+			fx.As(new(repo.QueueInspector)),
+		),
 
 		// Service Layer
 		service.NewNotificationService,
@@ -50,10 +63,25 @@ var APIModule = fx.Options(
 	CommonModule, // Include all shared components
 	fx.Provide(
 		// API-specific components
+		fx.Annotate(postgres.NewAPIKeyRepository, fx.As(new(repo.APIKeyRepository))),
+		service.NewAPIKeyService,
+		service.NewQueueService,
+		auth.NewJWTVerifier,
+		service.NewAuthenticator,
+		deliveryHTTP.NewAuthMiddleware,
 		deliveryHTTP.NewHandlers,
 		deliveryHTTP.NewServer,
+		deliveryGRPC.NewHandlers,
+		deliveryGRPC.NewServer,
 	),
 
+	fx.Invoke(func(notifications *service.NotificationService, lc fx.Lifecycle) {
+		lc.Append(fx.Hook{
+			OnStop: func(ctx context.Context) error {
+				return notifications.StopBulkJobs(ctx)
+			},
+		})
+	}),
 	fx.Invoke(func(server *deliveryHTTP.Server, lc fx.Lifecycle) {
 		lc.Append(fx.Hook{
 			OnStart: func(ctx context.Context) error {
@@ -69,6 +97,21 @@ var APIModule = fx.Options(
 			},
 		})
 	}),
+	fx.Invoke(func(server *deliveryGRPC.Server, lc fx.Lifecycle) {
+		lc.Append(fx.Hook{
+			OnStart: func(ctx context.Context) error {
+				go func() {
+					if err := server.ListenAndServe(); err != nil {
+						panic(err)
+					}
+				}()
+				return nil
+			},
+			OnStop: func(ctx context.Context) error {
+				return server.Shutdown(ctx)
+			},
+		})
+	}),
 )
 
 // WorkerModule defines the Fx module for the background worker application.
@@ -76,18 +119,47 @@ var WorkerModule = fx.Options(
 	CommonModule, // Include all shared components
 	fx.Provide(
 		// Worker-specific components
+		fx.Annotate(redis.NewRateLimiter, fx.As(new(repo.RateLimiter))),
 		notifiers.NewDispatcher,
 		consumer.New,
+		consumer.NewCallbackConsumer,
+		deliveryHTTP.NewWorkerServer,
 	),
 	fx.Invoke(func(consumer *consumer.Consumer, lc fx.Lifecycle) {
 		lc.Append(fx.Hook{
 			OnStart: func(ctx context.Context) error {
-				go consumer.Start(ctx)
+				consumer.Start()
 				return nil
 			},
 			OnStop: func(ctx context.Context) error {
+				return consumer.Stop(ctx)
+			},
+		})
+	}),
+	fx.Invoke(func(callbacks *consumer.CallbackConsumer, lc fx.Lifecycle) {
+		lc.Append(fx.Hook{
+			OnStart: func(ctx context.Context) error {
+				callbacks.Start()
 				return nil
 			},
+			OnStop: func(ctx context.Context) error {
+				return callbacks.Stop(ctx)
+			},
+		})
+	}),
+	fx.Invoke(func(server *deliveryHTTP.WorkerServer, lc fx.Lifecycle) {
+		lc.Append(fx.Hook{
+			OnStart: func(ctx context.Context) error {
+				go func() {
+					if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+						panic(err)
+					}
+				}()
+				return nil
+			},
+			OnStop: func(ctx context.Context) error {
+				return server.Shutdown(ctx)
+			},
 		})
 	}),
 )
@@ -0,0 +1,75 @@
+// Package auth provides helpers for carrying the authenticated principal
+// through a request and for generating and hashing API keys.
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"github.com/ilindan-dev/delayed-notifier/internal/domain/model"
+)
+
+const (
+	// apiKeyPrefix marks raw keys issued by this service, so they are easy to spot in leaks.
+	apiKeyPrefix = "dn_"
+	// apiKeyBytes is the amount of random data in a raw key.
+	apiKeyBytes = 32
+	// displayPrefixLen is the number of raw key characters stored for identification.
+	displayPrefixLen = 10
+)
+
+type principalKey struct{}
+
+type tenantKey struct{}
+
+// WithPrincipal returns a copy of ctx carrying the authenticated principal.
+func WithPrincipal(ctx context.Context, p *model.Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+// PrincipalFromContext returns the authenticated principal, if any.
+// A missing principal means the call comes from a trusted internal caller
+// (e.g. the worker) or authentication is disabled.
+func PrincipalFromContext(ctx context.Context) (*model.Principal, bool) {
+	p, ok := ctx.Value(principalKey{}).(*model.Principal)
+	return p, ok && p != nil
+}
+
+// WithTenant returns a copy of ctx scoped to the tenant. It is used by trusted
+// internal callers, such as the worker, that act without a principal.
+func WithTenant(ctx context.Context, tenantID string) context.Context {
+	return context.WithValue(ctx, tenantKey{}, tenantID)
+}
+
+// TenantFromContext resolves the tenant a call is scoped to: the principal's tenant
+// if there is one, otherwise a tenant set with WithTenant, otherwise the default tenant.
+func TenantFromContext(ctx context.Context) string {
+	if p, ok := PrincipalFromContext(ctx); ok && p.TenantID != "" {
+		return p.TenantID
+	}
+	if tenantID, ok := ctx.Value(tenantKey{}).(string); ok && tenantID != "" {
+		return tenantID
+	}
+	return model.DefaultTenant
+}
+
+// GenerateAPIKey creates a new random raw API key and returns it with
+// its display prefix and hash.
+func GenerateAPIKey() (raw, prefix, hash string, err error) {
+	buf := make([]byte, apiKeyBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return "", "", "", fmt.Errorf("auth: failed to generate api key: %w", err)
+	}
+	raw = apiKeyPrefix + base64.RawURLEncoding.EncodeToString(buf)
+	return raw, raw[:displayPrefixLen], HashAPIKey(raw), nil
+}
+
+// HashAPIKey returns the hex-encoded SHA-256 hash of a raw API key.
+// API keys carry enough entropy that a fast, unsalted hash is sufficient.
+func HashAPIKey(raw string) string {
+	sum := sha256.Sum256([]byte(raw))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,273 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"errors"
+	"fmt"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/ilindan-dev/delayed-notifier/internal/config"
+	"github.com/ilindan-dev/delayed-notifier/internal/domain/model"
+	"github.com/rs/zerolog"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// defaultJWKSRefreshInterval is how often a JWKS URL is re-fetched by default.
+	defaultJWKSRefreshInterval = time.Hour
+	// minJWKSRefreshInterval limits re-fetches triggered by tokens with unknown key IDs.
+	minJWKSRefreshInterval = time.Minute
+	// defaultRolesClaim is the claim holding the caller's roles by default.
+	defaultRolesClaim = "roles"
+	// defaultTenantClaim is the claim holding the caller's tenant by default.
+	defaultTenantClaim = "tenant"
+)
+
+// ErrInvalidToken is returned when a bearer token cannot be verified.
+var ErrInvalidToken = errors.New("invalid bearer token")
+
+// JWTVerifier validates bearer JWTs against a JSON Web Key Set loaded from a file or URL
+// and maps their claims to a principal.
+type JWTVerifier struct {
+	cfg         config.JWTConfig
+	rolesClaim  string
+	tenantClaim string
+	client      *http.Client
+	parser      *jwt.Parser
+	logger      zerolog.Logger
+
+	// refreshMu serializes fetches of the JWKS URL, so that concurrent requests
+	// triggering a refresh fetch the key set once.
+	refreshMu   sync.Mutex
+	mu          sync.RWMutex
+	keys        map[string]crypto.PublicKey
+	lastRefresh time.Time
+}
+
+// NewJWTVerifier creates a JWTVerifier and loads the key set.
+// It returns nil if JWT authentication is not enabled in the configuration.
+func NewJWTVerifier(cfg *config.Config, logger *zerolog.Logger) (*JWTVerifier, error) {
+	if !cfg.Auth.JWTEnabled() {
+		return nil, nil
+	}
+	jwtCfg := cfg.Auth.JWT
+	if jwtCfg.JWKSFile == "" && jwtCfg.JWKSURL == "" {
+		return nil, fmt.Errorf("auth: jwt mode requires auth.jwt.jwks_file or auth.jwt.jwks_url")
+	}
+	if jwtCfg.RefreshInterval <= 0 {
+		jwtCfg.RefreshInterval = defaultJWKSRefreshInterval
+	}
+
+	opts := []jwt.ParserOption{
+		jwt.WithValidMethods([]string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512", "EdDSA"}),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(jwtCfg.Leeway),
+	}
+	if jwtCfg.Issuer != "" {
+		opts = append(opts, jwt.WithIssuer(jwtCfg.Issuer))
+	}
+	if jwtCfg.Audience != "" {
+		opts = append(opts, jwt.WithAudience(jwtCfg.Audience))
+	}
+
+	rolesClaim := jwtCfg.RolesClaim
+	if rolesClaim == "" {
+		rolesClaim = defaultRolesClaim
+	}
+
+	tenantClaim := jwtCfg.TenantClaim
+	if tenantClaim == "" {
+		tenantClaim = defaultTenantClaim
+	}
+
+	v := &JWTVerifier{
+		cfg:         jwtCfg,
+		rolesClaim:  rolesClaim,
+		tenantClaim: tenantClaim,
+		client:      &http.Client{Timeout: 10 * time.Second},
+		parser:      jwt.NewParser(opts...),
+		logger:      logger.With().Str("component", "jwt_verifier").Logger(),
+	}
+	if err := v.refresh(context.Background()); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// Verify validates the token and returns the principal it identifies.
+// The principal ID is taken from the subject claim, its tenant from the tenant claim
+// and its scopes from the roles claim.
+func (v *JWTVerifier) Verify(ctx context.Context, token string) (*model.Principal, error) {
+	v.refreshIfStale(ctx)
+
+	claims := jwt.MapClaims{}
+	_, err := v.parser.ParseWithClaims(token, claims, func(t *jwt.Token) (interface{}, error) {
+		kid, _ := t.Header["kid"].(string)
+		return v.key(ctx, kid)
+	})
+	if err != nil {
+		v.logger.Debug().Err(err).Msg("bearer token rejected")
+		return nil, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+
+	subject, err := claims.GetSubject()
+	if err != nil || subject == "" {
+		return nil, fmt.Errorf("%w: missing subject claim", ErrInvalidToken)
+	}
+
+	tenantID, _ := claims[v.tenantClaim].(string)
+	if tenantID == "" {
+		tenantID = model.DefaultTenant
+	}
+
+	return &model.Principal{ID: subject, TenantID: tenantID, Scopes: v.scopes(claims)}, nil
+}
+
+// scopes maps the roles claim to scopes. Without a role mapping, role names are used as scopes.
+// The claim may be a list of strings or a space-separated string (as in the "scope" claim).
+func (v *JWTVerifier) scopes(claims jwt.MapClaims) []model.Scope {
+	var roles []string
+	switch raw := claims[v.rolesClaim].(type) {
+	case string:
+		roles = strings.Fields(raw)
+	case []interface{}:
+		for _, r := range raw {
+			if s, ok := r.(string); ok {
+				roles = append(roles, s)
+			}
+		}
+	}
+
+	var scopes []model.Scope
+	for _, role := range roles {
+		if len(v.cfg.RoleScopes) == 0 {
+			if model.ValidScope(model.Scope(role)) {
+				scopes = append(scopes, model.Scope(role))
+			}
+			continue
+		}
+		// Viper lower-cases map keys, so roles are matched case-insensitively.
+		for _, s := range v.cfg.RoleScopes[strings.ToLower(role)] {
+			if model.ValidScope(model.Scope(s)) {
+				scopes = append(scopes, model.Scope(s))
+			}
+		}
+	}
+	return scopes
+}
+
+// key returns the verification key with the given ID, re-fetching the key set once
+// if the ID is unknown (the identity provider may have rotated its keys).
+func (v *JWTVerifier) key(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	if key, ok := v.lookup(kid); ok {
+		return key, nil
+	}
+
+	if err := v.refreshOlderThan(ctx, minJWKSRefreshInterval); err != nil {
+		v.logger.Warn().Err(err).Msg("failed to refresh jwks for unknown key id")
+	}
+	if key, ok := v.lookup(kid); ok {
+		return key, nil
+	}
+	return nil, fmt.Errorf("unknown key id %q", kid)
+}
+
+// lookup finds a key by ID. An empty ID matches the only key of a single-key set.
+func (v *JWTVerifier) lookup(kid string) (crypto.PublicKey, bool) {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+
+	if key, ok := v.keys[kid]; ok {
+		return key, true
+	}
+	if kid == "" && len(v.keys) == 1 {
+		for _, key := range v.keys {
+			return key, true
+		}
+	}
+	return nil, false
+}
+
+// refreshIfStale re-fetches a JWKS URL once the refresh interval has passed.
+// Failures are logged and the previous keys are kept.
+func (v *JWTVerifier) refreshIfStale(ctx context.Context) {
+	if err := v.refreshOlderThan(ctx, v.cfg.RefreshInterval); err != nil {
+		v.logger.Warn().Err(err).Msg("failed to refresh jwks, keeping previous keys")
+	}
+}
+
+// refreshOlderThan re-fetches a JWKS URL if the keys were loaded longer than maxAge ago.
+// Concurrent callers wait for a single fetch rather than each fetching the key set.
+func (v *JWTVerifier) refreshOlderThan(ctx context.Context, maxAge time.Duration) error {
+	if v.cfg.JWKSURL == "" || !v.loadedBefore(maxAge) {
+		return nil
+	}
+	v.refreshMu.Lock()
+	defer v.refreshMu.Unlock()
+	if !v.loadedBefore(maxAge) {
+		return nil // Refreshed by a concurrent caller.
+	}
+	return v.refresh(ctx)
+}
+
+// loadedBefore reports whether the keys were loaded longer than maxAge ago.
+func (v *JWTVerifier) loadedBefore(maxAge time.Duration) bool {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	return time.Since(v.lastRefresh) > maxAge
+}
+
+// refresh loads the key set from the configured file or URL.
+func (v *JWTVerifier) refresh(ctx context.Context) error {
+	data, err := v.fetch(ctx)
+	if err != nil {
+		return err
+	}
+	keys, err := parseJWKS(data)
+	if err != nil {
+		return err
+	}
+
+	v.mu.Lock()
+	v.keys = keys
+	v.lastRefresh = time.Now()
+	v.mu.Unlock()
+
+	v.logger.Info().Int("keys", len(keys)).Msg("jwks loaded")
+	return nil
+}
+
+// fetch reads the raw key set. The static file takes precedence over the URL.
+func (v *JWTVerifier) fetch(ctx context.Context) ([]byte, error) {
+	if v.cfg.JWKSFile != "" {
+		data, err := os.ReadFile(v.cfg.JWKSFile)
+		if err != nil {
+			return nil, fmt.Errorf("auth: failed to read jwks file: %w", err)
+		}
+		return data, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, v.cfg.JWKSURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("auth: invalid jwks url: %w", err)
+	}
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("auth: failed to fetch jwks: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("auth: failed to fetch jwks: unexpected status %d", resp.StatusCode)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, fmt.Errorf("auth: failed to read jwks response: %w", err)
+	}
+	return data, nil
+}
@@ -0,0 +1,299 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/ilindan-dev/delayed-notifier/internal/config"
+	"github.com/ilindan-dev/delayed-notifier/internal/domain/model"
+	"github.com/rs/zerolog"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+const (
+	testIssuer   = "https://idp.example.com"
+	testAudience = "notifier"
+)
+
+// testKey is an RSA signing key with the key ID it is published under.
+type testKey struct {
+	kid string
+	key *rsa.PrivateKey
+}
+
+func newTestKey(t *testing.T, kid string) testKey {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return testKey{kid: kid, key: key}
+}
+
+// jwks encodes the public keys as a JSON Web Key Set.
+func jwks(t *testing.T, keys ...testKey) []byte {
+	t.Helper()
+	set := struct {
+		Keys []jwk `json:"keys"`
+	}{}
+	for _, k := range keys {
+		set.Keys = append(set.Keys, jwk{
+			Kty: "RSA",
+			Kid: k.kid,
+			Use: "sig",
+			N:   base64.RawURLEncoding.EncodeToString(k.key.N.Bytes()),
+			E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(k.key.E)).Bytes()),
+		})
+	}
+	data, err := json.Marshal(set)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+// sign returns a token with the given claims, signed by k.
+func (k testKey) sign(t *testing.T, claims jwt.MapClaims) string {
+	t.Helper()
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = k.kid
+	signed, err := token.SignedString(k.key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signed
+}
+
+// validClaims returns the claims of a token the test verifiers accept.
+func validClaims() jwt.MapClaims {
+	return jwt.MapClaims{
+		"sub":    "user-1",
+		"iss":    testIssuer,
+		"aud":    testAudience,
+		"exp":    time.Now().Add(time.Hour).Unix(),
+		"tenant": "acme",
+		"roles":  []string{"notifier-user"},
+	}
+}
+
+// with returns the valid claims with some of them replaced, or removed if nil.
+func with(changes jwt.MapClaims) jwt.MapClaims {
+	claims := validClaims()
+	for name, value := range changes {
+		if value == nil {
+			delete(claims, name)
+			continue
+		}
+		claims[name] = value
+	}
+	return claims
+}
+
+// newTestVerifier creates a verifier reading the key set of keys from a static file.
+func newTestVerifier(t *testing.T, jwtCfg config.JWTConfig, keys ...testKey) *JWTVerifier {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	if err := os.WriteFile(path, jwks(t, keys...), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	jwtCfg.JWKSFile = path
+	logger := zerolog.Nop()
+	v, err := NewJWTVerifier(&config.Config{Auth: config.AuthConfig{Mode: "jwt", JWT: jwtCfg}}, &logger)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return v
+}
+
+func TestJWTVerify(t *testing.T) {
+	key := newTestKey(t, "key-1")
+	other := newTestKey(t, "key-2")
+	v := newTestVerifier(t, config.JWTConfig{
+		Issuer:   testIssuer,
+		Audience: testAudience,
+		Leeway:   30 * time.Second,
+		RoleScopes: map[string][]string{
+			"notifier-admin": {"admin"},
+			"notifier-user":  {"create", "read", "bogus"},
+		},
+	}, key)
+
+	publicDER, err := x509.MarshalPKIXPublicKey(&key.key.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	publicPEM := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicDER})
+	hmacWithPublicKey := jwt.NewWithClaims(jwt.SigningMethodHS256, validClaims())
+	hmacWithPublicKey.Header["kid"] = key.kid
+	confused, err := hmacWithPublicKey.SignedString(publicPEM)
+	if err != nil {
+		t.Fatal(err)
+	}
+	unsigned := jwt.NewWithClaims(jwt.SigningMethodNone, validClaims())
+	unsigned.Header["kid"] = key.kid
+	none, err := unsigned.SignedString(jwt.UnsafeAllowNoneSignatureType)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		token      string
+		wantTenant string
+		wantScopes []model.Scope
+		wantErr    bool
+	}{
+		{
+			name:       "valid",
+			token:      key.sign(t, validClaims()),
+			wantTenant: "acme",
+			wantScopes: []model.Scope{model.ScopeCreate, model.ScopeRead},
+		},
+		{
+			name:       "roles as a space-separated string, matched case-insensitively",
+			token:      key.sign(t, with(jwt.MapClaims{"roles": "Notifier-Admin unknown"})),
+			wantTenant: "acme",
+			wantScopes: []model.Scope{model.ScopeAdmin},
+		},
+		{
+			name:       "unmapped role",
+			token:      key.sign(t, with(jwt.MapClaims{"roles": []string{"viewer"}})),
+			wantTenant: "acme",
+		},
+		{
+			name:       "default tenant",
+			token:      key.sign(t, with(jwt.MapClaims{"tenant": nil})),
+			wantTenant: model.DefaultTenant,
+			wantScopes: []model.Scope{model.ScopeCreate, model.ScopeRead},
+		},
+		{
+			name:       "expired within leeway",
+			token:      key.sign(t, with(jwt.MapClaims{"exp": time.Now().Add(-10 * time.Second).Unix()})),
+			wantTenant: "acme",
+			wantScopes: []model.Scope{model.ScopeCreate, model.ScopeRead},
+		},
+		{name: "expired", token: key.sign(t, with(jwt.MapClaims{"exp": time.Now().Add(-time.Minute).Unix()})), wantErr: true},
+		{name: "without expiry", token: key.sign(t, with(jwt.MapClaims{"exp": nil})), wantErr: true},
+		{name: "not yet valid", token: key.sign(t, with(jwt.MapClaims{"nbf": time.Now().Add(time.Hour).Unix()})), wantErr: true},
+		{name: "wrong issuer", token: key.sign(t, with(jwt.MapClaims{"iss": "https://evil.example.com"})), wantErr: true},
+		{name: "wrong audience", token: key.sign(t, with(jwt.MapClaims{"aud": "another-service"})), wantErr: true},
+		{name: "without subject", token: key.sign(t, with(jwt.MapClaims{"sub": nil})), wantErr: true},
+		{name: "unknown key id", token: other.sign(t, validClaims()), wantErr: true},
+		{name: "signed by another key", token: testKey{kid: key.kid, key: other.key}.sign(t, validClaims()), wantErr: true},
+		{name: "alg none", token: none, wantErr: true},
+		{name: "hs256 with the public key as secret", token: confused, wantErr: true},
+		{name: "malformed", token: "not.a.token", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			principal, err := v.Verify(context.Background(), tt.token)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidToken) {
+					t.Errorf("got %v, want %v", err, ErrInvalidToken)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if principal.ID != "user-1" {
+				t.Errorf("got principal %q, want %q", principal.ID, "user-1")
+			}
+			if principal.TenantID != tt.wantTenant {
+				t.Errorf("got tenant %q, want %q", principal.TenantID, tt.wantTenant)
+			}
+			if !slices.Equal(principal.Scopes, tt.wantScopes) {
+				t.Errorf("got scopes %v, want %v", principal.Scopes, tt.wantScopes)
+			}
+		})
+	}
+}
+
+func TestJWTVerifyRolesAsScopes(t *testing.T) {
+	key := newTestKey(t, "key-1")
+	v := newTestVerifier(t, config.JWTConfig{}, key)
+
+	principal, err := v.Verify(context.Background(), key.sign(t, with(jwt.MapClaims{"roles": []string{"read", "cancel", "superuser"}})))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []model.Scope{model.ScopeRead, model.ScopeCancel}; !slices.Equal(principal.Scopes, want) {
+		t.Errorf("got scopes %v, want %v", principal.Scopes, want)
+	}
+}
+
+func TestJWTUnknownKeyRefresh(t *testing.T) {
+	oldKey := newTestKey(t, "key-1")
+	newKey := newTestKey(t, "key-2")
+
+	var mu sync.Mutex
+	published := jwks(t, oldKey)
+	var fetches atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		fetches.Add(1)
+		mu.Lock()
+		defer mu.Unlock()
+		_, _ = w.Write(published)
+	}))
+	defer server.Close()
+
+	logger := zerolog.Nop()
+	cfg := &config.Config{Auth: config.AuthConfig{Mode: "jwt", JWT: config.JWTConfig{JWKSURL: server.URL}}}
+	v, err := NewJWTVerifier(cfg, &logger)
+	if err != nil {
+		t.Fatal(err)
+	}
+	token := newKey.sign(t, validClaims())
+
+	// Right after a fetch, an unknown key ID does not fetch the key set again.
+	if _, err := v.Verify(context.Background(), token); !errors.Is(err, ErrInvalidToken) {
+		t.Fatalf("got %v, want %v", err, ErrInvalidToken)
+	}
+	if got := fetches.Load(); got != 1 {
+		t.Fatalf("got %d fetches, want 1", got)
+	}
+
+	// The identity provider rotates its keys.
+	mu.Lock()
+	published = jwks(t, oldKey, newKey)
+	mu.Unlock()
+	v.mu.Lock()
+	v.lastRefresh = time.Now().Add(-2 * minJWKSRefreshInterval)
+	v.mu.Unlock()
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 20)
+	for range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := v.Verify(context.Background(), token)
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Errorf("got %v, want the rotated key to verify", err)
+		}
+	}
+	if got := fetches.Load(); got != 2 {
+		t.Errorf("got %d fetches, want 2, as concurrent requests must share one refresh", got)
+	}
+}
@@ -10,10 +10,79 @@ import (
 type Config struct {
 	Logger    LoggerConfig    `mapstructure:"logger"`
 	HTTP      HTTPConfig      `mapstructure:"http"`
+	GRPC      GRPCConfig      `mapstructure:"grpc"`
 	Postgres  PostgresConfig  `mapstructure:"postgres"`
 	RabbitMQ  RabbitMQConfig  `mapstructure:"rabbitmq"`
 	Redis     RedisConfig     `mapstructure:"redis"`
 	Notifiers NotifiersConfig `mapstructure:"notifiers"`
+	Worker    WorkerConfig    `mapstructure:"worker"`
+	Auth      AuthConfig      `mapstructure:"auth"`
+	Callbacks CallbacksConfig `mapstructure:"callbacks"`
+	Events    EventsConfig    `mapstructure:"events"`
+	// Validation holds the rules notifications must satisfy when created or rescheduled.
+	Validation ValidationConfig `mapstructure:"validation"`
+	// Tenants holds per-tenant settings keyed by tenant ID. Tenants without an entry
+	// use the global notifier credentials and have no quota.
+	Tenants map[string]TenantConfig `mapstructure:"tenants"`
+}
+
+// TenantConfig holds the settings of a single tenant.
+type TenantConfig struct {
+	// DailyQuota caps the notifications the tenant may create per UTC day. 0 means unlimited.
+	DailyQuota int `mapstructure:"daily_quota"`
+	// Email and Telegram override the global notifier credentials for the tenant's
+	// notifications. Empty settings fall back to the global ones.
+	Email    EmailConfig    `mapstructure:"email"`
+	Telegram TelegramConfig `mapstructure:"telegram"`
+	// CallbackSecret overrides the global callback signing secret for the tenant's notifications.
+	CallbackSecret string `mapstructure:"callback_secret"`
+}
+
+// CallbacksConfig holds settings for delivering status change callbacks to callers.
+type CallbacksConfig struct {
+	// SigningSecret is the HMAC-SHA256 key used to sign callback payloads.
+	SigningSecret string `mapstructure:"signing_secret"`
+	// Timeout bounds a single callback request.
+	Timeout time.Duration `mapstructure:"timeout"`
+	// MaxAttempts is the number of delivery attempts before a callback is given up.
+	MaxAttempts int `mapstructure:"max_attempts"`
+	// Concurrency is the number of workers delivering callbacks.
+	Concurrency int `mapstructure:"concurrency"`
+	// AllowPrivateAddresses lets callbacks reach loopback, private and link-local
+	// addresses. It is meant for local development only.
+	AllowPrivateAddresses bool `mapstructure:"allow_private_addresses"`
+}
+
+// EventsConfig holds settings for the live stream of notification status events.
+type EventsConfig struct {
+	// StreamMaxLen is the approximate number of events kept per tenant for resuming streams.
+	StreamMaxLen int64 `mapstructure:"stream_max_len"`
+	// Heartbeat is how often an idle event stream sends a keep-alive comment.
+	Heartbeat time.Duration `mapstructure:"heartbeat"`
+	// WatchConnections is the number of Redis connections reserved for waiting on new
+	// events. Each tenant with open streams holds one, apart from the shared pool.
+	WatchConnections int `mapstructure:"watch_connections"`
+}
+
+// ValidationConfig holds the rules notifications must satisfy when created or rescheduled.
+type ValidationConfig struct {
+	// MaxHorizon is how far in the future a notification may be scheduled. 0 means unlimited.
+	MaxHorizon time.Duration `mapstructure:"max_horizon"`
+	// PastTolerance is how far in the past scheduled_at may lie, to allow for clock skew.
+	// Such notifications are scheduled for now; earlier times are rejected.
+	PastTolerance time.Duration `mapstructure:"past_tolerance"`
+	// RequireMessage rejects notifications with an empty message.
+	RequireMessage bool `mapstructure:"require_message"`
+	// Limits holds length limits keyed by channel name.
+	Limits map[string]ChannelLimitsConfig `mapstructure:"limits"`
+}
+
+// ChannelLimitsConfig holds the length limits of a channel, in characters. 0 means unlimited.
+type ChannelLimitsConfig struct {
+	MaxSubjectLength int `mapstructure:"max_subject_length"`
+	// MaxMessageLength bounds the message. Telegram sends the subject and message as a
+	// single text, so for Telegram it bounds that text.
+	MaxMessageLength int `mapstructure:"max_message_length"`
 }
 
 // LoggerConfig holds logging-specific settings.
@@ -27,6 +96,52 @@ type HTTPConfig struct {
 	GinMode string `mapstructure:"gin_mode"`
 }
 
+// GRPCConfig holds gRPC server-specific settings.
+type GRPCConfig struct {
+	Port string `mapstructure:"port"`
+}
+
+// AuthConfig holds settings for authenticating API callers.
+type AuthConfig struct {
+	// Mode selects how /api/v1 callers authenticate: "api_key", "jwt", "any" (either
+	// an API key or a JWT), or "none" to disable auth.
+	Mode string `mapstructure:"mode"`
+	// BootstrapAdminKey is an optional raw key granting admin scope, used to create the first real keys.
+	BootstrapAdminKey string    `mapstructure:"bootstrap_admin_key"`
+	JWT               JWTConfig `mapstructure:"jwt"`
+}
+
+// APIKeyEnabled reports whether callers may authenticate with an API key.
+func (c AuthConfig) APIKeyEnabled() bool {
+	return c.Mode == "api_key" || c.Mode == "any"
+}
+
+// JWTEnabled reports whether callers may authenticate with a bearer JWT.
+func (c AuthConfig) JWTEnabled() bool {
+	return c.Mode == "jwt" || c.Mode == "any"
+}
+
+// JWTConfig holds settings for validating bearer JWTs issued by an identity provider.
+type JWTConfig struct {
+	// JWKSFile is a local JSON Web Key Set, e.g. for offline testing. It takes precedence over JWKSURL.
+	JWKSFile string `mapstructure:"jwks_file"`
+	// JWKSURL is the identity provider's JWKS endpoint.
+	JWKSURL string `mapstructure:"jwks_url"`
+	// RefreshInterval is how often the JWKS URL is re-fetched.
+	RefreshInterval time.Duration `mapstructure:"refresh_interval"`
+	// Issuer and Audience, if set, must match the "iss" and "aud" claims.
+	Issuer   string `mapstructure:"issuer"`
+	Audience string `mapstructure:"audience"`
+	// Leeway tolerates clock skew when checking time-based claims.
+	Leeway time.Duration `mapstructure:"leeway"`
+	// RolesClaim is the claim holding the caller's roles.
+	RolesClaim string `mapstructure:"roles_claim"`
+	// TenantClaim is the claim holding the caller's tenant. Tokens without it act in the default tenant.
+	TenantClaim string `mapstructure:"tenant_claim"`
+	// RoleScopes maps roles to scopes. If empty, role names are used as scopes directly.
+	RoleScopes map[string][]string `mapstructure:"role_scopes"`
+}
+
 // PostgresConfig holds all settings for the PostgreSQL database connection.
 type PostgresConfig struct {
 	MasterDSN string     `mapstructure:"master_dsn"`
@@ -44,6 +159,8 @@ type PoolConfig struct {
 // RabbitMQConfig holds all settings for the RabbitMQ connection.
 type RabbitMQConfig struct {
 	DSN string `mapstructure:"dsn"`
+	// PublishTimeout is how long a publish waits for the broker to confirm the message.
+	PublishTimeout time.Duration `mapstructure:"publish_timeout"`
 }
 
 // RedisConfig holds all settings for the Redis connection.
@@ -53,6 +170,38 @@ type RedisConfig struct {
 	DB       int    `mapstructure:"db"`
 }
 
+// WorkerConfig holds settings for the background worker.
+type WorkerConfig struct {
+	// HTTPPort is the address of the worker's health and metrics endpoint.
+	HTTPPort string `mapstructure:"http_port"`
+	// ShutdownTimeout is how long the worker waits for in-flight messages on shutdown.
+	ShutdownTimeout time.Duration `mapstructure:"shutdown_timeout"`
+	// Concurrency is the number of workers consuming from the shared process queue.
+	Concurrency int `mapstructure:"concurrency"`
+	// Prefetch is the number of unacknowledged messages each worker may hold.
+	// Prefetched messages can no longer be overtaken by ones of a higher priority.
+	Prefetch int `mapstructure:"prefetch"`
+	// PerChannelQueues routes each channel to its own process queue with a dedicated pool,
+	// so a slow provider cannot block the others. It must match between API and worker.
+	PerChannelQueues bool `mapstructure:"per_channel_queues"`
+	// PriorityQueues declares the process queues as priority queues, so that notifications
+	// of a higher priority overtake a backlog. They have names of their own; the queues of
+	// the other setting are drained into them on startup. It must match between API and worker.
+	PriorityQueues bool `mapstructure:"priority_queues"`
+	// Channels holds per-channel pool settings used when PerChannelQueues is enabled.
+	// Missing values fall back to Concurrency and Prefetch.
+	Channels map[string]WorkerPoolConfig `mapstructure:"channels"`
+	// MaxLateness expires notifications picked up longer than this after their
+	// scheduled time instead of sending them. 0 disables expiry.
+	MaxLateness time.Duration `mapstructure:"max_lateness"`
+}
+
+// WorkerPoolConfig defines the size of a single worker pool.
+type WorkerPoolConfig struct {
+	Concurrency int `mapstructure:"concurrency"`
+	Prefetch    int `mapstructure:"prefetch"`
+}
+
 // NotifiersConfig holds configurations for all notification channels.
 type NotifiersConfig struct {
 	// Mode can be "development" or "production".
@@ -60,6 +209,34 @@ type NotifiersConfig struct {
 	Mode     string         `mapstructure:"mode"`
 	Email    EmailConfig    `mapstructure:"email"`
 	Telegram TelegramConfig `mapstructure:"telegram"`
+	// RateLimits holds the send rate limits keyed by channel name.
+	RateLimits map[string]ChannelRateLimitConfig `mapstructure:"rate_limits"`
+	// CircuitBreaker configures the circuit breaker wrapped around each channel notifier.
+	CircuitBreaker CircuitBreakerConfig `mapstructure:"circuit_breaker"`
+}
+
+// CircuitBreakerConfig holds the settings of the per-channel circuit breakers.
+type CircuitBreakerConfig struct {
+	// FailureThreshold is the number of consecutive failures that opens the breaker.
+	FailureThreshold int `mapstructure:"failure_threshold"`
+	// OpenTimeout is how long the breaker stays open before letting a probe through.
+	OpenTimeout time.Duration `mapstructure:"open_timeout"`
+}
+
+// ChannelRateLimitConfig holds the rate limits applied to a single channel.
+type ChannelRateLimitConfig struct {
+	// Channel limits the total send rate of the channel across all workers.
+	Channel RateLimitConfig `mapstructure:"channel"`
+	// Recipient limits the send rate to a single recipient of the channel.
+	Recipient RateLimitConfig `mapstructure:"recipient"`
+}
+
+// RateLimitConfig describes a token bucket: Rate tokens are added every Per,
+// up to Burst tokens. A zero Rate disables the limit.
+type RateLimitConfig struct {
+	Rate  float64       `mapstructure:"rate"`
+	Per   time.Duration `mapstructure:"per"`
+	Burst int           `mapstructure:"burst"`
 }
 
 // EmailConfig holds SMTP settings for the email notifier.
@@ -85,7 +262,33 @@ func NewConfig() (*Config, error) {
 	v.SetDefault("logger.level", "info")
 	v.SetDefault("http.port", ":8080")
 	v.SetDefault("http.gin_mode", "release")
+	v.SetDefault("grpc.port", ":9090")
 	v.SetDefault("notifiers.mode", "log_only")
+	v.SetDefault("rabbitmq.publish_timeout", "5s")
+	v.SetDefault("auth.mode", "api_key")
+	v.SetDefault("auth.bootstrap_admin_key", "")
+	v.SetDefault("auth.jwt.jwks_file", "")
+	v.SetDefault("auth.jwt.jwks_url", "")
+	v.SetDefault("auth.jwt.refresh_interval", "1h")
+	v.SetDefault("auth.jwt.roles_claim", "roles")
+	v.SetDefault("auth.jwt.tenant_claim", "tenant")
+	v.SetDefault("notifiers.circuit_breaker.failure_threshold", 5)
+	v.SetDefault("notifiers.circuit_breaker.open_timeout", "30s")
+	v.SetDefault("worker.http_port", ":8081")
+	v.SetDefault("worker.shutdown_timeout", "10s")
+	v.SetDefault("worker.concurrency", 5)
+	v.SetDefault("worker.prefetch", 1)
+	v.SetDefault("worker.per_channel_queues", false)
+	v.SetDefault("worker.max_lateness", "0s")
+	v.SetDefault("callbacks.signing_secret", "")
+	v.SetDefault("callbacks.timeout", "10s")
+	v.SetDefault("callbacks.max_attempts", 8)
+	v.SetDefault("callbacks.concurrency", 2)
+	v.SetDefault("events.stream_max_len", 10000)
+	v.SetDefault("events.heartbeat", "15s")
+	v.SetDefault("validation.max_horizon", "0s")
+	v.SetDefault("validation.past_tolerance", "1m")
+	v.SetDefault("validation.require_message", false)
 
 	v.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
 	v.AutomaticEnv()
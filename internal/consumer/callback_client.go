@@ -0,0 +1,85 @@
+package consumer
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"syscall"
+	"time"
+)
+
+// errForbiddenAddress is returned when a callback URL resolves to an address callbacks may not reach.
+var errForbiddenAddress = errors.New("callback address is not publicly routable")
+
+// reservedPrefixes are ranges that netip does not classify as private or local, but
+// that must not be reachable from callbacks either.
+var reservedPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),       // "This network".
+	netip.MustParsePrefix("100.64.0.0/10"),   // Carrier-grade NAT.
+	netip.MustParsePrefix("192.0.0.0/24"),    // IETF protocol assignments.
+	netip.MustParsePrefix("198.18.0.0/15"),   // Benchmarking.
+	netip.MustParsePrefix("240.0.0.0/4"),     // Reserved, including broadcast.
+	netip.MustParsePrefix("64:ff9b::/96"),    // NAT64, which embeds IPv4 addresses.
+	netip.MustParsePrefix("64:ff9b:1::/48"),  // Local-use NAT64.
+	netip.MustParsePrefix("2002::/16"),       // 6to4, which embeds IPv4 addresses.
+	netip.MustParsePrefix("2001:db8::/32"),   // Documentation.
+	netip.MustParsePrefix("2001::/32"),       // Teredo, which embeds IPv4 addresses.
+	netip.MustParsePrefix("100::/64"),        // Discard.
+	netip.MustParsePrefix("192.88.99.0/24"),  // 6to4 relay anycast.
+	netip.MustParsePrefix("198.51.100.0/24"), // Documentation.
+}
+
+// newCallbackClient returns the HTTP client of callback requests. Unless allowPrivate is
+// set, it refuses to connect to loopback, private, link-local and other non-public
+// addresses, such as the cloud metadata endpoint. The check runs on the resolved address
+// of every connection, so that a host name resolving to such an address, also after the
+// URL was validated, cannot bypass it. Redirects are not followed and count as failed
+// deliveries, and no proxy is used, since it would connect on the worker's behalf.
+func newCallbackClient(timeout time.Duration, allowPrivate bool) *http.Client {
+	dialer := &net.Dialer{Timeout: timeout}
+	if !allowPrivate {
+		dialer.Control = controlPublicAddress
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// controlPublicAddress is a net.Dialer Control hook rejecting connections to non-public addresses.
+func controlPublicAddress(_, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	addr, err := netip.ParseAddr(host)
+	if err != nil {
+		return err
+	}
+	if !isPublicAddress(addr) {
+		return fmt.Errorf("%w: %s", errForbiddenAddress, addr)
+	}
+	return nil
+}
+
+// isPublicAddress reports whether addr is a publicly routable unicast address.
+func isPublicAddress(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	if !addr.IsGlobalUnicast() || addr.IsPrivate() {
+		return false
+	}
+	for _, prefix := range reservedPrefixes {
+		if prefix.Contains(addr) {
+			return false
+		}
+	}
+	return true
+}
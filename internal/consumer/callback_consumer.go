@@ -0,0 +1,349 @@
+package consumer
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"github.com/ilindan-dev/delayed-notifier/internal/config"
+	"github.com/ilindan-dev/delayed-notifier/internal/domain/model"
+	repo "github.com/ilindan-dev/delayed-notifier/internal/domain/repository"
+	"github.com/ilindan-dev/delayed-notifier/internal/storage/rabbitmq"
+	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/rs/zerolog"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	// defaultCallbackAttempts is the default number of delivery attempts per callback event.
+	defaultCallbackAttempts = 8
+	// defaultCallbackConcurrency is the default number of callback delivery workers.
+	defaultCallbackConcurrency = 2
+	// defaultCallbackTimeout is the default timeout of a single callback request.
+	defaultCallbackTimeout = 10 * time.Second
+	// maxCallbackBackoff caps the delay between callback delivery attempts.
+	maxCallbackBackoff = time.Hour
+	// maxErrorBodySize limits how much of a failed response is kept in the delivery log.
+	maxErrorBodySize = 512
+)
+
+// Headers sent with every callback request. The signature is an HMAC-SHA256 of
+// "<timestamp>.<body>" keyed with the tenant's signing secret, as "sha256=<hex>".
+const (
+	HeaderCallbackEvent     = "X-Notifier-Event"
+	HeaderCallbackEventID   = "X-Notifier-Event-Id"
+	HeaderCallbackTimestamp = "X-Notifier-Timestamp"
+	HeaderCallbackSignature = "X-Notifier-Signature"
+)
+
+// callbackPayload is the JSON body POSTed to a callback URL.
+type callbackPayload struct {
+	EventID        string     `json:"event_id"`
+	Event          string     `json:"event"`
+	NotificationID string     `json:"notification_id"`
+	TenantID       string     `json:"tenant_id"`
+	Attempts       int        `json:"attempts"`
+	ScheduledAt    time.Time  `json:"scheduled_at"`
+	SentAt         *time.Time `json:"sent_at,omitempty"`
+	OccurredAt     time.Time  `json:"occurred_at"`
+	// Tags and Metadata are those of the notification, omitted if it has none.
+	Tags     []string       `json:"tags,omitempty"`
+	Metadata map[string]any `json:"metadata,omitempty"`
+}
+
+// CallbackConsumer delivers status change callbacks from the callback queue
+// to the callers' URLs, retrying failed deliveries with exponential backoff.
+type CallbackConsumer struct {
+	logger      zerolog.Logger
+	conn        *amqp.Connection
+	queue       repo.CallbackQueue
+	deliveries  repo.CallbackDeliveryRepository
+	client      *http.Client
+	secret      string
+	tenants     map[string]config.TenantConfig
+	maxAttempts int
+	concurrency int
+
+	shutdownTimeout time.Duration
+	stopConsuming   context.CancelFunc
+	abortInFlight   context.CancelFunc
+	wg              sync.WaitGroup
+}
+
+// NewCallbackConsumer creates a new instance of CallbackConsumer.
+func NewCallbackConsumer(
+	cfg *config.Config,
+	logger *zerolog.Logger,
+	conn *amqp.Connection,
+	queue repo.CallbackQueue,
+	deliveries repo.CallbackDeliveryRepository,
+) *CallbackConsumer {
+	log := logger.With().Str("component", "callback_consumer").Logger()
+	if cfg.Callbacks.SigningSecret == "" {
+		log.Warn().Msg("callbacks.signing_secret is empty, callbacks of tenants without their own secret are sent unsigned")
+	}
+
+	timeout := cfg.Callbacks.Timeout
+	if timeout <= 0 {
+		timeout = defaultCallbackTimeout
+	}
+	shutdownTimeout := cfg.Worker.ShutdownTimeout
+	if shutdownTimeout <= 0 {
+		shutdownTimeout = defaultShutdownTimeout
+	}
+
+	return &CallbackConsumer{
+		logger:          log,
+		conn:            conn,
+		queue:           queue,
+		deliveries:      deliveries,
+		client:          newCallbackClient(timeout, cfg.Callbacks.AllowPrivateAddresses),
+		secret:          cfg.Callbacks.SigningSecret,
+		tenants:         cfg.Tenants,
+		maxAttempts:     positiveOr(cfg.Callbacks.MaxAttempts, defaultCallbackAttempts),
+		concurrency:     positiveOr(cfg.Callbacks.Concurrency, defaultCallbackConcurrency),
+		shutdownTimeout: shutdownTimeout,
+	}
+}
+
+// Start launches the callback delivery workers. It returns immediately.
+func (c *CallbackConsumer) Start() {
+	consumeCtx, stopConsuming := context.WithCancel(context.Background())
+	handleCtx, abortInFlight := context.WithCancel(context.Background())
+	c.stopConsuming = stopConsuming
+	c.abortInFlight = abortInFlight
+
+	c.logger.Info().Int("count", c.concurrency).Msg("Starting callback workers")
+	for i := 0; i < c.concurrency; i++ {
+		c.wg.Add(1)
+		go func(workerID int) {
+			defer c.wg.Done()
+			c.runWorker(consumeCtx, handleCtx, workerID)
+		}(i + 1)
+	}
+}
+
+// Stop stops consuming new events and waits for in-flight deliveries to finish,
+// cancelling them once the shutdown timeout expires.
+func (c *CallbackConsumer) Stop(ctx context.Context) error {
+	if c.stopConsuming == nil {
+		return nil
+	}
+	c.logger.Info().Dur("timeout", c.shutdownTimeout).Msg("Stopping callback consumer")
+	c.stopConsuming()
+
+	done := make(chan struct{})
+	go func() {
+		c.wg.Wait()
+		close(done)
+	}()
+
+	timer := time.NewTimer(c.shutdownTimeout)
+	defer timer.Stop()
+
+	select {
+	case <-done:
+		c.abortInFlight()
+		c.logger.Info().Msg("Callback consumer stopped")
+		return nil
+	case <-timer.C:
+	case <-ctx.Done():
+	}
+
+	c.logger.Warn().Msg("Shutdown timeout reached, cancelling in-flight callbacks")
+	c.abortInFlight()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("callback consumer: workers did not stop in time: %w", ctx.Err())
+	}
+}
+
+// runWorker consumes callback events until consumeCtx is cancelled.
+func (c *CallbackConsumer) runWorker(consumeCtx, handleCtx context.Context, workerID int) {
+	logger := c.logger.With().Int("worker_id", workerID).Logger()
+
+	ch, err := c.conn.Channel()
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to open channel for callback worker")
+		return
+	}
+	defer ch.Close()
+
+	if err := ch.Qos(1, 0, false); err != nil {
+		logger.Error().Err(err).Msg("Failed to set QoS")
+		return
+	}
+
+	consumerTag := fmt.Sprintf("callback-worker-%d", workerID)
+	msgs, err := ch.Consume(rabbitmq.CallbacksQueue, consumerTag, false, false, false, false, nil)
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to register a consumer")
+		return
+	}
+
+	for {
+		select {
+		case <-consumeCtx.Done():
+			if err := ch.Cancel(consumerTag, false); err != nil {
+				logger.Warn().Err(err).Msg("Failed to cancel consumer")
+			}
+			return
+		case msg, ok := <-msgs:
+			if !ok {
+				logger.Warn().Msg("Message channel closed by RabbitMQ, callback worker stopping")
+				return
+			}
+			c.handleMessage(handleCtx, msg, logger)
+		}
+	}
+}
+
+// handleMessage delivers a single callback event and records the attempt.
+func (c *CallbackConsumer) handleMessage(ctx context.Context, msg amqp.Delivery, logger zerolog.Logger) {
+	var event model.CallbackEvent
+	if err := json.Unmarshal(msg.Body, &event); err != nil {
+		logger.Error().Err(err).Msg("Failed to unmarshal callback event, rejecting")
+		_ = msg.Nack(false, false)
+		return
+	}
+	logCtx := logger.With().
+		Stringer("event_id", event.ID).
+		Stringer("notification_id", event.NotificationID).
+		Str("event", string(event.Status))
+	log := withCorrelation(logCtx, event.Tags, event.Metadata).Logger()
+
+	event.DeliveryAttempts++
+	delivery := c.deliver(ctx, &event)
+	if err := c.deliveries.Save(ctx, delivery); err != nil {
+		log.Warn().Err(err).Msg("Failed to record callback delivery")
+	}
+
+	if delivery.Delivered {
+		log.Info().Int("attempt", event.DeliveryAttempts).Msg("Callback delivered")
+		_ = msg.Ack(false)
+		return
+	}
+	if ctx.Err() != nil {
+		// Shutting down; let the broker redeliver the event.
+		_ = msg.Nack(false, true)
+		return
+	}
+	if event.DeliveryAttempts >= c.maxAttempts {
+		log.Error().Int("attempts", event.DeliveryAttempts).Msg("Max callback attempts reached, giving up")
+		_ = msg.Ack(false)
+		return
+	}
+
+	backoff := calculateCallbackBackoff(event.DeliveryAttempts)
+	log.Warn().Int("attempt", event.DeliveryAttempts).Dur("backoff", backoff).Msg("Callback failed, scheduling retry")
+	if err := c.queue.PublishCallbackRetry(ctx, &event, backoff); err != nil {
+		log.Error().Err(err).Msg("CRITICAL: failed to publish callback event to retry queue")
+		_ = msg.Nack(false, true)
+		return
+	}
+	_ = msg.Ack(false)
+}
+
+// deliver POSTs the signed event to its callback URL. Only 2xx responses count as delivered.
+func (c *CallbackConsumer) deliver(ctx context.Context, event *model.CallbackEvent) *model.CallbackDelivery {
+	delivery := &model.CallbackDelivery{
+		EventID:        event.ID,
+		NotificationID: event.NotificationID,
+		TenantID:       event.TenantID,
+		Event:          event.Status,
+		URL:            event.URL,
+		Attempt:        event.DeliveryAttempts,
+	}
+	fail := func(err error) *model.CallbackDelivery {
+		msg := err.Error()
+		delivery.Error = &msg
+		return delivery
+	}
+
+	body, err := json.Marshal(callbackPayload{
+		EventID:        event.ID.String(),
+		Event:          string(event.Status),
+		NotificationID: event.NotificationID.String(),
+		TenantID:       event.TenantID,
+		Attempts:       event.Attempts,
+		ScheduledAt:    event.ScheduledAt,
+		SentAt:         event.SentAt,
+		OccurredAt:     event.OccurredAt,
+		Tags:           event.Tags,
+		Metadata:       event.Metadata,
+	})
+	if err != nil {
+		return fail(fmt.Errorf("failed to marshal callback payload: %w", err))
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, event.URL, bytes.NewReader(body))
+	if err != nil {
+		return fail(fmt.Errorf("failed to build callback request: %w", err))
+	}
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "delayed-notifier-callbacks")
+	req.Header.Set(HeaderCallbackEvent, string(event.Status))
+	req.Header.Set(HeaderCallbackEventID, event.ID.String())
+	req.Header.Set(HeaderCallbackTimestamp, timestamp)
+	if secret := c.secretFor(event.TenantID); secret != "" {
+		req.Header.Set(HeaderCallbackSignature, SignCallback(secret, timestamp, body))
+	}
+
+	start := time.Now()
+	resp, err := c.client.Do(req)
+	delivery.Duration = time.Since(start)
+	if err != nil {
+		return fail(err)
+	}
+	defer resp.Body.Close()
+
+	code := resp.StatusCode
+	delivery.StatusCode = &code
+	if code >= 200 && code < 300 {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		delivery.Delivered = true
+		return delivery
+	}
+
+	snippet, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+	return fail(fmt.Errorf("unexpected status %d: %s", code, bytes.TrimSpace(snippet)))
+}
+
+// secretFor returns the signing secret of a tenant, falling back to the global one.
+func (c *CallbackConsumer) secretFor(tenantID string) string {
+	if secret := c.tenants[tenantID].CallbackSecret; secret != "" {
+		return secret
+	}
+	return c.secret
+}
+
+// SignCallback computes the signature header value of a callback body sent at the given timestamp.
+// Receivers verify a callback by recomputing it and comparing it in constant time.
+func SignCallback(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// calculateCallbackBackoff returns the delay before the next delivery attempt:
+// 5s * 2^(attempt-1), capped at maxCallbackBackoff.
+func calculateCallbackBackoff(attempt int) time.Duration {
+	delay := 5 * time.Second * time.Duration(math.Pow(2, float64(attempt-1)))
+	if delay <= 0 || delay > maxCallbackBackoff {
+		return maxCallbackBackoff
+	}
+	return delay
+}
@@ -3,10 +3,14 @@ package consumer
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"github.com/ilindan-dev/delayed-notifier/internal/auth"
 	"github.com/ilindan-dev/delayed-notifier/internal/config"
+	"github.com/ilindan-dev/delayed-notifier/internal/domain"
 	"github.com/ilindan-dev/delayed-notifier/internal/domain/model"
 	repo "github.com/ilindan-dev/delayed-notifier/internal/domain/repository"
+	"github.com/ilindan-dev/delayed-notifier/internal/metrics"
 	"github.com/ilindan-dev/delayed-notifier/internal/notifiers"
 	"github.com/ilindan-dev/delayed-notifier/internal/service"
 	"github.com/ilindan-dev/delayed-notifier/internal/storage/rabbitmq"
@@ -22,17 +26,57 @@ const (
 	maxRetries = 5
 	// defaultWorkerCount is the default number of worker goroutines in the pool.
 	defaultWorkerCount = 5
+	// defaultPrefetch is the default number of unacknowledged messages per worker.
+	defaultPrefetch = 1
+	// defaultShutdownTimeout is how long Stop waits for in-flight messages by default.
+	defaultShutdownTimeout = 10 * time.Second
 )
 
 // Consumer listens to a RabbitMQ queue and processes messages using a pool of workers.
 type Consumer struct {
-	cfg         *config.Config
-	logger      zerolog.Logger
-	conn        *amqp.Connection // Raw connection to create channels for each worker.
-	service     *service.NotificationService
-	queue       repo.NotificationQueue
-	notifier    notifiers.Notifier
-	workerCount int
+	cfg      *config.Config
+	logger   zerolog.Logger
+	channels channelOpener // Opens a channel for each worker.
+	service  *service.NotificationService
+	queue    repo.NotificationQueue
+	dead     repo.DeadLetterQueue
+	notifier notifiers.Notifier
+	pools    []workerPool
+
+	shutdownTimeout time.Duration
+	stopConsuming   context.CancelFunc // Stops workers from taking new deliveries.
+	abortInFlight   context.CancelFunc // Cancels in-flight handlers once the drain timeout expires.
+	wg              sync.WaitGroup
+}
+
+// deliveryChannel is the part of an amqp channel a worker consumes deliveries with.
+type deliveryChannel interface {
+	Qos(prefetchCount, prefetchSize int, global bool) error
+	Consume(queue, consumer string, autoAck, exclusive, noLocal, noWait bool, args amqp.Table) (<-chan amqp.Delivery, error)
+	Cancel(consumer string, noWait bool) error
+	Close() error
+}
+
+// channelOpener opens the channels of the workers.
+type channelOpener interface {
+	Channel() (deliveryChannel, error)
+}
+
+// connectionChannels opens worker channels on an amqp connection.
+type connectionChannels struct {
+	conn *amqp.Connection
+}
+
+func (c connectionChannels) Channel() (deliveryChannel, error) {
+	return c.conn.Channel()
+}
+
+// workerPool describes a group of workers consuming from a single queue.
+type workerPool struct {
+	name        string
+	queue       string
+	concurrency int
+	prefetch    int
 }
 
 // New creates a new instance of Consumer.
@@ -42,62 +86,157 @@ func New(
 	conn *amqp.Connection,
 	service *service.NotificationService,
 	queue repo.NotificationQueue,
+	dead repo.DeadLetterQueue,
 	notifier notifiers.Notifier,
 ) *Consumer {
+	shutdownTimeout := cfg.Worker.ShutdownTimeout
+	if shutdownTimeout <= 0 {
+		shutdownTimeout = defaultShutdownTimeout
+	}
+
 	return &Consumer{
-		cfg:         cfg,
-		logger:      logger.With().Str("component", "consumer").Logger(),
-		conn:        conn,
-		service:     service,
-		queue:       queue,
-		notifier:    notifier,
-		workerCount: defaultWorkerCount,
+		cfg:             cfg,
+		logger:          logger.With().Str("component", "consumer").Logger(),
+		channels:        connectionChannels{conn: conn},
+		service:         service,
+		queue:           queue,
+		dead:            dead,
+		notifier:        notifier,
+		pools:           buildPools(cfg.Worker),
+		shutdownTimeout: shutdownTimeout,
 	}
 }
 
-// Start launches the worker pool to process messages from the queue.
-// This is a blocking method that will run until the context is cancelled.
-func (c *Consumer) Start(ctx context.Context) {
-	c.logger.Info().Int("count", c.workerCount).Msg("Starting worker pool")
-	var wg sync.WaitGroup
+// buildPools derives the worker pools from the configuration.
+// The shared pool always runs; in per-channel mode it only drains messages that were
+// published before the switch, while each channel gets a dedicated pool.
+func buildPools(cfg config.WorkerConfig) []workerPool {
+	shared := workerPool{
+		name:        "shared",
+		queue:       rabbitmq.SharedQueueName(cfg.PriorityQueues),
+		concurrency: positiveOr(cfg.Concurrency, defaultWorkerCount),
+		prefetch:    positiveOr(cfg.Prefetch, defaultPrefetch),
+	}
+	pools := []workerPool{shared}
+	if !cfg.PerChannelQueues {
+		return pools
+	}
 
-	for i := 0; i < c.workerCount; i++ {
-		wg.Add(1)
-		go func(workerID int) {
-			defer wg.Done()
-			c.runWorker(ctx, workerID)
-		}(i + 1)
+	for _, channel := range model.AllChannels {
+		poolCfg := cfg.Channels[string(channel)]
+		pools = append(pools, workerPool{
+			name:        string(channel),
+			queue:       rabbitmq.ProcessQueueName(channel, cfg.PriorityQueues),
+			concurrency: positiveOr(poolCfg.Concurrency, shared.concurrency),
+			prefetch:    positiveOr(poolCfg.Prefetch, shared.prefetch),
+		})
 	}
+	return pools
+}
 
-	wg.Wait()
-	c.logger.Info().Msg("Consumer stopped")
+// positiveOr returns v if it is positive and fallback otherwise.
+func positiveOr(v, fallback int) int {
+	if v > 0 {
+		return v
+	}
+	return fallback
+}
+
+// Start launches the worker pools to process messages from their queues.
+// It returns immediately; the workers run on contexts owned by the consumer
+// until Stop is called.
+func (c *Consumer) Start() {
+	consumeCtx, stopConsuming := context.WithCancel(context.Background())
+	handleCtx, abortInFlight := context.WithCancel(context.Background())
+	c.stopConsuming = stopConsuming
+	c.abortInFlight = abortInFlight
+
+	for _, pool := range c.pools {
+		c.logger.Info().
+			Str("pool", pool.name).
+			Str("queue", pool.queue).
+			Int("count", pool.concurrency).
+			Int("prefetch", pool.prefetch).
+			Msg("Starting worker pool")
+
+		for i := 0; i < pool.concurrency; i++ {
+			c.wg.Add(1)
+			go func(pool workerPool, workerID int) {
+				defer c.wg.Done()
+				c.runWorker(consumeCtx, handleCtx, pool, workerID)
+			}(pool, i+1)
+		}
+	}
+}
+
+// Stop stops consuming new messages and waits for in-flight messages to finish.
+// If they do not finish within the shutdown timeout, their context is cancelled.
+// Stop returns an error only if ctx expires before the workers have exited.
+func (c *Consumer) Stop(ctx context.Context) error {
+	if c.stopConsuming == nil {
+		return nil
+	}
+	c.logger.Info().Dur("timeout", c.shutdownTimeout).Msg("Stopping consumer, draining in-flight messages")
+	c.stopConsuming()
+
+	done := make(chan struct{})
+	go func() {
+		c.wg.Wait()
+		close(done)
+	}()
+
+	timer := time.NewTimer(c.shutdownTimeout)
+	defer timer.Stop()
+
+	select {
+	case <-done:
+		c.abortInFlight()
+		c.logger.Info().Msg("Consumer stopped")
+		return nil
+	case <-timer.C:
+		c.logger.Warn().Msg("Shutdown timeout reached, cancelling in-flight messages")
+	case <-ctx.Done():
+		c.logger.Warn().Msg("Shutdown deadline reached, cancelling in-flight messages")
+	}
+
+	c.abortInFlight()
+	select {
+	case <-done:
+		c.logger.Info().Msg("Consumer stopped")
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("consumer: workers did not stop in time: %w", ctx.Err())
+	}
 }
 
 // runWorker contains the main logic for a single worker goroutine.
-func (c *Consumer) runWorker(ctx context.Context, workerID int) {
-	logger := c.logger.With().Int("worker_id", workerID).Logger()
+// consumeCtx controls when the worker stops taking new deliveries, while
+// handleCtx is passed to message handlers so that a stop does not interrupt them.
+func (c *Consumer) runWorker(consumeCtx, handleCtx context.Context, pool workerPool, workerID int) {
+	logger := c.logger.With().Str("pool", pool.name).Int("worker_id", workerID).Logger()
 	logger.Info().Msg("Worker started")
 
-	ch, err := c.conn.Channel()
+	ch, err := c.channels.Channel()
 	if err != nil {
 		logger.Error().Err(err).Msg("Failed to open channel for worker")
 		return
 	}
 	defer ch.Close()
 
-	if err := ch.Qos(1, 0, false); err != nil {
+	if err := ch.Qos(pool.prefetch, 0, false); err != nil {
 		logger.Error().Err(err).Msg("Failed to set QoS")
 		return
 	}
 
+	consumerTag := fmt.Sprintf("%s-worker-%d", pool.name, workerID) // A unique consumer tag.
 	msgs, err := ch.Consume(
-		rabbitmq.NotificationsQueue,
-		fmt.Sprintf("worker-%d", workerID), // A unique consumer tag.
-		false,                              // autoAck: false. We will manually acknowledge messages.
-		false,                              // exclusive
-		false,                              // noLocal
-		false,                              // noWait
-		nil,                                // args
+		pool.queue,
+		consumerTag,
+		false, // autoAck: false. We will manually acknowledge messages.
+		false, // exclusive
+		false, // noLocal
+		false, // noWait
+		nil,   // args
 	)
 	if err != nil {
 		logger.Error().Err(err).Msg("Failed to register a consumer")
@@ -106,17 +245,29 @@ func (c *Consumer) runWorker(ctx context.Context, workerID int) {
 
 	logger.Info().Msg("Worker is waiting for messages")
 
+	stop := func() {
+		logger.Info().Msg("Worker stopping, cancelling consumer")
+		// Unacknowledged prefetched messages are requeued by the broker when the channel closes.
+		if err := ch.Cancel(consumerTag, false); err != nil {
+			logger.Warn().Err(err).Msg("Failed to cancel consumer")
+		}
+	}
 	for {
 		select {
-		case <-ctx.Done():
-			logger.Info().Msg("Worker stopping due to context cancellation")
+		case <-consumeCtx.Done():
+			stop()
 			return
 		case msg, ok := <-msgs:
 			if !ok {
 				logger.Warn().Msg("Message channel closed by RabbitMQ, worker stopping")
 				return
 			}
-			c.handleMessage(ctx, msg, logger)
+			if consumeCtx.Err() != nil {
+				// Prefetched messages may still be ready once the worker is stopped.
+				stop()
+				return
+			}
+			c.handleMessage(handleCtx, msg, logger)
 		}
 	}
 }
@@ -125,15 +276,24 @@ func (c *Consumer) runWorker(ctx context.Context, workerID int) {
 func (c *Consumer) handleMessage(ctx context.Context, msg amqp.Delivery, logger zerolog.Logger) {
 	var notification model.Notification
 	if err := json.Unmarshal(msg.Body, &notification); err != nil {
-		logger.Error().Err(err).Msg("Failed to unmarshal message, rejecting")
-		_ = msg.Nack(false, false)
+		logger.Error().Err(err).Msg("Failed to unmarshal message, moving it to the dead letter queue")
+		c.deadLetter(ctx, msg, "malformed message: "+err.Error(), logger)
 		return
 	}
 
-	log := logger.With().Stringer("notification_id", notification.ID).Logger()
+	logCtx := logger.With().Stringer("notification_id", notification.ID).Str("tenant_id", notification.TenantID)
+	log := withCorrelation(logCtx, notification.Tags, notification.Metadata).Logger()
+	if notification.TenantID == "" {
+		// Published before tenants were introduced.
+		notification.TenantID = model.DefaultTenant
+	}
+	ctx = auth.WithTenant(ctx, notification.TenantID)
 
 	latest, err := c.service.GetNotificationByID(ctx, notification.ID)
-	if err != nil || latest.Status != model.StatusScheduled {
+	// A processing notification whose message is redelivered was claimed by a worker
+	// that stopped before it recorded the outcome of the send.
+	resumed := err == nil && latest.Status == model.StatusProcessing && msg.Redelivered
+	if err != nil || (latest.Status != model.StatusScheduled && !resumed) {
 		status := "unknown"
 		if latest != nil {
 			status = string(latest.Status)
@@ -142,9 +302,44 @@ func (c *Consumer) handleMessage(ctx context.Context, msg amqp.Delivery, logger
 		_ = msg.Ack(false)
 		return
 	}
+	if isRescheduled(latest, &notification) {
+		log.Info().Time("scheduled_at", latest.ScheduledAt).Msg("Notification was rescheduled, skipping stale message")
+		_ = msg.Ack(false)
+		return
+	}
+
+	// The queued copy may be stale, so updates are based on the stored version. Attempts are
+	// counted in the message too, as they were only stored on the outcome of older sends.
+	notification.Version = latest.Version
+	notification.Attempts = max(notification.Attempts, latest.Attempts)
+
+	sender := c.notifier
+	if resumed {
+		log.Warn().Msg("Resuming notification claimed by a stopped worker")
+		notification.Status = model.StatusProcessing
+	} else {
+		admitted, ok := c.claim(ctx, &notification, msg, log)
+		if !ok {
+			return
+		}
+		sender = admitted
+	}
 
 	log.Info().Int("attempt", notification.Attempts+1).Msg("Processing notification")
-	err = c.notifier.Send(ctx, &notification)
+	err = sender.Send(ctx, &notification)
+	if err != nil && ctx.Err() != nil {
+		// The send was aborted by a stop, so it says nothing about the notification. The
+		// requeued message resumes it, as a message of a stopped worker would.
+		log.Warn().Err(err).Msg("Send aborted by shutdown, requeueing message")
+		_ = msg.Nack(false, true)
+		return
+	}
+	if retryAfter, reason, ok := notAttempted(err); ok {
+		if c.release(ctx, &notification, msg, log) {
+			c.deferMessage(ctx, &notification, retryAfter, msg, log.With().Str("reason", reason).Logger())
+		}
+		return
+	}
 	if err != nil {
 		c.handleSendError(ctx, &notification, err, msg, log)
 		return
@@ -154,14 +349,87 @@ func (c *Consumer) handleMessage(ctx context.Context, msg amqp.Delivery, logger
 	notification.Status = model.StatusSent
 	now := time.Now().UTC()
 	notification.SentAt = &now
-	if err := c.service.UpdateNotification(ctx, &notification); err != nil {
-		log.Error().Err(err).Msg("CRITICAL: failed to update notification status to 'sent' after successful send")
-		_ = msg.Nack(false, true)
+	if err := c.service.TransitionNotification(ctx, &notification, model.StatusProcessing); err != nil {
+		transitionFailed(msg, err, log, "failed to update notification status to 'sent' after successful send")
 		return
 	}
 	_ = msg.Ack(false)
 }
 
+// claim checks that a scheduled notification may be sent now and claims it for this
+// worker by moving it to processing, so that it can no longer be cancelled or rescheduled.
+// It returns the notifier to send it with. Otherwise it expires or defers the notification,
+// handles msg and returns false. Deferred notifications are left unchanged, so that
+// waiting for a window, a circuit breaker or a rate limit does not produce status events.
+func (c *Consumer) claim(ctx context.Context, n *model.Notification, msg amqp.Delivery, log zerolog.Logger) (notifiers.Notifier, bool) {
+	window, err := c.service.DeliveryWindow(ctx, n)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get delivery window, requeueing message")
+		_ = msg.Nack(false, true)
+		return nil, false
+	}
+	if c.isExpired(n, window, msg) {
+		log.Warn().Time("scheduled_at", n.ScheduledAt).Msg("Notification is past its maximum lateness, expiring")
+		c.expireMessage(ctx, n, msg, log)
+		return nil, false
+	}
+	if now := time.Now(); window != nil && !window.Allows(now) {
+		next, ok := window.Next(now)
+		if !ok {
+			log.Error().Msg("Delivery window never opens, expiring notification")
+			c.expireMessage(ctx, n, msg, log)
+			return nil, false
+		}
+		metrics.DeliveryWindowDeferrals.WithLabelValues(n.TenantID, string(n.Channel)).Inc()
+		c.deferMessage(ctx, n, next.Sub(now), msg, log.With().Str("reason", "delivery_window").Time("until", next).Logger())
+		return nil, false
+	}
+
+	admission, err := c.admit(ctx, n)
+	if retryAfter, reason, ok := notAttempted(err); ok {
+		c.deferMessage(ctx, n, retryAfter, msg, log.With().Str("reason", reason).Logger())
+		return nil, false
+	}
+	if err != nil {
+		// E.g. no notifier for the channel; the send reports it like any failed send.
+		admission = unchecked{c.notifier}
+	}
+
+	n.Status = model.StatusProcessing
+	if err := c.service.TransitionNotification(ctx, n, model.StatusScheduled); err != nil {
+		admission.Release()
+		transitionFailed(msg, err, log, "failed to claim notification")
+		return nil, false
+	}
+	return admission, true
+}
+
+// admit reserves a send of the notification if the notifier checks sends ahead.
+func (c *Consumer) admit(ctx context.Context, n *model.Notification) (notifiers.Admission, error) {
+	if admitter, ok := c.notifier.(notifiers.Admitter); ok {
+		return admitter.Admit(ctx, n)
+	}
+	return unchecked{c.notifier}, nil
+}
+
+// unchecked admits every send of a notifier that does not check sends ahead.
+type unchecked struct {
+	notifiers.Notifier
+}
+
+func (unchecked) Release() {}
+
+// release hands a claimed notification back to the scheduler before its message is
+// published again. It reports whether that succeeded; otherwise msg has been handled.
+func (c *Consumer) release(ctx context.Context, n *model.Notification, msg amqp.Delivery, log zerolog.Logger) bool {
+	n.Status = model.StatusScheduled
+	if err := c.service.TransitionNotification(ctx, n, model.StatusProcessing); err != nil {
+		transitionFailed(msg, err, log, "failed to update notification status back to 'scheduled'")
+		return false
+	}
+	return true
+}
+
 // handleSendError encapsulates the logic for processing failed sends.
 func (c *Consumer) handleSendError(ctx context.Context, n *model.Notification, sendErr error, msg amqp.Delivery, log zerolog.Logger) {
 	n.Attempts++
@@ -169,12 +437,14 @@ func (c *Consumer) handleSendError(ctx context.Context, n *model.Notification, s
 	if n.Attempts >= maxRetries {
 		log.Error().Err(sendErr).Int("attempts", n.Attempts).Msg("Max retries reached, failing notification")
 		n.Status = model.StatusFailed
-		if err := c.service.UpdateNotification(ctx, n); err != nil {
-			log.Error().Err(err).Msg("CRITICAL: failed to update notification status to 'failed'")
-			_ = msg.Nack(false, true) // Requeue.
+		if err := c.service.TransitionNotification(ctx, n, model.StatusProcessing); err != nil {
+			transitionFailed(msg, err, log, "failed to update notification status to 'failed'")
 			return
 		}
-		_ = msg.Ack(false)
+		c.deadLetter(ctx, msg, "max retries reached: "+sendErr.Error(), log)
+		return
+	}
+	if !c.release(ctx, n, msg, log) {
 		return
 	}
 
@@ -194,6 +464,103 @@ func (c *Consumer) handleSendError(ctx context.Context, n *model.Notification, s
 	_ = msg.Ack(false)
 }
 
+// notAttempted reports whether a send was rejected before it was attempted, e.g. by an
+// open circuit breaker or a rate limit, and when and why the notification is tried again.
+func notAttempted(err error) (retryAfter time.Duration, reason string, ok bool) {
+	var openErr *notifiers.CircuitOpenError
+	if errors.As(err, &openErr) {
+		return openErr.RetryAfter, "circuit_open", true
+	}
+	var limitErr *notifiers.RateLimitedError
+	if errors.As(err, &limitErr) {
+		return limitErr.RetryAfter, "rate_limited", true
+	}
+	return 0, "", false
+}
+
+// deferMessage re-schedules a notification that was not attempted, e.g. because its
+// channel's circuit breaker is open or it is over a rate limit. The attempts counter is left unchanged.
+// Deferred notifications wait apart from retries, so that long deferrals do not hold them back.
+func (c *Consumer) deferMessage(ctx context.Context, n *model.Notification, delay time.Duration, msg amqp.Delivery, log zerolog.Logger) {
+	log.Info().Dur("delay", delay).Msg("Send deferred without consuming an attempt")
+
+	if err := c.queue.PublishDeferred(ctx, n, delay); err != nil {
+		log.Error().Err(err).Msg("CRITICAL: failed to publish deferred message to deferral queue")
+		_ = msg.Nack(false, true)
+		return
+	}
+
+	_ = msg.Ack(false)
+}
+
+// deadLetter moves a message the worker gives up on to the dead letter queue, where
+// operators can inspect it. If that fails the message is dropped, as before dead letters existed.
+func (c *Consumer) deadLetter(ctx context.Context, msg amqp.Delivery, reason string, log zerolog.Logger) {
+	if err := c.dead.PublishDeadLetter(ctx, msg.Body, reason); err != nil {
+		log.Error().Err(err).Msg("failed to publish dead letter, dropping message")
+		_ = msg.Nack(false, false)
+		return
+	}
+	_ = msg.Ack(false)
+}
+
+// isExpired reports whether a notification was picked up too late after its scheduled time.
+// Only first attempts are checked, so that retries are not expired by their own backoff,
+// and deferred messages are not checked, whatever they waited for.
+// With a delivery window, lateness counts from the first opening of the window at or
+// after the scheduled time.
+func (c *Consumer) isExpired(n *model.Notification, window *model.DeliveryWindow, msg amqp.Delivery) bool {
+	maxLateness := c.cfg.Worker.MaxLateness
+	if maxLateness <= 0 || n.Attempts > 0 {
+		return false
+	}
+	if deferred, _ := msg.Headers[rabbitmq.DeferredHeader].(bool); deferred {
+		return false
+	}
+	due := n.ScheduledAt
+	if window != nil {
+		if next, ok := window.Next(due); ok {
+			due = next
+		}
+	}
+	return time.Since(due) > maxLateness
+}
+
+// expireMessage marks a notification that can no longer be sent as expired.
+func (c *Consumer) expireMessage(ctx context.Context, n *model.Notification, msg amqp.Delivery, log zerolog.Logger) {
+	n.Status = model.StatusExpired
+	if err := c.service.TransitionNotification(ctx, n, model.StatusScheduled); err != nil {
+		transitionFailed(msg, err, log, "failed to update notification status to 'expired'")
+		return
+	}
+	_ = msg.Ack(false)
+}
+
+// transitionFailed handles msg after the status of its notification could not be updated.
+// A notification that changed since it was read, e.g. because it was cancelled, is
+// evaluated again from the requeued message; illegal transitions and unknown notifications
+// are skipped, and on other errors the message is requeued.
+func transitionFailed(msg amqp.Delivery, err error, log zerolog.Logger, action string) {
+	switch {
+	case errors.Is(err, repo.ErrConflict):
+		log.Warn().Err(err).Msg("Notification changed concurrently, requeueing message")
+		_ = msg.Nack(false, true)
+		return
+	case errors.Is(err, domain.ErrInvalidTransition), errors.Is(err, repo.ErrNotFound):
+		log.Warn().Err(err).Msg("Notification cannot make the transition, skipping")
+		_ = msg.Ack(false)
+		return
+	}
+	log.Error().Err(err).Msg("CRITICAL: " + action)
+	_ = msg.Nack(false, true) // Requeue.
+}
+
+// isRescheduled reports whether a message was queued for an earlier schedule of the notification.
+// Rescheduling queues a new message, so the old one must not be sent.
+func isRescheduled(latest, queued *model.Notification) bool {
+	return !latest.ScheduledAt.Truncate(time.Microsecond).Equal(queued.ScheduledAt.Truncate(time.Microsecond))
+}
+
 // calculateExponentialBackoff implements the exponential backoff strategy.
 // Formula: 5s * 2^(attempt)
 func calculateExponentialBackoff(attempt int) time.Duration {
@@ -201,3 +568,14 @@ func calculateExponentialBackoff(attempt int) time.Duration {
 	delay := baseDelay * math.Pow(2, float64(attempt))
 	return time.Duration(delay) * time.Second
 }
+
+// withCorrelation adds the tags and metadata of a notification to a log context, if it has any.
+func withCorrelation(logCtx zerolog.Context, tags []string, metadata map[string]any) zerolog.Context {
+	if len(tags) > 0 {
+		logCtx = logCtx.Strs("tags", tags)
+	}
+	if len(metadata) > 0 {
+		logCtx = logCtx.Interface("metadata", metadata)
+	}
+	return logCtx
+}
@@ -0,0 +1,558 @@
+package consumer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/ilindan-dev/delayed-notifier/internal/config"
+	"github.com/ilindan-dev/delayed-notifier/internal/domain/model"
+	repo "github.com/ilindan-dev/delayed-notifier/internal/domain/repository"
+	"github.com/ilindan-dev/delayed-notifier/internal/notifiers"
+	"github.com/ilindan-dev/delayed-notifier/internal/service"
+	"github.com/ilindan-dev/delayed-notifier/internal/storage/rabbitmq"
+	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/rs/zerolog"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeNotificationRepo stores notifications in memory. Only the methods the consumer
+// uses are implemented. Transitions to a status in fail return its error instead.
+type fakeNotificationRepo struct {
+	repo.NotificationRepository
+	items map[uuid.UUID]*model.Notification
+	fail  map[model.Status]error
+}
+
+func (r *fakeNotificationRepo) GetByID(_ context.Context, tenantID string, id uuid.UUID) (*model.Notification, error) {
+	n, ok := r.items[id]
+	if !ok || n.TenantID != tenantID {
+		return nil, repo.ErrNotFound
+	}
+	result := *n
+	return &result, nil
+}
+
+func (r *fakeNotificationRepo) Transition(_ context.Context, n *model.Notification, from model.Status) error {
+	if err := r.fail[n.Status]; err != nil {
+		return err
+	}
+	stored, ok := r.items[n.ID]
+	if !ok || stored.TenantID != n.TenantID {
+		return repo.ErrNotFound
+	}
+	if stored.Status != from || stored.Version != n.Version {
+		return repo.ErrConflict
+	}
+	n.Version++
+	updated := *n
+	r.items[n.ID] = &updated
+	return nil
+}
+
+type fakeWindows struct{ repo.DeliveryWindowRepository }
+
+func (fakeWindows) Get(context.Context, string, model.Channel, string) (*model.DeliveryWindow, error) {
+	return nil, repo.ErrNotFound
+}
+
+// fakeEvents counts the published status events.
+type fakeEvents struct {
+	repo.StatusEventStream
+	published int
+}
+
+func (e *fakeEvents) Publish(context.Context, *model.StatusEvent) error {
+	e.published++
+	return nil
+}
+
+// fakeQueue records the notifications queued again by the consumer.
+type fakeQueue struct {
+	repo.NotificationQueue
+	retries, deferrals int
+}
+
+func (q *fakeQueue) PublishRetry(context.Context, *model.Notification, time.Duration) error {
+	q.retries++
+	return nil
+}
+
+func (q *fakeQueue) PublishDeferred(context.Context, *model.Notification, time.Duration) error {
+	q.deferrals++
+	return nil
+}
+
+type fakeDeadLetters struct{ published int }
+
+func (d *fakeDeadLetters) PublishDeadLetter(context.Context, []byte, string) error {
+	d.published++
+	return nil
+}
+
+// fakeNotifier answers every send with err and counts the sends.
+type fakeNotifier struct {
+	err   error
+	sends int
+}
+
+func (f *fakeNotifier) Send(context.Context, *model.Notification) error {
+	f.sends++
+	return f.err
+}
+
+// fakeAdmitter checks sends ahead like the dispatcher, rejecting them with admitErr.
+type fakeAdmitter struct {
+	fakeNotifier
+	admitErr         error
+	admits, releases int
+}
+
+func (f *fakeAdmitter) Admit(context.Context, *model.Notification) (notifiers.Admission, error) {
+	f.admits++
+	if f.admitErr != nil {
+		return nil, f.admitErr
+	}
+	return f, nil
+}
+
+func (f *fakeAdmitter) Release() {
+	f.releases++
+}
+
+// fakeAcknowledger records how a delivery was handled.
+type fakeAcknowledger struct {
+	acked, nacked, requeued bool
+}
+
+func (a *fakeAcknowledger) Ack(uint64, bool) error {
+	a.acked = true
+	return nil
+}
+
+func (a *fakeAcknowledger) Nack(_ uint64, _ bool, requeue bool) error {
+	a.nacked, a.requeued = true, requeue
+	return nil
+}
+
+func (a *fakeAcknowledger) Reject(_ uint64, requeue bool) error {
+	a.nacked, a.requeued = true, requeue
+	return nil
+}
+
+// outcome is how the consumer handled a delivery.
+type outcome int
+
+const (
+	acked outcome = iota
+	requeued
+)
+
+func TestHandleMessage(t *testing.T) {
+	scheduledAt := time.Now().Add(-time.Second).UTC()
+	tests := []struct {
+		name        string
+		stored      model.Status // Status of the stored notification.
+		redelivered bool
+		stale       bool // Whether the message is for an earlier schedule.
+		sendErr     error
+		fail        map[model.Status]error
+		want        outcome
+		wantSends   int
+		wantStatus  model.Status
+		wantRetry   bool
+		wantDefer   bool
+	}{
+		{name: "sent", stored: model.StatusScheduled, want: acked, wantSends: 1, wantStatus: model.StatusSent},
+		{name: "cancelled", stored: model.StatusCancelled, want: acked, wantStatus: model.StatusCancelled},
+		{name: "stale message", stored: model.StatusScheduled, stale: true, want: acked, wantStatus: model.StatusScheduled},
+		{
+			name:       "claimed by another worker",
+			stored:     model.StatusProcessing,
+			want:       acked,
+			wantStatus: model.StatusProcessing,
+		},
+		{
+			name:        "resumed after a stopped worker",
+			stored:      model.StatusProcessing,
+			redelivered: true,
+			want:        acked,
+			wantSends:   1,
+			wantStatus:  model.StatusSent,
+		},
+		{
+			name:       "claim conflict",
+			stored:     model.StatusScheduled,
+			fail:       map[model.Status]error{model.StatusProcessing: repo.ErrConflict},
+			want:       requeued,
+			wantStatus: model.StatusScheduled,
+		},
+		{
+			name:       "claimed notification gone",
+			stored:     model.StatusScheduled,
+			fail:       map[model.Status]error{model.StatusProcessing: repo.ErrNotFound},
+			want:       acked,
+			wantStatus: model.StatusScheduled,
+		},
+		{
+			name:       "outcome conflict",
+			stored:     model.StatusScheduled,
+			fail:       map[model.Status]error{model.StatusSent: repo.ErrConflict},
+			want:       requeued,
+			wantSends:  1,
+			wantStatus: model.StatusProcessing,
+		},
+		{
+			name:       "send failed",
+			stored:     model.StatusScheduled,
+			sendErr:    errors.New("provider unavailable"),
+			want:       acked,
+			wantSends:  1,
+			wantStatus: model.StatusScheduled,
+			wantRetry:  true,
+		},
+		{
+			name:       "rate limited",
+			stored:     model.StatusScheduled,
+			sendErr:    &notifiers.RateLimitedError{Key: "email", RetryAfter: time.Minute},
+			want:       acked,
+			wantSends:  1,
+			wantStatus: model.StatusScheduled,
+			wantDefer:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger := zerolog.Nop()
+			n := model.NewEmailNotification(model.DefaultTenant, "user@example.com", "Hello", "World", scheduledAt, nil)
+			stored := *n
+			stored.Status = tt.stored
+			stored.Version = 3
+			notifications := &fakeNotificationRepo{items: map[uuid.UUID]*model.Notification{n.ID: &stored}, fail: tt.fail}
+			queue := &fakeQueue{}
+			dead := &fakeDeadLetters{}
+			notifier := &fakeNotifier{err: tt.sendErr}
+			cfg := &config.Config{}
+			svc := service.NewNotificationService(cfg, notifications, queue, nil, nil, nil, &fakeEvents{}, fakeWindows{}, nil, &logger)
+			c := New(cfg, &logger, nil, svc, queue, dead, notifier)
+
+			if tt.stale {
+				n.ScheduledAt = scheduledAt.Add(-time.Hour)
+			}
+			body, err := json.Marshal(n)
+			if err != nil {
+				t.Fatal(err)
+			}
+			ack := &fakeAcknowledger{}
+			c.handleMessage(context.Background(), amqp.Delivery{Acknowledger: ack, Body: body, Redelivered: tt.redelivered}, logger)
+
+			switch tt.want {
+			case acked:
+				if !ack.acked || ack.nacked {
+					t.Errorf("got acked %v, nacked %v, want the message acknowledged", ack.acked, ack.nacked)
+				}
+			case requeued:
+				if ack.acked || !ack.requeued {
+					t.Errorf("got acked %v, requeued %v, want the message requeued", ack.acked, ack.requeued)
+				}
+			}
+			if notifier.sends != tt.wantSends {
+				t.Errorf("got %d sends, want %d", notifier.sends, tt.wantSends)
+			}
+			if got := notifications.items[n.ID].Status; got != tt.wantStatus {
+				t.Errorf("got status %s, want %s", got, tt.wantStatus)
+			}
+			if got := queue.retries > 0; got != tt.wantRetry {
+				t.Errorf("got retried %v, want %v", got, tt.wantRetry)
+			}
+			if got := queue.deferrals > 0; got != tt.wantDefer {
+				t.Errorf("got deferred %v, want %v", got, tt.wantDefer)
+			}
+			if tt.wantDefer && notifications.items[n.ID].Attempts != 0 {
+				t.Errorf("got %d attempts, want a deferral not to consume one", notifications.items[n.ID].Attempts)
+			}
+			if dead.published > 0 {
+				t.Errorf("got %d dead letters, want none", dead.published)
+			}
+		})
+	}
+}
+
+func TestHandleMessageChecksSendBeforeClaiming(t *testing.T) {
+	tests := []struct {
+		name         string
+		admitErr     error
+		fail         map[model.Status]error
+		want         outcome
+		wantSends    int
+		wantStatus   model.Status
+		wantVersion  int
+		wantEvents   int
+		wantReleases int
+	}{
+		{
+			name:        "admitted",
+			want:        acked,
+			wantSends:   1,
+			wantStatus:  model.StatusSent,
+			wantVersion: 5,
+			wantEvents:  2,
+		},
+		{
+			name:        "circuit open",
+			admitErr:    &notifiers.CircuitOpenError{Name: "default/email", RetryAfter: time.Minute},
+			want:        acked,
+			wantStatus:  model.StatusScheduled,
+			wantVersion: 3,
+		},
+		{
+			name:        "rate limited",
+			admitErr:    &notifiers.RateLimitedError{Key: "email", RetryAfter: time.Minute},
+			want:        acked,
+			wantStatus:  model.StatusScheduled,
+			wantVersion: 3,
+		},
+		{
+			name:         "claim conflict",
+			fail:         map[model.Status]error{model.StatusProcessing: repo.ErrConflict},
+			want:         requeued,
+			wantStatus:   model.StatusScheduled,
+			wantVersion:  3,
+			wantReleases: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger := zerolog.Nop()
+			n := model.NewEmailNotification(model.DefaultTenant, "user@example.com", "Hello", "World", time.Now().Add(-time.Second).UTC(), nil)
+			stored := *n
+			stored.Version = 3
+			notifications := &fakeNotificationRepo{items: map[uuid.UUID]*model.Notification{n.ID: &stored}, fail: tt.fail}
+			queue := &fakeQueue{}
+			events := &fakeEvents{}
+			notifier := &fakeAdmitter{admitErr: tt.admitErr}
+			cfg := &config.Config{}
+			svc := service.NewNotificationService(cfg, notifications, queue, nil, nil, nil, events, fakeWindows{}, nil, &logger)
+			c := New(cfg, &logger, nil, svc, queue, &fakeDeadLetters{}, notifier)
+
+			body, err := json.Marshal(n)
+			if err != nil {
+				t.Fatal(err)
+			}
+			ack := &fakeAcknowledger{}
+			c.handleMessage(context.Background(), amqp.Delivery{Acknowledger: ack, Body: body}, logger)
+
+			switch tt.want {
+			case acked:
+				if !ack.acked || ack.nacked {
+					t.Errorf("got acked %v, nacked %v, want the message acknowledged", ack.acked, ack.nacked)
+				}
+			case requeued:
+				if ack.acked || !ack.requeued {
+					t.Errorf("got acked %v, requeued %v, want the message requeued", ack.acked, ack.requeued)
+				}
+			}
+			if notifier.admits != 1 {
+				t.Errorf("got %d admissions, want 1", notifier.admits)
+			}
+			if notifier.sends != tt.wantSends {
+				t.Errorf("got %d sends, want %d", notifier.sends, tt.wantSends)
+			}
+			if notifier.releases != tt.wantReleases {
+				t.Errorf("got %d releases, want %d", notifier.releases, tt.wantReleases)
+			}
+			if got := queue.deferrals > 0; got != (tt.admitErr != nil) {
+				t.Errorf("got deferred %v, want %v", got, tt.admitErr != nil)
+			}
+			got := notifications.items[n.ID]
+			if got.Status != tt.wantStatus {
+				t.Errorf("got status %s, want %s", got.Status, tt.wantStatus)
+			}
+			// A deferral must leave the notification as it was, so that ETags stay valid
+			// and event streams are not flooded.
+			if got.Version != tt.wantVersion {
+				t.Errorf("got version %d, want %d", got.Version, tt.wantVersion)
+			}
+			if events.published != tt.wantEvents {
+				t.Errorf("got %d status events, want %d", events.published, tt.wantEvents)
+			}
+		})
+	}
+}
+
+func TestHandleMessageMaxLateness(t *testing.T) {
+	tests := []struct {
+		name       string
+		late       time.Duration
+		attempts   int
+		deferred   bool
+		wantStatus model.Status
+	}{
+		{name: "on time", late: time.Second, wantStatus: model.StatusSent},
+		{name: "late", late: time.Hour, wantStatus: model.StatusExpired},
+		{name: "late retry", late: time.Hour, attempts: 1, wantStatus: model.StatusSent},
+		{name: "late after a deferral", late: time.Hour, deferred: true, wantStatus: model.StatusSent},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger := zerolog.Nop()
+			n := model.NewEmailNotification(model.DefaultTenant, "user@example.com", "Hello", "World", time.Now().Add(-tt.late).UTC(), nil)
+			n.Attempts = tt.attempts
+			stored := *n
+			notifications := &fakeNotificationRepo{items: map[uuid.UUID]*model.Notification{n.ID: &stored}}
+			queue := &fakeQueue{}
+			cfg := &config.Config{Worker: config.WorkerConfig{MaxLateness: time.Minute}}
+			svc := service.NewNotificationService(cfg, notifications, queue, nil, nil, nil, &fakeEvents{}, fakeWindows{}, nil, &logger)
+			c := New(cfg, &logger, nil, svc, queue, &fakeDeadLetters{}, &fakeNotifier{})
+
+			body, err := json.Marshal(n)
+			if err != nil {
+				t.Fatal(err)
+			}
+			msg := amqp.Delivery{Acknowledger: &fakeAcknowledger{}, Body: body}
+			if tt.deferred {
+				// As the message comes back from a deferral queue, e.g. after an open circuit breaker.
+				msg.Headers = amqp.Table{rabbitmq.DeferredHeader: true}
+			}
+			c.handleMessage(context.Background(), msg, logger)
+
+			if got := notifications.items[n.ID].Status; got != tt.wantStatus {
+				t.Errorf("got status %s, want %s", got, tt.wantStatus)
+			}
+		})
+	}
+}
+
+// fakeChannel hands out its deliveries to a worker and records how the worker leaves it.
+type fakeChannel struct {
+	deliveries chan amqp.Delivery
+	mu         sync.Mutex
+	cancelled  bool
+	closed     bool
+}
+
+func (ch *fakeChannel) Qos(int, int, bool) error { return nil }
+
+func (ch *fakeChannel) Consume(string, string, bool, bool, bool, bool, amqp.Table) (<-chan amqp.Delivery, error) {
+	return ch.deliveries, nil
+}
+
+func (ch *fakeChannel) Cancel(string, bool) error {
+	ch.mu.Lock()
+	defer ch.mu.Unlock()
+	ch.cancelled = true
+	return nil
+}
+
+func (ch *fakeChannel) Close() error {
+	ch.mu.Lock()
+	defer ch.mu.Unlock()
+	ch.closed = true
+	return nil
+}
+
+// fakeChannels opens the same channel for every worker.
+type fakeChannels struct{ ch *fakeChannel }
+
+func (c fakeChannels) Channel() (deliveryChannel, error) { return c.ch, nil }
+
+// slowNotifier takes sendTime for a send, unless its context is cancelled first.
+type slowNotifier struct {
+	sendTime time.Duration
+	started  chan struct{}
+	sends    int
+}
+
+func (n *slowNotifier) Send(ctx context.Context, _ *model.Notification) error {
+	n.sends++
+	if n.sends == 1 {
+		close(n.started)
+	}
+	select {
+	case <-time.After(n.sendTime):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func TestStopDrainsInFlightMessages(t *testing.T) {
+	tests := []struct {
+		name            string
+		sendTime        time.Duration
+		shutdownTimeout time.Duration
+		want            outcome
+		wantStatus      model.Status
+	}{
+		{
+			name:            "finished within the shutdown timeout",
+			sendTime:        300 * time.Millisecond,
+			shutdownTimeout: 5 * time.Second,
+			want:            acked,
+			wantStatus:      model.StatusSent,
+		},
+		{
+			name:            "aborted at the shutdown timeout",
+			sendTime:        time.Hour,
+			shutdownTimeout: 50 * time.Millisecond,
+			want:            requeued,
+			wantStatus:      model.StatusProcessing, // Resumed from the redelivered message.
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger := zerolog.Nop()
+			n := model.NewEmailNotification(model.DefaultTenant, "user@example.com", "Hello", "World", time.Now().Add(-time.Second).UTC(), nil)
+			stored := *n
+			notifications := &fakeNotificationRepo{items: map[uuid.UUID]*model.Notification{n.ID: &stored}}
+			queue := &fakeQueue{}
+			notifier := &slowNotifier{sendTime: tt.sendTime, started: make(chan struct{})}
+			cfg := &config.Config{Worker: config.WorkerConfig{Concurrency: 1, ShutdownTimeout: tt.shutdownTimeout}}
+			svc := service.NewNotificationService(cfg, notifications, queue, nil, nil, nil, &fakeEvents{}, fakeWindows{}, nil, &logger)
+			c := New(cfg, &logger, nil, svc, queue, &fakeDeadLetters{}, notifier)
+			ch := &fakeChannel{deliveries: make(chan amqp.Delivery, 2)}
+			c.channels = fakeChannels{ch: ch}
+
+			body, err := json.Marshal(n)
+			if err != nil {
+				t.Fatal(err)
+			}
+			inFlight, prefetched := &fakeAcknowledger{}, &fakeAcknowledger{}
+			ch.deliveries <- amqp.Delivery{Acknowledger: inFlight, Body: body}
+			c.Start()
+			<-notifier.started
+			// A message prefetched while the first was handled is left to the broker.
+			ch.deliveries <- amqp.Delivery{Acknowledger: prefetched, Body: body}
+
+			start := time.Now()
+			if err := c.Stop(context.Background()); err != nil {
+				t.Fatal(err)
+			}
+			if elapsed := time.Since(start); elapsed > tt.shutdownTimeout+time.Second {
+				t.Errorf("stopped after %s, want within the shutdown timeout of %s", elapsed, tt.shutdownTimeout)
+			}
+
+			switch tt.want {
+			case acked:
+				if !inFlight.acked || inFlight.nacked {
+					t.Errorf("got acked %v, nacked %v, want the in-flight message acknowledged", inFlight.acked, inFlight.nacked)
+				}
+			case requeued:
+				if inFlight.acked || !inFlight.requeued {
+					t.Errorf("got acked %v, requeued %v, want the in-flight message requeued", inFlight.acked, inFlight.requeued)
+				}
+			}
+			if got := notifications.items[n.ID]; got.Status != tt.wantStatus || got.Attempts != 0 {
+				t.Errorf("got status %s with %d attempts, want %s with none", got.Status, got.Attempts, tt.wantStatus)
+			}
+			if prefetched.acked || prefetched.nacked || notifier.sends != 1 {
+				t.Errorf("got %d sends and the prefetched message acked %v, nacked %v, want it left unhandled", notifier.sends, prefetched.acked, prefetched.nacked)
+			}
+			if !ch.cancelled || !ch.closed {
+				t.Errorf("got consumer cancelled %v, channel closed %v, want both", ch.cancelled, ch.closed)
+			}
+		})
+	}
+}
@@ -0,0 +1,384 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/ilindan-dev/delayed-notifier/internal/config"
+	"github.com/ilindan-dev/delayed-notifier/internal/domain"
+	"github.com/ilindan-dev/delayed-notifier/internal/domain/model"
+	"github.com/ilindan-dev/delayed-notifier/internal/service"
+	notifierv1 "github.com/ilindan-dev/delayed-notifier/pkg/api/notifier/v1"
+	"github.com/rs/zerolog"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"time"
+)
+
+// defaultHeartbeat is how long WatchStatus waits for events before checking the stream again.
+const defaultHeartbeat = 15 * time.Second
+
+// errorDomain identifies the notifier in the ErrorInfo details of errors.
+const errorDomain = "delayed-notifier"
+
+// grpcCodes maps domain error codes to gRPC status codes.
+var grpcCodes = map[domain.Code]codes.Code{
+	domain.CodeValidationFailed:      codes.InvalidArgument,
+	domain.CodeMalformedRequest:      codes.InvalidArgument,
+	domain.CodeInvalidID:             codes.InvalidArgument,
+	domain.CodeInvalidRecipient:      codes.InvalidArgument,
+	domain.CodeInvalidChannel:        codes.InvalidArgument,
+	domain.CodeInvalidCallback:       codes.InvalidArgument,
+	domain.CodeScheduledInPast:       codes.InvalidArgument,
+	domain.CodeScheduledTooFar:       codes.InvalidArgument,
+	domain.CodeInvalidTimezone:       codes.InvalidArgument,
+	domain.CodeInvalidLocalTime:      codes.InvalidArgument,
+	domain.CodeInvalidDelay:          codes.InvalidArgument,
+	domain.CodeInvalidPriority:       codes.InvalidArgument,
+	domain.CodeInvalidTags:           codes.InvalidArgument,
+	domain.CodeInvalidMetadata:       codes.InvalidArgument,
+	domain.CodeInvalidFilter:         codes.InvalidArgument,
+	domain.CodeInvalidDeliveryWindow: codes.InvalidArgument,
+	domain.CodeRequired:              codes.InvalidArgument,
+	domain.CodeTooLong:               codes.InvalidArgument,
+	domain.CodeBatchTooLarge:         codes.InvalidArgument,
+	domain.CodeInvalidPageToken:      codes.InvalidArgument,
+	domain.CodeInvalidEventID:        codes.InvalidArgument,
+	domain.CodeUnauthenticated:       codes.Unauthenticated,
+	domain.CodeMissingScope:          codes.PermissionDenied,
+	domain.CodeForeignTenant:         codes.PermissionDenied,
+	domain.CodeOperatorOnly:          codes.PermissionDenied,
+	domain.CodeNotFound:              codes.NotFound,
+	domain.CodeDuplicate:             codes.AlreadyExists,
+	domain.CodeNotCancellable:        codes.FailedPrecondition,
+	domain.CodeNotScheduled:          codes.FailedPrecondition,
+	domain.CodeInvalidTransition:     codes.FailedPrecondition,
+	domain.CodeVersionConflict:       codes.Aborted,
+	domain.CodeQuotaExceeded:         codes.ResourceExhausted,
+}
+
+// Handlers implements the gRPC notification service on top of NotificationService.
+type Handlers struct {
+	notifierv1.UnimplementedNotificationServiceServer
+	service   *service.NotificationService
+	heartbeat time.Duration
+	logger    zerolog.Logger
+}
+
+// NewHandlers creates a new instance of Handlers.
+func NewHandlers(cfg *config.Config, service *service.NotificationService, logger *zerolog.Logger) *Handlers {
+	heartbeat := cfg.Events.Heartbeat
+	if heartbeat <= 0 {
+		heartbeat = defaultHeartbeat
+	}
+	return &Handlers{
+		service:   service,
+		heartbeat: heartbeat,
+		logger:    logger.With().Str("layer", "grpc_handler").Logger(),
+	}
+}
+
+// CreateNotification schedules a new notification.
+func (h *Handlers) CreateNotification(ctx context.Context, req *notifierv1.CreateNotificationRequest) (*notifierv1.Notification, error) {
+	in, err := toCreateInput(req)
+	if err != nil {
+		return nil, err
+	}
+	notification, err := h.service.CreateNotification(ctx, in)
+	if err != nil {
+		return nil, h.toStatus(err, "failed to create notification")
+	}
+	return toProtoNotification(notification), nil
+}
+
+// GetNotification returns a notification by ID.
+func (h *Handlers) GetNotification(ctx context.Context, req *notifierv1.GetNotificationRequest) (*notifierv1.Notification, error) {
+	id, err := parseID(req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	notification, err := h.service.GetNotificationByID(ctx, id)
+	if err != nil {
+		return nil, h.toStatus(err, "failed to retrieve notification")
+	}
+	return toProtoNotification(notification), nil
+}
+
+// CancelNotification cancels a scheduled notification.
+func (h *Handlers) CancelNotification(ctx context.Context, req *notifierv1.CancelNotificationRequest) (*notifierv1.CancelNotificationResponse, error) {
+	id, err := parseID(req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	if err := h.service.CancelNotification(ctx, id, fromProtoVersion(req.Version)); err != nil {
+		return nil, h.toStatus(err, "failed to cancel notification")
+	}
+	return &notifierv1.CancelNotificationResponse{}, nil
+}
+
+// RescheduleNotification moves a scheduled notification to a new time.
+func (h *Handlers) RescheduleNotification(ctx context.Context, req *notifierv1.RescheduleNotificationRequest) (*notifierv1.Notification, error) {
+	id, err := parseID(req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	notification, err := h.service.RescheduleNotification(ctx, id, service.RescheduleInput{
+		ScheduledAt: fromProtoTime(req.GetScheduledAt()),
+		LocalTime:   req.GetLocalTime(),
+		Timezone:    req.GetTimezone(),
+		Version:     fromProtoVersion(req.Version),
+	})
+	if err != nil {
+		return nil, h.toStatus(err, "failed to reschedule notification")
+	}
+	return toProtoNotification(notification), nil
+}
+
+// CancelNotifications starts a job cancelling every scheduled notification matching a filter.
+func (h *Handlers) CancelNotifications(ctx context.Context, req *notifierv1.CancelNotificationsRequest) (*notifierv1.StartBulkJobResponse, error) {
+	return h.startBulkJob(ctx, service.BulkJobInput{
+		Action: model.BulkActionCancel,
+		Filter: fromProtoFilter(req.GetFilter()),
+		DryRun: req.GetDryRun(),
+	})
+}
+
+// RescheduleNotifications starts a job moving every scheduled notification matching a filter to a new time.
+func (h *Handlers) RescheduleNotifications(ctx context.Context, req *notifierv1.RescheduleNotificationsRequest) (*notifierv1.StartBulkJobResponse, error) {
+	return h.startBulkJob(ctx, service.BulkJobInput{
+		Action: model.BulkActionReschedule,
+		Filter: fromProtoFilter(req.GetFilter()),
+		Reschedule: service.RescheduleInput{
+			ScheduledAt: fromProtoTime(req.GetScheduledAt()),
+			LocalTime:   req.GetLocalTime(),
+			Timezone:    req.GetTimezone(),
+		},
+		DryRun: req.GetDryRun(),
+	})
+}
+
+func (h *Handlers) startBulkJob(ctx context.Context, in service.BulkJobInput) (*notifierv1.StartBulkJobResponse, error) {
+	job, err := h.service.StartBulkJob(ctx, in)
+	if err != nil {
+		return nil, h.toStatus(err, "failed to start bulk job")
+	}
+	resp := &notifierv1.StartBulkJobResponse{Matched: int32(job.Total)}
+	if !in.DryRun {
+		resp.Job = toProtoBulkJob(job)
+	}
+	return resp, nil
+}
+
+// GetBulkJob returns a bulk job and its progress.
+func (h *Handlers) GetBulkJob(ctx context.Context, req *notifierv1.GetBulkJobRequest) (*notifierv1.BulkJob, error) {
+	id, err := uuid.Parse(req.GetId())
+	if err != nil {
+		return nil, invalidArgument(domain.CodeInvalidID, "id", "invalid job ID format")
+	}
+	job, err := h.service.GetBulkJob(ctx, id)
+	if err != nil {
+		return nil, h.toStatus(err, "failed to retrieve bulk job")
+	}
+	return toProtoBulkJob(job), nil
+}
+
+// ListNotifications lists notifications, newest first.
+func (h *Handlers) ListNotifications(ctx context.Context, req *notifierv1.ListNotificationsRequest) (*notifierv1.ListNotificationsResponse, error) {
+	if req.GetPageSize() < 0 || req.GetPageSize() > service.MaxPageSize {
+		return nil, invalidArgument(domain.CodeValidationFailed, "page_size", fmt.Sprintf("page_size must be between 0 and %d", service.MaxPageSize))
+	}
+
+	filter := model.NotificationFilter{
+		AuthorID: req.AuthorId,
+		Search:   req.Search,
+		Tags:     req.GetTags(),
+		Metadata: req.GetMetadata().AsMap(),
+	}
+	if req.GetStatus() != notifierv1.Status_STATUS_UNSPECIFIED {
+		s := fromProtoStatus(req.GetStatus())
+		filter.Status = &s
+	}
+	if req.GetChannel() != notifierv1.Channel_CHANNEL_UNSPECIFIED {
+		c := fromProtoChannel(req.GetChannel())
+		filter.Channel = &c
+	}
+	if req.ScheduledFrom != nil {
+		from := req.ScheduledFrom.AsTime()
+		filter.ScheduledFrom = &from
+	}
+	if req.ScheduledTo != nil {
+		to := req.ScheduledTo.AsTime()
+		filter.ScheduledTo = &to
+	}
+
+	notifications, nextPageToken, err := h.service.ListNotifications(ctx, filter, req.GetPageToken(), int(req.GetPageSize()))
+	if err != nil {
+		return nil, h.toStatus(err, "failed to list notifications")
+	}
+
+	resp := &notifierv1.ListNotificationsResponse{NextPageToken: nextPageToken}
+	for _, n := range notifications {
+		resp.Notifications = append(resp.Notifications, toProtoNotification(n))
+	}
+	return resp, nil
+}
+
+// BatchCreateNotifications creates several notifications independently.
+func (h *Handlers) BatchCreateNotifications(ctx context.Context, req *notifierv1.BatchCreateNotificationsRequest) (*notifierv1.BatchCreateNotificationsResponse, error) {
+	if len(req.GetNotifications()) == 0 {
+		return nil, invalidArgument(domain.CodeValidationFailed, "notifications", "notifications must not be empty")
+	}
+
+	// Requests that fail conversion are reported in place without being sent to the service.
+	results := make([]*notifierv1.BatchCreateResult, len(req.GetNotifications()))
+	inputs := make([]service.CreateNotificationInput, 0, len(req.GetNotifications()))
+	positions := make([]int, 0, len(req.GetNotifications()))
+	for i, item := range req.GetNotifications() {
+		in, err := toCreateInput(item)
+		if err != nil {
+			results[i] = batchError(status.Convert(err))
+			continue
+		}
+		inputs = append(inputs, in)
+		positions = append(positions, i)
+	}
+
+	created, err := h.service.CreateNotifications(ctx, inputs, "")
+	if err != nil {
+		return nil, h.toStatus(err, "failed to create notifications")
+	}
+	for j, result := range created {
+		if result.Err != nil {
+			results[positions[j]] = batchError(status.Convert(h.toStatus(result.Err, "failed to create notification")))
+			continue
+		}
+		results[positions[j]] = &notifierv1.BatchCreateResult{
+			Result: &notifierv1.BatchCreateResult_Notification{Notification: toProtoNotification(result.Notification)},
+		}
+	}
+	return &notifierv1.BatchCreateNotificationsResponse{Results: results}, nil
+}
+
+// WatchStatus streams status changes until the client cancels the call.
+func (h *Handlers) WatchStatus(req *notifierv1.WatchStatusRequest, stream grpc.ServerStreamingServer[notifierv1.StatusEvent]) error {
+	ctx := stream.Context()
+
+	var notificationID *uuid.UUID
+	if req.NotificationId != nil {
+		id, err := parseID(req.GetNotificationId())
+		if err != nil {
+			return err
+		}
+		if _, err := h.service.GetNotificationByID(ctx, id); err != nil {
+			return h.toStatus(err, "failed to retrieve notification")
+		}
+		notificationID = &id
+	}
+
+	cursor := req.GetLastEventId()
+	for {
+		events, next, err := h.service.NextStatusEvents(ctx, notificationID, cursor, h.heartbeat)
+		if err != nil {
+			if ctx.Err() != nil {
+				return status.FromContextError(ctx.Err()).Err()
+			}
+			return h.toStatus(err, "failed to read status events")
+		}
+		cursor = next
+
+		for _, e := range events {
+			if err := stream.Send(toProtoStatusEvent(e)); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// toStatus maps domain errors to gRPC status errors carrying the domain error code
+// as the reason of an ErrorInfo detail. Unexpected errors are logged and reported
+// as Internal with a generic message.
+func (h *Handlers) toStatus(err error, internalMsg string) error {
+	var invalid *domain.ValidationError
+	if errors.As(err, &invalid) {
+		return validationStatus(invalid)
+	}
+	var domainErr *domain.Error
+	if errors.As(err, &domainErr) {
+		if code, ok := grpcCodes[domainErr.Code]; ok {
+			return withErrorInfo(status.New(code, domainErr.Message), domainErr.Code, domainErr.Field)
+		}
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return status.FromContextError(err).Err()
+	}
+	h.logger.Error().Err(err).Msg(internalMsg)
+	return withErrorInfo(status.New(codes.Internal, internalMsg), domain.CodeInternal, "")
+}
+
+// withErrorInfo attaches the domain error code, and the field at fault if any, to st.
+func withErrorInfo(st *status.Status, code domain.Code, field string) error {
+	info := &errdetails.ErrorInfo{Reason: string(code), Domain: errorDomain}
+	if field != "" {
+		info.Metadata = map[string]string{"field": field}
+	}
+	if detailed, err := st.WithDetails(info); err == nil {
+		st = detailed
+	}
+	return st.Err()
+}
+
+// validationStatus returns an InvalidArgument error listing every invalid field in a
+// BadRequest detail, whose violations carry the domain error codes as reasons.
+func validationStatus(invalid *domain.ValidationError) error {
+	badRequest := &errdetails.BadRequest{}
+	for _, f := range invalid.Fields {
+		badRequest.FieldViolations = append(badRequest.FieldViolations, &errdetails.BadRequest_FieldViolation{
+			Field:       f.Field,
+			Description: f.Message,
+			Reason:      string(f.Code),
+		})
+	}
+	st := status.New(codes.InvalidArgument, invalid.Error())
+	if detailed, err := st.WithDetails(badRequest); err == nil {
+		st = detailed
+	}
+	return withErrorInfo(st, domain.CodeValidationFailed, "")
+}
+
+// invalidArgument returns an InvalidArgument error for a request field.
+func invalidArgument(code domain.Code, field, msg string) error {
+	return withErrorInfo(status.New(codes.InvalidArgument, msg), code, field)
+}
+
+// errorReason returns the domain error code attached to st by withErrorInfo.
+func errorReason(st *status.Status) string {
+	for _, detail := range st.Details() {
+		if info, ok := detail.(*errdetails.ErrorInfo); ok && info.GetDomain() == errorDomain {
+			return info.GetReason()
+		}
+	}
+	return ""
+}
+
+// parseID parses a notification ID, reporting malformed IDs as InvalidArgument.
+func parseID(raw string) (uuid.UUID, error) {
+	id, err := uuid.Parse(raw)
+	if err != nil {
+		return uuid.Nil, invalidArgument(domain.CodeInvalidID, "id", "invalid notification ID format")
+	}
+	return id, nil
+}
+
+// batchError converts a status to the error result of a batch item.
+func batchError(s *status.Status) *notifierv1.BatchCreateResult {
+	return &notifierv1.BatchCreateResult{
+		Result: &notifierv1.BatchCreateResult_Error{Error: &notifierv1.BatchCreateError{
+			Code:    int32(s.Code()),
+			Message: s.Message(),
+			Reason:  errorReason(s),
+		}},
+	}
+}
@@ -0,0 +1,134 @@
+package grpc
+
+import (
+	"context"
+	"github.com/ilindan-dev/delayed-notifier/internal/auth"
+	"github.com/ilindan-dev/delayed-notifier/internal/domain"
+	"github.com/ilindan-dev/delayed-notifier/internal/domain/model"
+	"github.com/ilindan-dev/delayed-notifier/internal/service"
+	notifierv1 "github.com/ilindan-dev/delayed-notifier/pkg/api/notifier/v1"
+	"github.com/rs/zerolog"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"strings"
+)
+
+const apiKeyMetadata = "x-api-key"
+
+// methodScopes lists the scope required by each method of the notification service.
+// Methods not listed here, such as health checks and reflection, need no authentication.
+var methodScopes = map[string]model.Scope{
+	notifierv1.NotificationService_CreateNotification_FullMethodName:       model.ScopeCreate,
+	notifierv1.NotificationService_BatchCreateNotifications_FullMethodName: model.ScopeCreate,
+	notifierv1.NotificationService_GetNotification_FullMethodName:          model.ScopeRead,
+	notifierv1.NotificationService_ListNotifications_FullMethodName:        model.ScopeRead,
+	notifierv1.NotificationService_WatchStatus_FullMethodName:              model.ScopeRead,
+	notifierv1.NotificationService_RescheduleNotification_FullMethodName:   model.ScopeCreate,
+	notifierv1.NotificationService_CancelNotification_FullMethodName:       model.ScopeCancel,
+	notifierv1.NotificationService_CancelNotifications_FullMethodName:      model.ScopeCancel,
+	notifierv1.NotificationService_RescheduleNotifications_FullMethodName:  model.ScopeCreate,
+	notifierv1.NotificationService_GetBulkJob_FullMethodName:               model.ScopeRead,
+}
+
+// authInterceptor authenticates callers from request metadata and enforces method scopes,
+// accepting the same credentials as the REST API.
+type authInterceptor struct {
+	authenticator *service.Authenticator
+	logger        zerolog.Logger
+}
+
+func newAuthInterceptor(authenticator *service.Authenticator, logger zerolog.Logger) *authInterceptor {
+	return &authInterceptor{authenticator: authenticator, logger: logger}
+}
+
+func (i *authInterceptor) unary(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	ctx, err := i.authorize(ctx, info.FullMethod)
+	if err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+func (i *authInterceptor) stream(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, err := i.authorize(ss.Context(), info.FullMethod)
+	if err != nil {
+		return err
+	}
+	return handler(srv, &contextStream{ServerStream: ss, ctx: ctx})
+}
+
+// authorize returns ctx carrying the caller's principal if the caller may call the method.
+func (i *authInterceptor) authorize(ctx context.Context, method string) (context.Context, error) {
+	scope, protected := methodScopes[method]
+	if !protected || !i.authenticator.Enabled() {
+		return ctx, nil
+	}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	principal, err := i.authenticator.Authenticate(ctx, firstValue(md, apiKeyMetadata), bearerToken(md))
+	if err != nil {
+		if service.IsUnauthenticated(err) {
+			return nil, withErrorInfo(status.New(codes.Unauthenticated, service.UnauthenticatedMessage(err)), domain.CodeUnauthenticated, "")
+		}
+		i.logger.Error().Err(err).Str("method", method).Msg("failed to authenticate rpc")
+		return nil, status.Error(codes.Internal, "failed to authenticate")
+	}
+	if !principal.HasScope(scope) {
+		return nil, withErrorInfo(status.New(codes.PermissionDenied, "missing scope: "+string(scope)), domain.CodeMissingScope, "")
+	}
+	return auth.WithPrincipal(ctx, principal), nil
+}
+
+// contextStream overrides the context of a server stream.
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *contextStream) Context() context.Context {
+	return s.ctx
+}
+
+// recoveryUnaryInterceptor turns handler panics into Internal errors, like gin.Recovery for HTTP.
+func recoveryUnaryInterceptor(logger zerolog.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				logger.Error().Interface("panic", r).Str("method", info.FullMethod).Msg("recovered from panic in rpc")
+				err = status.Error(codes.Internal, "internal error")
+			}
+		}()
+		return handler(ctx, req)
+	}
+}
+
+// recoveryStreamInterceptor turns stream handler panics into Internal errors.
+func recoveryStreamInterceptor(logger zerolog.Logger) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				logger.Error().Interface("panic", r).Str("method", info.FullMethod).Msg("recovered from panic in rpc")
+				err = status.Error(codes.Internal, "internal error")
+			}
+		}()
+		return handler(srv, ss)
+	}
+}
+
+// firstValue returns the first metadata value of the key, or an empty string.
+func firstValue(md metadata.MD, key string) string {
+	if values := md.Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+// bearerToken reads the token from a bearer authorization metadata entry.
+func bearerToken(md metadata.MD) string {
+	if token, ok := strings.CutPrefix(firstValue(md, "authorization"), "Bearer "); ok {
+		return strings.TrimSpace(token)
+	}
+	return ""
+}
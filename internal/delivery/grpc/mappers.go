@@ -0,0 +1,250 @@
+package grpc
+
+import (
+	"github.com/ilindan-dev/delayed-notifier/internal/domain"
+	"github.com/ilindan-dev/delayed-notifier/internal/domain/model"
+	"github.com/ilindan-dev/delayed-notifier/internal/service"
+	notifierv1 "github.com/ilindan-dev/delayed-notifier/pkg/api/notifier/v1"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"time"
+)
+
+var (
+	channelsToProto = map[model.Channel]notifierv1.Channel{
+		model.ChannelEmail:    notifierv1.Channel_CHANNEL_EMAIL,
+		model.ChannelTelegram: notifierv1.Channel_CHANNEL_TELEGRAM,
+	}
+	prioritiesToProto = map[model.Priority]notifierv1.Priority{
+		model.PriorityLow:      notifierv1.Priority_PRIORITY_LOW,
+		model.PriorityNormal:   notifierv1.Priority_PRIORITY_NORMAL,
+		model.PriorityHigh:     notifierv1.Priority_PRIORITY_HIGH,
+		model.PriorityCritical: notifierv1.Priority_PRIORITY_CRITICAL,
+	}
+	statusesToProto = map[model.Status]notifierv1.Status{
+		model.StatusScheduled:  notifierv1.Status_STATUS_SCHEDULED,
+		model.StatusProcessing: notifierv1.Status_STATUS_PROCESSING,
+		model.StatusSent:       notifierv1.Status_STATUS_SENT,
+		model.StatusFailed:     notifierv1.Status_STATUS_FAILED,
+		model.StatusCancelled:  notifierv1.Status_STATUS_CANCELLED,
+		model.StatusExpired:    notifierv1.Status_STATUS_EXPIRED,
+	}
+	bulkActionsToProto = map[model.BulkAction]notifierv1.BulkAction{
+		model.BulkActionCancel:     notifierv1.BulkAction_BULK_ACTION_CANCEL,
+		model.BulkActionReschedule: notifierv1.BulkAction_BULK_ACTION_RESCHEDULE,
+	}
+	bulkJobStatusesToProto = map[model.BulkJobStatus]notifierv1.BulkJobStatus{
+		model.BulkJobRunning:   notifierv1.BulkJobStatus_BULK_JOB_STATUS_RUNNING,
+		model.BulkJobCompleted: notifierv1.BulkJobStatus_BULK_JOB_STATUS_COMPLETED,
+		model.BulkJobFailed:    notifierv1.BulkJobStatus_BULK_JOB_STATUS_FAILED,
+	}
+)
+
+// fromProtoChannel converts a protobuf channel to the domain channel.
+// Unknown values map to an empty channel, which the service rejects.
+func fromProtoChannel(c notifierv1.Channel) model.Channel {
+	for channel, proto := range channelsToProto {
+		if proto == c {
+			return channel
+		}
+	}
+	return ""
+}
+
+// fromProtoPriority converts a protobuf priority to the domain priority. Unspecified
+// maps to an empty priority, which defaults to normal; unknown values map to their
+// number, which the service rejects.
+func fromProtoPriority(p notifierv1.Priority) model.Priority {
+	if p == notifierv1.Priority_PRIORITY_UNSPECIFIED {
+		return ""
+	}
+	for priority, proto := range prioritiesToProto {
+		if proto == p {
+			return priority
+		}
+	}
+	return model.Priority(p.String())
+}
+
+// fromProtoStatus converts a protobuf status to the domain status.
+func fromProtoStatus(s notifierv1.Status) model.Status {
+	for st, proto := range statusesToProto {
+		if proto == s {
+			return st
+		}
+	}
+	return ""
+}
+
+// fromProtoVersion converts an optional expected version of a request.
+func fromProtoVersion(v *int32) *int {
+	if v == nil {
+		return nil
+	}
+	version := int(*v)
+	return &version
+}
+
+// localTimeLayout formats Notification.local_time.
+const localTimeLayout = "2006-01-02T15:04:05"
+
+// fromProtoTime converts an optional timestamp, returning the zero time if it is unset.
+func fromProtoTime(ts *timestamppb.Timestamp) time.Time {
+	if ts == nil {
+		return time.Time{}
+	}
+	return ts.AsTime()
+}
+
+// toCreateInput converts a create request to the service's creation input.
+func toCreateInput(req *notifierv1.CreateNotificationRequest) (service.CreateNotificationInput, error) {
+	if req.GetRecipient() == "" || req.GetSubject() == "" {
+		return service.CreateNotificationInput{}, invalidArgument(domain.CodeValidationFailed, "", "recipient and subject are required")
+	}
+	if req.GetChannel() == notifierv1.Channel_CHANNEL_UNSPECIFIED {
+		return service.CreateNotificationInput{}, invalidArgument(domain.CodeValidationFailed, "channel", "channel is required")
+	}
+	in := service.CreateNotificationInput{
+		Recipient:   req.GetRecipient(),
+		Channel:     fromProtoChannel(req.GetChannel()),
+		Subject:     req.GetSubject(),
+		Message:     req.GetMessage(),
+		ScheduledAt: fromProtoTime(req.GetScheduledAt()),
+		LocalTime:   req.GetLocalTime(),
+		Timezone:    req.GetTimezone(),
+		Delay:       req.GetDelay(),
+		SendNow:     req.GetSendNow(),
+		Priority:    fromProtoPriority(req.GetPriority()),
+		Tags:        req.GetTags(),
+		Metadata:    req.GetMetadata().AsMap(),
+		AuthorID:    req.AuthorId,
+		CallbackURL: req.CallbackUrl,
+	}
+	if w := req.GetDeliveryWindow(); w != nil {
+		in.DeliveryWindow = &service.DeliveryWindowInput{
+			Timezone:   w.GetTimezone(),
+			Days:       w.GetDays(),
+			Hours:      fromProtoClockRange(w.GetHours()),
+			QuietHours: fromProtoClockRange(w.GetQuietHours()),
+		}
+	}
+	return in, nil
+}
+
+func fromProtoClockRange(r *notifierv1.ClockRange) *service.ClockRangeInput {
+	if r == nil {
+		return nil
+	}
+	return &service.ClockRangeInput{Start: r.GetStart(), End: r.GetEnd()}
+}
+
+// toProtoNotification maps the domain model to its protobuf representation.
+func toProtoNotification(n *model.Notification) *notifierv1.Notification {
+	pb := &notifierv1.Notification{
+		Id:          n.ID.String(),
+		Status:      statusesToProto[n.Status],
+		Channel:     channelsToProto[n.Channel],
+		Priority:    prioritiesToProto[n.Priority],
+		Recipient:   n.Recipient(),
+		Subject:     n.Subject,
+		Attempts:    int32(n.Attempts),
+		Version:     int32(n.Version),
+		ScheduledAt: timestamppb.New(n.ScheduledAt),
+		CreatedAt:   timestamppb.New(n.CreatedAt),
+		CallbackUrl: n.CallbackURL,
+		Timezone:    n.Timezone,
+		Tags:        n.Tags,
+	}
+	if n.SentAt != nil {
+		pb.SentAt = timestamppb.New(*n.SentAt)
+	}
+	// Metadata was decoded from JSON, which structpb always accepts.
+	if metadata, err := structpb.NewStruct(n.Metadata); err == nil && len(n.Metadata) > 0 {
+		pb.Metadata = metadata
+	}
+	if n.Timezone != nil {
+		pb.LocalTime = n.LocalScheduledAt().Format(localTimeLayout)
+	}
+	if w := n.DeliveryWindow; w != nil {
+		pb.DeliveryWindow = &notifierv1.DeliveryWindow{
+			Timezone:   w.Timezone,
+			Hours:      toProtoClockRange(w.Hours),
+			QuietHours: toProtoClockRange(w.QuietHours),
+		}
+		for _, d := range w.Days {
+			pb.DeliveryWindow.Days = append(pb.DeliveryWindow.Days, model.WeekdayName(d))
+		}
+	}
+	return pb
+}
+
+func toProtoClockRange(r *model.ClockRange) *notifierv1.ClockRange {
+	if r == nil {
+		return nil
+	}
+	return &notifierv1.ClockRange{Start: r.Start.String(), End: r.End.String()}
+}
+
+// toProtoStatusEvent maps a status event to its protobuf representation.
+func toProtoStatusEvent(e *model.StatusEvent) *notifierv1.StatusEvent {
+	return &notifierv1.StatusEvent{
+		Id:             e.ID,
+		NotificationId: e.NotificationID.String(),
+		Status:         statusesToProto[e.Status],
+		Attempts:       int32(e.Attempts),
+		OccurredAt:     timestamppb.New(e.OccurredAt),
+	}
+}
+
+// fromProtoFilter converts a bulk job filter to a list filter. Unset fields match everything.
+func fromProtoFilter(f *notifierv1.NotificationFilter) model.NotificationFilter {
+	if f == nil {
+		return model.NotificationFilter{}
+	}
+	filter := model.NotificationFilter{
+		AuthorID: f.AuthorId,
+		Search:   f.Search,
+		Tags:     f.GetTags(),
+		Metadata: f.GetMetadata().AsMap(),
+	}
+	if f.GetStatus() != notifierv1.Status_STATUS_UNSPECIFIED {
+		s := fromProtoStatus(f.GetStatus())
+		filter.Status = &s
+	}
+	if f.GetChannel() != notifierv1.Channel_CHANNEL_UNSPECIFIED {
+		c := fromProtoChannel(f.GetChannel())
+		filter.Channel = &c
+	}
+	if f.GetScheduledFrom() != nil {
+		from := f.GetScheduledFrom().AsTime()
+		filter.ScheduledFrom = &from
+	}
+	if f.GetScheduledTo() != nil {
+		to := f.GetScheduledTo().AsTime()
+		filter.ScheduledTo = &to
+	}
+	return filter
+}
+
+// toProtoBulkJob maps a bulk job to its protobuf representation.
+func toProtoBulkJob(j *model.BulkJob) *notifierv1.BulkJob {
+	pb := &notifierv1.BulkJob{
+		Id:        j.ID.String(),
+		Action:    bulkActionsToProto[j.Action],
+		Status:    bulkJobStatusesToProto[j.Status],
+		Timezone:  j.Timezone,
+		Total:     int32(j.Total),
+		Processed: int32(j.Processed),
+		Failed:    int32(j.Failed),
+		Error:     j.Error,
+		CreatedAt: timestamppb.New(j.CreatedAt),
+		UpdatedAt: timestamppb.New(j.UpdatedAt),
+	}
+	if j.ScheduledAt != nil {
+		pb.ScheduledAt = timestamppb.New(*j.ScheduledAt)
+	}
+	if j.FinishedAt != nil {
+		pb.FinishedAt = timestamppb.New(*j.FinishedAt)
+	}
+	return pb
+}
@@ -0,0 +1,68 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"github.com/ilindan-dev/delayed-notifier/internal/config"
+	"github.com/ilindan-dev/delayed-notifier/internal/service"
+	notifierv1 "github.com/ilindan-dev/delayed-notifier/pkg/api/notifier/v1"
+	"github.com/rs/zerolog"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+	"net"
+)
+
+// Server is a wrapper for the gRPC server.
+type Server struct {
+	*grpc.Server
+	addr   string
+	logger zerolog.Logger
+}
+
+// NewServer creates and configures a new gRPC server exposing the notification service.
+func NewServer(cfg *config.Config, handlers *Handlers, authenticator *service.Authenticator, logger *zerolog.Logger) *Server {
+	log := logger.With().Str("layer", "grpc_server").Logger()
+	log.Info().Msg("initializing grpc server")
+
+	interceptor := newAuthInterceptor(authenticator, log)
+	server := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(recoveryUnaryInterceptor(log), interceptor.unary),
+		grpc.ChainStreamInterceptor(recoveryStreamInterceptor(log), interceptor.stream),
+	)
+
+	notifierv1.RegisterNotificationServiceServer(server, handlers)
+	healthpb.RegisterHealthServer(server, health.NewServer())
+	reflection.Register(server)
+
+	return &Server{Server: server, addr: cfg.GRPC.Port, logger: log}
+}
+
+// ListenAndServe listens on the configured address and serves until the server is stopped.
+func (s *Server) ListenAndServe() error {
+	listener, err := net.Listen("tcp", s.addr)
+	if err != nil {
+		return fmt.Errorf("grpc: failed to listen on %s: %w", s.addr, err)
+	}
+	s.logger.Info().Str("addr", s.addr).Msg("grpc server listening")
+	return s.Serve(listener)
+}
+
+// Shutdown stops accepting new RPCs and waits for running ones to finish.
+// Once ctx expires, remaining RPCs, such as open status streams, are cancelled.
+func (s *Server) Shutdown(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		s.GracefulStop()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		s.Stop()
+		return ctx.Err()
+	}
+}
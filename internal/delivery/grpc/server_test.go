@@ -0,0 +1,511 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/ilindan-dev/delayed-notifier/internal/config"
+	"github.com/ilindan-dev/delayed-notifier/internal/domain"
+	"github.com/ilindan-dev/delayed-notifier/internal/domain/model"
+	repo "github.com/ilindan-dev/delayed-notifier/internal/domain/repository"
+	"github.com/ilindan-dev/delayed-notifier/internal/service"
+	notifierv1 "github.com/ilindan-dev/delayed-notifier/pkg/api/notifier/v1"
+	"github.com/rs/zerolog"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// panicID is a notification ID whose lookup panics, to exercise the recovery interceptors.
+var panicID = uuid.MustParse("00000000-0000-0000-0000-00000000dead")
+
+// fakeNotificationRepo stores notifications in memory. Only the methods the API uses are implemented.
+type fakeNotificationRepo struct {
+	repo.NotificationRepository
+	mu    sync.Mutex
+	items map[uuid.UUID]*model.Notification
+}
+
+func (r *fakeNotificationRepo) Save(_ context.Context, n *model.Notification) (*model.Notification, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	saved := *n
+	saved.Version = 1
+	r.items[saved.ID] = &saved
+	result := saved
+	return &result, nil
+}
+
+func (r *fakeNotificationRepo) GetByID(_ context.Context, tenantID string, id uuid.UUID) (*model.Notification, error) {
+	if id == panicID {
+		panic("lookup failed")
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	n, ok := r.items[id]
+	if !ok || n.TenantID != tenantID {
+		return nil, repo.ErrNotFound
+	}
+	result := *n
+	return &result, nil
+}
+
+func (r *fakeNotificationRepo) Delete(_ context.Context, tenantID string, id uuid.UUID, version *int) (*model.Notification, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	n, ok := r.items[id]
+	if !ok || n.TenantID != tenantID || n.Status != model.StatusScheduled || (version != nil && n.Version != *version) {
+		return nil, repo.ErrNotFound
+	}
+	n.Status = model.StatusCancelled
+	n.Version++
+	result := *n
+	return &result, nil
+}
+
+type fakeQueue struct{ repo.NotificationQueue }
+
+func (fakeQueue) Publish(context.Context, *model.Notification) error { return nil }
+
+// fakeEventStream keeps one stream of status events per tenant in memory, with
+// sequential IDs such as "3-0".
+type fakeEventStream struct {
+	mu      sync.Mutex
+	streams map[string][]*model.StatusEvent
+	last    int
+}
+
+func newFakeEventStream() *fakeEventStream {
+	return &fakeEventStream{streams: make(map[string][]*model.StatusEvent)}
+}
+
+func (s *fakeEventStream) Publish(_ context.Context, e *model.StatusEvent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.last++
+	e.ID = fmt.Sprintf("%d-0", s.last)
+	stored := *e
+	s.streams[e.TenantID] = append(s.streams[e.TenantID], &stored)
+	return nil
+}
+
+func (s *fakeEventStream) LatestID(_ context.Context, tenantID string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if events := s.streams[tenantID]; len(events) > 0 {
+		return events[len(events)-1].ID, nil
+	}
+	return "0-0", nil
+}
+
+func (s *fakeEventStream) ReadAfter(ctx context.Context, tenantID, afterID string, block time.Duration) ([]*model.StatusEvent, error) {
+	seq, ok := strings.CutSuffix(afterID, "-0")
+	after, err := strconv.Atoi(seq)
+	if !ok || err != nil {
+		return nil, repo.ErrInvalidEventID
+	}
+	deadline := time.Now().Add(block)
+	for {
+		var events []*model.StatusEvent
+		s.mu.Lock()
+		for _, e := range s.streams[tenantID] {
+			if n, _ := strconv.Atoi(strings.TrimSuffix(e.ID, "-0")); n > after {
+				copied := *e
+				events = append(events, &copied)
+			}
+		}
+		s.mu.Unlock()
+		if len(events) > 0 || block <= 0 || time.Now().After(deadline) {
+			return events, nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(5 * time.Millisecond):
+		}
+	}
+}
+
+// fakeAPIKeyRepo stores API keys in memory.
+type fakeAPIKeyRepo struct {
+	repo.APIKeyRepository
+	mu   sync.Mutex
+	keys []*model.APIKey
+}
+
+func (r *fakeAPIKeyRepo) Save(_ context.Context, key *model.APIKey) (*model.APIKey, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	saved := *key
+	saved.ID = uuid.New()
+	r.keys = append(r.keys, &saved)
+	result := saved
+	return &result, nil
+}
+
+func (r *fakeAPIKeyRepo) GetActiveByHash(_ context.Context, hash string) (*model.APIKey, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, k := range r.keys {
+		if k.Hash == hash {
+			result := *k
+			return &result, nil
+		}
+	}
+	return nil, repo.ErrNotFound
+}
+
+func (r *fakeAPIKeyRepo) Touch(context.Context, uuid.UUID) error { return nil }
+
+// testServer is the API served over an in-memory connection.
+type testServer struct {
+	client notifierv1.NotificationServiceClient
+	conn   *grpc.ClientConn
+	events *fakeEventStream
+	keys   *service.APIKeyService
+}
+
+func newTestServer(t *testing.T) *testServer {
+	t.Helper()
+	logger := zerolog.Nop()
+	cfg := &config.Config{
+		Auth:   config.AuthConfig{Mode: "api_key"},
+		Events: config.EventsConfig{Heartbeat: 20 * time.Millisecond},
+	}
+	events := newFakeEventStream()
+	notifications := &fakeNotificationRepo{items: make(map[uuid.UUID]*model.Notification)}
+	svc := service.NewNotificationService(cfg, notifications, fakeQueue{}, nil, nil, nil, events, nil, nil, &logger)
+	keys := service.NewAPIKeyService(cfg, &fakeAPIKeyRepo{}, &logger)
+	server := NewServer(cfg, NewHandlers(cfg, svc, &logger), service.NewAuthenticator(cfg, keys, nil, &logger), &logger)
+
+	listener := bufconn.Listen(1 << 20)
+	go func() { _ = server.Serve(listener) }()
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+	return &testServer{client: notifierv1.NewNotificationServiceClient(conn), conn: conn, events: events, keys: keys}
+}
+
+// withKey returns a context authenticating calls with a new API key of the default tenant.
+func (s *testServer) withKey(t *testing.T, scopes ...model.Scope) context.Context {
+	t.Helper()
+	_, raw, err := s.keys.CreateAPIKey(context.Background(), model.DefaultTenant, "test", "test-service", scopes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return metadata.AppendToOutgoingContext(context.Background(), apiKeyMetadata, raw)
+}
+
+// wantStatus reports an error unless err has the code and carries reason in its ErrorInfo.
+func wantStatus(t *testing.T, err error, code codes.Code, reason domain.Code) {
+	t.Helper()
+	st := status.Convert(err)
+	if st.Code() != code {
+		t.Fatalf("got %v, want code %s", err, code)
+	}
+	if got := errorReason(st); got != string(reason) {
+		t.Errorf("got reason %q, want %q", got, reason)
+	}
+}
+
+func newCreateRequest(recipient string) *notifierv1.CreateNotificationRequest {
+	return &notifierv1.CreateNotificationRequest{
+		Recipient: recipient,
+		Channel:   notifierv1.Channel_CHANNEL_EMAIL,
+		Subject:   "Hello",
+		Delay:     "PT1H",
+	}
+}
+
+func TestAuthInterceptor(t *testing.T) {
+	s := newTestServer(t)
+	invalidKey := metadata.AppendToOutgoingContext(context.Background(), apiKeyMetadata, "dn_not_a_key")
+	invalidBearer := metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer dn_not_a_key")
+	readOnly := s.withKey(t, model.ScopeRead)
+	creator := s.withKey(t, model.ScopeCreate)
+
+	tests := []struct {
+		name       string
+		ctx        context.Context
+		wantCode   codes.Code
+		wantReason domain.Code
+	}{
+		{name: "missing key", ctx: context.Background(), wantCode: codes.Unauthenticated, wantReason: domain.CodeUnauthenticated},
+		{name: "invalid key", ctx: invalidKey, wantCode: codes.Unauthenticated, wantReason: domain.CodeUnauthenticated},
+		{name: "invalid bearer token", ctx: invalidBearer, wantCode: codes.Unauthenticated, wantReason: domain.CodeUnauthenticated},
+		{name: "missing scope", ctx: readOnly, wantCode: codes.PermissionDenied, wantReason: domain.CodeMissingScope},
+		{name: "allowed", ctx: creator, wantCode: codes.OK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := s.client.CreateNotification(tt.ctx, newCreateRequest("user@example.com"))
+			if tt.wantCode == codes.OK {
+				if err != nil {
+					t.Fatalf("got %v, want no error", err)
+				}
+				return
+			}
+			wantStatus(t, err, tt.wantCode, tt.wantReason)
+		})
+	}
+
+	t.Run("streams", func(t *testing.T) {
+		stream, err := s.client.WatchStatus(context.Background(), &notifierv1.WatchStatusRequest{})
+		if err == nil {
+			_, err = stream.Recv()
+		}
+		wantStatus(t, err, codes.Unauthenticated, domain.CodeUnauthenticated)
+	})
+
+	t.Run("unprotected methods", func(t *testing.T) {
+		resp, err := healthpb.NewHealthClient(s.conn).Check(context.Background(), &healthpb.HealthCheckRequest{})
+		if err != nil || resp.GetStatus() != healthpb.HealthCheckResponse_SERVING {
+			t.Errorf("got %v, %v, want the health check to be served without a key", resp, err)
+		}
+	})
+}
+
+func TestRecovery(t *testing.T) {
+	s := newTestServer(t)
+	ctx := s.withKey(t, model.ScopeAdmin)
+
+	_, err := s.client.GetNotification(ctx, &notifierv1.GetNotificationRequest{Id: panicID.String()})
+	if status.Code(err) != codes.Internal {
+		t.Errorf("unary: got %v, want code %s", err, codes.Internal)
+	}
+
+	id := panicID.String()
+	stream, err := s.client.WatchStatus(ctx, &notifierv1.WatchStatusRequest{NotificationId: &id})
+	if err == nil {
+		_, err = stream.Recv()
+	}
+	if status.Code(err) != codes.Internal {
+		t.Errorf("stream: got %v, want code %s", err, codes.Internal)
+	}
+
+	// The server keeps serving after a panic.
+	if _, err := s.client.CreateNotification(ctx, newCreateRequest("user@example.com")); err != nil {
+		t.Errorf("got %v after recovering, want no error", err)
+	}
+}
+
+func TestErrorCodes(t *testing.T) {
+	s := newTestServer(t)
+	ctx := s.withKey(t, model.ScopeAdmin)
+
+	created, err := s.client.CreateNotification(ctx, newCreateRequest("user@example.com"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.client.CancelNotification(ctx, &notifierv1.CancelNotificationRequest{Id: created.GetId()}); err != nil {
+		t.Fatal(err)
+	}
+
+	stale := int32(1)
+	tests := []struct {
+		name       string
+		call       func() error
+		wantCode   codes.Code
+		wantReason domain.Code
+	}{
+		{
+			name: "malformed ID",
+			call: func() error {
+				_, err := s.client.GetNotification(ctx, &notifierv1.GetNotificationRequest{Id: "42"})
+				return err
+			},
+			wantCode:   codes.InvalidArgument,
+			wantReason: domain.CodeInvalidID,
+		},
+		{
+			name: "not found",
+			call: func() error {
+				_, err := s.client.GetNotification(ctx, &notifierv1.GetNotificationRequest{Id: uuid.NewString()})
+				return err
+			},
+			wantCode:   codes.NotFound,
+			wantReason: domain.CodeNotFound,
+		},
+		{
+			name: "invalid notification",
+			call: func() error {
+				_, err := s.client.CreateNotification(ctx, newCreateRequest("not-an-email"))
+				return err
+			},
+			wantCode:   codes.InvalidArgument,
+			wantReason: domain.CodeInvalidRecipient,
+		},
+		{
+			name: "not cancellable",
+			call: func() error {
+				_, err := s.client.CancelNotification(ctx, &notifierv1.CancelNotificationRequest{Id: created.GetId()})
+				return err
+			},
+			wantCode:   codes.FailedPrecondition,
+			wantReason: domain.CodeNotCancellable,
+		},
+		{
+			name: "stale version",
+			call: func() error {
+				_, err := s.client.CancelNotification(ctx, &notifierv1.CancelNotificationRequest{Id: created.GetId(), Version: &stale})
+				return err
+			},
+			wantCode:   codes.Aborted,
+			wantReason: domain.CodeVersionConflict,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wantStatus(t, tt.call(), tt.wantCode, tt.wantReason)
+		})
+	}
+}
+
+func TestToStatus(t *testing.T) {
+	logger := zerolog.Nop()
+	h := NewHandlers(&config.Config{}, nil, &logger)
+	invalid := &domain.ValidationError{}
+	invalid.Add(&domain.Error{Code: domain.CodeInvalidRecipient, Field: "recipient", Message: "invalid email"})
+	invalid.Add(&domain.Error{Code: domain.CodeTooLong, Field: "subject", Message: "subject is too long"})
+
+	tests := []struct {
+		name       string
+		err        error
+		wantCode   codes.Code
+		wantReason domain.Code
+	}{
+		{name: "validation", err: invalid, wantCode: codes.InvalidArgument, wantReason: domain.CodeValidationFailed},
+		{name: "wrapped domain error", err: fmt.Errorf("saving: %w", domain.ErrQuotaExceeded), wantCode: codes.ResourceExhausted, wantReason: domain.CodeQuotaExceeded},
+		{name: "foreign tenant", err: service.ErrForeignTenant, wantCode: codes.PermissionDenied, wantReason: domain.CodeForeignTenant},
+		{name: "duplicate", err: repo.ErrDuplicateRecord, wantCode: codes.AlreadyExists, wantReason: domain.CodeDuplicate},
+		{name: "cancelled", err: context.Canceled, wantCode: codes.Canceled},
+		{name: "deadline", err: fmt.Errorf("query: %w", context.DeadlineExceeded), wantCode: codes.DeadlineExceeded},
+		{name: "unexpected", err: errors.New("connection refused"), wantCode: codes.Internal, wantReason: domain.CodeInternal},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wantStatus(t, h.toStatus(tt.err, "failed"), tt.wantCode, tt.wantReason)
+		})
+	}
+
+	t.Run("validation fields", func(t *testing.T) {
+		var fields []string
+		for _, detail := range status.Convert(h.toStatus(invalid, "failed")).Details() {
+			if badRequest, ok := detail.(*errdetails.BadRequest); ok {
+				for _, v := range badRequest.GetFieldViolations() {
+					fields = append(fields, v.GetField()+":"+v.GetReason())
+				}
+			}
+		}
+		if want := "recipient:invalid_recipient subject:too_long"; strings.Join(fields, " ") != want {
+			t.Errorf("got field violations %v, want %s", fields, want)
+		}
+	})
+
+	// The unexpected error's text must not leak to clients.
+	if msg := status.Convert(h.toStatus(errors.New("connection refused"), "failed")).Message(); msg != "failed" {
+		t.Errorf("got message %q, want %q", msg, "failed")
+	}
+}
+
+func TestBatchCreateNotifications(t *testing.T) {
+	s := newTestServer(t)
+	ctx := s.withKey(t, model.ScopeCreate, model.ScopeRead)
+
+	resp, err := s.client.BatchCreateNotifications(ctx, &notifierv1.BatchCreateNotificationsRequest{
+		Notifications: []*notifierv1.CreateNotificationRequest{
+			newCreateRequest("first@example.com"),
+			{Recipient: "user@example.com", Subject: "Hello", Delay: "PT1H"}, // Without a channel.
+			newCreateRequest("not-an-email"),
+			newCreateRequest("last@example.com"),
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	results := resp.GetResults()
+	if len(results) != 4 {
+		t.Fatalf("got %d results, want 4", len(results))
+	}
+	for i, recipient := range map[int]string{0: "first@example.com", 3: "last@example.com"} {
+		n := results[i].GetNotification()
+		if n.GetRecipient() != recipient {
+			t.Errorf("result %d: got %v, want the notification to %s", i, results[i], recipient)
+			continue
+		}
+		if _, err := s.client.GetNotification(ctx, &notifierv1.GetNotificationRequest{Id: n.GetId()}); err != nil {
+			t.Errorf("result %d: got %v, want it to be stored", i, err)
+		}
+	}
+	// Requests the handler rejects and those the service rejects are both reported in place.
+	for i, reason := range map[int]domain.Code{1: domain.CodeValidationFailed, 2: domain.CodeInvalidRecipient} {
+		batchErr := results[i].GetError()
+		if batchErr == nil || codes.Code(batchErr.GetCode()) != codes.InvalidArgument || batchErr.GetReason() != string(reason) {
+			t.Errorf("result %d: got %v, want an invalid argument error with reason %s", i, results[i], reason)
+		}
+	}
+
+	_, err = s.client.BatchCreateNotifications(ctx, &notifierv1.BatchCreateNotificationsRequest{})
+	wantStatus(t, err, codes.InvalidArgument, domain.CodeValidationFailed)
+}
+
+func TestWatchStatusResume(t *testing.T) {
+	s := newTestServer(t)
+	ctx, cancel := context.WithTimeout(s.withKey(t, model.ScopeAdmin), 5*time.Second)
+	defer cancel()
+
+	publish := func(tenantID string) string {
+		t.Helper()
+		e := &model.StatusEvent{NotificationID: uuid.New(), TenantID: tenantID, Status: model.StatusSent, OccurredAt: time.Now().UTC()}
+		if err := s.events.Publish(context.Background(), e); err != nil {
+			t.Fatal(err)
+		}
+		return e.ID
+	}
+	first := publish(model.DefaultTenant)
+	second := publish(model.DefaultTenant)
+	publish("acme")
+	third := publish(model.DefaultTenant)
+
+	stream, err := s.client.WatchStatus(ctx, &notifierv1.WatchStatusRequest{LastEventId: first})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Events of other tenants are skipped, and events published later follow.
+	fourth := publish(model.DefaultTenant)
+	for _, want := range []string{second, third, fourth} {
+		e, err := stream.Recv()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if e.GetId() != want {
+			t.Errorf("got event %s, want %s", e.GetId(), want)
+		}
+	}
+
+	invalid, err := s.client.WatchStatus(ctx, &notifierv1.WatchStatusRequest{LastEventId: "yesterday"})
+	if err == nil {
+		_, err = invalid.Recv()
+	}
+	wantStatus(t, err, codes.InvalidArgument, domain.CodeInvalidEventID)
+}
@@ -0,0 +1,48 @@
+package http
+
+import (
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+// ListQueues handles the HTTP request for the depths of the broker queues.
+func (h *Handlers) ListQueues(c *gin.Context) {
+	stats, err := h.queues.QueueStats(c.Request.Context())
+	if err != nil {
+		h.fail(c, err, "failed to inspect queues")
+		return
+	}
+
+	resp := make([]QueueStatsResponse, 0, len(stats))
+	for _, s := range stats {
+		resp = append(resp, QueueStatsResponse{Name: s.Name, Messages: s.Messages, Consumers: s.Consumers})
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// ListDeadLetters handles the HTTP request for the oldest messages of the dead letter queue.
+// The messages stay in the queue.
+func (h *Handlers) ListDeadLetters(c *gin.Context) {
+	var query ListDeadLettersQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		writeProblem(c, bindingProblem(err))
+		return
+	}
+
+	letters, err := h.queues.DeadLetters(c.Request.Context(), query.Limit)
+	if err != nil {
+		h.fail(c, err, "failed to list dead letters")
+		return
+	}
+
+	resp := make([]DeadLetterResponse, 0, len(letters))
+	for _, l := range letters {
+		resp = append(resp, DeadLetterResponse{
+			MessageID: l.MessageID,
+			Reason:    l.Reason,
+			DeadAt:    l.DeadAt,
+			Body:      string(l.Body),
+		})
+	}
+	c.JSON(http.StatusOK, resp)
+}
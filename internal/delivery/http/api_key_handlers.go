@@ -0,0 +1,85 @@
+package http
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/ilindan-dev/delayed-notifier/internal/domain"
+	"github.com/ilindan-dev/delayed-notifier/internal/domain/model"
+	"net/http"
+)
+
+// CreateAPIKey handles the HTTP request for issuing a new API key.
+func (h *Handlers) CreateAPIKey(c *gin.Context) {
+	var req CreateAPIKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeProblem(c, bindingProblem(err))
+		return
+	}
+
+	scopes := make([]model.Scope, len(req.Scopes))
+	for i, s := range req.Scopes {
+		scopes[i] = model.Scope(s)
+		if !model.ValidScope(scopes[i]) {
+			h.fail(c, &domain.Error{Code: domain.CodeValidationFailed, Field: "scopes", Message: "unknown scope: " + s}, "")
+			return
+		}
+	}
+
+	key, raw, err := h.keys.CreateAPIKey(c.Request.Context(), req.TenantID, req.Name, req.OwnerID, scopes)
+	if err != nil {
+		h.fail(c, err, "failed to create api key")
+		return
+	}
+
+	c.JSON(http.StatusCreated, CreateAPIKeyResponse{APIKeyResponse: toAPIKeyResponse(key), Key: raw})
+}
+
+// ListAPIKeys handles the HTTP request for listing all API keys.
+func (h *Handlers) ListAPIKeys(c *gin.Context) {
+	keys, err := h.keys.ListAPIKeys(c.Request.Context())
+	if err != nil {
+		h.fail(c, err, "failed to list api keys")
+		return
+	}
+
+	resp := make([]APIKeyResponse, 0, len(keys))
+	for _, k := range keys {
+		resp = append(resp, toAPIKeyResponse(k))
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// RevokeAPIKey handles the HTTP request for revoking an API key.
+func (h *Handlers) RevokeAPIKey(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		h.fail(c, errInvalidAPIKeyID, "")
+		return
+	}
+
+	if err := h.keys.RevokeAPIKey(c.Request.Context(), id); err != nil {
+		h.fail(c, err, "failed to revoke api key")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// toAPIKeyResponse is a helper function to map the domain model to the DTO.
+func toAPIKeyResponse(k *model.APIKey) APIKeyResponse {
+	scopes := make([]string, len(k.Scopes))
+	for i, s := range k.Scopes {
+		scopes[i] = string(s)
+	}
+	return APIKeyResponse{
+		ID:         k.ID,
+		TenantID:   k.TenantID,
+		Name:       k.Name,
+		Prefix:     k.Prefix,
+		OwnerID:    k.OwnerID,
+		Scopes:     scopes,
+		CreatedAt:  k.CreatedAt,
+		LastUsedAt: k.LastUsedAt,
+		RevokedAt:  k.RevokedAt,
+	}
+}
@@ -0,0 +1,82 @@
+package http
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/ilindan-dev/delayed-notifier/internal/auth"
+	"github.com/ilindan-dev/delayed-notifier/internal/domain"
+	"github.com/ilindan-dev/delayed-notifier/internal/domain/model"
+	"github.com/ilindan-dev/delayed-notifier/internal/service"
+	"github.com/rs/zerolog"
+	"net/http"
+	"strings"
+)
+
+const apiKeyHeader = "X-API-Key"
+
+// AuthMiddleware authenticates API callers and enforces scopes.
+// Depending on the configured mode, callers present an API key, a bearer JWT, or either.
+type AuthMiddleware struct {
+	authenticator *service.Authenticator
+	logger        zerolog.Logger
+}
+
+// NewAuthMiddleware creates a new instance of AuthMiddleware.
+func NewAuthMiddleware(authenticator *service.Authenticator, logger *zerolog.Logger) *AuthMiddleware {
+	return &AuthMiddleware{
+		authenticator: authenticator,
+		logger:        logger.With().Str("layer", "http_auth").Logger(),
+	}
+}
+
+// Authenticate resolves the caller's credentials to a principal and stores it
+// in the request context. Requests without valid credentials are rejected.
+func (m *AuthMiddleware) Authenticate() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !m.authenticator.Enabled() {
+			c.Next()
+			return
+		}
+
+		principal, err := m.authenticator.Authenticate(c.Request.Context(), c.GetHeader(apiKeyHeader), bearerToken(c.Request))
+		if err != nil {
+			if service.IsUnauthenticated(err) {
+				c.Header("WWW-Authenticate", "Bearer")
+				writeProblem(c, newProblem(http.StatusUnauthorized, domain.CodeUnauthenticated, service.UnauthenticatedMessage(err)))
+				return
+			}
+			m.logger.Error().Err(err).Msg("failed to authenticate request")
+			writeProblem(c, newProblem(http.StatusInternalServerError, domain.CodeInternal, "failed to authenticate"))
+			return
+		}
+
+		c.Request = c.Request.WithContext(auth.WithPrincipal(c.Request.Context(), principal))
+		c.Next()
+	}
+}
+
+// RequireScope rejects requests whose principal lacks the given scope.
+// It must run after Authenticate. With authentication disabled it lets everything through.
+func (m *AuthMiddleware) RequireScope(scope model.Scope) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !m.authenticator.Enabled() {
+			c.Next()
+			return
+		}
+
+		principal, ok := auth.PrincipalFromContext(c.Request.Context())
+		if !ok || !principal.HasScope(scope) {
+			writeProblem(c, newProblem(http.StatusForbidden, domain.CodeMissingScope, "missing scope: "+string(scope)))
+			return
+		}
+		c.Next()
+	}
+}
+
+// bearerToken reads the token from a bearer Authorization header.
+func bearerToken(r *http.Request) string {
+	header := r.Header.Get("Authorization")
+	if token, ok := strings.CutPrefix(header, "Bearer "); ok {
+		return strings.TrimSpace(token)
+	}
+	return ""
+}
@@ -0,0 +1,118 @@
+package http
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/ilindan-dev/delayed-notifier/internal/domain"
+	"github.com/ilindan-dev/delayed-notifier/internal/domain/model"
+	"github.com/ilindan-dev/delayed-notifier/internal/service"
+	"net/http"
+)
+
+var errInvalidJobID = &domain.Error{Code: domain.CodeInvalidID, Field: "id", Message: "invalid job ID format"}
+
+// customMethods routes the custom methods of a collection, such as POST /notifications:cancel,
+// to their handler chains. Gin takes the colon for the start of a path parameter, so all
+// methods share one route and arrive as its "method" parameter, colon included.
+func customMethods(methods map[string]gin.HandlersChain) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		chain, ok := methods[c.Param("method")]
+		if !ok {
+			writeProblem(c, newProblem(http.StatusNotFound, domain.CodeNotFound, "unknown method"))
+			return
+		}
+		for _, handler := range chain {
+			if handler(c); c.IsAborted() {
+				return
+			}
+		}
+	}
+}
+
+// BulkCancelNotifications handles the HTTP request to cancel every scheduled notification
+// matching a filter. The notifications are cancelled by a background job.
+func (h *Handlers) BulkCancelNotifications(c *gin.Context) {
+	var req BulkCancelRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeProblem(c, bindingProblem(err))
+		return
+	}
+
+	h.startBulkJob(c, service.BulkJobInput{
+		Action: model.BulkActionCancel,
+		Filter: req.Filter.toFilter(),
+		DryRun: req.DryRun,
+	})
+}
+
+// BulkRescheduleNotifications handles the HTTP request to move every scheduled notification
+// matching a filter to a new time. The notifications are rescheduled by a background job.
+func (h *Handlers) BulkRescheduleNotifications(c *gin.Context) {
+	var req BulkRescheduleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeProblem(c, bindingProblem(err))
+		return
+	}
+
+	h.startBulkJob(c, service.BulkJobInput{
+		Action: model.BulkActionReschedule,
+		Filter: req.Filter.toFilter(),
+		Reschedule: service.RescheduleInput{
+			ScheduledAt: req.ScheduledAt,
+			LocalTime:   req.LocalTime,
+			Timezone:    req.Timezone,
+		},
+		DryRun: req.DryRun,
+	})
+}
+
+// startBulkJob starts the job and responds with it, or with its count for a dry run.
+func (h *Handlers) startBulkJob(c *gin.Context, in service.BulkJobInput) {
+	job, err := h.service.StartBulkJob(c.Request.Context(), in)
+	if err != nil {
+		h.fail(c, err, "failed to start bulk job")
+		return
+	}
+
+	if in.DryRun {
+		c.JSON(http.StatusOK, BulkDryRunResponse{Action: string(job.Action), Matched: job.Total})
+		return
+	}
+	c.Header("Location", "/api/v1/jobs/"+job.ID.String())
+	c.JSON(http.StatusAccepted, toBulkJobResponse(job))
+}
+
+// GetBulkJob handles the HTTP request to follow the progress of a bulk job.
+func (h *Handlers) GetBulkJob(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		h.fail(c, errInvalidJobID, "")
+		return
+	}
+
+	job, err := h.service.GetBulkJob(c.Request.Context(), id)
+	if err != nil {
+		h.fail(c, err, "failed to retrieve bulk job")
+		return
+	}
+
+	c.JSON(http.StatusOK, toBulkJobResponse(job))
+}
+
+// toBulkJobResponse converts a domain bulk job to its API representation.
+func toBulkJobResponse(j *model.BulkJob) BulkJobResponse {
+	return BulkJobResponse{
+		ID:          j.ID,
+		Action:      string(j.Action),
+		Status:      string(j.Status),
+		ScheduledAt: j.ScheduledAt,
+		Timezone:    j.Timezone,
+		Total:       j.Total,
+		Processed:   j.Processed,
+		Failed:      j.Failed,
+		Error:       j.Error,
+		CreatedAt:   j.CreatedAt,
+		UpdatedAt:   j.UpdatedAt,
+		FinishedAt:  j.FinishedAt,
+	}
+}
@@ -0,0 +1,66 @@
+package http
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/ilindan-dev/delayed-notifier/internal/domain/model"
+	"net/http"
+)
+
+// GetRecipientWindow handles the HTTP request for the delivery window of a recipient.
+func (h *Handlers) GetRecipientWindow(c *gin.Context) {
+	window, err := h.service.GetRecipientWindow(c.Request.Context(), model.Channel(c.Param("channel")), c.Param("recipient"))
+	if err != nil {
+		h.fail(c, err, "failed to get recipient delivery window")
+		return
+	}
+
+	c.JSON(http.StatusOK, toDeliveryWindow(window))
+}
+
+// SetRecipientWindow handles the HTTP request for setting the delivery window of a recipient,
+// which applies to all their notifications without a window of their own.
+func (h *Handlers) SetRecipientWindow(c *gin.Context) {
+	var req DeliveryWindow
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeProblem(c, bindingProblem(err))
+		return
+	}
+
+	window, err := h.service.SetRecipientWindow(c.Request.Context(), model.Channel(c.Param("channel")), c.Param("recipient"), req.toInput())
+	if err != nil {
+		h.fail(c, err, "failed to set recipient delivery window")
+		return
+	}
+
+	c.JSON(http.StatusOK, toDeliveryWindow(window))
+}
+
+// DeleteRecipientWindow handles the HTTP request for removing the delivery window of a recipient.
+func (h *Handlers) DeleteRecipientWindow(c *gin.Context) {
+	if err := h.service.DeleteRecipientWindow(c.Request.Context(), model.Channel(c.Param("channel")), c.Param("recipient")); err != nil {
+		h.fail(c, err, "failed to delete recipient delivery window")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// toDeliveryWindow converts a domain delivery window to its API representation.
+func toDeliveryWindow(w *model.DeliveryWindow) DeliveryWindow {
+	resp := DeliveryWindow{
+		Timezone:   w.Timezone,
+		Hours:      toClockRange(w.Hours),
+		QuietHours: toClockRange(w.QuietHours),
+	}
+	for _, d := range w.Days {
+		resp.Days = append(resp.Days, model.WeekdayName(d))
+	}
+	return resp
+}
+
+func toClockRange(r *model.ClockRange) *ClockRange {
+	if r == nil {
+		return nil
+	}
+	return &ClockRange{Start: r.Start.String(), End: r.End.String()}
+}
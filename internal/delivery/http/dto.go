@@ -1,33 +1,382 @@
 package http
 
 import (
+	"encoding/json"
 	"github.com/google/uuid"
+	"github.com/ilindan-dev/delayed-notifier/internal/domain"
+	"github.com/ilindan-dev/delayed-notifier/internal/domain/model"
+	"github.com/ilindan-dev/delayed-notifier/internal/service"
 	"time"
 )
 
 // CreateNotificationRequest defines the structure for a new notification request.
 // It uses `json` tags for unmarshalling and `binding` for validation with Gin.
 type CreateNotificationRequest struct {
-	Recipient   string    `json:"recipient" binding:"required"`
-	Channel     string    `json:"channel" binding:"required"`
-	Subject     string    `json:"subject" binding:"required"`
-	Message     string    `json:"message"`
-	ScheduledAt time.Time `json:"scheduled_at" binding:"required"`
-	AuthorID    *string   `json:"author_id,omitempty"`
+	Recipient string `json:"recipient" binding:"required"`
+	Channel   string `json:"channel" binding:"required"`
+	Subject   string `json:"subject" binding:"required"`
+	Message   string `json:"message"`
+	// ScheduledAt is the absolute send time. Alternatively, LocalTime gives a wall-clock
+	// time in Timezone, Delay an ISO 8601 duration from now, or SendNow asks for immediate
+	// delivery, see service.CreateNotificationInput.
+	ScheduledAt time.Time `json:"scheduled_at"`
+	LocalTime   string    `json:"local_time,omitempty" binding:"omitempty,max=32"`
+	Timezone    string    `json:"timezone,omitempty" binding:"omitempty,max=64"`
+	Delay       string    `json:"delay,omitempty" binding:"omitempty,max=32"`
+	SendNow     bool      `json:"send_now,omitempty"`
+	// Priority is low, normal, high or critical; it defaults to normal.
+	Priority string `json:"priority,omitempty"`
+	// Tags and Metadata correlate the notification with the caller's own entities,
+	// see service.CreateNotificationInput.
+	Tags     []string       `json:"tags,omitempty"`
+	Metadata map[string]any `json:"metadata,omitempty"`
+	// AuthorID is ignored when authentication is enabled; the authenticated principal is used instead.
+	AuthorID *string `json:"author_id,omitempty"`
+	// CallbackURL optionally receives a signed POST after the notification reaches a terminal status.
+	CallbackURL *string `json:"callback_url,omitempty"`
+	// DeliveryWindow optionally restricts when the notification may be delivered.
+	// Its time zone defaults to Timezone.
+	DeliveryWindow *DeliveryWindow `json:"delivery_window,omitempty"`
+}
+
+// toInput converts the request to the service's creation input.
+func (r CreateNotificationRequest) toInput() service.CreateNotificationInput {
+	in := service.CreateNotificationInput{
+		Recipient:   r.Recipient,
+		Channel:     model.Channel(r.Channel),
+		Subject:     r.Subject,
+		Message:     r.Message,
+		ScheduledAt: r.ScheduledAt,
+		LocalTime:   r.LocalTime,
+		Timezone:    r.Timezone,
+		Delay:       r.Delay,
+		SendNow:     r.SendNow,
+		Priority:    model.Priority(r.Priority),
+		Tags:        r.Tags,
+		Metadata:    r.Metadata,
+		AuthorID:    r.AuthorID,
+		CallbackURL: r.CallbackURL,
+	}
+	if r.DeliveryWindow != nil {
+		window := r.DeliveryWindow.toInput()
+		in.DeliveryWindow = &window
+	}
+	return in
+}
+
+// DeliveryWindow restricts when a notification may be delivered, e.g. on weekdays from
+// 09:00 to 18:00, or outside quiet hours from 22:00 to 07:00. Notifications falling
+// outside are deferred to the next opening of the window.
+type DeliveryWindow struct {
+	Timezone   string      `json:"timezone,omitempty" binding:"omitempty,max=64"`
+	Days       []string    `json:"days,omitempty" binding:"omitempty,max=7"`
+	Hours      *ClockRange `json:"hours,omitempty"`
+	QuietHours *ClockRange `json:"quiet_hours,omitempty"`
+}
+
+// ClockRange is a daily range of times of day like "09:00". It wraps past midnight
+// if End is not after Start.
+type ClockRange struct {
+	Start string `json:"start" binding:"required"`
+	End   string `json:"end" binding:"required"`
+}
+
+// toInput converts the window to the service's input.
+func (w DeliveryWindow) toInput() service.DeliveryWindowInput {
+	return service.DeliveryWindowInput{
+		Timezone:   w.Timezone,
+		Days:       w.Days,
+		Hours:      w.Hours.toInput(),
+		QuietHours: w.QuietHours.toInput(),
+	}
+}
+
+func (r *ClockRange) toInput() *service.ClockRangeInput {
+	if r == nil {
+		return nil
+	}
+	return &service.ClockRangeInput{Start: r.Start, End: r.End}
+}
+
+// BatchCreateNotificationsRequest defines the structure for creating several notifications at once.
+type BatchCreateNotificationsRequest struct {
+	Notifications []CreateNotificationRequest `json:"notifications" binding:"required,min=1,dive"`
+}
+
+// BatchItemResponse is the outcome of a single batch item. Status is the HTTP status
+// the item would have received as a single request.
+type BatchItemResponse struct {
+	Status       int                   `json:"status"`
+	Notification *NotificationResponse `json:"notification,omitempty"`
+	Error        *Problem              `json:"error,omitempty"`
+}
+
+// BatchCreateNotificationsResponse lists the outcome of each batch item in request order.
+type BatchCreateNotificationsResponse struct {
+	Results []BatchItemResponse `json:"results"`
+}
+
+// RescheduleNotificationRequest defines the structure for moving a notification to a new time.
+// LocalTime is read in Timezone, which defaults to the notification's time zone.
+type RescheduleNotificationRequest struct {
+	ScheduledAt time.Time `json:"scheduled_at"`
+	LocalTime   string    `json:"local_time,omitempty" binding:"omitempty,max=32"`
+	Timezone    string    `json:"timezone,omitempty" binding:"omitempty,max=64"`
+}
+
+// toInput converts the request to the service's reschedule input.
+func (r RescheduleNotificationRequest) toInput() service.RescheduleInput {
+	return service.RescheduleInput{ScheduledAt: r.ScheduledAt, LocalTime: r.LocalTime, Timezone: r.Timezone}
+}
+
+// ListNotificationsQuery defines the query parameters of the notification list.
+type ListNotificationsQuery struct {
+	Status        string     `form:"status" binding:"omitempty,oneof=scheduled processing sent failed cancelled expired"`
+	Channel       string     `form:"channel" binding:"omitempty,oneof=email telegram"`
+	AuthorID      string     `form:"author_id"`
+	ScheduledFrom *time.Time `form:"scheduled_from" time_format:"2006-01-02T15:04:05Z07:00"`
+	ScheduledTo   *time.Time `form:"scheduled_to" time_format:"2006-01-02T15:04:05Z07:00"`
+	Search        string     `form:"search" binding:"omitempty,max=200"`
+	// Tags matches notifications carrying all of the tags, given as repeated tag parameters.
+	Tags []string `form:"tag" binding:"omitempty,max=20,dive,max=64"`
+	// Metadata is a JSON object that the metadata of matching notifications contains.
+	Metadata  string `form:"metadata" binding:"omitempty,max=1024"`
+	Limit     int    `form:"limit" binding:"omitempty,min=1,max=500"`
+	PageToken string `form:"page_token"`
+}
+
+// toFilter converts the query to a list filter.
+func (q ListNotificationsQuery) toFilter() (model.NotificationFilter, error) {
+	filter := model.NotificationFilter{
+		ScheduledFrom: q.ScheduledFrom,
+		ScheduledTo:   q.ScheduledTo,
+		Tags:          q.Tags,
+	}
+	if q.Metadata != "" {
+		if err := json.Unmarshal([]byte(q.Metadata), &filter.Metadata); err != nil {
+			return model.NotificationFilter{}, domain.ErrInvalidMetadata.Withf("metadata must be a JSON object")
+		}
+	}
+	if q.Status != "" {
+		status := model.Status(q.Status)
+		filter.Status = &status
+	}
+	if q.Channel != "" {
+		channel := model.Channel(q.Channel)
+		filter.Channel = &channel
+	}
+	if q.AuthorID != "" {
+		filter.AuthorID = &q.AuthorID
+	}
+	if q.Search != "" {
+		filter.Search = &q.Search
+	}
+	return filter, nil
+}
+
+// ListNotificationsResponse is a page of notifications. NextPageToken is empty on the last page.
+type ListNotificationsResponse struct {
+	Notifications []NotificationResponse `json:"notifications"`
+	NextPageToken string                 `json:"next_page_token,omitempty"`
 }
 
 // NotificationResponse defines the structure for a standard notification response.
 // We don't expose all internal fields to the client.
 type NotificationResponse struct {
-	ID          uuid.UUID `json:"id"`
-	Status      string    `json:"status"`
-	Channel     string    `json:"channel"`
-	Subject     string    `json:"subject"`
+	ID        uuid.UUID `json:"id"`
+	Status    string    `json:"status"`
+	Channel   string    `json:"channel"`
+	Priority  string    `json:"priority"`
+	Recipient string    `json:"recipient"`
+	Subject   string    `json:"subject"`
+	Attempts  int       `json:"attempts"`
+	// Version is incremented by every change of the notification; it is also sent as the ETag.
+	Version     int       `json:"version"`
 	ScheduledAt time.Time `json:"scheduled_at"`
-	CreatedAt   time.Time `json:"created_at"`
+	// Timezone is the time zone the notification was scheduled in, and LocalTime the
+	// scheduled time on the clocks there. Both are omitted for absolute schedules.
+	Timezone    *string    `json:"timezone,omitempty"`
+	LocalTime   string     `json:"local_time,omitempty"`
+	SentAt      *time.Time `json:"sent_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	CallbackURL *string    `json:"callback_url,omitempty"`
+	// DeliveryWindow is the notification's own window; the recipient's window is not included.
+	DeliveryWindow *DeliveryWindow `json:"delivery_window,omitempty"`
+	Tags           []string        `json:"tags,omitempty"`
+	Metadata       map[string]any  `json:"metadata,omitempty"`
+}
+
+// localTimeLayout formats the LocalTime of a NotificationResponse.
+const localTimeLayout = "2006-01-02T15:04:05"
+
+// CallbackDeliveryResponse describes a single attempt to deliver a status callback.
+type CallbackDeliveryResponse struct {
+	EventID    uuid.UUID `json:"event_id"`
+	Event      string    `json:"event"`
+	URL        string    `json:"url"`
+	Attempt    int       `json:"attempt"`
+	StatusCode *int      `json:"status_code,omitempty"`
+	Error      *string   `json:"error,omitempty"`
+	DurationMs int64     `json:"duration_ms"`
+	Delivered  bool      `json:"delivered"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// StatusEventResponse is the data of a "status" server-sent event.
+type StatusEventResponse struct {
+	ID             string    `json:"id"`
+	NotificationID uuid.UUID `json:"notification_id"`
+	Status         string    `json:"status"`
+	Attempts       int       `json:"attempts"`
+	OccurredAt     time.Time `json:"occurred_at"`
+}
+
+// Problem is an RFC 7807 error response. Code is a stable, machine-readable error code;
+// Errors lists the fields at fault, if the error concerns specific request fields.
+type Problem struct {
+	Type     string       `json:"type"`
+	Title    string       `json:"title"`
+	Status   int          `json:"status"`
+	Detail   string       `json:"detail,omitempty"`
+	Instance string       `json:"instance,omitempty"`
+	Code     domain.Code  `json:"code"`
+	Errors   []FieldError `json:"errors,omitempty"`
+}
+
+// FieldError describes why a request field is invalid. Code is the failed validation
+// rule, e.g. required, or a domain error code, e.g. invalid_recipient.
+type FieldError struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// QueueStatsResponse describes the current state of a broker queue.
+type QueueStatsResponse struct {
+	Name      string `json:"name"`
+	Messages  int    `json:"messages"`
+	Consumers int    `json:"consumers"`
+}
+
+// ListDeadLettersQuery defines the query parameters of the dead letter list.
+type ListDeadLettersQuery struct {
+	Limit int `form:"limit" binding:"omitempty,min=1,max=200"`
+}
+
+// DeadLetterResponse describes a message the worker gave up on.
+// Body is the original message body as text, as it may not be valid JSON.
+type DeadLetterResponse struct {
+	MessageID string    `json:"message_id"`
+	Reason    string    `json:"reason"`
+	DeadAt    time.Time `json:"dead_at"`
+	Body      string    `json:"body"`
+}
+
+// CreateAPIKeyRequest defines the structure for issuing a new API key.
+type CreateAPIKeyRequest struct {
+	// TenantID defaults to the caller's tenant. Only default-tenant admins may set another one.
+	TenantID string   `json:"tenant_id,omitempty"`
+	Name     string   `json:"name" binding:"required"`
+	OwnerID  string   `json:"owner_id" binding:"required"`
+	Scopes   []string `json:"scopes" binding:"required,min=1"`
+}
+
+// APIKeyResponse describes an API key. The raw key is never included.
+type APIKeyResponse struct {
+	ID         uuid.UUID  `json:"id"`
+	TenantID   string     `json:"tenant_id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	OwnerID    string     `json:"owner_id"`
+	Scopes     []string   `json:"scopes"`
+	CreatedAt  time.Time  `json:"created_at"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+}
+
+// CreateAPIKeyResponse is returned once, when a key is created. It is the only
+// time the raw key is revealed.
+type CreateAPIKeyResponse struct {
+	APIKeyResponse
+	Key string `json:"key"`
+}
+
+// BulkFilter selects the notifications of a bulk job, like the list query parameters.
+// Only scheduled notifications are changed, and at least one criterion besides the
+// status is required.
+type BulkFilter struct {
+	Status        string         `json:"status,omitempty" binding:"omitempty,oneof=scheduled processing sent failed cancelled expired"`
+	Channel       string         `json:"channel,omitempty" binding:"omitempty,oneof=email telegram"`
+	AuthorID      string         `json:"author_id,omitempty"`
+	ScheduledFrom *time.Time     `json:"scheduled_from,omitempty"`
+	ScheduledTo   *time.Time     `json:"scheduled_to,omitempty"`
+	Search        string         `json:"search,omitempty" binding:"omitempty,max=200"`
+	Tags          []string       `json:"tags,omitempty" binding:"omitempty,max=20,dive,max=64"`
+	Metadata      map[string]any `json:"metadata,omitempty"`
+}
+
+// toFilter converts the bulk filter to a list filter.
+func (f BulkFilter) toFilter() model.NotificationFilter {
+	filter := model.NotificationFilter{
+		ScheduledFrom: f.ScheduledFrom,
+		ScheduledTo:   f.ScheduledTo,
+		Tags:          f.Tags,
+		Metadata:      f.Metadata,
+	}
+	if f.Status != "" {
+		status := model.Status(f.Status)
+		filter.Status = &status
+	}
+	if f.Channel != "" {
+		channel := model.Channel(f.Channel)
+		filter.Channel = &channel
+	}
+	if f.AuthorID != "" {
+		filter.AuthorID = &f.AuthorID
+	}
+	if f.Search != "" {
+		filter.Search = &f.Search
+	}
+	return filter
+}
+
+// BulkCancelRequest defines the structure for cancelling every notification matching a filter.
+// With DryRun, the notifications are only counted.
+type BulkCancelRequest struct {
+	Filter BulkFilter `json:"filter"`
+	DryRun bool       `json:"dry_run,omitempty"`
+}
+
+// BulkRescheduleRequest defines the structure for moving every notification matching a
+// filter to a new time. LocalTime requires Timezone, which then replaces the time zones
+// of the notifications. With DryRun, the notifications are only counted.
+type BulkRescheduleRequest struct {
+	Filter      BulkFilter `json:"filter"`
+	ScheduledAt time.Time  `json:"scheduled_at"`
+	LocalTime   string     `json:"local_time,omitempty" binding:"omitempty,max=32"`
+	Timezone    string     `json:"timezone,omitempty" binding:"omitempty,max=64"`
+	DryRun      bool       `json:"dry_run,omitempty"`
+}
+
+// BulkDryRunResponse is the number of notifications a bulk job would change.
+type BulkDryRunResponse struct {
+	Action  string `json:"action"`
+	Matched int    `json:"matched"`
 }
 
-// ErrorResponse defines a standard structure for API error responses.
-type ErrorResponse struct {
-	Error string `json:"error"`
+// BulkJobResponse defines the structure of a bulk job and its progress.
+type BulkJobResponse struct {
+	ID     uuid.UUID `json:"id"`
+	Action string    `json:"action"`
+	Status string    `json:"status"`
+	// ScheduledAt and Timezone are the new send time of a reschedule job.
+	ScheduledAt *time.Time `json:"scheduled_at,omitempty"`
+	Timezone    *string    `json:"timezone,omitempty"`
+	Total       int        `json:"total"`
+	Processed   int        `json:"processed"`
+	Failed      int        `json:"failed"`
+	Error       string     `json:"error,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	FinishedAt  *time.Time `json:"finished_at,omitempty"`
 }
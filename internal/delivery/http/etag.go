@@ -0,0 +1,36 @@
+package http
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/ilindan-dev/delayed-notifier/internal/domain"
+	"github.com/ilindan-dev/delayed-notifier/internal/domain/model"
+	"strconv"
+	"strings"
+)
+
+// errInvalidIfMatch is returned for an If-Match header that is not a single strong entity
+// tag. Such a header never matches the current entity tag, hence the version conflict.
+var errInvalidIfMatch = &domain.Error{Code: domain.CodeVersionConflict, Message: `If-Match must be a single entity tag such as "3", or *`}
+
+// setETag sets the ETag header of a response to the version of the notification, so that
+// clients can make their next change conditional on it with If-Match.
+func setETag(c *gin.Context, n *model.Notification) {
+	c.Header("ETag", strconv.Quote(strconv.Itoa(n.Version)))
+}
+
+// ifMatch returns the notification version the If-Match header of the request requires,
+// or nil if there is no header or it is *.
+func ifMatch(c *gin.Context) (*int, error) {
+	raw := strings.TrimSpace(c.GetHeader("If-Match"))
+	if raw == "" || raw == "*" {
+		return nil, nil
+	}
+	if len(raw) < 2 || raw[0] != '"' || raw[len(raw)-1] != '"' {
+		return nil, errInvalidIfMatch
+	}
+	version, err := strconv.Atoi(raw[1 : len(raw)-1])
+	if err != nil {
+		return nil, errInvalidIfMatch
+	}
+	return &version, nil
+}
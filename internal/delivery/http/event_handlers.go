@@ -0,0 +1,103 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/ilindan-dev/delayed-notifier/internal/domain/model"
+	"net/http"
+	"time"
+)
+
+const (
+	// defaultHeartbeat is how often an idle event stream sends a keep-alive comment by default.
+	defaultHeartbeat = 15 * time.Second
+	// lastEventIDQuery lets clients that cannot set headers resume a stream.
+	lastEventIDQuery = "last_event_id"
+)
+
+// StreamEvents handles the server-sent events stream of all status changes the caller may see.
+func (h *Handlers) StreamEvents(c *gin.Context) {
+	h.streamEvents(c, nil)
+}
+
+// StreamNotificationEvents handles the server-sent events stream of a single notification's status changes.
+func (h *Handlers) StreamNotificationEvents(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		h.fail(c, errInvalidID, "")
+		return
+	}
+
+	if _, err := h.service.GetNotificationByID(c.Request.Context(), id); err != nil {
+		h.fail(c, err, "failed to retrieve notification")
+		return
+	}
+
+	h.streamEvents(c, &id)
+}
+
+// streamEvents writes status events as server-sent events until the client disconnects.
+// Events missed since the Last-Event-ID are replayed first; without one the stream
+// starts with the next event.
+func (h *Handlers) streamEvents(c *gin.Context, notificationID *uuid.UUID) {
+	ctx := c.Request.Context()
+	cursor := c.GetHeader("Last-Event-ID")
+	if cursor == "" {
+		cursor = c.Query(lastEventIDQuery)
+	}
+
+	// The first read does not block, so that an invalid cursor can still be
+	// answered with a regular error response.
+	events, cursor, err := h.service.NextStatusEvents(ctx, notificationID, cursor, 0)
+	if err != nil {
+		h.fail(c, err, "failed to open event stream")
+		return
+	}
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no") // Disables proxy buffering, e.g. in nginx.
+	c.Status(http.StatusOK)
+	c.Writer.Flush()
+
+	for {
+		for _, e := range events {
+			if err := writeEvent(c, e); err != nil {
+				return
+			}
+		}
+		if len(events) == 0 {
+			if _, err := fmt.Fprint(c.Writer, ": keep-alive\n\n"); err != nil {
+				return
+			}
+		}
+		c.Writer.Flush()
+
+		events, cursor, err = h.service.NextStatusEvents(ctx, notificationID, cursor, h.heartbeat)
+		if err != nil {
+			if ctx.Err() == nil {
+				h.logger.Error().Err(err).Msg("event stream aborted")
+			}
+			return
+		}
+	}
+}
+
+// writeEvent writes a single status event in the server-sent events format.
+func writeEvent(c *gin.Context, e *model.StatusEvent) error {
+	data, err := json.Marshal(StatusEventResponse{
+		ID:             e.ID,
+		NotificationID: e.NotificationID,
+		Status:         string(e.Status),
+		Attempts:       e.Attempts,
+		OccurredAt:     e.OccurredAt,
+	})
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(c.Writer, "id: %s\nevent: status\ndata: %s\n\n", e.ID, data)
+	return err
+}
@@ -0,0 +1,118 @@
+package http
+
+import (
+	"context"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/ilindan-dev/delayed-notifier/internal/domain/model"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// streamEventIDs opens the event stream for a moment and returns the IDs of the events
+// it sent, or the response of a request that failed.
+func streamEventIDs(t *testing.T, router *gin.Engine, key, lastEventID string) ([]string, *httptest.ResponseRecorder) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	req := httptest.NewRequestWithContext(ctx, http.MethodGet, "http://localhost/api/v1/notifications/events", nil)
+	req.Header.Set(apiKeyHeader, key)
+	if lastEventID != "" {
+		req.Header.Set("Last-Event-ID", lastEventID)
+	}
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		return nil, rec
+	}
+
+	var ids []string
+	for _, line := range strings.Split(rec.Body.String(), "\n") {
+		if id, ok := strings.CutPrefix(line, "id: "); ok {
+			ids = append(ids, id)
+		}
+	}
+	return ids, rec
+}
+
+func TestStreamEvents(t *testing.T) {
+	events := newFakeEventStream()
+	router, keys := newAPIKeyTestRouter(t, events)
+	defaultKey := newTestAPIKey(t, keys, model.DefaultTenant, "operator", model.ScopeAdmin)
+	acmeKey := newTestAPIKey(t, keys, "acme", "acme-admin", model.ScopeAdmin)
+
+	publish := func(tenantID string) string {
+		t.Helper()
+		e := &model.StatusEvent{NotificationID: uuid.New(), TenantID: tenantID, Status: model.StatusSent, OccurredAt: time.Now().UTC()}
+		if err := events.Publish(context.Background(), e); err != nil {
+			t.Fatal(err)
+		}
+		return e.ID
+	}
+	first := publish(model.DefaultTenant)
+	acmeFirst := publish("acme")
+	second := publish(model.DefaultTenant)
+	third := publish(model.DefaultTenant)
+	acmeSecond := publish("acme")
+
+	tests := []struct {
+		name        string
+		key         string
+		lastEventID string
+		want        []string
+		wantStatus  int
+	}{
+		{name: "new events only", key: defaultKey, want: nil, wantStatus: http.StatusOK},
+		{name: "resume", key: defaultKey, lastEventID: first, want: []string{second, third}, wantStatus: http.StatusOK},
+		{name: "resume from the start", key: defaultKey, lastEventID: "0-0", want: []string{first, second, third}, wantStatus: http.StatusOK},
+		{name: "resume up to date", key: defaultKey, lastEventID: third, want: nil, wantStatus: http.StatusOK},
+		{name: "other tenant", key: acmeKey, lastEventID: "0-0", want: []string{acmeFirst, acmeSecond}, wantStatus: http.StatusOK},
+		{name: "invalid event id", key: defaultKey, lastEventID: "yesterday", wantStatus: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ids, rec := streamEventIDs(t, router, tt.key, tt.lastEventID)
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("got status %d, want %d: %s", rec.Code, tt.wantStatus, rec.Body)
+			}
+			if tt.wantStatus != http.StatusOK {
+				if !strings.Contains(rec.Body.String(), "invalid_event_id") {
+					t.Errorf("got %s, want an invalid_event_id problem", rec.Body)
+				}
+				return
+			}
+			if !slices.Equal(ids, tt.want) {
+				t.Errorf("got events %v, want %v", ids, tt.want)
+			}
+		})
+	}
+}
+
+func TestStreamEventsDeliversNewEvents(t *testing.T) {
+	events := newFakeEventStream()
+	router, keys := newAPIKeyTestRouter(t, events)
+	defaultKey := newTestAPIKey(t, keys, model.DefaultTenant, "operator", model.ScopeAdmin)
+
+	published := make(chan string, 2)
+	go func() {
+		time.Sleep(30 * time.Millisecond)
+		for _, tenantID := range []string{"acme", model.DefaultTenant} {
+			e := &model.StatusEvent{NotificationID: uuid.New(), TenantID: tenantID, Status: model.StatusSent, OccurredAt: time.Now().UTC()}
+			_ = events.Publish(context.Background(), e)
+			published <- e.ID
+		}
+	}()
+
+	ids, rec := streamEventIDs(t, router, defaultKey, "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("got status %d, want %d", rec.Code, http.StatusOK)
+	}
+	<-published // The other tenant's event.
+	if want := []string{<-published}; !slices.Equal(ids, want) {
+		t.Errorf("got events %v, want %v", ids, want)
+	}
+}
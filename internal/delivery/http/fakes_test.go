@@ -0,0 +1,442 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/ilindan-dev/delayed-notifier/internal/domain/model"
+	repo "github.com/ilindan-dev/delayed-notifier/internal/domain/repository"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// In-memory implementations of the repository interfaces, used to run the
+// handlers against a real NotificationService without external services.
+
+type fakeNotificationRepo struct {
+	mu    sync.Mutex
+	items map[uuid.UUID]*model.Notification
+}
+
+func newFakeNotificationRepo() *fakeNotificationRepo {
+	return &fakeNotificationRepo{items: make(map[uuid.UUID]*model.Notification)}
+}
+
+func (r *fakeNotificationRepo) Save(_ context.Context, n *model.Notification) (*model.Notification, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.items[n.ID]; ok {
+		return nil, repo.ErrDuplicateRecord
+	}
+	saved := *n
+	saved.CreatedAt = time.Now().UTC()
+	saved.Version = 1
+	r.items[n.ID] = &saved
+	result := saved
+	return &result, nil
+}
+
+func (r *fakeNotificationRepo) GetByID(_ context.Context, tenantID string, id uuid.UUID) (*model.Notification, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	n, ok := r.items[id]
+	if !ok || n.TenantID != tenantID {
+		return nil, repo.ErrNotFound
+	}
+	result := *n
+	return &result, nil
+}
+
+func (r *fakeNotificationRepo) Transition(_ context.Context, n *model.Notification, from model.Status) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stored, ok := r.items[n.ID]
+	if !ok || stored.TenantID != n.TenantID {
+		return repo.ErrNotFound
+	}
+	if stored.Status != from || stored.Version != n.Version {
+		return repo.ErrConflict
+	}
+	n.Version++
+	updated := *n
+	r.items[n.ID] = &updated
+	return nil
+}
+
+func (r *fakeNotificationRepo) Delete(_ context.Context, tenantID string, id uuid.UUID, version *int) (*model.Notification, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	n, ok := r.items[id]
+	if !ok || n.TenantID != tenantID || n.Status != model.StatusScheduled || (version != nil && n.Version != *version) {
+		return nil, repo.ErrNotFound
+	}
+	n.Status = model.StatusCancelled
+	n.Version++
+	result := *n
+	return &result, nil
+}
+
+func (r *fakeNotificationRepo) Reschedule(_ context.Context, tenantID string, id uuid.UUID, scheduledAt time.Time, timezone *string, version *int) (*model.Notification, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	n, ok := r.items[id]
+	if !ok || n.TenantID != tenantID || n.Status != model.StatusScheduled || (version != nil && n.Version != *version) {
+		return nil, repo.ErrNotFound
+	}
+	n.ScheduledAt = scheduledAt
+	if timezone != nil {
+		n.Timezone = timezone
+	}
+	n.Attempts = 0
+	n.Version++
+	result := *n
+	return &result, nil
+}
+
+func (r *fakeNotificationRepo) List(_ context.Context, tenantID string, filter model.NotificationFilter, after *model.Cursor, limit int) ([]*model.Notification, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	result := r.matching(tenantID, filter)
+	sort.Slice(result, func(i, j int) bool { return result[i].CreatedAt.After(result[j].CreatedAt) })
+	if after != nil {
+		i := sort.Search(len(result), func(i int) bool { return !result[i].CreatedAt.After(after.CreatedAt) })
+		for i < len(result) && result[i].CreatedAt.Equal(after.CreatedAt) {
+			i++
+		}
+		result = result[i:]
+	}
+	if len(result) > limit {
+		result = result[:limit]
+	}
+	return result, nil
+}
+
+func (r *fakeNotificationRepo) Count(_ context.Context, tenantID string, filter model.NotificationFilter) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.matching(tenantID, filter)), nil
+}
+
+func (r *fakeNotificationRepo) CancelMany(ctx context.Context, tenantID string, ids []uuid.UUID) ([]*model.Notification, error) {
+	var cancelled []*model.Notification
+	for _, id := range ids {
+		if n, err := r.Delete(ctx, tenantID, id, nil); err == nil {
+			cancelled = append(cancelled, n)
+		}
+	}
+	return cancelled, nil
+}
+
+func (r *fakeNotificationRepo) RescheduleMany(ctx context.Context, tenantID string, ids []uuid.UUID, scheduledAt time.Time, timezone *string) ([]*model.Notification, error) {
+	var rescheduled []*model.Notification
+	for _, id := range ids {
+		if n, err := r.Reschedule(ctx, tenantID, id, scheduledAt, timezone, nil); err == nil {
+			rescheduled = append(rescheduled, n)
+		}
+	}
+	return rescheduled, nil
+}
+
+// matching returns copies of the tenant's notifications matching the status, search,
+// author and tags of the filter. The caller must hold the lock.
+func (r *fakeNotificationRepo) matching(tenantID string, filter model.NotificationFilter) []*model.Notification {
+	var result []*model.Notification
+	for _, n := range r.items {
+		if n.TenantID != tenantID || (filter.Status != nil && n.Status != *filter.Status) {
+			continue
+		}
+		if filter.Search != nil && !strings.Contains(strings.ToLower(n.Subject), strings.ToLower(*filter.Search)) &&
+			!strings.Contains(strings.ToLower(n.Recipient()), strings.ToLower(*filter.Search)) {
+			continue
+		}
+		if filter.AuthorID != nil && (n.AuthorID == nil || *n.AuthorID != *filter.AuthorID) {
+			continue
+		}
+		if !containsAll(n.Tags, filter.Tags) {
+			continue
+		}
+		copied := *n
+		result = append(result, &copied)
+	}
+	return result
+}
+
+func containsAll(tags, wanted []string) bool {
+	for _, tag := range wanted {
+		if !slices.Contains(tags, tag) {
+			return false
+		}
+	}
+	return true
+}
+
+type fakeQueue struct{}
+
+func (fakeQueue) Publish(context.Context, *model.Notification) error { return nil }
+
+func (fakeQueue) PublishRetry(context.Context, *model.Notification, time.Duration) error { return nil }
+
+func (fakeQueue) PublishDeferred(context.Context, *model.Notification, time.Duration) error {
+	return nil
+}
+
+func (fakeQueue) PublishNow(context.Context, *model.Notification) error { return nil }
+
+func (fakeQueue) PublishCallback(context.Context, *model.CallbackEvent) error { return nil }
+
+func (fakeQueue) PublishCallbackRetry(context.Context, *model.CallbackEvent, time.Duration) error {
+	return nil
+}
+
+// flakyQueue fails the first failures publishes and counts the successful ones.
+type flakyQueue struct {
+	fakeQueue
+	failures  int
+	published int
+}
+
+func (q *flakyQueue) Publish(context.Context, *model.Notification) error {
+	if q.failures > 0 {
+		q.failures--
+		return errors.New("broker unavailable")
+	}
+	q.published++
+	return nil
+}
+
+type fakeQueueInspector struct{}
+
+func (fakeQueueInspector) Stats(context.Context) ([]model.QueueStats, error) {
+	return []model.QueueStats{{Name: "notifications.queue.process", Messages: 3, Consumers: 1}}, nil
+}
+
+func (fakeQueueInspector) PeekDeadLetters(context.Context, int) ([]model.DeadLetter, error) {
+	return []model.DeadLetter{{
+		MessageID: uuid.NewString(),
+		Reason:    "malformed message",
+		DeadAt:    time.Now().UTC(),
+		Body:      []byte("{"),
+	}}, nil
+}
+
+type fakeQuotaStore struct{}
+
+func (fakeQuotaStore) Increment(context.Context, string, time.Time) (int64, error) { return 1, nil }
+
+func (fakeQuotaStore) Decrement(context.Context, string, time.Time) error { return nil }
+
+type fakeDeliveryRepo struct{}
+
+func (fakeDeliveryRepo) Save(context.Context, *model.CallbackDelivery) error { return nil }
+
+func (fakeDeliveryRepo) ListByNotification(_ context.Context, tenantID string, notificationID uuid.UUID) ([]*model.CallbackDelivery, error) {
+	code := 200
+	return []*model.CallbackDelivery{{
+		ID:             uuid.New(),
+		EventID:        uuid.New(),
+		NotificationID: notificationID,
+		TenantID:       tenantID,
+		Event:          model.StatusSent,
+		URL:            "https://example.com/hook",
+		Attempt:        1,
+		StatusCode:     &code,
+		Duration:       42 * time.Millisecond,
+		Delivered:      true,
+		CreatedAt:      time.Now().UTC(),
+	}}, nil
+}
+
+// fakeEventStream keeps a stream of events per tenant, with IDs shaped like those of
+// Redis streams. Reads without new events wait for the block time.
+type fakeEventStream struct {
+	mu      sync.Mutex
+	streams map[string][]*model.StatusEvent
+	last    int
+}
+
+func newFakeEventStream() *fakeEventStream {
+	return &fakeEventStream{streams: make(map[string][]*model.StatusEvent)}
+}
+
+func (s *fakeEventStream) Publish(_ context.Context, e *model.StatusEvent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.last++
+	e.ID = fmt.Sprintf("%d-0", s.last)
+	stored := *e
+	s.streams[e.TenantID] = append(s.streams[e.TenantID], &stored)
+	return nil
+}
+
+func (s *fakeEventStream) LatestID(_ context.Context, tenantID string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stream := s.streams[tenantID]
+	if len(stream) == 0 {
+		return "0-0", nil
+	}
+	return stream[len(stream)-1].ID, nil
+}
+
+func (s *fakeEventStream) ReadAfter(ctx context.Context, tenantID, afterID string, block time.Duration) ([]*model.StatusEvent, error) {
+	after, ok := eventSequence(afterID)
+	if !ok {
+		return nil, repo.ErrInvalidEventID
+	}
+
+	s.mu.Lock()
+	var events []*model.StatusEvent
+	for _, e := range s.streams[tenantID] {
+		if seq, _ := eventSequence(e.ID); seq > after {
+			result := *e
+			events = append(events, &result)
+		}
+	}
+	s.mu.Unlock()
+
+	if len(events) == 0 && block > 0 {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(block):
+		}
+	}
+	return events, nil
+}
+
+// eventSequence returns the first part of an event ID such as "12-0".
+func eventSequence(id string) (int, bool) {
+	first, second, ok := strings.Cut(id, "-")
+	seq, err := strconv.Atoi(first)
+	if _, secondErr := strconv.Atoi(second); !ok || err != nil || secondErr != nil {
+		return 0, false
+	}
+	return seq, true
+}
+
+type fakeAPIKeyRepo struct {
+	mu   sync.Mutex
+	keys map[uuid.UUID]*model.APIKey
+}
+
+func newFakeAPIKeyRepo() *fakeAPIKeyRepo {
+	return &fakeAPIKeyRepo{keys: make(map[uuid.UUID]*model.APIKey)}
+}
+
+func (r *fakeAPIKeyRepo) Save(_ context.Context, key *model.APIKey) (*model.APIKey, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	saved := *key
+	saved.ID = uuid.New()
+	saved.CreatedAt = time.Now().UTC()
+	r.keys[saved.ID] = &saved
+	result := saved
+	return &result, nil
+}
+
+func (r *fakeAPIKeyRepo) GetActiveByHash(_ context.Context, hash string) (*model.APIKey, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, k := range r.keys {
+		if k.Hash == hash && k.RevokedAt == nil {
+			copied := *k
+			return &copied, nil
+		}
+	}
+	return nil, repo.ErrNotFound
+}
+
+func (r *fakeAPIKeyRepo) List(_ context.Context, tenantID string) ([]*model.APIKey, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var result []*model.APIKey
+	for _, k := range r.keys {
+		if k.TenantID == tenantID {
+			copied := *k
+			result = append(result, &copied)
+		}
+	}
+	return result, nil
+}
+
+func (r *fakeAPIKeyRepo) Revoke(_ context.Context, tenantID string, id uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	k, ok := r.keys[id]
+	if !ok || k.TenantID != tenantID {
+		return repo.ErrNotFound
+	}
+	now := time.Now().UTC()
+	k.RevokedAt = &now
+	return nil
+}
+
+func (r *fakeAPIKeyRepo) Touch(context.Context, uuid.UUID) error { return nil }
+
+type fakeDeliveryWindowRepo struct {
+	mu      sync.Mutex
+	windows map[string]*model.DeliveryWindow
+}
+
+func newFakeDeliveryWindowRepo() *fakeDeliveryWindowRepo {
+	return &fakeDeliveryWindowRepo{windows: make(map[string]*model.DeliveryWindow)}
+}
+
+func (r *fakeDeliveryWindowRepo) Get(_ context.Context, tenantID string, channel model.Channel, recipient string) (*model.DeliveryWindow, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	w, ok := r.windows[tenantID+"/"+string(channel)+"/"+recipient]
+	if !ok {
+		return nil, repo.ErrNotFound
+	}
+	return w, nil
+}
+
+func (r *fakeDeliveryWindowRepo) Put(_ context.Context, tenantID string, channel model.Channel, recipient string, window *model.DeliveryWindow) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.windows[tenantID+"/"+string(channel)+"/"+recipient] = window
+	return nil
+}
+
+func (r *fakeDeliveryWindowRepo) Delete(_ context.Context, tenantID string, channel model.Channel, recipient string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	key := tenantID + "/" + string(channel) + "/" + recipient
+	if _, ok := r.windows[key]; !ok {
+		return repo.ErrNotFound
+	}
+	delete(r.windows, key)
+	return nil
+}
+
+type fakeBulkJobStore struct {
+	mu   sync.Mutex
+	jobs map[uuid.UUID]model.BulkJob
+}
+
+func newFakeBulkJobStore() *fakeBulkJobStore {
+	return &fakeBulkJobStore{jobs: make(map[uuid.UUID]model.BulkJob)}
+}
+
+func (s *fakeBulkJobStore) Save(_ context.Context, job *model.BulkJob) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.jobs[job.ID] = *job
+	return nil
+}
+
+func (s *fakeBulkJobStore) Get(_ context.Context, tenantID string, id uuid.UUID) (*model.BulkJob, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	job, ok := s.jobs[id]
+	if !ok || job.TenantID != tenantID {
+		return nil, repo.ErrNotFound
+	}
+	return &job, nil
+}
@@ -1,36 +1,82 @@
 package http
 
 import (
-	"errors"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"github.com/ilindan-dev/delayed-notifier/internal/config"
 	"github.com/ilindan-dev/delayed-notifier/internal/domain/model"
-	repo "github.com/ilindan-dev/delayed-notifier/internal/domain/repository"
 	"github.com/ilindan-dev/delayed-notifier/internal/service"
 	"github.com/rs/zerolog"
 	"net/http"
+	"time"
 )
 
+// idempotencyKeyHeader makes create requests safe to retry: a repeated key returns
+// the notification created by the first request.
+const idempotencyKeyHeader = "Idempotency-Key"
+
 type Handlers struct {
-	service *service.NotificationService
-	logger  zerolog.Logger
+	service   *service.NotificationService
+	keys      *service.APIKeyService
+	queues    *service.QueueService
+	auth      *AuthMiddleware
+	heartbeat time.Duration
+	logger    zerolog.Logger
 }
 
 // NewHandlers creates a new instance of Handlers.
-func NewHandlers(service *service.NotificationService, logger *zerolog.Logger) *Handlers {
+func NewHandlers(
+	cfg *config.Config,
+	service *service.NotificationService,
+	keys *service.APIKeyService,
+	queues *service.QueueService,
+	auth *AuthMiddleware,
+	logger *zerolog.Logger,
+) *Handlers {
+	heartbeat := cfg.Events.Heartbeat
+	if heartbeat <= 0 {
+		heartbeat = defaultHeartbeat
+	}
 	return &Handlers{
-		service: service,
-		logger:  logger.With().Str("layer", "http_handler").Logger(),
+		service:   service,
+		keys:      keys,
+		queues:    queues,
+		auth:      auth,
+		heartbeat: heartbeat,
+		logger:    logger.With().Str("layer", "http_handler").Logger(),
 	}
 }
 
 // RegisterRoutes sets up the routing for the notification API.
+// Every route requires authentication and the scope noted next to it.
 func (h *Handlers) RegisterRoutes(router *gin.Engine) {
-	api := router.Group("/api/v1")
+	api := router.Group("/api/v1", h.auth.Authenticate())
 	{
-		api.POST("/notifications", h.CreateNotification)
-		api.GET("/notifications/:id", h.GetNotificationByID)
-		api.DELETE("/notifications/:id", h.CancelNotification)
+		api.POST("/notifications", h.auth.RequireScope(model.ScopeCreate), h.CreateNotification)
+		api.POST("/notifications/batch", h.auth.RequireScope(model.ScopeCreate), h.BatchCreateNotifications)
+		api.GET("/notifications", h.auth.RequireScope(model.ScopeRead), h.ListNotifications)
+		api.GET("/notifications/events", h.auth.RequireScope(model.ScopeRead), h.StreamEvents)
+		api.GET("/notifications/:id/events", h.auth.RequireScope(model.ScopeRead), h.StreamNotificationEvents)
+		api.GET("/notifications/:id", h.auth.RequireScope(model.ScopeRead), h.GetNotificationByID)
+		api.PATCH("/notifications/:id", h.auth.RequireScope(model.ScopeCreate), h.RescheduleNotification)
+		api.DELETE("/notifications/:id", h.auth.RequireScope(model.ScopeCancel), h.CancelNotification)
+		api.GET("/notifications/:id/callbacks", h.auth.RequireScope(model.ScopeRead), h.ListCallbackDeliveries)
+		api.POST("/notifications:method", customMethods(map[string]gin.HandlersChain{
+			":cancel":     {h.auth.RequireScope(model.ScopeCancel), h.BulkCancelNotifications},
+			":reschedule": {h.auth.RequireScope(model.ScopeCreate), h.BulkRescheduleNotifications},
+		}))
+		api.GET("/jobs/:id", h.auth.RequireScope(model.ScopeRead), h.GetBulkJob)
+
+		api.GET("/recipients/:channel/:recipient/delivery-window", h.auth.RequireScope(model.ScopeRead), h.GetRecipientWindow)
+		api.PUT("/recipients/:channel/:recipient/delivery-window", h.auth.RequireScope(model.ScopeAdmin), h.SetRecipientWindow)
+		api.DELETE("/recipients/:channel/:recipient/delivery-window", h.auth.RequireScope(model.ScopeAdmin), h.DeleteRecipientWindow)
+
+		api.POST("/api-keys", h.auth.RequireScope(model.ScopeAdmin), h.CreateAPIKey)
+		api.GET("/api-keys", h.auth.RequireScope(model.ScopeAdmin), h.ListAPIKeys)
+		api.DELETE("/api-keys/:id", h.auth.RequireScope(model.ScopeAdmin), h.RevokeAPIKey)
+
+		api.GET("/admin/queues", h.auth.RequireScope(model.ScopeAdmin), h.ListQueues)
+		api.GET("/admin/dead-letters", h.auth.RequireScope(model.ScopeAdmin), h.ListDeadLetters)
 	}
 }
 
@@ -39,53 +85,130 @@ func (h *Handlers) CreateNotification(c *gin.Context) {
 	var req CreateNotificationRequest
 
 	if err := c.ShouldBindJSON(&req); err != nil {
-		h.logger.Warn().Err(err).Msg("invalid request body")
-		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		writeProblem(c, bindingProblem(err))
 		return
 	}
 
-	notification, err := h.service.CreateNotification(
-		c.Request.Context(),
-		req.Recipient,
-		model.Channel(req.Channel),
-		req.Subject,
-		req.Message,
-		req.ScheduledAt,
-		req.AuthorID,
-	)
+	in := req.toInput()
+	in.IdempotencyKey = c.GetHeader(idempotencyKeyHeader)
+	notification, err := h.service.CreateNotification(c.Request.Context(), in)
 	if err != nil {
-		if errors.Is(err, repo.ErrDuplicateRecord) {
-			c.JSON(http.StatusConflict, ErrorResponse{Error: err.Error()})
-			return
-		}
-		h.logger.Error().Err(err).Msg("failed to create notification")
-		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "failed to create notification"})
+		h.fail(c, err, "failed to create notification")
 		return
 	}
 
+	setETag(c, notification)
 	c.JSON(http.StatusCreated, toNotificationResponse(notification))
 }
 
+// BatchCreateNotifications handles the HTTP request for creating several notifications at once.
+// Items are created independently; the response reports the outcome of each in request order.
+func (h *Handlers) BatchCreateNotifications(c *gin.Context) {
+	var req BatchCreateNotificationsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeProblem(c, bindingProblem(err))
+		return
+	}
+
+	inputs := make([]service.CreateNotificationInput, len(req.Notifications))
+	for i, item := range req.Notifications {
+		inputs[i] = item.toInput()
+	}
+
+	results, err := h.service.CreateNotifications(c.Request.Context(), inputs, c.GetHeader(idempotencyKeyHeader))
+	if err != nil {
+		h.fail(c, err, "failed to create notifications")
+		return
+	}
+
+	resp := BatchCreateNotificationsResponse{Results: make([]BatchItemResponse, len(results))}
+	for i, result := range results {
+		if result.Err != nil {
+			problem := h.problem(result.Err, "failed to create notification")
+			resp.Results[i] = BatchItemResponse{Status: problem.Status, Error: &problem}
+			continue
+		}
+		notification := toNotificationResponse(result.Notification)
+		resp.Results[i] = BatchItemResponse{Status: http.StatusCreated, Notification: &notification}
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// ListNotifications handles the HTTP request to list notifications, newest first.
+func (h *Handlers) ListNotifications(c *gin.Context) {
+	var query ListNotificationsQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		writeProblem(c, bindingProblem(err))
+		return
+	}
+
+	filter, err := query.toFilter()
+	if err != nil {
+		h.fail(c, err, "")
+		return
+	}
+
+	notifications, nextPageToken, err := h.service.ListNotifications(c.Request.Context(), filter, query.PageToken, query.Limit)
+	if err != nil {
+		h.fail(c, err, "failed to list notifications")
+		return
+	}
+
+	resp := ListNotificationsResponse{
+		Notifications: make([]NotificationResponse, 0, len(notifications)),
+		NextPageToken: nextPageToken,
+	}
+	for _, n := range notifications {
+		resp.Notifications = append(resp.Notifications, toNotificationResponse(n))
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
 // GetNotificationByID handles the HTTP request to retrieve a notification.
 func (h *Handlers) GetNotificationByID(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := uuid.Parse(idStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid notification ID format"})
+		h.fail(c, errInvalidID, "")
 		return
 	}
 
 	notification, err := h.service.GetNotificationByID(c.Request.Context(), id)
 	if err != nil {
-		if errors.Is(err, repo.ErrNotFound) {
-			c.JSON(http.StatusNotFound, ErrorResponse{Error: err.Error()})
-			return
-		}
-		h.logger.Error().Err(err).Stringer("id", id).Msg("failed to get notification by id")
-		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "failed to retrieve notification"})
+		h.fail(c, err, "failed to retrieve notification")
 		return
 	}
 
+	setETag(c, notification)
+	c.JSON(http.StatusOK, toNotificationResponse(notification))
+}
+
+// RescheduleNotification handles the HTTP request to move a scheduled notification to a new time.
+func (h *Handlers) RescheduleNotification(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		h.fail(c, errInvalidID, "")
+		return
+	}
+
+	var req RescheduleNotificationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeProblem(c, bindingProblem(err))
+		return
+	}
+	in := req.toInput()
+	if in.Version, err = ifMatch(c); err != nil {
+		h.fail(c, err, "")
+		return
+	}
+
+	notification, err := h.service.RescheduleNotification(c.Request.Context(), id, in)
+	if err != nil {
+		h.fail(c, err, "failed to reschedule notification")
+		return
+	}
+
+	setETag(c, notification)
 	c.JSON(http.StatusOK, toNotificationResponse(notification))
 }
 
@@ -94,33 +217,82 @@ func (h *Handlers) CancelNotification(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := uuid.Parse(idStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid notification ID format"})
+		h.fail(c, errInvalidID, "")
 		return
 	}
 
-	err = h.service.CancelNotification(c.Request.Context(), id)
+	version, err := ifMatch(c)
 	if err != nil {
-		if errors.Is(err, repo.ErrNotFound) {
-			c.JSON(http.StatusNotFound, ErrorResponse{Error: err.Error()})
-			return
-		}
+		h.fail(c, err, "")
+		return
+	}
 
-		h.logger.Error().Err(err).Stringer("id", id).Msg("failed to cancel notification")
-		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "failed to cancel notification"})
+	err = h.service.CancelNotification(c.Request.Context(), id, version)
+	if err != nil {
+		h.fail(c, err, "failed to cancel notification")
 		return
 	}
 
 	c.Status(http.StatusNoContent)
 }
 
+// ListCallbackDeliveries handles the HTTP request to list the status callback delivery attempts of a notification.
+func (h *Handlers) ListCallbackDeliveries(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		h.fail(c, errInvalidID, "")
+		return
+	}
+
+	deliveries, err := h.service.ListCallbackDeliveries(c.Request.Context(), id)
+	if err != nil {
+		h.fail(c, err, "failed to list callback deliveries")
+		return
+	}
+
+	resp := make([]CallbackDeliveryResponse, 0, len(deliveries))
+	for _, d := range deliveries {
+		resp = append(resp, CallbackDeliveryResponse{
+			EventID:    d.EventID,
+			Event:      string(d.Event),
+			URL:        d.URL,
+			Attempt:    d.Attempt,
+			StatusCode: d.StatusCode,
+			Error:      d.Error,
+			DurationMs: d.Duration.Milliseconds(),
+			Delivered:  d.Delivered,
+			CreatedAt:  d.CreatedAt,
+		})
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
 // toNotificationResponse is a helper function to map the domain model to the DTO.
 func toNotificationResponse(n *model.Notification) NotificationResponse {
-	return NotificationResponse{
+	resp := NotificationResponse{
 		ID:          n.ID,
 		Status:      string(n.Status),
 		Channel:     string(n.Channel),
+		Priority:    string(n.Priority),
+		Recipient:   n.Recipient(),
 		Subject:     n.Subject,
+		Attempts:    n.Attempts,
+		Version:     n.Version,
 		ScheduledAt: n.ScheduledAt,
+		SentAt:      n.SentAt,
 		CreatedAt:   n.CreatedAt,
+		CallbackURL: n.CallbackURL,
+		Timezone:    n.Timezone,
+		Tags:        n.Tags,
+		Metadata:    n.Metadata,
+	}
+	if n.DeliveryWindow != nil {
+		window := toDeliveryWindow(n.DeliveryWindow)
+		resp.DeliveryWindow = &window
+	}
+	if n.Timezone != nil {
+		resp.LocalTime = n.LocalScheduledAt().Format(localTimeLayout)
 	}
+	return resp
 }
@@ -0,0 +1,43 @@
+package http
+
+import (
+	_ "embed"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+// OpenAPISpec is the OpenAPI 3 document describing every route under /api/v1.
+// It must be kept in sync with RegisterRoutes and the DTOs in dto.go.
+//
+//go:embed openapi.json
+var OpenAPISpec []byte
+
+// swaggerUIPage renders the OpenAPI document with Swagger UI loaded from a CDN.
+const swaggerUIPage = `<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <title>Delayed Notifier API</title>
+  <link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@5/swagger-ui.css">
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="https://unpkg.com/swagger-ui-dist@5/swagger-ui-bundle.js" crossorigin></script>
+  <script>
+    window.onload = () => {
+      window.ui = SwaggerUIBundle({ url: "/api/v1/openapi.json", dom_id: "#swagger-ui" });
+    };
+  </script>
+</body>
+</html>`
+
+// RegisterDocsRoutes serves the OpenAPI document and the Swagger UI page.
+// Both are public, so that clients can generate SDKs without credentials.
+func RegisterDocsRoutes(router *gin.Engine) {
+	router.GET("/api/v1/openapi.json", func(c *gin.Context) {
+		c.Data(http.StatusOK, "application/json", OpenAPISpec)
+	})
+	router.GET("/api/v1/docs", func(c *gin.Context) {
+		c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(swaggerUIPage))
+	})
+}
@@ -0,0 +1,479 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+	"github.com/gin-gonic/gin"
+	"github.com/ilindan-dev/delayed-notifier/internal/config"
+	"github.com/ilindan-dev/delayed-notifier/internal/domain/model"
+	repo "github.com/ilindan-dev/delayed-notifier/internal/domain/repository"
+	"github.com/ilindan-dev/delayed-notifier/internal/service"
+	"github.com/rs/zerolog"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+func loadSpec(t *testing.T) (*openapi3.T, routers.Router) {
+	t.Helper()
+	doc, err := openapi3.NewLoader().LoadFromData(OpenAPISpec)
+	if err != nil {
+		t.Fatalf("failed to load openapi spec: %v", err)
+	}
+	if err := doc.Validate(context.Background()); err != nil {
+		t.Fatalf("openapi spec is invalid: %v", err)
+	}
+	// Match requests regardless of the host they were sent to.
+	doc.Servers = openapi3.Servers{{URL: "http://localhost/api/v1"}}
+	router, err := gorillamux.NewRouter(doc)
+	if err != nil {
+		t.Fatalf("failed to build openapi router: %v", err)
+	}
+	return doc, router
+}
+
+// newTestRouter builds the API router on top of in-memory repositories, with authentication disabled.
+func newTestRouter(t *testing.T) *gin.Engine {
+	t.Helper()
+	return newTestRouterWithQueue(t, fakeQueue{})
+}
+
+// newTestRouterWithQueue is like newTestRouter, but publishes notifications to queue.
+func newTestRouterWithQueue(t *testing.T, queue repo.NotificationQueue) *gin.Engine {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	logger := zerolog.Nop()
+	cfg := &config.Config{Auth: config.AuthConfig{Mode: service.AuthModeNone}}
+
+	notifications := service.NewNotificationService(
+		cfg, newFakeNotificationRepo(), queue, fakeQuotaStore{}, fakeQueue{}, fakeDeliveryRepo{}, newFakeEventStream(), newFakeDeliveryWindowRepo(), newFakeBulkJobStore(), &logger,
+	)
+	keys := service.NewAPIKeyService(cfg, newFakeAPIKeyRepo(), &logger)
+	queues := service.NewQueueService(fakeQueueInspector{}, &logger)
+	authMiddleware := NewAuthMiddleware(service.NewAuthenticator(cfg, keys, nil, &logger), &logger)
+
+	router := gin.New()
+	NewHandlers(cfg, notifications, keys, queues, authMiddleware, &logger).RegisterRoutes(router)
+	RegisterDocsRoutes(router)
+	return router
+}
+
+// newAPIKeyTestRouter builds the API router on top of in-memory repositories, authenticating
+// API keys of the returned service. Status events are read from events.
+func newAPIKeyTestRouter(t *testing.T, events *fakeEventStream) (*gin.Engine, *service.APIKeyService) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	logger := zerolog.Nop()
+	cfg := &config.Config{
+		Auth:   config.AuthConfig{Mode: "api_key"},
+		Events: config.EventsConfig{Heartbeat: 20 * time.Millisecond},
+	}
+
+	notifications := service.NewNotificationService(
+		cfg, newFakeNotificationRepo(), fakeQueue{}, fakeQuotaStore{}, fakeQueue{}, fakeDeliveryRepo{}, events, newFakeDeliveryWindowRepo(), newFakeBulkJobStore(), &logger,
+	)
+	keys := service.NewAPIKeyService(cfg, newFakeAPIKeyRepo(), &logger)
+	queues := service.NewQueueService(fakeQueueInspector{}, &logger)
+	authMiddleware := NewAuthMiddleware(service.NewAuthenticator(cfg, keys, nil, &logger), &logger)
+
+	router := gin.New()
+	NewHandlers(cfg, notifications, keys, queues, authMiddleware, &logger).RegisterRoutes(router)
+	return router, keys
+}
+
+// newTestAPIKey creates an API key with the given scopes and returns the raw key.
+func newTestAPIKey(t *testing.T, keys *service.APIKeyService, tenantID, ownerID string, scopes ...model.Scope) string {
+	t.Helper()
+	_, raw, err := keys.CreateAPIKey(context.Background(), tenantID, ownerID, ownerID, scopes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return raw
+}
+
+func TestOpenAPISpecCoversAllRoutes(t *testing.T) {
+	doc, _ := loadSpec(t)
+	paramPattern := regexp.MustCompile(`:(\w+)`)
+
+	for _, route := range newTestRouter(t).Routes() {
+		path, ok := strings.CutPrefix(route.Path, "/api/v1")
+		if !ok || route.Path == "/api/v1/openapi.json" || route.Path == "/api/v1/docs" {
+			continue
+		}
+		specPaths := []string{paramPattern.ReplaceAllString(path, "{$1}")}
+		if collection, ok := strings.CutSuffix(path, ":method"); ok {
+			// Custom methods share a route, see customMethods.
+			specPaths = []string{collection + ":cancel", collection + ":reschedule"}
+		}
+		for _, specPath := range specPaths {
+			item := doc.Paths.Find(specPath)
+			if item == nil || item.GetOperation(route.Method) == nil {
+				t.Errorf("route %s %s is not documented in the openapi spec", route.Method, specPath)
+			}
+		}
+	}
+}
+
+func TestHandlersConformToOpenAPISpec(t *testing.T) {
+	_, specRouter := loadSpec(t)
+	router := newTestRouter(t)
+
+	// do sends a request through the API router and validates both the request
+	// and the response against the spec.
+	do := func(t *testing.T, method, path string, body any, wantStatus int) []byte {
+		t.Helper()
+		var payload []byte
+		if body != nil {
+			var err error
+			if payload, err = json.Marshal(body); err != nil {
+				t.Fatal(err)
+			}
+		}
+		req := httptest.NewRequest(method, "http://localhost/api/v1"+path, bytes.NewReader(payload))
+		if body != nil {
+			req.Header.Set("Content-Type", "application/json")
+		}
+
+		route, pathParams, err := specRouter.FindRoute(req)
+		if err != nil {
+			t.Fatalf("%s %s: no matching operation in the spec: %v", method, path, err)
+		}
+		requestInput := &openapi3filter.RequestValidationInput{
+			Request:    req,
+			PathParams: pathParams,
+			Route:      route,
+			Options:    &openapi3filter.Options{AuthenticationFunc: openapi3filter.NoopAuthenticationFunc},
+		}
+		// Requests expected to be rejected are deliberately invalid, so only their responses are checked.
+		if wantStatus < http.StatusBadRequest {
+			if err := openapi3filter.ValidateRequest(context.Background(), requestInput); err != nil {
+				t.Fatalf("%s %s: request does not match the spec: %v", method, path, err)
+			}
+			req.Body = io.NopCloser(bytes.NewReader(payload))
+		}
+
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		if rec.Code != wantStatus {
+			t.Fatalf("%s %s: got status %d, want %d: %s", method, path, rec.Code, wantStatus, rec.Body.String())
+		}
+
+		responseInput := &openapi3filter.ResponseValidationInput{
+			RequestValidationInput: requestInput,
+			Status:                 rec.Code,
+			Header:                 rec.Header(),
+			Options:                &openapi3filter.Options{IncludeResponseStatus: true},
+		}
+		responseInput.SetBodyBytes(rec.Body.Bytes())
+		if err := openapi3filter.ValidateResponse(context.Background(), responseInput); err != nil {
+			t.Fatalf("%s %s: response does not match the spec: %v", method, path, err)
+		}
+		return rec.Body.Bytes()
+	}
+
+	createReq := map[string]any{
+		"recipient":    "user@example.com",
+		"channel":      "email",
+		"subject":      "Hello",
+		"message":      "World",
+		"scheduled_at": time.Now().Add(time.Hour).UTC().Format(time.RFC3339),
+		"callback_url": "https://example.com/hook",
+	}
+
+	var created NotificationResponse
+	if err := json.Unmarshal(do(t, http.MethodPost, "/notifications", createReq, http.StatusCreated), &created); err != nil {
+		t.Fatal(err)
+	}
+	id := created.ID.String()
+
+	do(t, http.MethodPost, "/notifications", map[string]any{"channel": "email"}, http.StatusBadRequest)
+	do(t, http.MethodPost, "/notifications", map[string]any{
+		"recipient":  "user@example.com",
+		"channel":    "email",
+		"subject":    "Standup",
+		"local_time": "09:00",
+		"timezone":   "Europe/Berlin",
+		"delivery_window": map[string]any{
+			"days":        []string{"mon", "tue", "wed", "thu", "fri"},
+			"hours":       map[string]any{"start": "09:00", "end": "18:00"},
+			"quiet_hours": map[string]any{"start": "12:00", "end": "13:00"},
+		},
+	}, http.StatusCreated)
+	do(t, http.MethodPost, "/notifications", map[string]any{
+		"recipient": "user@example.com",
+		"channel":   "email",
+		"subject":   "Reminder",
+		"delay":     "PT15M",
+		"tags":      []string{"campaign-spring"},
+		"metadata":  map[string]any{"order_id": "A-1001", "items": 3},
+	}, http.StatusCreated)
+	do(t, http.MethodPost, "/notifications", map[string]any{
+		"recipient": "42",
+		"channel":   "telegram",
+		"subject":   "Alert",
+		"send_now":  true,
+		"priority":  "critical",
+	}, http.StatusCreated)
+	do(t, http.MethodPost, "/notifications", map[string]any{
+		"recipient": "user@example.com",
+		"channel":   "email",
+		"subject":   "Reminder",
+		"delay":     "15 minutes",
+	}, http.StatusBadRequest)
+	do(t, http.MethodPost, "/notifications/batch", map[string]any{
+		"notifications": []any{createReq, createReq},
+	}, http.StatusOK)
+	do(t, http.MethodGet, "/notifications?status=scheduled&limit=10", nil, http.StatusOK)
+	do(t, http.MethodGet, "/notifications?search=HELLO", nil, http.StatusOK)
+	do(t, http.MethodGet, "/notifications?tag=campaign-spring&metadata=%7B%22order_id%22%3A%22A-1001%22%7D", nil, http.StatusOK)
+	do(t, http.MethodGet, "/notifications?metadata=%5B1%5D", nil, http.StatusBadRequest)
+	do(t, http.MethodPost, "/notifications:reschedule", map[string]any{
+		"filter":       map[string]any{"tags": []string{"campaign-spring"}},
+		"scheduled_at": time.Now().Add(3 * time.Hour).UTC().Format(time.RFC3339),
+		"dry_run":      true,
+	}, http.StatusOK)
+	do(t, http.MethodPost, "/notifications:cancel", map[string]any{"filter": map[string]any{}}, http.StatusBadRequest)
+	do(t, http.MethodPost, "/notifications:cancel", map[string]any{
+		"filter": map[string]any{"status": "sent", "tags": []string{"campaign-spring"}},
+	}, http.StatusBadRequest)
+	var job BulkJobResponse
+	if err := json.Unmarshal(do(t, http.MethodPost, "/notifications:cancel", map[string]any{
+		"filter": map[string]any{"tags": []string{"campaign-spring"}, "metadata": map[string]any{"order_id": "A-1001"}},
+	}, http.StatusAccepted), &job); err != nil {
+		t.Fatal(err)
+	}
+	do(t, http.MethodGet, "/jobs/"+job.ID.String(), nil, http.StatusOK)
+	do(t, http.MethodGet, "/jobs/not-a-uuid", nil, http.StatusBadRequest)
+	do(t, http.MethodGet, "/jobs/00000000-0000-0000-0000-000000000000", nil, http.StatusNotFound)
+	do(t, http.MethodGet, "/notifications/"+id, nil, http.StatusOK)
+	do(t, http.MethodGet, "/notifications/not-a-uuid", nil, http.StatusBadRequest)
+	do(t, http.MethodGet, "/notifications/00000000-0000-0000-0000-000000000000", nil, http.StatusNotFound)
+	do(t, http.MethodGet, "/notifications/"+id+"/callbacks", nil, http.StatusOK)
+	do(t, http.MethodPatch, "/notifications/"+id, map[string]any{
+		"scheduled_at": time.Now().Add(2 * time.Hour).UTC().Format(time.RFC3339),
+	}, http.StatusOK)
+	do(t, http.MethodPatch, "/notifications/"+id, map[string]any{
+		"local_time": "10:30",
+		"timezone":   "America/New_York",
+	}, http.StatusOK)
+	do(t, http.MethodDelete, "/notifications/"+id, nil, http.StatusNoContent)
+	do(t, http.MethodDelete, "/notifications/"+id, nil, http.StatusConflict)
+	do(t, http.MethodPatch, "/notifications/"+id, map[string]any{
+		"scheduled_at": time.Now().Add(2 * time.Hour).UTC().Format(time.RFC3339),
+	}, http.StatusConflict)
+
+	window := "/recipients/email/user@example.com/delivery-window"
+	do(t, http.MethodGet, window, nil, http.StatusNotFound)
+	do(t, http.MethodPut, window, map[string]any{
+		"timezone":    "Europe/Berlin",
+		"quiet_hours": map[string]any{"start": "22:00", "end": "07:00"},
+	}, http.StatusOK)
+	do(t, http.MethodPut, window, map[string]any{"days": []string{"someday"}}, http.StatusBadRequest)
+	do(t, http.MethodGet, window, nil, http.StatusOK)
+	do(t, http.MethodDelete, window, nil, http.StatusNoContent)
+	do(t, http.MethodDelete, "/recipients/sms/42/delivery-window", nil, http.StatusBadRequest)
+
+	var key CreateAPIKeyResponse
+	if err := json.Unmarshal(do(t, http.MethodPost, "/api-keys", map[string]any{
+		"name":     "billing",
+		"owner_id": "billing-service",
+		"scopes":   []string{"create", "read"},
+	}, http.StatusCreated), &key); err != nil {
+		t.Fatal(err)
+	}
+	do(t, http.MethodGet, "/api-keys", nil, http.StatusOK)
+	do(t, http.MethodDelete, "/api-keys/"+key.ID.String(), nil, http.StatusNoContent)
+	do(t, http.MethodDelete, "/api-keys/"+key.ID.String()+"0", nil, http.StatusBadRequest)
+
+	do(t, http.MethodGet, "/admin/queues", nil, http.StatusOK)
+	do(t, http.MethodGet, "/admin/dead-letters?limit=5", nil, http.StatusOK)
+}
+
+func TestOpenAPIDocumentIsServed(t *testing.T) {
+	router := newTestRouter(t)
+	for _, path := range []string{"/api/v1/openapi.json", "/api/v1/docs"} {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		if rec.Code != http.StatusOK || rec.Body.Len() == 0 {
+			t.Errorf("GET %s: got status %d with %d bytes", path, rec.Code, rec.Body.Len())
+		}
+	}
+}
+
+func TestCreateNotificationWithIdempotencyKey(t *testing.T) {
+	router := newTestRouter(t)
+	body := `{"recipient":"user@example.com","channel":"email","subject":"Hello","scheduled_at":"` +
+		time.Now().Add(time.Hour).UTC().Format(time.RFC3339) + `"}`
+
+	var ids []string
+	for range 2 {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/notifications", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set(idempotencyKeyHeader, "order-42")
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		if rec.Code != http.StatusCreated {
+			t.Fatalf("got status %d: %s", rec.Code, rec.Body.String())
+		}
+		var resp NotificationResponse
+		if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, resp.ID.String())
+	}
+	if ids[0] != ids[1] {
+		t.Errorf("repeated idempotency key created %s and %s", ids[0], ids[1])
+	}
+}
+
+func TestIdempotentRetryAfterPublishFailure(t *testing.T) {
+	queue := &flakyQueue{failures: 1}
+	router := newTestRouterWithQueue(t, queue)
+	send := func(body string, wantStatus int) {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/notifications", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set(idempotencyKeyHeader, "order-42")
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		if rec.Code != wantStatus {
+			t.Fatalf("got status %d, want %d: %s", rec.Code, wantStatus, rec.Body.String())
+		}
+	}
+	body := `{"recipient":"user@example.com","channel":"email","subject":"Hello","delay":"PT1H"}`
+
+	send(body, http.StatusInternalServerError)
+	send(body, http.StatusCreated)
+	if queue.published != 1 {
+		t.Errorf("published %d times after the retry, want 1", queue.published)
+	}
+	send(`{"recipient":"user@example.com","channel":"email","subject":"Goodbye","delay":"PT1H"}`, http.StatusConflict)
+}
+
+func TestBulkCancelByTag(t *testing.T) {
+	router := newTestRouter(t)
+	send := func(method, path, body string, wantStatus int) []byte {
+		t.Helper()
+		req := httptest.NewRequest(method, "/api/v1"+path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		if rec.Code != wantStatus {
+			t.Fatalf("%s %s: got status %d, want %d: %s", method, path, rec.Code, wantStatus, rec.Body.String())
+		}
+		return rec.Body.Bytes()
+	}
+	for _, tags := range []string{`["spring"]`, `["spring","vip"]`, `["spring"]`, `["summer"]`} {
+		send(http.MethodPost, "/notifications", `{"recipient":"user@example.com","channel":"email","subject":"Sale","delay":"PT1H","tags":`+tags+`}`, http.StatusCreated)
+	}
+
+	var dryRun BulkDryRunResponse
+	if err := json.Unmarshal(send(http.MethodPost, "/notifications:cancel", `{"filter":{"tags":["spring"]},"dry_run":true}`, http.StatusOK), &dryRun); err != nil {
+		t.Fatal(err)
+	}
+	if dryRun.Matched != 3 {
+		t.Errorf("dry run matched %d notifications, want 3", dryRun.Matched)
+	}
+
+	var job BulkJobResponse
+	if err := json.Unmarshal(send(http.MethodPost, "/notifications:cancel", `{"filter":{"tags":["spring"]}}`, http.StatusAccepted), &job); err != nil {
+		t.Fatal(err)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for job.Status == string(model.BulkJobRunning) && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+		if err := json.Unmarshal(send(http.MethodGet, "/jobs/"+job.ID.String(), "", http.StatusOK), &job); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if job.Status != string(model.BulkJobCompleted) || job.Total != 3 || job.Processed != 3 {
+		t.Fatalf("job ended as %s with %d of %d processed, want completed with 3 of 3", job.Status, job.Processed, job.Total)
+	}
+
+	var list ListNotificationsResponse
+	if err := json.Unmarshal(send(http.MethodGet, "/notifications?status=scheduled", "", http.StatusOK), &list); err != nil {
+		t.Fatal(err)
+	}
+	if len(list.Notifications) != 1 || list.Notifications[0].Tags[0] != "summer" {
+		t.Errorf("scheduled after the job: %+v, want only the summer notification", list.Notifications)
+	}
+}
+
+func TestIfMatch(t *testing.T) {
+	router := newTestRouter(t)
+	send := func(method, path, ifMatch, body string, wantStatus int) *httptest.ResponseRecorder {
+		t.Helper()
+		req := httptest.NewRequest(method, "/api/v1"+path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		if ifMatch != "" {
+			req.Header.Set("If-Match", ifMatch)
+		}
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		if rec.Code != wantStatus {
+			t.Fatalf("%s %s with If-Match %s: got status %d, want %d: %s", method, path, ifMatch, rec.Code, wantStatus, rec.Body.String())
+		}
+		return rec
+	}
+
+	rec := send(http.MethodPost, "/notifications", "", `{"recipient":"user@example.com","channel":"email","subject":"Hello","delay":"PT1H"}`, http.StatusCreated)
+	var created NotificationResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &created); err != nil {
+		t.Fatal(err)
+	}
+	if etag := rec.Header().Get("ETag"); etag != `"1"` || created.Version != 1 {
+		t.Fatalf("created with ETag %s and version %d, want \"1\" and 1", etag, created.Version)
+	}
+
+	path := "/notifications/" + created.ID.String()
+	reschedule := `{"scheduled_at":"` + time.Now().Add(2*time.Hour).UTC().Format(time.RFC3339) + `"}`
+	send(http.MethodPatch, path, `"2"`, reschedule, http.StatusPreconditionFailed)
+	send(http.MethodPatch, path, `1`, reschedule, http.StatusPreconditionFailed)
+	if etag := send(http.MethodPatch, path, `"1"`, reschedule, http.StatusOK).Header().Get("ETag"); etag != `"2"` {
+		t.Errorf("rescheduled with ETag %s, want \"2\"", etag)
+	}
+	send(http.MethodDelete, path, `"1"`, "", http.StatusPreconditionFailed)
+	send(http.MethodDelete, path, `"2"`, "", http.StatusNoContent)
+}
+
+func TestRecipientWindowRequiresAdmin(t *testing.T) {
+	router, keys := newAPIKeyTestRouter(t, newFakeEventStream())
+	userKey := newTestAPIKey(t, keys, "acme", "billing-service", model.ScopeCreate, model.ScopeRead, model.ScopeCancel)
+	adminKey := newTestAPIKey(t, keys, "acme", "acme-admin", model.ScopeAdmin)
+	window := `{"timezone":"Europe/Berlin","quiet_hours":{"start":"22:00","end":"07:00"}}`
+
+	tests := []struct {
+		name       string
+		method     string
+		body       string
+		key        string
+		wantStatus int
+	}{
+		{name: "set without admin", method: http.MethodPut, body: window, key: userKey, wantStatus: http.StatusForbidden},
+		{name: "set", method: http.MethodPut, body: window, key: adminKey, wantStatus: http.StatusOK},
+		{name: "get without admin", method: http.MethodGet, key: userKey, wantStatus: http.StatusOK},
+		{name: "delete without admin", method: http.MethodDelete, key: userKey, wantStatus: http.StatusForbidden},
+		{name: "delete", method: http.MethodDelete, key: adminKey, wantStatus: http.StatusNoContent},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "/api/v1/recipients/email/user@example.com/delivery-window", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set(apiKeyHeader, tt.key)
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+			if rec.Code != tt.wantStatus {
+				t.Errorf("got status %d, want %d: %s", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,192 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+	"github.com/ilindan-dev/delayed-notifier/internal/domain"
+	"net/http"
+	"reflect"
+	"strings"
+)
+
+// problemContentType is the media type of error responses, see RFC 7807.
+const problemContentType = "application/problem+json"
+
+// problemStatuses maps domain error codes to HTTP status codes.
+// Errors with a code missing here are reported as internal errors.
+var problemStatuses = map[domain.Code]int{
+	domain.CodeValidationFailed:      http.StatusBadRequest,
+	domain.CodeMalformedRequest:      http.StatusBadRequest,
+	domain.CodeInvalidID:             http.StatusBadRequest,
+	domain.CodeInvalidRecipient:      http.StatusBadRequest,
+	domain.CodeInvalidChannel:        http.StatusBadRequest,
+	domain.CodeInvalidCallback:       http.StatusBadRequest,
+	domain.CodeScheduledInPast:       http.StatusBadRequest,
+	domain.CodeScheduledTooFar:       http.StatusBadRequest,
+	domain.CodeInvalidTimezone:       http.StatusBadRequest,
+	domain.CodeInvalidLocalTime:      http.StatusBadRequest,
+	domain.CodeInvalidDelay:          http.StatusBadRequest,
+	domain.CodeInvalidPriority:       http.StatusBadRequest,
+	domain.CodeInvalidTags:           http.StatusBadRequest,
+	domain.CodeInvalidMetadata:       http.StatusBadRequest,
+	domain.CodeInvalidFilter:         http.StatusBadRequest,
+	domain.CodeInvalidDeliveryWindow: http.StatusBadRequest,
+	domain.CodeRequired:              http.StatusBadRequest,
+	domain.CodeTooLong:               http.StatusBadRequest,
+	domain.CodeBatchTooLarge:         http.StatusBadRequest,
+	domain.CodeInvalidPageToken:      http.StatusBadRequest,
+	domain.CodeInvalidEventID:        http.StatusBadRequest,
+	domain.CodeUnauthenticated:       http.StatusUnauthorized,
+	domain.CodeMissingScope:          http.StatusForbidden,
+	domain.CodeForeignTenant:         http.StatusForbidden,
+	domain.CodeOperatorOnly:          http.StatusForbidden,
+	domain.CodeNotFound:              http.StatusNotFound,
+	domain.CodeDuplicate:             http.StatusConflict,
+	domain.CodeNotCancellable:        http.StatusConflict,
+	domain.CodeNotScheduled:          http.StatusConflict,
+	domain.CodeInvalidTransition:     http.StatusConflict,
+	domain.CodeVersionConflict:       http.StatusPreconditionFailed,
+	domain.CodeQuotaExceeded:         http.StatusTooManyRequests,
+}
+
+var (
+	errInvalidID       = &domain.Error{Code: domain.CodeInvalidID, Field: "id", Message: "invalid notification ID format"}
+	errInvalidAPIKeyID = &domain.Error{Code: domain.CodeInvalidID, Field: "id", Message: "invalid api key ID format"}
+)
+
+func init() {
+	// Report invalid fields by the names clients use rather than the Go field names.
+	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		v.RegisterTagNameFunc(requestFieldName)
+	}
+}
+
+// newProblem creates a problem without a specific type, so its title is the status text.
+func newProblem(status int, code domain.Code, detail string) Problem {
+	return Problem{
+		Type:   "about:blank",
+		Title:  http.StatusText(status),
+		Status: status,
+		Detail: detail,
+		Code:   code,
+	}
+}
+
+// problemOf converts err to a problem. Errors without a known code become an internal
+// error whose detail is internalMsg, so that no internals leak to clients.
+func problemOf(err error, internalMsg string) Problem {
+	var invalid *domain.ValidationError
+	if errors.As(err, &invalid) {
+		p := newProblem(http.StatusBadRequest, domain.CodeValidationFailed, "the request has invalid fields")
+		for _, f := range invalid.Fields {
+			p.Errors = append(p.Errors, FieldError{Field: f.Field, Code: string(f.Code), Message: f.Message})
+		}
+		return p
+	}
+
+	var domainErr *domain.Error
+	if errors.As(err, &domainErr) {
+		if status, ok := problemStatuses[domainErr.Code]; ok {
+			p := newProblem(status, domainErr.Code, domainErr.Message)
+			if domainErr.Field != "" {
+				p.Errors = []FieldError{{Field: domainErr.Field, Code: string(domainErr.Code), Message: domainErr.Message}}
+			}
+			return p
+		}
+	}
+	return newProblem(http.StatusInternalServerError, domain.CodeInternal, internalMsg)
+}
+
+// bindingProblem converts an error binding a request body or query to a problem.
+// Validation errors list every invalid field.
+func bindingProblem(err error) Problem {
+	var invalid validator.ValidationErrors
+	if errors.As(err, &invalid) {
+		p := newProblem(http.StatusBadRequest, domain.CodeValidationFailed, "the request has invalid fields")
+		for _, fe := range invalid {
+			p.Errors = append(p.Errors, FieldError{Field: fieldPath(fe), Code: fe.Tag(), Message: fieldMessage(fe)})
+		}
+		return p
+	}
+
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) && typeErr.Field != "" {
+		msg := fmt.Sprintf("%s must not be a JSON %s", typeErr.Field, typeErr.Value)
+		p := newProblem(http.StatusBadRequest, domain.CodeValidationFailed, msg)
+		p.Errors = []FieldError{{Field: typeErr.Field, Code: "type", Message: msg}}
+		return p
+	}
+	return newProblem(http.StatusBadRequest, domain.CodeMalformedRequest, err.Error())
+}
+
+// writeProblem aborts the request with p as an application/problem+json response.
+func writeProblem(c *gin.Context, p Problem) {
+	p.Instance = c.Request.URL.Path
+	c.Header("Content-Type", problemContentType)
+	c.AbortWithStatusJSON(p.Status, p)
+}
+
+// fail responds with the problem of err. See problem.
+func (h *Handlers) fail(c *gin.Context, err error, internalMsg string) {
+	writeProblem(c, h.problem(err, internalMsg))
+}
+
+// problem converts err to a problem. Internal errors are logged with internalMsg.
+func (h *Handlers) problem(err error, internalMsg string) Problem {
+	p := problemOf(err, internalMsg)
+	if p.Status == http.StatusInternalServerError {
+		h.logger.Error().Err(err).Msg(internalMsg)
+	}
+	return p
+}
+
+// requestFieldName returns the name of a request field as sent by clients:
+// its JSON key or query parameter.
+func requestFieldName(f reflect.StructField) string {
+	for _, tag := range []string{"json", "form"} {
+		if name, _, _ := strings.Cut(f.Tag.Get(tag), ","); name != "" && name != "-" {
+			return name
+		}
+	}
+	return f.Name
+}
+
+// fieldPath returns the path of an invalid field without the request type,
+// e.g. notifications[1].recipient.
+func fieldPath(fe validator.FieldError) string {
+	_, path, ok := strings.Cut(fe.Namespace(), ".")
+	if !ok {
+		return fe.Field()
+	}
+	return path
+}
+
+// fieldMessage describes a failed validation rule in words.
+func fieldMessage(fe validator.FieldError) string {
+	field := fieldPath(fe)
+	switch fe.Tag() {
+	case "required":
+		return field + " is required"
+	case "oneof":
+		return field + " must be one of " + strings.ReplaceAll(fe.Param(), " ", ", ")
+	case "min", "max":
+		bound := "at least"
+		if fe.Tag() == "max" {
+			bound = "at most"
+		}
+		switch fe.Kind() {
+		case reflect.String:
+			return fmt.Sprintf("%s must be %s %s characters long", field, bound, fe.Param())
+		case reflect.Slice, reflect.Map:
+			return fmt.Sprintf("%s must hold %s %s items", field, bound, fe.Param())
+		default:
+			return fmt.Sprintf("%s must be %s %s", field, bound, fe.Param())
+		}
+	default:
+		return fmt.Sprintf("%s failed the %s check", field, fe.Tag())
+	}
+}
@@ -0,0 +1,134 @@
+package http
+
+import (
+	"encoding/json"
+	"github.com/ilindan-dev/delayed-notifier/internal/domain"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestErrorsAreReportedAsProblems(t *testing.T) {
+	router := newTestRouter(t)
+	future := time.Now().Add(time.Hour).UTC().Format(time.RFC3339)
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		body       string
+		wantStatus int
+		wantCode   domain.Code
+		wantFields []string
+	}{
+		{
+			name:       "missing fields",
+			method:     http.MethodPost,
+			path:       "/api/v1/notifications",
+			body:       `{"channel":"email"}`,
+			wantStatus: http.StatusBadRequest,
+			wantCode:   domain.CodeValidationFailed,
+			wantFields: []string{"recipient", "subject"},
+		},
+		{
+			name:       "missing schedule",
+			method:     http.MethodPost,
+			path:       "/api/v1/notifications",
+			body:       `{"recipient":"user@example.com","channel":"email","subject":"Hi"}`,
+			wantStatus: http.StatusBadRequest,
+			wantCode:   domain.CodeRequired,
+			wantFields: []string{"scheduled_at"},
+		},
+		{
+			name:       "unknown time zone",
+			method:     http.MethodPost,
+			path:       "/api/v1/notifications",
+			body:       `{"recipient":"user@example.com","channel":"email","subject":"Hi","local_time":"09:00","timezone":"Mars/Olympus"}`,
+			wantStatus: http.StatusBadRequest,
+			wantCode:   domain.CodeInvalidTimezone,
+			wantFields: []string{"timezone"},
+		},
+		{
+			name:       "invalid channel",
+			method:     http.MethodPost,
+			path:       "/api/v1/notifications",
+			body:       `{"recipient":"user@example.com","channel":"sms","subject":"Hi","scheduled_at":"` + future + `"}`,
+			wantStatus: http.StatusBadRequest,
+			wantCode:   domain.CodeInvalidChannel,
+			wantFields: []string{"channel"},
+		},
+		{
+			name:       "malformed body",
+			method:     http.MethodPost,
+			path:       "/api/v1/notifications",
+			body:       `{"recipient":`,
+			wantStatus: http.StatusBadRequest,
+			wantCode:   domain.CodeMalformedRequest,
+		},
+		{
+			name:       "invalid recipient",
+			method:     http.MethodPost,
+			path:       "/api/v1/notifications",
+			body:       `{"recipient":"not-a-number","channel":"telegram","subject":"Hi","scheduled_at":"` + future + `"}`,
+			wantStatus: http.StatusBadRequest,
+			wantCode:   domain.CodeInvalidRecipient,
+			wantFields: []string{"recipient"},
+		},
+		{
+			name:       "scheduled in the past",
+			method:     http.MethodPost,
+			path:       "/api/v1/notifications",
+			body:       `{"recipient":"user@example.com","channel":"email","subject":"Hi","scheduled_at":"2000-01-01T00:00:00Z"}`,
+			wantStatus: http.StatusBadRequest,
+			wantCode:   domain.CodeScheduledInPast,
+			wantFields: []string{"scheduled_at"},
+		},
+		{
+			name:       "invalid id",
+			method:     http.MethodGet,
+			path:       "/api/v1/notifications/not-a-uuid",
+			wantStatus: http.StatusBadRequest,
+			wantCode:   domain.CodeInvalidID,
+			wantFields: []string{"id"},
+		},
+		{
+			name:       "not found",
+			method:     http.MethodGet,
+			path:       "/api/v1/notifications/00000000-0000-0000-0000-000000000000",
+			wantStatus: http.StatusNotFound,
+			wantCode:   domain.CodeNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("got status %d, want %d: %s", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+			if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, problemContentType) {
+				t.Errorf("got content type %q, want %q", ct, problemContentType)
+			}
+			var p Problem
+			if err := json.Unmarshal(rec.Body.Bytes(), &p); err != nil {
+				t.Fatal(err)
+			}
+			if p.Code != tt.wantCode || p.Status != tt.wantStatus || p.Instance != tt.path {
+				t.Errorf("got problem %+v, want code %s", p, tt.wantCode)
+			}
+			var fields []string
+			for _, fe := range p.Errors {
+				fields = append(fields, fe.Field)
+			}
+			if strings.Join(fields, ",") != strings.Join(tt.wantFields, ",") {
+				t.Errorf("got invalid fields %v, want %v", fields, tt.wantFields)
+			}
+		})
+	}
+}
@@ -29,6 +29,12 @@ func NewServer(cfg *config.Config, handlers *Handlers, logger *zerolog.Logger) *
 	log.Info().Msg("registering api routes")
 	handlers.RegisterRoutes(router)
 
+	log.Info().Msg("registering api docs endpoints")
+	RegisterDocsRoutes(router)
+
+	log.Info().Msg("registering web admin ui")
+	RegisterUIRoutes(router)
+
 	log.Info().Msg("registering health check endpoint")
 	router.GET("/health", func(c *gin.Context) {
 		c.JSON(http.StatusOK, gin.H{"status": "ok"})
@@ -0,0 +1,20 @@
+package http
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/ilindan-dev/delayed-notifier/static"
+	"net/http"
+)
+
+// uiPath is where the web admin UI is served.
+const uiPath = "/ui"
+
+// RegisterUIRoutes serves the embedded web admin UI and redirects the root to it.
+// The page itself is public; it asks for an API key or token and sends it with
+// every API call, so the API enforces the usual scopes.
+func RegisterUIRoutes(router *gin.Engine) {
+	router.StaticFS(uiPath, http.FS(static.Files))
+	router.GET("/", func(c *gin.Context) {
+		c.Redirect(http.StatusFound, uiPath+"/")
+	})
+}
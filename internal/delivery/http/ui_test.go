@@ -0,0 +1,33 @@
+package http
+
+import (
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestUIIsServed(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	RegisterUIRoutes(router)
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	if rec.Code != http.StatusFound || rec.Header().Get("Location") != "/ui/" {
+		t.Errorf("GET /: got status %d redirecting to %q, want a redirect to /ui/", rec.Code, rec.Header().Get("Location"))
+	}
+
+	for path, want := range map[string]string{
+		"/ui/":              `<script src="js/app.js">`,
+		"/ui/js/app.js":     "function streamEvents(",
+		"/ui/css/style.css": ".badge",
+	} {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), want) {
+			t.Errorf("GET %s: got status %d without %q", path, rec.Code, want)
+		}
+	}
+}
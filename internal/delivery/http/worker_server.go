@@ -0,0 +1,46 @@
+package http
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/ilindan-dev/delayed-notifier/internal/config"
+	"github.com/ilindan-dev/delayed-notifier/internal/notifiers"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/rs/zerolog"
+	"net/http"
+)
+
+// WorkerServer is a small HTTP server exposing the worker's health and metrics.
+type WorkerServer struct {
+	*http.Server
+	logger zerolog.Logger
+}
+
+// NewWorkerServer creates the health and metrics server of the background worker.
+func NewWorkerServer(cfg *config.Config, dispatcher *notifiers.Dispatcher, logger *zerolog.Logger) *WorkerServer {
+	log := logger.With().Str("layer", "worker_http_server").Logger()
+	log.Info().Str("addr", cfg.Worker.HTTPPort).Msg("initializing worker http server")
+
+	gin.SetMode(cfg.HTTP.GinMode)
+	router := gin.New()
+	router.Use(gin.Recovery())
+
+	router.GET("/health", func(c *gin.Context) {
+		status := "ok"
+		breakers := make(map[string]string)
+		for name, state := range dispatcher.BreakerStates() {
+			breakers[name] = state.String()
+			if state != notifiers.BreakerClosed {
+				status = "degraded"
+			}
+		}
+		c.JSON(http.StatusOK, gin.H{"status": status, "circuit_breakers": breakers})
+	})
+	router.GET("/metrics", gin.WrapH(promhttp.Handler()))
+
+	server := &http.Server{
+		Addr:    cfg.Worker.HTTPPort,
+		Handler: router,
+	}
+
+	return &WorkerServer{server, log}
+}
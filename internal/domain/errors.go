@@ -0,0 +1,164 @@
+// Package domain defines the errors that the notifier reports to its clients.
+// Every error carries a stable, machine-readable code, so that clients can react
+// to it without parsing messages, and the delivery layers map codes to their own
+// status codes in one place.
+package domain
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Code identifies a kind of error. Codes are part of the API and never change.
+type Code string
+
+const (
+	// CodeValidationFailed means request fields failed validation; the problem lists them.
+	CodeValidationFailed Code = "validation_failed"
+	// CodeMalformedRequest means the request could not be decoded at all.
+	CodeMalformedRequest Code = "malformed_request"
+	CodeInvalidID        Code = "invalid_id"
+	CodeInvalidRecipient Code = "invalid_recipient"
+	CodeInvalidChannel   Code = "invalid_channel"
+	CodeInvalidCallback  Code = "invalid_callback_url"
+	CodeScheduledInPast  Code = "scheduled_in_past"
+	CodeScheduledTooFar  Code = "scheduled_too_far"
+	CodeInvalidTimezone  Code = "invalid_timezone"
+	CodeInvalidLocalTime Code = "invalid_local_time"
+	CodeInvalidDelay     Code = "invalid_delay"
+	CodeInvalidPriority  Code = "invalid_priority"
+	CodeInvalidTags      Code = "invalid_tags"
+	CodeInvalidMetadata  Code = "invalid_metadata"
+	// CodeInvalidFilter means a bulk job filter selects no or unchangeable notifications.
+	CodeInvalidFilter Code = "invalid_filter"
+	// CodeInvalidDeliveryWindow means a delivery window is malformed or never open.
+	CodeInvalidDeliveryWindow Code = "invalid_delivery_window"
+	// CodeRequired and CodeTooLong report a missing or overlong field.
+	CodeRequired         Code = "required"
+	CodeTooLong          Code = "too_long"
+	CodeBatchTooLarge    Code = "batch_too_large"
+	CodeInvalidPageToken Code = "invalid_page_token"
+	CodeInvalidEventID   Code = "invalid_event_id"
+	CodeUnauthenticated  Code = "unauthenticated"
+	CodeMissingScope     Code = "missing_scope"
+	CodeForeignTenant    Code = "foreign_tenant"
+	CodeOperatorOnly     Code = "operator_only"
+	CodeNotFound         Code = "not_found"
+	CodeDuplicate        Code = "duplicate"
+	CodeNotCancellable   Code = "not_cancellable"
+	CodeNotScheduled     Code = "not_scheduled"
+	// CodeInvalidTransition means a notification cannot move from its current status to the requested one.
+	CodeInvalidTransition Code = "invalid_transition"
+	// CodeVersionConflict means a notification changed since the version an update was based on.
+	CodeVersionConflict Code = "version_conflict"
+	CodeQuotaExceeded   Code = "quota_exceeded"
+	// CodeInternal is reported for every error without a code of its own.
+	CodeInternal Code = "internal"
+)
+
+// Error is an error with a stable code. Errors match by code, so
+// errors.Is(err, ErrInvalidRecipient) holds for every invalid recipient error,
+// whatever its message.
+type Error struct {
+	Code Code
+	// Field names the request field at fault, if any, e.g. "recipient".
+	Field   string
+	Message string
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// Is reports whether target is an *Error with the same code.
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Code == e.Code
+}
+
+// Withf returns a copy of e with a more specific message.
+func (e *Error) Withf(format string, args ...any) *Error {
+	copied := *e
+	copied.Message = fmt.Sprintf(format, args...)
+	return &copied
+}
+
+var (
+	// ErrInvalidRecipient is returned when a recipient does not fit the channel,
+	// e.g. a malformed email address or a non-numeric Telegram chat ID.
+	ErrInvalidRecipient = &Error{Code: CodeInvalidRecipient, Field: "recipient", Message: "invalid recipient"}
+	// ErrInvalidChannel is returned for an unknown delivery channel.
+	ErrInvalidChannel = &Error{Code: CodeInvalidChannel, Field: "channel", Message: "unknown channel"}
+	// ErrInvalidCallbackURL is returned when a callback URL is not an absolute http(s) URL.
+	ErrInvalidCallbackURL = &Error{Code: CodeInvalidCallback, Field: "callback_url", Message: "callback url must be an absolute http or https url"}
+	// ErrScheduledInPast is returned when a notification is scheduled for a time that has passed.
+	ErrScheduledInPast = &Error{Code: CodeScheduledInPast, Field: "scheduled_at", Message: "scheduled_at must not be in the past"}
+	// ErrScheduledTooFar is returned when a notification is scheduled beyond the configured horizon.
+	ErrScheduledTooFar = &Error{Code: CodeScheduledTooFar, Field: "scheduled_at", Message: "scheduled_at is too far in the future"}
+	// ErrInvalidTimezone is returned for a time zone that is not a known IANA name.
+	ErrInvalidTimezone = &Error{Code: CodeInvalidTimezone, Field: "timezone", Message: "unknown time zone"}
+	// ErrInvalidLocalTime is returned for a malformed local wall-clock time.
+	ErrInvalidLocalTime = &Error{Code: CodeInvalidLocalTime, Field: "local_time", Message: "invalid local time"}
+	// ErrInvalidDelay is returned for a delay that is not an ISO 8601 duration.
+	ErrInvalidDelay = &Error{Code: CodeInvalidDelay, Field: "delay", Message: "invalid delay"}
+	// ErrInvalidPriority is returned for an unknown notification priority.
+	ErrInvalidPriority = &Error{Code: CodeInvalidPriority, Field: "priority", Message: "unknown priority"}
+	// ErrInvalidTags is returned for too many tags, or an empty or overlong tag.
+	ErrInvalidTags = &Error{Code: CodeInvalidTags, Field: "tags", Message: "invalid tags"}
+	// ErrInvalidMetadata is returned for metadata that is not a JSON object or too large.
+	ErrInvalidMetadata = &Error{Code: CodeInvalidMetadata, Field: "metadata", Message: "invalid metadata"}
+	// ErrInvalidFilter is returned for a bulk job filter without criteria, or one selecting
+	// notifications that have left the scheduled status.
+	ErrInvalidFilter = &Error{Code: CodeInvalidFilter, Field: "filter", Message: "invalid filter"}
+	// ErrInvalidDeliveryWindow is returned for a malformed delivery window, or one that never allows delivery.
+	ErrInvalidDeliveryWindow = &Error{Code: CodeInvalidDeliveryWindow, Field: "delivery_window", Message: "invalid delivery window"}
+	// ErrNotCancellable is returned when cancelling a notification that has left the scheduled status.
+	ErrNotCancellable = &Error{Code: CodeNotCancellable, Message: "notification can no longer be cancelled"}
+	// ErrNotScheduled is returned when changing a notification that has left the scheduled status.
+	ErrNotScheduled = &Error{Code: CodeNotScheduled, Message: "notification is no longer scheduled"}
+	// ErrInvalidTransition is returned when a status change is not allowed by the state machine
+	// of notifications, or the notification left the expected status concurrently.
+	ErrInvalidTransition = &Error{Code: CodeInvalidTransition, Message: "illegal status transition"}
+	// ErrVersionConflict is returned when a notification changed since the version an update was based on.
+	ErrVersionConflict = &Error{Code: CodeVersionConflict, Message: "notification was changed concurrently"}
+	// ErrQuotaExceeded is returned when a tenant has used up its daily notification quota.
+	ErrQuotaExceeded = &Error{Code: CodeQuotaExceeded, Message: "daily notification quota exceeded"}
+)
+
+// ValidationError reports every invalid field of a request at once.
+// It matches errors.Is(err, &Error{Code: CodeValidationFailed}).
+type ValidationError struct {
+	Fields []*Error
+}
+
+func (e *ValidationError) Error() string {
+	msgs := make([]string, len(e.Fields))
+	for i, f := range e.Fields {
+		msgs[i] = f.Message
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// Is reports whether target is an *Error with CodeValidationFailed.
+func (e *ValidationError) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Code == CodeValidationFailed
+}
+
+// Add records an invalid field.
+func (e *ValidationError) Add(err *Error) {
+	e.Fields = append(e.Fields, err)
+}
+
+// Err returns nil if no field was added, the field's error if there is exactly one,
+// so that its code is reported, and e otherwise.
+func (e *ValidationError) Err() error {
+	switch len(e.Fields) {
+	case 0:
+		return nil
+	case 1:
+		return e.Fields[0]
+	default:
+		return e
+	}
+}
@@ -0,0 +1,102 @@
+package model
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+// Scope is a permission granted to an authenticated principal.
+type Scope string
+
+const (
+	ScopeCreate Scope = "create" // Create notifications.
+	ScopeRead   Scope = "read"   // Read own notifications.
+	ScopeCancel Scope = "cancel" // Cancel own notifications.
+	ScopeAdmin  Scope = "admin"  // Everything, on any notification, plus API key management.
+)
+
+// ValidScope reports whether s is a known scope.
+func ValidScope(s Scope) bool {
+	switch s {
+	case ScopeCreate, ScopeRead, ScopeCancel, ScopeAdmin:
+		return true
+	default:
+		return false
+	}
+}
+
+// Principal is the authenticated identity on whose behalf a request is made.
+type Principal struct {
+	ID       string // Recorded as the AuthorID of notifications created by the principal.
+	TenantID string // The tenant the principal acts in; it only ever sees this tenant's data.
+	Scopes   []Scope
+}
+
+// HasScope reports whether the principal was granted the scope. Admin implies every scope.
+func (p *Principal) HasScope(scope Scope) bool {
+	for _, s := range p.Scopes {
+		if s == scope || s == ScopeAdmin {
+			return true
+		}
+	}
+	return false
+}
+
+// IsAdmin reports whether the principal has the admin scope.
+func (p *Principal) IsAdmin() bool {
+	return p.HasScope(ScopeAdmin)
+}
+
+// IsOperator reports whether the principal is an admin of the default tenant,
+// which runs the service and may see state shared by all tenants.
+func (p *Principal) IsOperator() bool {
+	return p.TenantID == DefaultTenant && p.IsAdmin()
+}
+
+// CanAccess reports whether the principal may read or modify the notification:
+// admins may access any notification of their tenant, everyone else only their own.
+func (p *Principal) CanAccess(n *Notification) bool {
+	return p.canAccess(n.TenantID, n.AuthorID)
+}
+
+// CanSee reports whether the principal may receive the status event,
+// following the same rules as CanAccess.
+func (p *Principal) CanSee(e *StatusEvent) bool {
+	return p.canAccess(e.TenantID, e.AuthorID)
+}
+
+// CanFollow reports whether the principal may see the bulk job,
+// following the same rules as CanAccess.
+func (p *Principal) CanFollow(j *BulkJob) bool {
+	return p.canAccess(j.TenantID, j.AuthorID)
+}
+
+func (p *Principal) canAccess(tenantID string, authorID *string) bool {
+	if tenantID != p.TenantID {
+		return false
+	}
+	if p.IsAdmin() {
+		return true
+	}
+	return authorID != nil && *authorID == p.ID
+}
+
+// APIKey is a credential used to authenticate callers of the API.
+// Only the hash of the raw key is ever stored.
+type APIKey struct {
+	ID         uuid.UUID
+	TenantID   string // The tenant the key's principal acts in.
+	Name       string // Human-readable label, e.g. the name of the calling service.
+	Prefix     string // First characters of the raw key, safe to display.
+	Hash       string // Hex-encoded SHA-256 of the raw key.
+	OwnerID    string // The principal the key authenticates as.
+	Scopes     []Scope
+	CreatedAt  time.Time
+	LastUsedAt *time.Time
+	RevokedAt  *time.Time
+}
+
+// Principal returns the principal authenticated by the key.
+func (k *APIKey) Principal() *Principal {
+	return &Principal{ID: k.OwnerID, TenantID: k.TenantID, Scopes: k.Scopes}
+}
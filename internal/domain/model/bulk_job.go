@@ -0,0 +1,52 @@
+package model
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+// BulkAction is the change a bulk job applies to every notification it selects.
+type BulkAction string
+
+const (
+	BulkActionCancel     BulkAction = "cancel"
+	BulkActionReschedule BulkAction = "reschedule"
+)
+
+// BulkJobStatus represents the current state of a bulk job.
+type BulkJobStatus string
+
+const (
+	BulkJobRunning   BulkJobStatus = "running"   // The job is working through the notifications.
+	BulkJobCompleted BulkJobStatus = "completed" // The job has processed every selected notification.
+	BulkJobFailed    BulkJobStatus = "failed"    // The job stopped early; Error tells why.
+)
+
+// BulkJob cancels or reschedules every scheduled notification of a tenant matching a
+// filter, in the background. Its counters report the progress while it runs.
+type BulkJob struct {
+	ID       uuid.UUID
+	TenantID string
+	AuthorID *string // The principal who started the job, if the caller was authenticated.
+	Action   BulkAction
+	Status   BulkJobStatus
+	Filter   NotificationFilter
+
+	// ScheduledAt and Timezone are the new send time of a reschedule job. A nil Timezone
+	// keeps the time zones of the notifications.
+	ScheduledAt *time.Time
+	Timezone    *string
+
+	// Total is the number of notifications matching the filter when the job started.
+	// Notifications created later may be processed too, so Processed can exceed it.
+	Total int
+	// Processed counts the notifications changed so far, and Failed those changed
+	// that could not be queued again.
+	Processed int
+	Failed    int
+	Error     string
+
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+	FinishedAt *time.Time
+}
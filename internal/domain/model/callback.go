@@ -0,0 +1,59 @@
+package model
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+// CallbackEvent describes a terminal status transition of a notification,
+// delivered to the notification's callback URL.
+type CallbackEvent struct {
+	ID             uuid.UUID // Unique per event; lets receivers deduplicate redeliveries.
+	NotificationID uuid.UUID
+	TenantID       string
+	URL            string
+	Status         Status // The terminal status the notification reached; also the event type.
+	Attempts       int    // Send attempts made for the notification.
+	ScheduledAt    time.Time
+	SentAt         *time.Time
+	OccurredAt     time.Time
+	// Tags and Metadata are copied from the notification.
+	Tags     []string
+	Metadata map[string]any
+
+	// DeliveryAttempts counts the callback deliveries already tried for this event.
+	DeliveryAttempts int
+}
+
+// NewCallbackEvent creates the callback event for a notification that has just reached a terminal status.
+func NewCallbackEvent(n *Notification, url string) *CallbackEvent {
+	return &CallbackEvent{
+		ID:             uuid.New(),
+		NotificationID: n.ID,
+		TenantID:       n.TenantID,
+		URL:            url,
+		Status:         n.Status,
+		Attempts:       n.Attempts,
+		ScheduledAt:    n.ScheduledAt,
+		SentAt:         n.SentAt,
+		OccurredAt:     time.Now().UTC(),
+		Tags:           n.Tags,
+		Metadata:       n.Metadata,
+	}
+}
+
+// CallbackDelivery is a single attempt to deliver a CallbackEvent.
+type CallbackDelivery struct {
+	ID             uuid.UUID
+	EventID        uuid.UUID
+	NotificationID uuid.UUID
+	TenantID       string
+	Event          Status
+	URL            string
+	Attempt        int
+	StatusCode     *int // Nil if no response was received.
+	Error          *string
+	Duration       time.Duration
+	Delivered      bool
+	CreatedAt      time.Time
+}
@@ -0,0 +1,129 @@
+package model
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// ClockTime is a wall-clock time of day, in minutes after midnight.
+type ClockTime int
+
+// ParseClockTime parses a time of day such as "09:00".
+func ParseClockTime(s string) (ClockTime, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time of day %q, must look like 09:00", s)
+	}
+	return ClockTime(t.Hour()*60 + t.Minute()), nil
+}
+
+// String formats the time of day like "09:00".
+func (c ClockTime) String() string {
+	return fmt.Sprintf("%02d:%02d", c/60, c%60)
+}
+
+// ClockRange is a daily range of wall-clock times from Start up to, but excluding, End.
+// A range whose End is not after its Start wraps past midnight, e.g. 22:00–07:00.
+type ClockRange struct {
+	Start ClockTime
+	End   ClockTime
+}
+
+// Contains reports whether the range contains the time of day c.
+func (r ClockRange) Contains(c ClockTime) bool {
+	if r.Start < r.End {
+		return c >= r.Start && c < r.End
+	}
+	return c >= r.Start || c < r.End
+}
+
+// weekdayNames are the short names of the weekdays used by the APIs, indexed by time.Weekday.
+var weekdayNames = [...]string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}
+
+// ParseWeekday parses the short name of a weekday such as "mon".
+func ParseWeekday(s string) (time.Weekday, error) {
+	for d, name := range weekdayNames {
+		if strings.EqualFold(s, name) {
+			return time.Weekday(d), nil
+		}
+	}
+	return 0, fmt.Errorf("invalid weekday %q, must be one of %s", s, strings.Join(weekdayNames[:], ", "))
+}
+
+// WeekdayName returns the short name of a weekday such as "mon".
+func WeekdayName(d time.Weekday) string {
+	return weekdayNames[d]
+}
+
+// DeliveryWindow restricts when a notification may be delivered, in the wall-clock time
+// of a time zone, e.g. Monday to Friday from 09:00 to 18:00 in Europe/Berlin.
+// Notifications falling outside the window are deferred to its next opening.
+type DeliveryWindow struct {
+	// Timezone is the IANA time zone the window is defined in.
+	Timezone string
+	// Days are the weekdays on which delivery is allowed; empty means every day.
+	// The weekday is that of the local date, also within hours that wrap past midnight.
+	Days []time.Weekday
+	// Hours optionally restricts delivery to a daily range of times.
+	Hours *ClockRange
+	// QuietHours optionally forbids delivery during a daily range of times.
+	QuietHours *ClockRange
+}
+
+// Allows reports whether delivery is allowed at t.
+func (w *DeliveryWindow) Allows(t time.Time) bool {
+	return w.allows(t.In(w.location()))
+}
+
+// Next returns the earliest instant at or after t at which delivery is allowed.
+// It returns false if the window never allows delivery.
+func (w *DeliveryWindow) Next(t time.Time) (time.Time, bool) {
+	loc := w.location()
+	local := t.In(loc)
+	if w.allows(local) {
+		return t, true
+	}
+
+	// Delivery can only become allowed at midnight, when hours start or when quiet
+	// hours end. The window repeats every week, so a week and a day are enough.
+	openings := []ClockTime{0}
+	if w.Hours != nil {
+		openings = append(openings, w.Hours.Start)
+	}
+	if w.QuietHours != nil {
+		openings = append(openings, w.QuietHours.End)
+	}
+	slices.Sort(openings)
+	for day := 0; day <= 8; day++ {
+		date := time.Date(local.Year(), local.Month(), local.Day()+day, 0, 0, 0, 0, time.UTC)
+		for _, opening := range openings {
+			at := InZone(date.Add(time.Duration(opening)*time.Minute), loc)
+			if at.After(t) && w.allows(at) {
+				return at, true
+			}
+		}
+	}
+	return time.Time{}, false
+}
+
+// allows reports whether delivery is allowed at the local time t.
+func (w *DeliveryWindow) allows(t time.Time) bool {
+	if len(w.Days) > 0 && !slices.Contains(w.Days, t.Weekday()) {
+		return false
+	}
+	clock := ClockTime(t.Hour()*60 + t.Minute())
+	if w.Hours != nil && !w.Hours.Contains(clock) {
+		return false
+	}
+	return w.QuietHours == nil || !w.QuietHours.Contains(clock)
+}
+
+// location returns the time zone of the window, or UTC if it is unknown.
+func (w *DeliveryWindow) location() *time.Location {
+	if loc, err := time.LoadLocation(w.Timezone); err == nil {
+		return loc
+	}
+	return time.UTC
+}
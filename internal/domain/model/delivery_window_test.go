@@ -0,0 +1,54 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDeliveryWindowNext(t *testing.T) {
+	businessHours := &DeliveryWindow{
+		Timezone: "Europe/Berlin",
+		Days:     []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday},
+		Hours:    &ClockRange{Start: 9 * 60, End: 18 * 60},
+	}
+	quietNights := &DeliveryWindow{
+		Timezone:   "Europe/Berlin",
+		QuietHours: &ClockRange{Start: 22 * 60, End: 7 * 60},
+	}
+
+	tests := []struct {
+		name   string
+		window *DeliveryWindow
+		at     string
+		want   string
+	}{
+		// 2026-01-14 is a Wednesday; Berlin is at UTC+1.
+		{name: "within business hours", window: businessHours, at: "2026-01-14T10:00:00Z", want: "2026-01-14T10:00:00Z"},
+		{name: "before business hours", window: businessHours, at: "2026-01-14T05:00:00Z", want: "2026-01-14T08:00:00Z"},
+		{name: "after business hours", window: businessHours, at: "2026-01-14T17:00:00Z", want: "2026-01-15T08:00:00Z"},
+		{name: "friday evening", window: businessHours, at: "2026-01-16T17:30:00Z", want: "2026-01-19T08:00:00Z"},
+		{name: "outside quiet hours", window: quietNights, at: "2026-01-14T20:59:00Z", want: "2026-01-14T20:59:00Z"},
+		{name: "during quiet hours", window: quietNights, at: "2026-01-14T21:00:00Z", want: "2026-01-15T06:00:00Z"},
+		{name: "after midnight in quiet hours", window: quietNights, at: "2026-01-15T02:00:00Z", want: "2026-01-15T06:00:00Z"},
+		// Clocks go forward on 29 March 2026, so 07:00 is at UTC+2.
+		{name: "quiet hours across transition", window: quietNights, at: "2026-03-28T23:00:00Z", want: "2026-03-29T05:00:00Z"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			at, _ := time.Parse(time.RFC3339, tt.at)
+			got, ok := tt.window.Next(at)
+			if !ok || got.UTC().Format(time.RFC3339) != tt.want {
+				t.Errorf("Next(%s) = %s, %v, want %s", tt.at, got.UTC().Format(time.RFC3339), ok, tt.want)
+			}
+		})
+	}
+
+	never := &DeliveryWindow{
+		Timezone:   "UTC",
+		Hours:      &ClockRange{Start: 9 * 60, End: 18 * 60},
+		QuietHours: &ClockRange{Start: 8 * 60, End: 19 * 60},
+	}
+	if got, ok := never.Next(time.Now()); ok {
+		t.Errorf("Next of a window that is always closed = %s, want false", got)
+	}
+}
@@ -0,0 +1,30 @@
+package model
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+// StatusEvent records a change of a notification's status or attempts,
+// streamed to live subscribers.
+type StatusEvent struct {
+	ID             string // Assigned by the event stream; orders events and allows resuming after it.
+	NotificationID uuid.UUID
+	TenantID       string
+	AuthorID       *string
+	Status         Status
+	Attempts       int
+	OccurredAt     time.Time
+}
+
+// NewStatusEvent creates the status event describing the notification's current state.
+func NewStatusEvent(n *Notification) *StatusEvent {
+	return &StatusEvent{
+		NotificationID: n.ID,
+		TenantID:       n.TenantID,
+		AuthorID:       n.AuthorID,
+		Status:         n.Status,
+		Attempts:       n.Attempts,
+		OccurredAt:     time.Now().UTC(),
+	}
+}
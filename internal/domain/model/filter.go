@@ -0,0 +1,35 @@
+package model
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+// NotificationFilter selects notifications in list queries. Nil fields match everything.
+type NotificationFilter struct {
+	Status        *Status
+	Channel       *Channel
+	AuthorID      *string
+	ScheduledFrom *time.Time // Inclusive.
+	ScheduledTo   *time.Time // Exclusive.
+	// Search matches a case-insensitive substring of the subject or email recipient,
+	// or a Telegram chat ID exactly.
+	Search *string
+	// Tags matches notifications carrying all of the tags.
+	Tags []string
+	// Metadata matches notifications whose metadata contains the object, i.e. has
+	// all of its keys with equal values, recursively.
+	Metadata map[string]any
+}
+
+// Cursor marks the position of a notification in the newest-first list order.
+// A page continues after the cursor of the previous page's last notification.
+type Cursor struct {
+	CreatedAt time.Time
+	ID        uuid.UUID
+}
+
+// CursorOf returns the list position of the notification.
+func CursorOf(n *Notification) Cursor {
+	return Cursor{CreatedAt: n.CreatedAt, ID: n.ID}
+}
@@ -0,0 +1,46 @@
+package model
+
+import "time"
+
+// InZone returns the instant at which the clocks in loc show the wall-clock date and
+// time of wall; wall's own location is ignored.
+//
+// Unlike time.Date, the result is well-defined around daylight saving transitions:
+// a wall-clock time skipped by a forward transition is moved forward by the length
+// of the gap, e.g. 02:30 becomes 03:30, and a wall-clock time that occurs twice
+// during a backward transition resolves to its first occurrence.
+func InZone(wall time.Time, loc *time.Location) time.Time {
+	// The wall-clock time read as if it were UTC. Subtracting a UTC offset of loc
+	// yields a candidate instant, which is valid if loc shows the wall-clock time then.
+	asUTC := time.Date(wall.Year(), wall.Month(), wall.Day(), wall.Hour(), wall.Minute(), wall.Second(), wall.Nanosecond(), time.UTC)
+
+	// Zones change their offset at most once a day, so the offsets a day before and
+	// after are the only ones in effect around the wall-clock time.
+	_, before := asUTC.Add(-24 * time.Hour).In(loc).Zone()
+	_, after := asUTC.Add(24 * time.Hour).In(loc).Zone()
+
+	var resolved time.Time
+	for _, offset := range []int{before, after} {
+		candidate := asUTC.Add(-time.Duration(offset) * time.Second)
+		if !sameWallClock(candidate.In(loc), asUTC) {
+			continue
+		}
+		if resolved.IsZero() || candidate.Before(resolved) {
+			resolved = candidate
+		}
+	}
+	if resolved.IsZero() {
+		// The wall-clock time falls into a gap. Reading it with the offset in effect
+		// before the gap moves it forward by the length of the gap.
+		resolved = asUTC.Add(-time.Duration(before) * time.Second)
+	}
+	return resolved.In(loc)
+}
+
+// sameWallClock reports whether a and b show the same date and time of day.
+func sameWallClock(a, b time.Time) bool {
+	ay, am, ad := a.Date()
+	by, bm, bd := b.Date()
+	return ay == by && am == bm && ad == bd &&
+		a.Hour() == b.Hour() && a.Minute() == b.Minute() && a.Second() == b.Second() && a.Nanosecond() == b.Nanosecond()
+}
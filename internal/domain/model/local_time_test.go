@@ -0,0 +1,47 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+func TestInZone(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	wall := func(s string) time.Time {
+		t.Helper()
+		w, err := time.Parse("2006-01-02T15:04", s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return w
+	}
+
+	tests := []struct {
+		name string
+		wall string
+		want string
+	}{
+		{name: "winter", wall: "2026-01-15T09:00", want: "2026-01-15T08:00:00Z"},
+		{name: "summer", wall: "2026-07-15T09:00", want: "2026-07-15T07:00:00Z"},
+		{name: "day of forward transition", wall: "2026-03-29T09:00", want: "2026-03-29T07:00:00Z"},
+		// 02:30 does not exist on 29 March; it is moved forward to 03:30 CEST.
+		{name: "skipped by forward transition", wall: "2026-03-29T02:30", want: "2026-03-29T01:30:00Z"},
+		// 02:30 occurs twice on 25 October; the first occurrence, in CEST, is used.
+		{name: "repeated by backward transition", wall: "2026-10-25T02:30", want: "2026-10-25T00:30:00Z"},
+		{name: "after backward transition", wall: "2026-10-25T03:30", want: "2026-10-25T02:30:00Z"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := InZone(wall(tt.wall), berlin)
+			if got.UTC().Format(time.RFC3339) != tt.want {
+				t.Errorf("InZone(%s) = %s, want %s", tt.wall, got.UTC().Format(time.RFC3339), tt.want)
+			}
+			if got.Location() != berlin {
+				t.Errorf("got location %s, want %s", got.Location(), berlin)
+			}
+		})
+	}
+}
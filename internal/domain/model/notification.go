@@ -2,6 +2,8 @@ package model
 
 import (
 	"github.com/google/uuid"
+	"slices"
+	"strconv"
 	"time"
 )
 
@@ -13,16 +15,64 @@ const (
 	ChannelTelegram Channel = "telegram"
 )
 
+// DefaultTenant is the tenant of notifications and callers not bound to any specific tenant.
+const DefaultTenant = "default"
+
+// AllChannels lists every supported delivery channel.
+var AllChannels = []Channel{ChannelEmail, ChannelTelegram}
+
 // Status represents the current state of a notification.
 type Status string
 
 const (
-	StatusScheduled Status = "scheduled" // The notification is scheduled for a future time.
-	StatusSent      Status = "sent"      // The notification has been successfully sent.
-	StatusFailed    Status = "failed"    // The notification failed to send after all retry attempts.
-	StatusCancelled Status = "cancelled" // The notification was cancelled by a user request.
+	StatusScheduled  Status = "scheduled"  // The notification is scheduled for a future time.
+	StatusProcessing Status = "processing" // A worker has claimed the notification and is sending it.
+	StatusSent       Status = "sent"       // The notification has been successfully sent.
+	StatusFailed     Status = "failed"     // The notification failed to send after all retry attempts.
+	StatusCancelled  Status = "cancelled"  // The notification was cancelled by a user request.
+	StatusExpired    Status = "expired"    // The notification was picked up too late after its scheduled time.
+)
+
+// transitions lists the statuses a notification may move to from each status.
+// A processing notification goes back to scheduled when its send is retried or deferred.
+var transitions = map[Status][]Status{
+	StatusScheduled:  {StatusProcessing, StatusCancelled, StatusExpired},
+	StatusProcessing: {StatusSent, StatusFailed, StatusScheduled},
+}
+
+// Priority is the delivery priority of a notification. Notifications of a higher
+// priority overtake those of a lower one waiting to be processed.
+type Priority string
+
+const (
+	PriorityLow      Priority = "low"
+	PriorityNormal   Priority = "normal"
+	PriorityHigh     Priority = "high"
+	PriorityCritical Priority = "critical"
 )
 
+// AllPriorities lists every priority, from lowest to highest.
+var AllPriorities = []Priority{PriorityLow, PriorityNormal, PriorityHigh, PriorityCritical}
+
+// Level returns the rank of the priority in AllPriorities, from 0 for low to 3 for critical.
+// Unknown priorities, e.g. of notifications queued before priorities existed, rank as normal.
+func (p Priority) Level() int {
+	if i := slices.Index(AllPriorities, p); i >= 0 {
+		return i
+	}
+	return slices.Index(AllPriorities, PriorityNormal)
+}
+
+// IsTerminal reports whether no further transitions are possible from the status.
+func (s Status) IsTerminal() bool {
+	return len(transitions[s]) == 0
+}
+
+// CanTransitionTo reports whether a notification may move from status s to next.
+func (s Status) CanTransitionTo(next Status) bool {
+	return slices.Contains(transitions[s], next)
+}
+
 // EmailDetails contains recipient information specific to the email channel.
 type EmailDetails struct {
 	To string // The recipient's email address.
@@ -37,13 +87,34 @@ type TelegramDetails struct {
 // It is technology-agnostic and does not contain any DB or JSON tags.
 type Notification struct {
 	ID       uuid.UUID
+	TenantID string // The tenant owning the notification; all lookups are scoped to it.
 	Subject  string // The subject or title of the notification.
 	Message  string // The main content/body of the notification.
 	Channel  Channel
 	Status   Status
+	Priority Priority
 	Attempts int
+	// Version is incremented by every update of the notification. Updates require the
+	// version they were based on, so that they fail instead of overwriting newer changes.
+	Version  int
 	AuthorID *string // Optional: ID of the user or system that created the notification.
 
+	// CallbackURL optionally receives a signed event after each terminal status transition.
+	CallbackURL *string
+	// Timezone is the IANA time zone the notification was scheduled in, e.g. "Europe/Berlin",
+	// or nil if it was scheduled with an absolute time only.
+	Timezone *string
+	// DeliveryWindow optionally restricts when the notification may be delivered.
+	// Without one, the window of the recipient applies, if any.
+	DeliveryWindow *DeliveryWindow
+	// Tags and Metadata correlate the notification with the caller's own entities, e.g.
+	// a campaign tag or an order ID. Metadata is an arbitrary JSON object.
+	Tags     []string
+	Metadata map[string]any
+	// RequestHash identifies the request that created the notification with an idempotency
+	// key, so that the key cannot be reused for another request. It is empty without a key.
+	RequestHash string
+
 	// Recipient details are mutually exclusive based on the Channel.
 	Email    *EmailDetails
 	Telegram *TelegramDetails
@@ -54,14 +125,43 @@ type Notification struct {
 	UpdatedAt   time.Time
 }
 
+// Recipient returns the channel-specific recipient address as a string,
+// or an empty string if the recipient details are missing.
+func (n *Notification) Recipient() string {
+	switch n.Channel {
+	case ChannelEmail:
+		if n.Email != nil {
+			return n.Email.To
+		}
+	case ChannelTelegram:
+		if n.Telegram != nil {
+			return strconv.FormatInt(n.Telegram.ChatID, 10)
+		}
+	}
+	return ""
+}
+
+// LocalScheduledAt returns the scheduled time in the notification's time zone, or in
+// UTC if it has none or the zone is unknown.
+func (n *Notification) LocalScheduledAt() time.Time {
+	if n.Timezone != nil {
+		if loc, err := time.LoadLocation(*n.Timezone); err == nil {
+			return n.ScheduledAt.In(loc)
+		}
+	}
+	return n.ScheduledAt.UTC()
+}
+
 // NewEmailNotification is a factory function to create a new notification for the email channel.
-func NewEmailNotification(recipientEmail, subject, message string, scheduledAt time.Time, authorID *string) *Notification {
+func NewEmailNotification(tenantID, recipientEmail, subject, message string, scheduledAt time.Time, authorID *string) *Notification {
 	return &Notification{
 		ID:          uuid.New(),
+		TenantID:    tenantID,
 		Subject:     subject,
 		Message:     message,
 		Channel:     ChannelEmail,
 		Status:      StatusScheduled,
+		Priority:    PriorityNormal,
 		Attempts:    0,
 		AuthorID:    authorID,
 		Email:       &EmailDetails{To: recipientEmail},
@@ -72,13 +172,15 @@ func NewEmailNotification(recipientEmail, subject, message string, scheduledAt t
 }
 
 // NewTelegramNotification is a factory function to create a new notification for the telegram channel.
-func NewTelegramNotification(chatID int64, subject, message string, scheduledAt time.Time, authorID *string) *Notification {
+func NewTelegramNotification(tenantID string, chatID int64, subject, message string, scheduledAt time.Time, authorID *string) *Notification {
 	return &Notification{
 		ID:          uuid.New(),
+		TenantID:    tenantID,
 		Subject:     subject,
 		Message:     message,
 		Channel:     ChannelTelegram,
 		Status:      StatusScheduled,
+		Priority:    PriorityNormal,
 		Attempts:    0,
 		AuthorID:    authorID,
 		Telegram:    &TelegramDetails{ChatID: chatID},
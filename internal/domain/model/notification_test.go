@@ -0,0 +1,38 @@
+package model
+
+import "testing"
+
+func TestStatusTransitions(t *testing.T) {
+	tests := []struct {
+		from, to Status
+		want     bool
+	}{
+		{StatusScheduled, StatusProcessing, true},
+		{StatusScheduled, StatusCancelled, true},
+		{StatusScheduled, StatusExpired, true},
+		{StatusScheduled, StatusSent, false},
+		{StatusProcessing, StatusSent, true},
+		{StatusProcessing, StatusFailed, true},
+		{StatusProcessing, StatusScheduled, true},
+		{StatusProcessing, StatusCancelled, false},
+		{StatusProcessing, StatusProcessing, false},
+		{StatusSent, StatusScheduled, false},
+		{StatusCancelled, StatusProcessing, false},
+	}
+	for _, tt := range tests {
+		if got := tt.from.CanTransitionTo(tt.to); got != tt.want {
+			t.Errorf("%s -> %s: got %v, want %v", tt.from, tt.to, got, tt.want)
+		}
+	}
+
+	for _, s := range []Status{StatusSent, StatusFailed, StatusCancelled, StatusExpired} {
+		if !s.IsTerminal() {
+			t.Errorf("%s: got non-terminal, want terminal", s)
+		}
+	}
+	for _, s := range []Status{StatusScheduled, StatusProcessing} {
+		if s.IsTerminal() {
+			t.Errorf("%s: got terminal, want non-terminal", s)
+		}
+	}
+}
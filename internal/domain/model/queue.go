@@ -0,0 +1,20 @@
+package model
+
+import "time"
+
+// QueueStats describes the current state of a broker queue.
+type QueueStats struct {
+	Name      string
+	Messages  int
+	Consumers int
+}
+
+// DeadLetter is a message the worker gave up on, kept in the dead letter queue for inspection.
+type DeadLetter struct {
+	MessageID string
+	// Reason explains why the message was dead-lettered.
+	Reason string
+	DeadAt time.Time
+	// Body is the original message body, which is not necessarily valid JSON.
+	Body []byte
+}
@@ -0,0 +1,25 @@
+package repository
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/ilindan-dev/delayed-notifier/internal/domain/model"
+)
+
+// APIKeyRepository defines the contract for API key persistence.
+type APIKeyRepository interface {
+	// Save persists a new API key.
+	Save(ctx context.Context, key *model.APIKey) (*model.APIKey, error)
+
+	// GetActiveByHash retrieves a non-revoked API key by the hash of its raw value.
+	GetActiveByHash(ctx context.Context, hash string) (*model.APIKey, error)
+
+	// List returns all API keys of a tenant, including revoked ones.
+	List(ctx context.Context, tenantID string) ([]*model.APIKey, error)
+
+	// Revoke marks an API key of a tenant as revoked.
+	Revoke(ctx context.Context, tenantID string, id uuid.UUID) error
+
+	// Touch records that an API key has just been used.
+	Touch(ctx context.Context, id uuid.UUID) error
+}
@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/ilindan-dev/delayed-notifier/internal/domain/model"
+)
+
+// BulkJobStore defines the contract for keeping track of bulk jobs and their progress.
+type BulkJobStore interface {
+	// Save stores a job, replacing any previous state of it.
+	Save(ctx context.Context, job *model.BulkJob) error
+
+	// Get returns a job of a tenant, or ErrNotFound if there is no such job or it has expired.
+	Get(ctx context.Context, tenantID string, id uuid.UUID) (*model.BulkJob, error)
+}
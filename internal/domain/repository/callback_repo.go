@@ -0,0 +1,26 @@
+package repository
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/ilindan-dev/delayed-notifier/internal/domain/model"
+	"time"
+)
+
+// CallbackDeliveryRepository defines the contract for the callback delivery log.
+type CallbackDeliveryRepository interface {
+	// Save records a single callback delivery attempt.
+	Save(ctx context.Context, d *model.CallbackDelivery) error
+
+	// ListByNotification returns the delivery attempts of a tenant's notification, oldest first.
+	ListByNotification(ctx context.Context, tenantID string, notificationID uuid.UUID) ([]*model.CallbackDelivery, error)
+}
+
+// CallbackQueue defines the contract for queueing callback events for delivery.
+type CallbackQueue interface {
+	// PublishCallback queues an event for immediate delivery.
+	PublishCallback(ctx context.Context, e *model.CallbackEvent) error
+
+	// PublishCallbackRetry queues an event for another delivery attempt after a delay.
+	PublishCallbackRetry(ctx context.Context, e *model.CallbackEvent, retryDelay time.Duration) error
+}
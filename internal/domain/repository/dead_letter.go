@@ -0,0 +1,21 @@
+package repository
+
+import (
+	"context"
+	"github.com/ilindan-dev/delayed-notifier/internal/domain/model"
+)
+
+// DeadLetterQueue defines the contract for parking messages the worker gives up on.
+type DeadLetterQueue interface {
+	// PublishDeadLetter stores a copy of a message body together with the reason it was given up on.
+	PublishDeadLetter(ctx context.Context, body []byte, reason string) error
+}
+
+// QueueInspector defines the contract for inspecting the broker, for operators.
+type QueueInspector interface {
+	// Stats returns the depth and consumer count of every queue of the service.
+	Stats(ctx context.Context) ([]model.QueueStats, error)
+
+	// PeekDeadLetters returns up to limit of the oldest dead letters without removing them.
+	PeekDeadLetters(ctx context.Context, limit int) ([]model.DeadLetter, error)
+}
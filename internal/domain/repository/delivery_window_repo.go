@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"context"
+	"github.com/ilindan-dev/delayed-notifier/internal/domain/model"
+)
+
+// DeliveryWindowRepository defines the contract for persisting the delivery windows of recipients.
+// Recipients are identified by their channel and canonical address, see model.Notification.Recipient.
+type DeliveryWindowRepository interface {
+	// Get returns the delivery window of a tenant's recipient, or ErrNotFound if it has none.
+	Get(ctx context.Context, tenantID string, channel model.Channel, recipient string) (*model.DeliveryWindow, error)
+
+	// Put sets the delivery window of a tenant's recipient, replacing any previous one.
+	Put(ctx context.Context, tenantID string, channel model.Channel, recipient string, window *model.DeliveryWindow) error
+
+	// Delete removes the delivery window of a tenant's recipient, or returns ErrNotFound if it has none.
+	Delete(ctx context.Context, tenantID string, channel model.Channel, recipient string) error
+}
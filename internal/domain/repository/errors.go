@@ -1,9 +1,12 @@
 package repository
 
-import "errors"
+import "github.com/ilindan-dev/delayed-notifier/internal/domain"
 
 // ErrNotFound is returned when a record is not found in the database.
-var ErrNotFound = errors.New("record not found")
+var ErrNotFound error = &domain.Error{Code: domain.CodeNotFound, Message: "record not found"}
+
+// ErrConflict is returned when a record changed since the version an update was based on.
+var ErrConflict error = &domain.Error{Code: domain.CodeVersionConflict, Message: "record was changed concurrently"}
 
 // ErrDuplicateRecord is returned when an insert operation violates a UNIQUE constraint.
-var ErrDuplicateRecord = errors.New("duplicate record")
+var ErrDuplicateRecord error = &domain.Error{Code: domain.CodeDuplicate, Message: "duplicate record"}
@@ -0,0 +1,25 @@
+package repository
+
+import (
+	"context"
+	"github.com/ilindan-dev/delayed-notifier/internal/domain"
+	"github.com/ilindan-dev/delayed-notifier/internal/domain/model"
+	"time"
+)
+
+// ErrInvalidEventID is returned when a cursor is not a valid event ID.
+var ErrInvalidEventID error = &domain.Error{Code: domain.CodeInvalidEventID, Field: "last_event_id", Message: "invalid event id"}
+
+// StatusEventStream defines the contract for a per-tenant, replayable stream of status events.
+type StatusEventStream interface {
+	// Publish appends an event to its tenant's stream and sets the event's ID.
+	Publish(ctx context.Context, e *model.StatusEvent) error
+
+	// LatestID returns the ID of the newest event of the tenant, to start reading after it.
+	LatestID(ctx context.Context, tenantID string) (string, error)
+
+	// ReadAfter returns the tenant's events following afterID, oldest first, waiting
+	// up to block for new ones. An empty result means no event arrived in time.
+	// A non-positive block returns immediately.
+	ReadAfter(ctx context.Context, tenantID, afterID string, block time.Duration) ([]*model.StatusEvent, error)
+}
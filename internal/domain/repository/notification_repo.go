@@ -12,26 +12,62 @@ type NotificationRepository interface {
 	// Save persists a new notification.
 	Save(ctx context.Context, n *model.Notification) (*model.Notification, error)
 
-	// GetByID retrieves a notification by its unique ID.
-	GetByID(ctx context.Context, id uuid.UUID) (*model.Notification, error)
+	// GetByID retrieves a notification of a tenant by its unique ID.
+	GetByID(ctx context.Context, tenantID string, id uuid.UUID) (*model.Notification, error)
 
-	// Update updates the mutable fields of a notification, primarily its status and attempts count.
-	Update(ctx context.Context, n *model.Notification) error
+	// Transition moves a notification of its tenant from status from to n.Status and
+	// stores its attempts count and sent time, if it still has version n.Version. On
+	// success, n.Version is set to the new version. It returns ErrNotFound if there is
+	// no such notification, and ErrConflict if it changed since version n.Version or
+	// is not in status from.
+	Transition(ctx context.Context, n *model.Notification, from model.Status) error
 
-	// Delete cancels a scheduled notification.
-	Delete(ctx context.Context, id uuid.UUID) error
+	// Delete cancels a scheduled notification of a tenant and returns it. A non-nil version
+	// must match the notification's. It returns ErrNotFound if there is no such notification,
+	// it is no longer scheduled or its version differs.
+	Delete(ctx context.Context, tenantID string, id uuid.UUID, version *int) (*model.Notification, error)
+
+	// Reschedule moves a scheduled notification of a tenant to a new time and resets its attempts.
+	// A non-nil timezone replaces the notification's time zone, and a non-nil version must
+	// match the notification's. It returns ErrNotFound if there is no such notification,
+	// it is no longer scheduled or its version differs.
+	Reschedule(ctx context.Context, tenantID string, id uuid.UUID, scheduledAt time.Time, timezone *string, version *int) (*model.Notification, error)
+
+	// List returns up to limit notifications of a tenant matching the filter, newest first,
+	// starting after the given cursor if it is not nil.
+	List(ctx context.Context, tenantID string, filter model.NotificationFilter, after *model.Cursor, limit int) ([]*model.Notification, error)
+
+	// Count returns the number of notifications of a tenant matching the filter.
+	Count(ctx context.Context, tenantID string, filter model.NotificationFilter) (int, error)
+
+	// CancelMany cancels those of the given notifications of a tenant that are still
+	// scheduled, like Delete, and returns them. The others are skipped.
+	CancelMany(ctx context.Context, tenantID string, ids []uuid.UUID) ([]*model.Notification, error)
+
+	// RescheduleMany reschedules those of the given notifications of a tenant that are
+	// still scheduled, like Reschedule, and returns them. The others are skipped.
+	RescheduleMany(ctx context.Context, tenantID string, ids []uuid.UUID, scheduledAt time.Time, timezone *string) ([]*model.Notification, error)
 }
 
 // NotificationCache defines the contract for a caching layer.
 type NotificationCache interface {
-	// Get retrieves an item from the cache.
-	Get(ctx context.Context, id uuid.UUID) (*model.Notification, error)
+	// Get retrieves an item of a tenant from the cache.
+	Get(ctx context.Context, tenantID string, id uuid.UUID) (*model.Notification, error)
 
 	// Set adds an item to the cache for a specified duration
 	Set(ctx context.Context, n *model.Notification, expiration time.Duration) error
 
-	// Delete removes an item from the cache.
-	Delete(ctx context.Context, id uuid.UUID) error
+	// Delete removes an item of a tenant from the cache.
+	Delete(ctx context.Context, tenantID string, id uuid.UUID) error
+}
+
+// QuotaStore defines the contract for counting notifications against per-tenant daily quotas.
+type QuotaStore interface {
+	// Increment adds one to the tenant's counter for the given day and returns the new count.
+	Increment(ctx context.Context, tenantID string, day time.Time) (int64, error)
+
+	// Decrement gives back one unit of the tenant's counter for the given day.
+	Decrement(ctx context.Context, tenantID string, day time.Time) error
 }
 
 // NotificationQueue defines the contract for interacting with a delayed job queue.
@@ -42,4 +78,12 @@ type NotificationQueue interface {
 
 	// PublishRetry schedules a notification for a retry attempt with a specific delay.
 	PublishRetry(ctx context.Context, n *model.Notification, retryDelay time.Duration) error
+
+	// PublishDeferred queues a notification that was not attempted, e.g. outside its
+	// delivery window, for another try. Long delays may return the notification early,
+	// so the consumer must check again whether it can be sent.
+	PublishDeferred(ctx context.Context, n *model.Notification, delay time.Duration) error
+
+	// PublishNow queues a notification for immediate processing, bypassing the delay.
+	PublishNow(ctx context.Context, n *model.Notification) error
 }
@@ -0,0 +1,22 @@
+package repository
+
+import (
+	"context"
+	"time"
+)
+
+// RateLimitBucket identifies a token bucket and how it is refilled.
+type RateLimitBucket struct {
+	Key   string
+	Rate  float64 // Tokens added per second.
+	Burst int     // Most tokens the bucket holds.
+}
+
+// RateLimiter defines the contract for a distributed token-bucket rate limiter.
+type RateLimiter interface {
+	// Take tries to take one token from each of the buckets at once: either every bucket
+	// gives a token or none does, so that a send rejected by one limit does not use up
+	// another. If a bucket is empty, it returns how long the caller should wait before
+	// trying again and the key of the bucket it waits for.
+	Take(ctx context.Context, buckets []RateLimitBucket) (wait time.Duration, key string, err error)
+}
@@ -0,0 +1,38 @@
+// Package metrics defines the Prometheus collectors exported by the application.
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	// CircuitBreakerState reports the current state of each tenant's channel circuit breaker:
+	// 0 = closed, 1 = half-open, 2 = open.
+	CircuitBreakerState = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "notifier",
+		Name:      "circuit_breaker_state",
+		Help:      "Current circuit breaker state per tenant and channel (0=closed, 1=half-open, 2=open).",
+	}, []string{"tenant", "channel"})
+
+	// CircuitBreakerTransitions counts circuit breaker state changes.
+	CircuitBreakerTransitions = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "notifier",
+		Name:      "circuit_breaker_transitions_total",
+		Help:      "Number of circuit breaker state transitions per tenant, channel and target state.",
+	}, []string{"tenant", "channel", "state"})
+
+	// CircuitBreakerRejections counts sends short-circuited by an open breaker.
+	CircuitBreakerRejections = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "notifier",
+		Name:      "circuit_breaker_rejections_total",
+		Help:      "Number of sends rejected by an open circuit breaker per tenant and channel.",
+	}, []string{"tenant", "channel"})
+
+	// DeliveryWindowDeferrals counts sends deferred because they fell outside a delivery window.
+	DeliveryWindowDeferrals = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "notifier",
+		Name:      "delivery_window_deferrals_total",
+		Help:      "Number of sends deferred to the next opening of a delivery window per tenant and channel.",
+	}, []string{"tenant", "channel"})
+)
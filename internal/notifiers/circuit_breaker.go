@@ -0,0 +1,166 @@
+package notifiers
+
+import (
+	"errors"
+	"fmt"
+	"github.com/ilindan-dev/delayed-notifier/internal/metrics"
+	"github.com/rs/zerolog"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned (wrapped in a CircuitOpenError) when a send is short-circuited.
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// CircuitOpenError reports that a send was rejected by an open circuit breaker.
+// The send was never attempted, so it must not count as a delivery attempt.
+type CircuitOpenError struct {
+	Name       string
+	RetryAfter time.Duration // Time until the breaker lets a probe through.
+}
+
+func (e *CircuitOpenError) Error() string {
+	return fmt.Sprintf("%s: %s, retry after %s", e.Name, ErrCircuitOpen, e.RetryAfter)
+}
+
+func (e *CircuitOpenError) Unwrap() error {
+	return ErrCircuitOpen
+}
+
+// BreakerState is the state of a circuit breaker.
+type BreakerState int
+
+const (
+	BreakerClosed   BreakerState = iota // Sends pass through normally.
+	BreakerHalfOpen                     // A single probe send is allowed to test recovery.
+	BreakerOpen                         // Sends are rejected until the open timeout expires.
+)
+
+// String returns the human-readable name of the state.
+func (s BreakerState) String() string {
+	switch s {
+	case BreakerClosed:
+		return "closed"
+	case BreakerHalfOpen:
+		return "half_open"
+	case BreakerOpen:
+		return "open"
+	default:
+		return "unknown"
+	}
+}
+
+// CircuitBreaker tracks consecutive send failures of a single notifier.
+// After failureThreshold consecutive failures it opens and rejects sends for
+// openTimeout, then lets one probe through (half-open). A successful probe closes
+// the breaker, a failed one opens it again.
+type CircuitBreaker struct {
+	name             string // "<tenant>/<channel>"
+	tenantID         string
+	channel          string
+	failureThreshold int
+	openTimeout      time.Duration
+	logger           zerolog.Logger
+	now              func() time.Time
+
+	mu       sync.Mutex
+	state    BreakerState
+	failures int
+	openedAt time.Time
+	probing  bool
+}
+
+// NewCircuitBreaker creates a new, closed CircuitBreaker for a tenant's channel notifier.
+func NewCircuitBreaker(tenantID, channel string, failureThreshold int, openTimeout time.Duration, logger zerolog.Logger) *CircuitBreaker {
+	name := tenantID + "/" + channel
+	metrics.CircuitBreakerState.WithLabelValues(tenantID, channel).Set(float64(BreakerClosed))
+	return &CircuitBreaker{
+		name:             name,
+		tenantID:         tenantID,
+		channel:          channel,
+		failureThreshold: failureThreshold,
+		openTimeout:      openTimeout,
+		logger:           logger.With().Str("breaker", name).Logger(),
+		now:              time.Now,
+	}
+}
+
+// Allow reports whether a send may proceed. If it may not, it returns a *CircuitOpenError.
+// Every successful Allow must be followed by exactly one call to Record.
+func (b *CircuitBreaker) Allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case BreakerOpen:
+		remaining := b.openTimeout - b.now().Sub(b.openedAt)
+		if remaining > 0 {
+			metrics.CircuitBreakerRejections.WithLabelValues(b.tenantID, b.channel).Inc()
+			return &CircuitOpenError{Name: b.name, RetryAfter: remaining}
+		}
+		b.transition(BreakerHalfOpen)
+		b.probing = true
+		return nil
+	case BreakerHalfOpen:
+		if b.probing {
+			metrics.CircuitBreakerRejections.WithLabelValues(b.tenantID, b.channel).Inc()
+			return &CircuitOpenError{Name: b.name, RetryAfter: b.openTimeout}
+		}
+		b.probing = true
+		return nil
+	default:
+		return nil
+	}
+}
+
+// Record registers the outcome of a send that was allowed by Allow.
+func (b *CircuitBreaker) Record(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.probing = false
+	if err == nil {
+		b.failures = 0
+		if b.state != BreakerClosed {
+			b.transition(BreakerClosed)
+		}
+		return
+	}
+
+	b.failures++
+	if b.state == BreakerHalfOpen || b.failures >= b.failureThreshold {
+		b.openedAt = b.now()
+		b.transition(BreakerOpen)
+	}
+}
+
+// Release gives back a probe slot taken by Allow without recording an outcome,
+// e.g. when the send was abandoned because its context was cancelled.
+func (b *CircuitBreaker) Release() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.probing = false
+}
+
+// Name returns the "<tenant>/<channel>" name of the breaker.
+func (b *CircuitBreaker) Name() string {
+	return b.name
+}
+
+// State returns the current state of the breaker.
+func (b *CircuitBreaker) State() BreakerState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state
+}
+
+// transition changes the state and publishes it. The caller must hold b.mu.
+func (b *CircuitBreaker) transition(to BreakerState) {
+	if b.state == to {
+		return
+	}
+	b.logger.Warn().Str("from", b.state.String()).Str("to", to.String()).Int("failures", b.failures).Msg("circuit breaker state changed")
+	b.state = to
+	metrics.CircuitBreakerState.WithLabelValues(b.tenantID, b.channel).Set(float64(to))
+	metrics.CircuitBreakerTransitions.WithLabelValues(b.tenantID, b.channel, to.String()).Inc()
+}
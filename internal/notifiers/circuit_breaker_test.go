@@ -0,0 +1,158 @@
+package notifiers
+
+import (
+	"errors"
+	"github.com/rs/zerolog"
+	"testing"
+	"time"
+)
+
+// breakerOp is an operation applied to a circuit breaker under test.
+type breakerOp int
+
+const (
+	opAllow breakerOp = iota
+	opSuccess
+	opFailure
+	opRelease
+)
+
+// breakerStep applies op after advancing the clock by advance, then checks the breaker.
+type breakerStep struct {
+	advance   time.Duration
+	op        breakerOp
+	rejected  bool // For opAllow: whether the send must be short-circuited.
+	wantState BreakerState
+}
+
+func TestCircuitBreaker(t *testing.T) {
+	const (
+		threshold   = 3
+		openTimeout = time.Minute
+	)
+	// trip opens a closed breaker with threshold consecutive failures.
+	trip := []breakerStep{
+		{op: opAllow, wantState: BreakerClosed},
+		{op: opFailure, wantState: BreakerClosed},
+		{op: opAllow, wantState: BreakerClosed},
+		{op: opFailure, wantState: BreakerClosed},
+		{op: opAllow, wantState: BreakerClosed},
+		{op: opFailure, wantState: BreakerOpen},
+	}
+	tests := []struct {
+		name  string
+		steps []breakerStep
+	}{
+		{
+			name: "opens after consecutive failures",
+			steps: append(trip[:len(trip):len(trip)],
+				breakerStep{op: opAllow, rejected: true, wantState: BreakerOpen},
+				breakerStep{advance: openTimeout - time.Second, op: opAllow, rejected: true, wantState: BreakerOpen},
+			),
+		},
+		{
+			name: "success resets the failure count",
+			steps: []breakerStep{
+				{op: opAllow, wantState: BreakerClosed},
+				{op: opFailure, wantState: BreakerClosed},
+				{op: opAllow, wantState: BreakerClosed},
+				{op: opFailure, wantState: BreakerClosed},
+				{op: opAllow, wantState: BreakerClosed},
+				{op: opSuccess, wantState: BreakerClosed},
+				{op: opAllow, wantState: BreakerClosed},
+				{op: opFailure, wantState: BreakerClosed},
+				{op: opAllow, wantState: BreakerClosed},
+				{op: opFailure, wantState: BreakerClosed},
+			},
+		},
+		{
+			name: "lets a single probe through when half-open",
+			steps: append(trip[:len(trip):len(trip)],
+				breakerStep{advance: openTimeout, op: opAllow, wantState: BreakerHalfOpen},
+				breakerStep{op: opAllow, rejected: true, wantState: BreakerHalfOpen},
+				breakerStep{advance: time.Hour, op: opAllow, rejected: true, wantState: BreakerHalfOpen},
+			),
+		},
+		{
+			name: "closes on a successful probe",
+			steps: append(trip[:len(trip):len(trip)],
+				breakerStep{advance: openTimeout, op: opAllow, wantState: BreakerHalfOpen},
+				breakerStep{op: opSuccess, wantState: BreakerClosed},
+				breakerStep{op: opAllow, wantState: BreakerClosed},
+				breakerStep{op: opAllow, wantState: BreakerClosed},
+			),
+		},
+		{
+			name: "reopens on a failed probe",
+			steps: append(trip[:len(trip):len(trip)],
+				breakerStep{advance: openTimeout, op: opAllow, wantState: BreakerHalfOpen},
+				breakerStep{op: opFailure, wantState: BreakerOpen},
+				breakerStep{advance: openTimeout - time.Second, op: opAllow, rejected: true, wantState: BreakerOpen},
+				breakerStep{advance: time.Second, op: opAllow, wantState: BreakerHalfOpen},
+			),
+		},
+		{
+			name: "release gives back the probe slot",
+			steps: append(trip[:len(trip):len(trip)],
+				breakerStep{advance: openTimeout, op: opAllow, wantState: BreakerHalfOpen},
+				breakerStep{op: opRelease, wantState: BreakerHalfOpen},
+				breakerStep{op: opAllow, wantState: BreakerHalfOpen},
+				breakerStep{op: opAllow, rejected: true, wantState: BreakerHalfOpen},
+			),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+			b := NewCircuitBreaker("acme", "email", threshold, openTimeout, zerolog.Nop())
+			b.now = func() time.Time { return now }
+
+			for i, step := range tt.steps {
+				now = now.Add(step.advance)
+				switch step.op {
+				case opAllow:
+					err := b.Allow()
+					var openErr *CircuitOpenError
+					if step.rejected && !errors.As(err, &openErr) {
+						t.Errorf("step %d: got %v, want the send to be rejected", i, err)
+					}
+					if !step.rejected && err != nil {
+						t.Errorf("step %d: got %v, want the send to be allowed", i, err)
+					}
+				case opSuccess:
+					b.Record(nil)
+				case opFailure:
+					b.Record(errors.New("provider unavailable"))
+				case opRelease:
+					b.Release()
+				}
+				if got := b.State(); got != step.wantState {
+					t.Errorf("step %d: got state %v, want %v", i, got, step.wantState)
+				}
+			}
+		})
+	}
+}
+
+func TestCircuitOpenErrorRetryAfter(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	b := NewCircuitBreaker("acme", "email", 1, time.Minute, zerolog.Nop())
+	b.now = func() time.Time { return now }
+
+	if err := b.Allow(); err != nil {
+		t.Fatal(err)
+	}
+	b.Record(errors.New("provider unavailable"))
+	now = now.Add(20 * time.Second)
+
+	var openErr *CircuitOpenError
+	if err := b.Allow(); !errors.As(err, &openErr) {
+		t.Fatalf("got %v, want a circuit open error", err)
+	}
+	if want := 40 * time.Second; openErr.RetryAfter != want {
+		t.Errorf("got retry after %s, want %s", openErr.RetryAfter, want)
+	}
+	if !errors.Is(openErr, ErrCircuitOpen) {
+		t.Errorf("got %v, want it to wrap ErrCircuitOpen", openErr)
+	}
+}
@@ -0,0 +1,167 @@
+package notifiers
+
+import (
+	"context"
+	"errors"
+	"github.com/ilindan-dev/delayed-notifier/internal/config"
+	"github.com/ilindan-dev/delayed-notifier/internal/domain/model"
+	repo "github.com/ilindan-dev/delayed-notifier/internal/domain/repository"
+	"github.com/ilindan-dev/delayed-notifier/pkg/keybuilder"
+	"github.com/rs/zerolog"
+	"testing"
+	"time"
+)
+
+// fakeLimiter emulates atomic takes from several token buckets. A bucket answers with
+// its queued waits, then with zero, and tokens are only taken if every bucket answers zero.
+type fakeLimiter struct {
+	waits map[string][]time.Duration // Keyed by bucket key.
+	taken map[string]int
+	takes int
+}
+
+func (l *fakeLimiter) Take(_ context.Context, buckets []repo.RateLimitBucket) (time.Duration, string, error) {
+	l.takes++
+	var wait time.Duration
+	var limited string
+	for _, b := range buckets {
+		if waits := l.waits[b.Key]; len(waits) > 0 {
+			l.waits[b.Key] = waits[1:]
+			if waits[0] > wait {
+				wait, limited = waits[0], b.Key
+			}
+		}
+	}
+	if wait > 0 {
+		return wait, limited, nil
+	}
+	if l.taken == nil {
+		l.taken = make(map[string]int)
+	}
+	for _, b := range buckets {
+		l.taken[b.Key]++
+	}
+	return 0, "", nil
+}
+
+var (
+	recipientKey = keybuilder.RedisRateLimitKeyBuild(model.DefaultTenant, "email", "user@example.com")
+	channelKey   = keybuilder.RedisRateLimitKeyBuild(model.DefaultTenant, "email")
+)
+
+func TestDispatcherRateLimits(t *testing.T) {
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	tests := []struct {
+		name           string
+		ctx            context.Context
+		waits          map[string][]time.Duration
+		wantRetryAfter time.Duration // Zero if the send must go through.
+	}{
+		{name: "tokens available", ctx: context.Background()},
+		{name: "short wait", ctx: context.Background(), waits: map[string][]time.Duration{recipientKey: {20 * time.Millisecond}}},
+		{name: "long wait", ctx: context.Background(), waits: map[string][]time.Duration{recipientKey: {time.Hour}}, wantRetryAfter: time.Hour},
+		{
+			name:           "channel limited, recipient not",
+			ctx:            context.Background(),
+			waits:          map[string][]time.Duration{channelKey: {time.Minute}},
+			wantRetryAfter: time.Minute,
+		},
+		{
+			name:           "both limited",
+			ctx:            context.Background(),
+			waits:          map[string][]time.Duration{recipientKey: {time.Minute}, channelKey: {time.Hour}},
+			wantRetryAfter: time.Hour,
+		},
+		{
+			name:           "stopped while waiting",
+			ctx:            cancelled,
+			waits:          map[string][]time.Duration{recipientKey: {time.Second}},
+			wantRetryAfter: time.Second,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger := zerolog.Nop()
+			cfg := &config.Config{Notifiers: config.NotifiersConfig{
+				RateLimits: map[string]config.ChannelRateLimitConfig{
+					"email": {
+						Channel:   config.RateLimitConfig{Rate: 10, Per: time.Hour, Burst: 1},
+						Recipient: config.RateLimitConfig{Rate: 1, Per: time.Minute, Burst: 1},
+					},
+				},
+				CircuitBreaker: config.CircuitBreakerConfig{FailureThreshold: 1, OpenTimeout: time.Minute},
+			}}
+			limiter := &fakeLimiter{waits: tt.waits}
+			d, err := NewDispatcher(cfg, limiter, &logger)
+			if err != nil {
+				t.Fatal(err)
+			}
+			n := model.NewEmailNotification(model.DefaultTenant, "user@example.com", "Hello", "World", time.Now(), nil)
+
+			err = d.Send(tt.ctx, n)
+			var limitErr *RateLimitedError
+			switch {
+			case tt.wantRetryAfter == 0 && err != nil:
+				t.Errorf("got %v, want the send to go through", err)
+			case tt.wantRetryAfter > 0 && (!errors.As(err, &limitErr) || limitErr.RetryAfter != tt.wantRetryAfter):
+				t.Errorf("got %v, want a rate limited error retrying after %s", err, tt.wantRetryAfter)
+			}
+			// A rejected send must not use up a token of a bucket that had one.
+			wantTaken := 1
+			if tt.wantRetryAfter > 0 {
+				wantTaken = 0
+			}
+			for _, key := range []string{recipientKey, channelKey} {
+				if limiter.taken[key] != wantTaken {
+					t.Errorf("got %d tokens taken from %s, want %d", limiter.taken[key], key, wantTaken)
+				}
+			}
+			// A rejected send is not a provider failure, so the breaker must stay closed.
+			for name, state := range d.BreakerStates() {
+				if state != BreakerClosed {
+					t.Errorf("breaker %s is %v, want closed", name, state)
+				}
+			}
+		})
+	}
+}
+
+func TestDispatcherAdmit(t *testing.T) {
+	logger := zerolog.Nop()
+	cfg := &config.Config{Notifiers: config.NotifiersConfig{
+		RateLimits: map[string]config.ChannelRateLimitConfig{
+			"email": {
+				Channel:   config.RateLimitConfig{Rate: 10, Per: time.Hour, Burst: 1},
+				Recipient: config.RateLimitConfig{Rate: 1, Per: time.Minute, Burst: 1},
+			},
+		},
+	}}
+	limiter := &fakeLimiter{}
+	d, err := NewDispatcher(cfg, limiter, &logger)
+	if err != nil {
+		t.Fatal(err)
+	}
+	n := model.NewEmailNotification(model.DefaultTenant, "user@example.com", "Hello", "World", time.Now(), nil)
+
+	admission, err := d.Admit(context.Background(), n)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if limiter.takes != 1 {
+		t.Errorf("got %d takes on admission, want 1", limiter.takes)
+	}
+	// The admitted send must not take the tokens again.
+	if err := admission.Send(context.Background(), n); err != nil {
+		t.Fatal(err)
+	}
+	if limiter.takes != 1 {
+		t.Errorf("got %d takes after the send, want 1", limiter.takes)
+	}
+
+	limiter.waits = map[string][]time.Duration{recipientKey: {time.Hour}}
+	var limitErr *RateLimitedError
+	if _, err := d.Admit(context.Background(), n); !errors.As(err, &limitErr) {
+		t.Errorf("got %v, want a rate limited error", err)
+	}
+}
@@ -2,25 +2,73 @@ package notifiers
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"github.com/ilindan-dev/delayed-notifier/internal/config"
 	"github.com/ilindan-dev/delayed-notifier/internal/domain/model"
+	repo "github.com/ilindan-dev/delayed-notifier/internal/domain/repository"
+	"github.com/ilindan-dev/delayed-notifier/pkg/keybuilder"
 	"github.com/rs/zerolog"
+	"time"
 )
 
+// maxRateLimitWait is the longest a send waits in the worker for a rate limit token.
+// Longer waits are left to the caller, so that workers are not parked on a limit.
+const maxRateLimitWait = time.Second
+
+// ErrRateLimited is returned (wrapped in a RateLimitedError) when a send is over a rate limit.
+var ErrRateLimited = errors.New("rate limit reached")
+
+// RateLimitedError reports that a send was rejected by a rate limit.
+// The send was never attempted, so it must not count as a delivery attempt.
+type RateLimitedError struct {
+	Key        string
+	RetryAfter time.Duration // Time until the bucket has a token again.
+}
+
+func (e *RateLimitedError) Error() string {
+	return fmt.Sprintf("%s: %s, retry after %s", e.Key, ErrRateLimited, e.RetryAfter)
+}
+
+func (e *RateLimitedError) Unwrap() error {
+	return ErrRateLimited
+}
+
 // Dispatcher is a composite notifier that routes notifications to the correct channel-specific notifier.
 // It implements the Notifier interface itself.
+// Tenants may have their own credentials for a channel; otherwise the default tenant's notifier is used.
 type Dispatcher struct {
-	notifiers map[model.Channel]Notifier
-	logger    zerolog.Logger
+	notifiers  map[string]map[model.Channel]*channelNotifier // Keyed by tenant ID.
+	limiter    repo.RateLimiter
+	rateLimits map[model.Channel]config.ChannelRateLimitConfig
+	logger     zerolog.Logger
+}
+
+// channelNotifier is the notifier of a single channel together with its circuit breaker.
+type channelNotifier struct {
+	notifier Notifier
+	breaker  *CircuitBreaker
+	// owner is the tenant whose credentials the notifier uses. Rate limits are
+	// scoped to it, since provider limits apply per bot token or SMTP account.
+	owner string
 }
 
 // NewDispatcher creates a new Dispatcher and initializes channel-specific notifiers
 // based on the application's configuration mode.
-func NewDispatcher(cfg *config.Config, logger *zerolog.Logger) (*Dispatcher, error) {
+func NewDispatcher(cfg *config.Config, limiter repo.RateLimiter, logger *zerolog.Logger) (*Dispatcher, error) {
 	log := logger.With().Str("component", "dispatcher").Logger()
 	log.Info().Str("mode", cfg.Notifiers.Mode).Msg("initializing notifiers")
 
+	d := &Dispatcher{
+		notifiers:  make(map[string]map[model.Channel]*channelNotifier),
+		limiter:    limiter,
+		rateLimits: make(map[model.Channel]config.ChannelRateLimitConfig, len(cfg.Notifiers.RateLimits)),
+		logger:     log,
+	}
+	for channel, limits := range cfg.Notifiers.RateLimits {
+		d.rateLimits[model.Channel(channel)] = limits
+	}
+
 	notifiersMap := make(map[model.Channel]Notifier)
 	// Create the LogNotifier once to use as a fallback.
 	logNotifier := NewLogNotifier(logger)
@@ -45,20 +93,172 @@ func NewDispatcher(cfg *config.Config, logger *zerolog.Logger) (*Dispatcher, err
 		}
 	}
 
-	return &Dispatcher{
-		notifiers: notifiersMap,
-		logger:    log,
-	}, nil
+	for channel, notifier := range notifiersMap {
+		d.register(cfg, model.DefaultTenant, channel, notifier)
+	}
+
+	// Tenant-specific credentials only make sense with real notifiers.
+	if cfg.Notifiers.Mode != "production" {
+		return d, nil
+	}
+	for tenantID, tenantCfg := range cfg.Tenants {
+		if tenantID == model.DefaultTenant {
+			continue
+		}
+		if tenantCfg.Email.Host != "" {
+			d.register(cfg, tenantID, model.ChannelEmail, NewEmailNotifier(tenantCfg.Email, logger))
+			log.Info().Str("tenant_id", tenantID).Msg("tenant email notifier enabled")
+		}
+		if tenantCfg.Telegram.BotToken != "" {
+			tgNotifier, err := NewTelegramNotifier(tenantCfg.Telegram, logger)
+			if err != nil {
+				return nil, fmt.Errorf("failed to initialize telegram notifier for tenant %s: %w", tenantID, err)
+			}
+			d.register(cfg, tenantID, model.ChannelTelegram, tgNotifier)
+			log.Info().Str("tenant_id", tenantID).Msg("tenant telegram notifier enabled")
+		}
+	}
+
+	return d, nil
+}
+
+// register adds a tenant's notifier for a channel, wrapped in its own circuit breaker.
+func (d *Dispatcher) register(cfg *config.Config, tenantID string, channel model.Channel, notifier Notifier) {
+	if d.notifiers[tenantID] == nil {
+		d.notifiers[tenantID] = make(map[model.Channel]*channelNotifier)
+	}
+	d.notifiers[tenantID][channel] = &channelNotifier{
+		notifier: notifier,
+		breaker: NewCircuitBreaker(
+			tenantID,
+			string(channel),
+			cfg.Notifiers.CircuitBreaker.FailureThreshold,
+			cfg.Notifiers.CircuitBreaker.OpenTimeout,
+			d.logger,
+		),
+		owner: tenantID,
+	}
+}
+
+// resolve finds the notifier for the tenant and channel, falling back to the default tenant's.
+func (d *Dispatcher) resolve(tenantID string, channel model.Channel) (*channelNotifier, bool) {
+	if cn, ok := d.notifiers[tenantID][channel]; ok {
+		return cn, true
+	}
+	cn, ok := d.notifiers[model.DefaultTenant][channel]
+	return cn, ok
 }
 
 // Send implements the Notifier interface. It finds the correct notifier for the
-// notification's channel and delegates the send operation to it.
+// notification's tenant and channel and delegates the send operation to it.
 func (d *Dispatcher) Send(ctx context.Context, n *model.Notification) error {
-	notifier, ok := d.notifiers[n.Channel]
+	admission, err := d.Admit(ctx, n)
+	if err != nil {
+		return err
+	}
+	return admission.Send(ctx, n)
+}
+
+// Admit implements the Admitter interface. It finds the notifier for the notification's
+// tenant and channel and reserves a send with its circuit breaker and rate limits.
+// Rate limit tokens are taken by the reservation and not given back on Release.
+func (d *Dispatcher) Admit(ctx context.Context, n *model.Notification) (Admission, error) {
+	cn, ok := d.resolve(n.TenantID, n.Channel)
 	if !ok {
 		d.logger.Error().Str("channel", string(n.Channel)).Msg("no notifier found for channel")
-		return fmt.Errorf("notifier for channel %s not found", n.Channel)
+		return nil, fmt.Errorf("notifier for channel %s not found", n.Channel)
+	}
+
+	if err := cn.breaker.Allow(); err != nil {
+		return nil, err
+	}
+
+	if err := d.takeRateLimits(ctx, cn.owner, n); err != nil {
+		cn.breaker.Release()
+		return nil, err
+	}
+	return admission{cn: cn}, nil
+}
+
+// admission is a send admitted by the circuit breaker and rate limits of a channelNotifier.
+type admission struct {
+	cn *channelNotifier
+}
+
+// Send sends the notification and records the outcome with the circuit breaker.
+func (a admission) Send(ctx context.Context, n *model.Notification) error {
+	err := a.cn.notifier.Send(ctx, n)
+	if err != nil && ctx.Err() != nil {
+		// An abandoned send says nothing about the health of the provider.
+		a.cn.breaker.Release()
+		return err
+	}
+	a.cn.breaker.Record(err)
+	return err
+}
+
+// Release gives the circuit breaker back the send it admitted.
+func (a admission) Release() {
+	a.cn.breaker.Release()
+}
+
+// BreakerStates returns the current circuit breaker state of every notifier,
+// keyed by "<tenant>/<channel>".
+func (d *Dispatcher) BreakerStates() map[string]BreakerState {
+	states := make(map[string]BreakerState)
+	for _, channels := range d.notifiers {
+		for _, cn := range channels {
+			states[cn.breaker.Name()] = cn.breaker.State()
+		}
+	}
+	return states
+}
+
+// takeRateLimits takes a token from both the per-recipient and the per-channel buckets
+// of the credentials' owner at once, or returns a *RateLimitedError. While a bucket is
+// empty, it sleeps for waits up to maxRateLimitWait. Limiter errors are logged and the
+// message is let through, as losing the limiter must not stop delivery.
+func (d *Dispatcher) takeRateLimits(ctx context.Context, owner string, n *model.Notification) error {
+	limits, ok := d.rateLimits[n.Channel]
+	if !ok || d.limiter == nil {
+		return nil
 	}
 
-	return notifier.Send(ctx, n)
+	channel := string(n.Channel)
+	var buckets []repo.RateLimitBucket
+	add := func(key string, limit config.RateLimitConfig) {
+		if limit.Rate > 0 && limit.Per > 0 {
+			buckets = append(buckets, repo.RateLimitBucket{Key: key, Rate: limit.Rate / limit.Per.Seconds(), Burst: limit.Burst})
+		}
+	}
+	add(keybuilder.RedisRateLimitKeyBuild(owner, channel, n.Recipient()), limits.Recipient)
+	add(keybuilder.RedisRateLimitKeyBuild(owner, channel), limits.Channel)
+	if len(buckets) == 0 {
+		return nil
+	}
+
+	for {
+		wait, key, err := d.limiter.Take(ctx, buckets)
+		if err != nil {
+			d.logger.Warn().Err(err).Str("owner", owner).Str("channel", channel).Msg("rate limiter unavailable, sending without limit")
+			return nil
+		}
+		if wait <= 0 {
+			return nil
+		}
+		if wait > maxRateLimitWait {
+			d.logger.Debug().Str("key", key).Dur("wait", wait).Msg("rate limit reached, deferring send")
+			return &RateLimitedError{Key: key, RetryAfter: wait}
+		}
+
+		d.logger.Debug().Str("key", key).Dur("wait", wait).Msg("rate limit reached, delaying send")
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			// Stopping the worker must not count against the notification's attempts.
+			timer.Stop()
+			return &RateLimitedError{Key: key, RetryAfter: wait}
+		case <-timer.C:
+		}
+	}
 }
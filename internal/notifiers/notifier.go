@@ -11,3 +11,19 @@ type Notifier interface {
 	// Send dispatches the notification.
 	Send(ctx context.Context, n *model.Notification) error
 }
+
+// Admitter is implemented by notifiers that may reject a send before attempting it,
+// e.g. because of an open circuit breaker or a rate limit. Checking ahead lets callers
+// defer a notification without changing it.
+type Admitter interface {
+	// Admit reserves a send of the notification, or returns a *CircuitOpenError or a
+	// *RateLimitedError if it would be rejected now.
+	Admit(ctx context.Context, n *model.Notification) (Admission, error)
+}
+
+// Admission is a send reserved by an Admitter. Its Send does not check the send again.
+type Admission interface {
+	Notifier
+	// Release gives up the reservation of a send that does not happen.
+	Release()
+}
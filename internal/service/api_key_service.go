@@ -0,0 +1,130 @@
+package service
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/ilindan-dev/delayed-notifier/internal/auth"
+	"github.com/ilindan-dev/delayed-notifier/internal/config"
+	"github.com/ilindan-dev/delayed-notifier/internal/domain"
+	"github.com/ilindan-dev/delayed-notifier/internal/domain/model"
+	repo "github.com/ilindan-dev/delayed-notifier/internal/domain/repository"
+	"github.com/rs/zerolog"
+	"time"
+)
+
+const (
+	// bootstrapAdminID is the principal ID of the bootstrap admin key from the configuration.
+	bootstrapAdminID = "bootstrap-admin"
+	// touchInterval is how often the last use of an API key is recorded, so that
+	// authenticating a request does not write to the database every time.
+	touchInterval = time.Minute
+)
+
+// ErrInvalidAPIKey is returned when an API key is unknown or revoked.
+var ErrInvalidAPIKey = errors.New("invalid api key")
+
+// ErrForeignTenant is returned when a caller tries to act on another tenant.
+var ErrForeignTenant = &domain.Error{Code: domain.CodeForeignTenant, Field: "tenant_id", Message: "operation on another tenant is not allowed"}
+
+// APIKeyService manages API keys and authenticates callers presenting them.
+type APIKeyService struct {
+	repo               repo.APIKeyRepository
+	bootstrapAdminHash string
+	logger             zerolog.Logger
+}
+
+// NewAPIKeyService creates a new instance of APIKeyService.
+func NewAPIKeyService(cfg *config.Config, repo repo.APIKeyRepository, logger *zerolog.Logger) *APIKeyService {
+	s := &APIKeyService{
+		repo:   repo,
+		logger: logger.With().Str("layer", "api_key_service").Logger(),
+	}
+	if cfg.Auth.BootstrapAdminKey != "" {
+		s.bootstrapAdminHash = auth.HashAPIKey(cfg.Auth.BootstrapAdminKey)
+		s.logger.Warn().Msg("bootstrap admin api key is enabled, use it only to create regular keys")
+	}
+	return s
+}
+
+// Authenticate resolves a raw API key to the principal it authenticates.
+// The last use of the key is recorded at most once per touchInterval.
+func (s *APIKeyService) Authenticate(ctx context.Context, rawKey string) (*model.Principal, error) {
+	hash := auth.HashAPIKey(rawKey)
+
+	if s.bootstrapAdminHash != "" && subtle.ConstantTimeCompare([]byte(hash), []byte(s.bootstrapAdminHash)) == 1 {
+		return &model.Principal{ID: bootstrapAdminID, TenantID: model.DefaultTenant, Scopes: []model.Scope{model.ScopeAdmin}}, nil
+	}
+
+	key, err := s.repo.GetActiveByHash(ctx, hash)
+	if err != nil {
+		if errors.Is(err, repo.ErrNotFound) {
+			return nil, ErrInvalidAPIKey
+		}
+		return nil, err
+	}
+
+	if key.LastUsedAt == nil || time.Since(*key.LastUsedAt) >= touchInterval {
+		if err := s.repo.Touch(ctx, key.ID); err != nil {
+			s.logger.Warn().Err(err).Stringer("key_id", key.ID).Msg("failed to record api key usage")
+		}
+	}
+
+	return key.Principal(), nil
+}
+
+// CreateAPIKey issues a new API key. The raw key is returned only here and is never stored.
+// Keys are created in the caller's tenant. Only callers of the default (operator) tenant
+// may create keys for another tenant by passing its ID.
+func (s *APIKeyService) CreateAPIKey(ctx context.Context, tenantID, name, ownerID string, scopes []model.Scope) (*model.APIKey, string, error) {
+	callerTenant := auth.TenantFromContext(ctx)
+	if tenantID == "" {
+		tenantID = callerTenant
+	}
+	if tenantID != callerTenant && callerTenant != model.DefaultTenant {
+		return nil, "", ErrForeignTenant
+	}
+
+	for _, scope := range scopes {
+		if !model.ValidScope(scope) {
+			return nil, "", fmt.Errorf("unknown scope: %s", scope)
+		}
+	}
+
+	raw, prefix, hash, err := auth.GenerateAPIKey()
+	if err != nil {
+		return nil, "", err
+	}
+
+	created, err := s.repo.Save(ctx, &model.APIKey{
+		TenantID: tenantID,
+		Name:     name,
+		Prefix:   prefix,
+		Hash:     hash,
+		OwnerID:  ownerID,
+		Scopes:   scopes,
+	})
+	if err != nil {
+		s.logger.Error().Err(err).Msg("failed to save api key")
+		return nil, "", err
+	}
+
+	s.logger.Info().Stringer("key_id", created.ID).Str("tenant_id", tenantID).Str("owner_id", ownerID).Msg("api key created")
+	return created, raw, nil
+}
+
+// ListAPIKeys returns all API keys of the caller's tenant.
+func (s *APIKeyService) ListAPIKeys(ctx context.Context) ([]*model.APIKey, error) {
+	return s.repo.List(ctx, auth.TenantFromContext(ctx))
+}
+
+// RevokeAPIKey revokes an API key of the caller's tenant so it can no longer be used.
+func (s *APIKeyService) RevokeAPIKey(ctx context.Context, id uuid.UUID) error {
+	if err := s.repo.Revoke(ctx, auth.TenantFromContext(ctx), id); err != nil {
+		return err
+	}
+	s.logger.Info().Stringer("key_id", id).Msg("api key revoked")
+	return nil
+}
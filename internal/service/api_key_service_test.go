@@ -0,0 +1,72 @@
+package service
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/ilindan-dev/delayed-notifier/internal/auth"
+	"github.com/ilindan-dev/delayed-notifier/internal/config"
+	"github.com/ilindan-dev/delayed-notifier/internal/domain/model"
+	repo "github.com/ilindan-dev/delayed-notifier/internal/domain/repository"
+	"github.com/rs/zerolog"
+	"testing"
+	"time"
+)
+
+// fakeAPIKeyRepo holds a single key and counts how often its use is recorded.
+type fakeAPIKeyRepo struct {
+	repo.APIKeyRepository
+	key     *model.APIKey
+	touches int
+}
+
+func (r *fakeAPIKeyRepo) GetActiveByHash(_ context.Context, hash string) (*model.APIKey, error) {
+	if hash != r.key.Hash {
+		return nil, repo.ErrNotFound
+	}
+	key := *r.key
+	return &key, nil
+}
+
+func (r *fakeAPIKeyRepo) Touch(context.Context, uuid.UUID) error {
+	r.touches++
+	now := time.Now()
+	r.key.LastUsedAt = &now
+	return nil
+}
+
+func TestAuthenticateTouchesKeyOncePerInterval(t *testing.T) {
+	const raw = "dn_test_key"
+	longAgo := time.Now().Add(-time.Hour)
+	recently := time.Now().Add(-time.Second)
+	tests := []struct {
+		name        string
+		lastUsedAt  *time.Time
+		wantTouches int
+	}{
+		{name: "never used", wantTouches: 1},
+		{name: "used long ago", lastUsedAt: &longAgo, wantTouches: 1},
+		{name: "used recently", lastUsedAt: &recently, wantTouches: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger := zerolog.Nop()
+			keys := &fakeAPIKeyRepo{key: &model.APIKey{
+				ID:         uuid.New(),
+				TenantID:   model.DefaultTenant,
+				Hash:       auth.HashAPIKey(raw),
+				LastUsedAt: tt.lastUsedAt,
+			}}
+			s := NewAPIKeyService(&config.Config{}, keys, &logger)
+
+			// Repeated requests within the interval must not record the use again.
+			for range 3 {
+				if _, err := s.Authenticate(context.Background(), raw); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if keys.touches != tt.wantTouches {
+				t.Errorf("got %d touches, want %d", keys.touches, tt.wantTouches)
+			}
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"github.com/ilindan-dev/delayed-notifier/internal/auth"
+	"github.com/ilindan-dev/delayed-notifier/internal/config"
+	"github.com/ilindan-dev/delayed-notifier/internal/domain/model"
+	"github.com/rs/zerolog"
+	"strings"
+)
+
+// AuthModeNone disables authentication.
+const AuthModeNone = "none"
+
+// ErrMissingCredentials is returned when a caller presents no usable credentials.
+var ErrMissingCredentials = errors.New("missing credentials")
+
+// Authenticator resolves the credentials presented by API callers to a principal.
+// It is shared by every transport, so that they all accept the same credentials.
+type Authenticator struct {
+	mode          string
+	apiKeyEnabled bool
+	keys          *APIKeyService
+	jwt           *auth.JWTVerifier // nil unless JWT authentication is enabled.
+}
+
+// NewAuthenticator creates a new instance of Authenticator.
+func NewAuthenticator(cfg *config.Config, keys *APIKeyService, jwt *auth.JWTVerifier, logger *zerolog.Logger) *Authenticator {
+	if cfg.Auth.Mode == AuthModeNone {
+		logger.Warn().Str("layer", "authenticator").Msg("authentication is disabled")
+	}
+	return &Authenticator{
+		mode:          cfg.Auth.Mode,
+		apiKeyEnabled: cfg.Auth.APIKeyEnabled(),
+		keys:          keys,
+		jwt:           jwt,
+	}
+}
+
+// Enabled reports whether callers must authenticate.
+func (a *Authenticator) Enabled() bool {
+	return a.mode != AuthModeNone
+}
+
+// Authenticate picks the authentication method from the presented credentials.
+// An explicit API key is always an API key. A bearer token is treated as a JWT if
+// JWTs are accepted and it has the three-part JWT shape, and as an API key otherwise.
+func (a *Authenticator) Authenticate(ctx context.Context, apiKey, bearerToken string) (*model.Principal, error) {
+	if apiKey != "" && a.apiKeyEnabled {
+		return a.keys.Authenticate(ctx, apiKey)
+	}
+
+	switch {
+	case bearerToken == "":
+		return nil, ErrMissingCredentials
+	case a.jwt != nil && (!a.apiKeyEnabled || strings.Count(bearerToken, ".") == 2):
+		return a.jwt.Verify(ctx, bearerToken)
+	case a.apiKeyEnabled:
+		return a.keys.Authenticate(ctx, bearerToken)
+	default:
+		return nil, ErrMissingCredentials
+	}
+}
+
+// IsUnauthenticated reports whether err means the credentials were missing or invalid,
+// as opposed to a failure while checking them.
+func IsUnauthenticated(err error) bool {
+	return errors.Is(err, ErrMissingCredentials) || errors.Is(err, ErrInvalidAPIKey) || errors.Is(err, auth.ErrInvalidToken)
+}
+
+// UnauthenticatedMessage returns the client-facing message for an authentication failure.
+// Token validation details are logged, not returned.
+func UnauthenticatedMessage(err error) string {
+	switch {
+	case errors.Is(err, auth.ErrInvalidToken):
+		return auth.ErrInvalidToken.Error()
+	case errors.Is(err, ErrInvalidAPIKey):
+		return ErrInvalidAPIKey.Error()
+	default:
+		return ErrMissingCredentials.Error()
+	}
+}
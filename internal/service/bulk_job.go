@@ -0,0 +1,247 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/ilindan-dev/delayed-notifier/internal/auth"
+	"github.com/ilindan-dev/delayed-notifier/internal/domain"
+	"github.com/ilindan-dev/delayed-notifier/internal/domain/model"
+	repo "github.com/ilindan-dev/delayed-notifier/internal/domain/repository"
+	"time"
+)
+
+// bulkPageSize is the number of notifications a bulk job changes at once.
+const bulkPageSize = MaxPageSize
+
+// bulkSaveTimeout bounds saving the final state of a job interrupted by shutdown.
+const bulkSaveTimeout = 5 * time.Second
+
+// BulkJobInput describes a bulk job.
+type BulkJobInput struct {
+	Action model.BulkAction
+	// Filter selects the notifications to change. Only scheduled notifications can be
+	// changed, and the filter needs a criterion besides the status, so that a job never
+	// changes every notification of the tenant by accident.
+	Filter model.NotificationFilter
+	// Reschedule is the new send time of a reschedule job. A given Timezone replaces those
+	// of the notifications; LocalTime requires one.
+	Reschedule RescheduleInput
+	// DryRun only validates the job and counts the notifications it would change.
+	DryRun bool
+}
+
+// StartBulkJob starts a job that cancels or reschedules every scheduled notification
+// matching the filter in the background, and returns it. Its progress can be followed
+// with GetBulkJob. With in.DryRun, the job is returned without being stored or started.
+// Non-admin callers only change their own notifications, whatever the filter says.
+func (s *NotificationService) StartBulkJob(ctx context.Context, in BulkJobInput) (*model.BulkJob, error) {
+	job, err := s.newBulkJob(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+
+	job.Total, err = s.repo.Count(ctx, job.TenantID, job.Filter)
+	if err != nil {
+		s.logger.Error().Err(err).Msg("failed to count notifications of bulk job")
+		return nil, err
+	}
+	if in.DryRun {
+		return job, nil
+	}
+
+	if err := s.bulkJobs.Save(ctx, job); err != nil {
+		s.logger.Error().Err(err).Msg("failed to save bulk job")
+		return nil, err
+	}
+	s.logger.Info().Stringer("job_id", job.ID).Str("action", string(job.Action)).Int("total", job.Total).Msg("bulk job started")
+
+	started := *job
+	// The job outlives the request, but keeps its tenant and principal.
+	jobCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	stop := context.AfterFunc(s.jobsCtx, cancel)
+	s.jobs.Add(1)
+	go func() {
+		defer s.jobs.Done()
+		defer stop()
+		defer cancel()
+		s.runBulkJob(jobCtx, job)
+	}()
+	return &started, nil
+}
+
+// GetBulkJob returns a bulk job of the tenant. Authenticated non-admin callers only
+// see their own jobs; for anything else ErrNotFound is returned.
+func (s *NotificationService) GetBulkJob(ctx context.Context, id uuid.UUID) (*model.BulkJob, error) {
+	job, err := s.bulkJobs.Get(ctx, auth.TenantFromContext(ctx), id)
+	if err != nil {
+		return nil, err
+	}
+	if principal, ok := auth.PrincipalFromContext(ctx); ok && !principal.CanFollow(job) {
+		return nil, repo.ErrNotFound
+	}
+	return job, nil
+}
+
+// StopBulkJobs interrupts the running bulk jobs and waits until they have recorded that they failed.
+func (s *NotificationService) StopBulkJobs(ctx context.Context) error {
+	s.stopJobs()
+
+	done := make(chan struct{})
+	go func() {
+		s.jobs.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		s.logger.Warn().Msg("shutdown deadline reached before bulk jobs stopped")
+		return ctx.Err()
+	}
+}
+
+// newBulkJob validates in and returns the job it describes, without its total.
+func (s *NotificationService) newBulkJob(ctx context.Context, in BulkJobInput) (*model.BulkJob, error) {
+	filter := in.Filter
+	if filter.Status != nil && *filter.Status != model.StatusScheduled {
+		return nil, domain.ErrInvalidFilter.Withf("only scheduled notifications can be changed, not %s ones", *filter.Status)
+	}
+	if !hasCriteria(filter) {
+		return nil, domain.ErrInvalidFilter.Withf("the filter must select notifications by at least one criterion besides the status")
+	}
+	scheduled := model.StatusScheduled
+	filter.Status = &scheduled
+
+	now := time.Now().UTC()
+	job := &model.BulkJob{
+		ID:        uuid.New(),
+		TenantID:  auth.TenantFromContext(ctx),
+		Action:    in.Action,
+		Status:    model.BulkJobRunning,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if principal, ok := auth.PrincipalFromContext(ctx); ok {
+		job.AuthorID = &principal.ID
+		if !principal.IsAdmin() {
+			filter.AuthorID = &principal.ID
+		}
+	}
+	job.Filter = filter
+
+	switch in.Action {
+	case model.BulkActionCancel:
+	case model.BulkActionReschedule:
+		if err := s.validator.validateReschedule(&in.Reschedule, now); err != nil {
+			return nil, err
+		}
+		job.ScheduledAt = &in.Reschedule.ScheduledAt
+		if in.Reschedule.Timezone != "" {
+			job.Timezone = &in.Reschedule.Timezone
+		}
+	default:
+		return nil, fmt.Errorf("unknown bulk action %q", in.Action)
+	}
+	return job, nil
+}
+
+// runBulkJob works through the notifications of the job page by page, saving its
+// progress after each page, until none are left or it fails.
+func (s *NotificationService) runBulkJob(ctx context.Context, job *model.BulkJob) {
+	logger := s.logger.With().Stringer("job_id", job.ID).Str("action", string(job.Action)).Logger()
+
+	var after *model.Cursor
+	for {
+		page, err := s.repo.List(ctx, job.TenantID, job.Filter, after, bulkPageSize)
+		if err == nil && len(page) > 0 {
+			cursor := model.CursorOf(page[len(page)-1])
+			after = &cursor
+			err = s.applyBulkJob(ctx, job, page)
+		}
+
+		switch {
+		case err != nil && ctx.Err() != nil:
+			logger.Warn().Int("processed", job.Processed).Msg("bulk job interrupted by shutdown")
+			s.finishBulkJob(ctx, job, "interrupted by shutdown")
+			return
+		case err != nil:
+			logger.Error().Err(err).Int("processed", job.Processed).Msg("bulk job failed")
+			s.finishBulkJob(ctx, job, "failed to change notifications")
+			return
+		case len(page) < bulkPageSize:
+			logger.Info().Int("processed", job.Processed).Int("failed", job.Failed).Msg("bulk job completed")
+			s.finishBulkJob(ctx, job, "")
+			return
+		}
+
+		job.UpdatedAt = time.Now().UTC()
+		if err := s.bulkJobs.Save(ctx, job); err != nil {
+			logger.Warn().Err(err).Msg("failed to save bulk job progress")
+		}
+	}
+}
+
+// applyBulkJob changes a page of the notifications of the job and counts them.
+// Notifications that left the scheduled status meanwhile are skipped.
+func (s *NotificationService) applyBulkJob(ctx context.Context, job *model.BulkJob, page []*model.Notification) error {
+	ids := make([]uuid.UUID, len(page))
+	for i, n := range page {
+		ids[i] = n.ID
+	}
+
+	switch job.Action {
+	case model.BulkActionCancel:
+		cancelled, err := s.repo.CancelMany(ctx, job.TenantID, ids)
+		if err != nil {
+			return err
+		}
+		for _, n := range cancelled {
+			s.publishStatusEvent(ctx, n)
+			s.publishCallback(ctx, n)
+		}
+		job.Processed += len(cancelled)
+
+	case model.BulkActionReschedule:
+		rescheduled, err := s.repo.RescheduleMany(ctx, job.TenantID, ids, *job.ScheduledAt, job.Timezone)
+		if err != nil {
+			return err
+		}
+		for _, n := range rescheduled {
+			if err := s.queue.Publish(ctx, n); err != nil {
+				s.logger.Error().Err(err).Stringer("id", n.ID).Stringer("job_id", job.ID).Msg("CRITICAL: failed to publish rescheduled notification to queue")
+				job.Failed++
+				continue
+			}
+			s.publishStatusEvent(ctx, n)
+		}
+		job.Processed += len(rescheduled)
+	}
+	return nil
+}
+
+// finishBulkJob records that the job completed, or failed with errMsg if it is not empty.
+// The final state is saved even if ctx was cancelled by shutdown.
+func (s *NotificationService) finishBulkJob(ctx context.Context, job *model.BulkJob, errMsg string) {
+	now := time.Now().UTC()
+	job.Status = model.BulkJobCompleted
+	if errMsg != "" {
+		job.Status = model.BulkJobFailed
+		job.Error = errMsg
+	}
+	job.UpdatedAt = now
+	job.FinishedAt = &now
+
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), bulkSaveTimeout)
+	defer cancel()
+	if err := s.bulkJobs.Save(ctx, job); err != nil {
+		s.logger.Error().Err(err).Stringer("job_id", job.ID).Str("status", string(job.Status)).Msg("failed to save finished bulk job")
+	}
+}
+
+// hasCriteria reports whether the filter selects notifications by anything besides their status.
+func hasCriteria(f model.NotificationFilter) bool {
+	return f.Channel != nil || f.AuthorID != nil || f.ScheduledFrom != nil || f.ScheduledTo != nil ||
+		f.Search != nil || len(f.Tags) > 0 || len(f.Metadata) > 0
+}
@@ -0,0 +1,79 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"github.com/ilindan-dev/delayed-notifier/internal/auth"
+	"github.com/ilindan-dev/delayed-notifier/internal/domain"
+	"github.com/ilindan-dev/delayed-notifier/internal/domain/model"
+	repo "github.com/ilindan-dev/delayed-notifier/internal/domain/repository"
+	"time"
+)
+
+// DeliveryWindowInput describes a delivery window, see model.DeliveryWindow.
+// Days are short weekday names such as "mon", times of day look like "09:00".
+type DeliveryWindowInput struct {
+	Timezone   string
+	Days       []string
+	Hours      *ClockRangeInput
+	QuietHours *ClockRangeInput
+}
+
+// ClockRangeInput describes a daily range of times of day, e.g. from "22:00" to "07:00".
+type ClockRangeInput struct {
+	Start string
+	End   string
+}
+
+// SetRecipientWindow sets the delivery window of a recipient of the tenant, which applies
+// to all their notifications without a window of their own. The time zone is required.
+func (s *NotificationService) SetRecipientWindow(ctx context.Context, channel model.Channel, recipient string, in DeliveryWindowInput) (*model.DeliveryWindow, error) {
+	var invalid domain.ValidationError
+	recipient, err := normalizeRecipient(channel, recipient)
+	if err != nil {
+		invalid.Add(err)
+	}
+	window := resolveWindow(in, "", "", time.Now().UTC(), &invalid)
+	if err := invalid.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := s.windows.Put(ctx, auth.TenantFromContext(ctx), channel, recipient, window); err != nil {
+		s.logger.Error().Err(err).Str("channel", string(channel)).Msg("failed to set recipient delivery window")
+		return nil, err
+	}
+	return window, nil
+}
+
+// GetRecipientWindow returns the delivery window of a recipient of the tenant,
+// or repo.ErrNotFound if the recipient has none.
+func (s *NotificationService) GetRecipientWindow(ctx context.Context, channel model.Channel, recipient string) (*model.DeliveryWindow, error) {
+	recipient, err := normalizeRecipient(channel, recipient)
+	if err != nil {
+		return nil, err
+	}
+	return s.windows.Get(ctx, auth.TenantFromContext(ctx), channel, recipient)
+}
+
+// DeleteRecipientWindow removes the delivery window of a recipient of the tenant,
+// or returns repo.ErrNotFound if the recipient has none.
+func (s *NotificationService) DeleteRecipientWindow(ctx context.Context, channel model.Channel, recipient string) error {
+	recipient, err := normalizeRecipient(channel, recipient)
+	if err != nil {
+		return err
+	}
+	return s.windows.Delete(ctx, auth.TenantFromContext(ctx), channel, recipient)
+}
+
+// DeliveryWindow returns the delivery window that applies to a notification: its own,
+// or else the window of its recipient. It returns nil if neither has one.
+func (s *NotificationService) DeliveryWindow(ctx context.Context, n *model.Notification) (*model.DeliveryWindow, error) {
+	if n.DeliveryWindow != nil {
+		return n.DeliveryWindow, nil
+	}
+	window, err := s.windows.Get(ctx, auth.TenantFromContext(ctx), n.Channel, n.Recipient())
+	if errors.Is(err, repo.ErrNotFound) {
+		return nil, nil
+	}
+	return window, err
+}
@@ -2,115 +2,592 @@ package service
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/google/uuid"
+	"github.com/ilindan-dev/delayed-notifier/internal/auth"
+	"github.com/ilindan-dev/delayed-notifier/internal/config"
+	"github.com/ilindan-dev/delayed-notifier/internal/domain"
 	"github.com/ilindan-dev/delayed-notifier/internal/domain/model"
 	repo "github.com/ilindan-dev/delayed-notifier/internal/domain/repository"
 	"github.com/rs/zerolog"
-	"net/mail"
 	"strconv"
+	"strings"
+	"sync"
 	"time"
 )
 
+var (
+	// ErrBatchTooLarge is returned when a batch holds more than MaxBatchSize notifications.
+	ErrBatchTooLarge = &domain.Error{
+		Code:    domain.CodeBatchTooLarge,
+		Field:   "notifications",
+		Message: fmt.Sprintf("a batch may hold at most %d notifications", MaxBatchSize),
+	}
+	// ErrInvalidPageToken is returned when a list page token is malformed.
+	ErrInvalidPageToken = &domain.Error{Code: domain.CodeInvalidPageToken, Field: "page_token", Message: "invalid page token"}
+	// ErrIdempotencyKeyReused is returned when an idempotency key is used again for a different request.
+	ErrIdempotencyKeyReused = &domain.Error{Code: domain.CodeDuplicate, Message: "idempotency key was already used for a different request"}
+)
+
+// idempotencyNamespace derives notification IDs from idempotency keys.
+var idempotencyNamespace = uuid.MustParse("3f6c1f0e-8a4b-4c51-9d3e-6b1f2a7c9e15")
+
+const (
+	// MaxBatchSize is the maximum number of notifications created in one batch.
+	MaxBatchSize = 100
+	// DefaultPageSize is the number of notifications listed per page if no limit is given.
+	DefaultPageSize = 50
+	// MaxPageSize is the maximum number of notifications listed per page.
+	MaxPageSize = 500
+	// MaxTags is the maximum number of tags of a notification, and MaxTagLength their maximum length.
+	MaxTags      = 20
+	MaxTagLength = 64
+	// MaxMetadataSize is the maximum size of the metadata of a notification, encoded as JSON.
+	MaxMetadataSize = 4096
+)
+
+// CreateNotificationInput describes a notification to create.
+type CreateNotificationInput struct {
+	Recipient string
+	Channel   model.Channel
+	Subject   string
+	Message   string
+	// ScheduledAt is the absolute send time. Alternatively, LocalTime and Timezone give a
+	// wall-clock date and time such as "2026-03-29T09:00", or a time of day such as "09:00"
+	// for its next occurrence. Timezone is stored with the notification, so that later
+	// local-time reschedules default to it.
+	ScheduledAt time.Time
+	LocalTime   string
+	Timezone    string
+	// Delay and SendNow schedule relative to the server's clock instead: Delay is an
+	// ISO 8601 duration such as "PT15M" to send after, and SendNow sends immediately,
+	// bypassing the wait queue. The resolved absolute time is stored as ScheduledAt.
+	Delay   string
+	SendNow bool
+	// Priority defaults to normal. Higher priorities overtake lower ones waiting to be processed.
+	Priority model.Priority
+	// Tags and Metadata correlate the notification with the caller's own entities. They are
+	// returned with the notification, filterable in lists and included in callbacks.
+	Tags     []string
+	Metadata map[string]any
+	// AuthorID is ignored for authenticated callers; the principal is recorded instead.
+	AuthorID *string
+	// CallbackURL optionally receives a signed event after each terminal status transition.
+	CallbackURL *string
+	// IdempotencyKey optionally makes retries safe: creating a notification with a key
+	// the tenant has already used returns the existing notification instead. Reusing a
+	// key for a different request fails with ErrIdempotencyKeyReused.
+	IdempotencyKey string
+	// DeliveryWindow optionally restricts when the notification may be delivered. Its time
+	// zone defaults to Timezone. Without a window, the window of the recipient applies.
+	DeliveryWindow *DeliveryWindowInput
+}
+
+// RescheduleInput describes the new send time of a notification, either as an absolute
+// ScheduledAt or as a LocalTime in Timezone, which defaults to the notification's time zone.
+// A given Timezone replaces the notification's one.
+type RescheduleInput struct {
+	ScheduledAt time.Time
+	LocalTime   string
+	Timezone    string
+	// Version, if set, is the version of the notification the change is based on.
+	Version *int
+}
+
+// BatchResult is the outcome of creating a single notification of a batch.
+// Exactly one of Notification and Err is set.
+type BatchResult struct {
+	Notification *model.Notification
+	Err          error
+}
+
 // NotificationService encapsulates the business logic for managing notifications.
 // It orchestrates the repository and the queue.
+// Every operation is scoped to the tenant resolved from the context.
 type NotificationService struct {
-	repo   repo.NotificationRepository
-	queue  repo.NotificationQueue
-	logger zerolog.Logger
+	repo       repo.NotificationRepository
+	queue      repo.NotificationQueue
+	quotas     repo.QuotaStore
+	callbacks  repo.CallbackQueue
+	deliveries repo.CallbackDeliveryRepository
+	events     repo.StatusEventStream
+	windows    repo.DeliveryWindowRepository
+	bulkJobs   repo.BulkJobStore
+	tenants    map[string]config.TenantConfig
+	validator  validator
+	logger     zerolog.Logger
+
+	// jobs tracks the running bulk jobs, which stopJobs interrupts on shutdown.
+	jobs     sync.WaitGroup
+	jobsCtx  context.Context
+	stopJobs context.CancelFunc
 }
 
 func NewNotificationService(
+	cfg *config.Config,
 	repo repo.NotificationRepository,
 	queue repo.NotificationQueue,
-	logger zerolog.Logger,
+	quotas repo.QuotaStore,
+	callbacks repo.CallbackQueue,
+	deliveries repo.CallbackDeliveryRepository,
+	events repo.StatusEventStream,
+	windows repo.DeliveryWindowRepository,
+	bulkJobs repo.BulkJobStore,
+	logger *zerolog.Logger,
 ) *NotificationService {
+	jobsCtx, stopJobs := context.WithCancel(context.Background())
 	return &NotificationService{
-		repo:   repo,
-		queue:  queue,
-		logger: logger.With().Str("layer", "service").Logger(),
+		repo:       repo,
+		queue:      queue,
+		quotas:     quotas,
+		callbacks:  callbacks,
+		deliveries: deliveries,
+		events:     events,
+		windows:    windows,
+		bulkJobs:   bulkJobs,
+		tenants:    cfg.Tenants,
+		validator:  validator{cfg: cfg.Validation},
+		logger:     logger.With().Str("layer", "service").Logger(),
+		jobsCtx:    jobsCtx,
+		stopJobs:   stopJobs,
 	}
 }
 
 // CreateNotification orchestrates the creation of a new notification.
-// It validates input, saves the notification, and publishes it to the queue.
-func (s *NotificationService) CreateNotification(ctx context.Context, recipient string, channel model.Channel, subject, message string, scheduledAt time.Time, authorID *string) (*model.Notification, error) {
-	s.logger.Info().Str("channel", string(channel)).Msg("creating new notification")
+// It validates and normalizes input, saves the notification, and publishes it to the queue.
+// If the caller is authenticated, the principal is recorded as the author and in.AuthorID is ignored.
+// If in.CallbackURL is set, it receives a signed event once the notification reaches a terminal status.
+func (s *NotificationService) CreateNotification(ctx context.Context, in CreateNotificationInput) (*model.Notification, error) {
+	s.logger.Info().Str("channel", string(in.Channel)).Msg("creating new notification")
+
+	authorID := in.AuthorID
+	if principal, ok := auth.PrincipalFromContext(ctx); ok {
+		authorID = &principal.ID
+	}
+	tenantID := auth.TenantFromContext(ctx)
+	// The hash is taken before validation resolves relative schedules, which differ between retries.
+	var hash string
+	if in.IdempotencyKey != "" {
+		hash = requestHash(in)
+	}
+
+	window, err := s.validator.validateCreate(&in, time.Now().UTC())
+	if err != nil {
+		s.logger.Warn().Err(err).Msg("invalid notification")
+		return nil, err
+	}
 
 	var notification *model.Notification
+	if in.Channel == model.ChannelTelegram {
+		// The chat ID was checked by the validator.
+		chatID, _ := strconv.ParseInt(in.Recipient, 10, 64)
+		notification = model.NewTelegramNotification(tenantID, chatID, in.Subject, in.Message, in.ScheduledAt, authorID)
+	} else {
+		notification = model.NewEmailNotification(tenantID, in.Recipient, in.Subject, in.Message, in.ScheduledAt, authorID)
+	}
+	notification.Priority = in.Priority
+	notification.Tags = in.Tags
+	notification.Metadata = in.Metadata
+	notification.CallbackURL = in.CallbackURL
+	if in.Timezone != "" {
+		notification.Timezone = &in.Timezone
+	}
+	notification.DeliveryWindow = window
+	if in.IdempotencyKey != "" {
+		notification.ID = idempotentID(tenantID, in.IdempotencyKey)
+		notification.RequestHash = hash
+	}
 
-	switch channel {
-	case model.ChannelEmail:
-		if _, err := mail.ParseAddress(recipient); err != nil {
-			s.logger.Warn().Err(err).Str("recipient", recipient).Msg("invalid recipient")
-			return nil, fmt.Errorf("invalid email format: %w", err)
-		}
-		notification = model.NewEmailNotification(recipient, subject, message, scheduledAt, authorID)
-	case model.ChannelTelegram:
-		chatID, err := strconv.ParseInt(recipient, 10, 64)
-		if err != nil {
-			s.logger.Warn().Err(err).Str("recipient", recipient).Msg("invalid recipient")
-			return nil, fmt.Errorf("invalid telegram chat id: %w", err)
-		}
-		notification = model.NewTelegramNotification(chatID, subject, message, scheduledAt, authorID)
-	default:
-		s.logger.Warn().Str("channel", string(channel)).Msg("invalid channel")
-		return nil, fmt.Errorf("unknown channel: %s", channel)
+	quotaDay := time.Now().UTC()
+	if err := s.reserveQuota(ctx, tenantID, quotaDay); err != nil {
+		return nil, err
 	}
 
 	createdNotification, err := s.repo.Save(ctx, notification)
 	if err != nil {
+		s.releaseQuota(ctx, tenantID, quotaDay)
+		if in.IdempotencyKey != "" && errors.Is(err, repo.ErrDuplicateRecord) {
+			return s.replayNotification(ctx, notification.ID, hash, in.SendNow, err)
+		}
 		s.logger.Error().Err(err).Msg("failed to save notification")
 		return nil, err
 	}
 	s.logger.Info().Stringer("id", createdNotification.ID).Msg("notification saved successfully")
 
-	err = s.queue.Publish(ctx, createdNotification)
-	if err != nil {
+	if err := s.publish(ctx, createdNotification, in.SendNow); err != nil {
+		// A retry with the same idempotency key publishes the saved notification again.
 		s.logger.Error().Err(err).Stringer("id", createdNotification.ID).Msg("CRITICAL: failed to publish notification to queue after saving")
 		return nil, fmt.Errorf("failed to schedule notification: %w", err)
 	}
 	s.logger.Info().Stringer("id", createdNotification.ID).Msg("notification published to queue")
+	s.publishStatusEvent(ctx, createdNotification)
 
 	return createdNotification, nil
 }
 
+// publish queues n for its scheduled time, or for immediate delivery if sendNow is set.
+func (s *NotificationService) publish(ctx context.Context, n *model.Notification, sendNow bool) error {
+	if sendNow {
+		return s.queue.PublishNow(ctx, n)
+	}
+	return s.queue.Publish(ctx, n)
+}
+
+// replayNotification returns the notification created earlier with the same idempotency key.
+// If the caller may not see it, the key was used by someone else and dupErr is returned;
+// if it was created by a different request, ErrIdempotencyKeyReused.
+// A replayed notification that is still scheduled is published again, since the request
+// that created it may have failed after saving it; the worker skips the extra message.
+func (s *NotificationService) replayNotification(ctx context.Context, id uuid.UUID, hash string, sendNow bool, dupErr error) (*model.Notification, error) {
+	existing, err := s.GetNotificationByID(ctx, id)
+	if errors.Is(err, repo.ErrNotFound) {
+		return nil, dupErr
+	}
+	if err != nil {
+		return nil, err
+	}
+	// Notifications created before request hashes were stored have none to compare.
+	if existing.RequestHash != "" && existing.RequestHash != hash {
+		s.logger.Warn().Stringer("id", id).Msg("idempotency key reused for a different request")
+		return nil, ErrIdempotencyKeyReused
+	}
+	if existing.Status == model.StatusScheduled {
+		if err := s.publish(ctx, existing, sendNow); err != nil {
+			s.logger.Error().Err(err).Stringer("id", id).Msg("CRITICAL: failed to publish replayed notification to queue")
+			return nil, fmt.Errorf("failed to schedule notification: %w", err)
+		}
+	}
+	s.logger.Info().Stringer("id", id).Msg("idempotent retry, returning existing notification")
+	return existing, nil
+}
+
+// requestHash returns a hash of the creation request in, without its idempotency key.
+func requestHash(in CreateNotificationInput) string {
+	in.IdempotencyKey = ""
+	// The input holds only JSON-encodable values, and maps are encoded with sorted keys.
+	data, _ := json.Marshal(in)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// CreateNotifications creates a batch of notifications. Each notification is created
+// independently, so a failing item does not affect the others; the results are
+// returned in the order of the inputs.
+// If idempotencyKey is set, each item is created with a key derived from it and the
+// item's position, so retrying the same batch does not create duplicates.
+func (s *NotificationService) CreateNotifications(ctx context.Context, inputs []CreateNotificationInput, idempotencyKey string) ([]BatchResult, error) {
+	if len(inputs) > MaxBatchSize {
+		return nil, ErrBatchTooLarge
+	}
+
+	results := make([]BatchResult, len(inputs))
+	for i, in := range inputs {
+		if idempotencyKey != "" {
+			in.IdempotencyKey = idempotencyKey + "/" + strconv.Itoa(i)
+		}
+		results[i].Notification, results[i].Err = s.CreateNotification(ctx, in)
+	}
+	return results, nil
+}
+
+// ListNotifications returns a page of the tenant's notifications matching the filter,
+// newest first, and the token of the next page, which is empty on the last page.
+// Non-admin callers only see their own notifications, whatever the filter says.
+func (s *NotificationService) ListNotifications(ctx context.Context, filter model.NotificationFilter, pageToken string, limit int) ([]*model.Notification, string, error) {
+	if principal, ok := auth.PrincipalFromContext(ctx); ok && !principal.IsAdmin() {
+		filter.AuthorID = &principal.ID
+	}
+	if limit <= 0 {
+		limit = DefaultPageSize
+	}
+	limit = min(limit, MaxPageSize)
+
+	var after *model.Cursor
+	if pageToken != "" {
+		cursor, err := decodePageToken(pageToken)
+		if err != nil {
+			return nil, "", err
+		}
+		after = &cursor
+	}
+
+	// One extra row tells whether there is a next page.
+	notifications, err := s.repo.List(ctx, auth.TenantFromContext(ctx), filter, after, limit+1)
+	if err != nil {
+		s.logger.Error().Err(err).Msg("failed to list notifications")
+		return nil, "", err
+	}
+	if len(notifications) <= limit {
+		return notifications, "", nil
+	}
+	notifications = notifications[:limit]
+	return notifications, encodePageToken(model.CursorOf(notifications[limit-1])), nil
+}
+
 // GetNotificationByID retrieves a notification by its ID.
-// The business logic is simple: just ask the repository.
 // The repository decorator handles the cache-aside logic transparently.
+// Authenticated non-admin callers only see their own notifications; for anything
+// else ErrNotFound is returned so as not to reveal that the notification exists.
 func (s *NotificationService) GetNotificationByID(ctx context.Context, id uuid.UUID) (*model.Notification, error) {
-	n, err := s.repo.GetByID(ctx, id)
+	n, err := s.repo.GetByID(ctx, auth.TenantFromContext(ctx), id)
 	if err != nil {
 		s.logger.Error().Err(err).Msgf("Failed to get notification by ID: %s", id)
 		return nil, err
 	}
+	if principal, ok := auth.PrincipalFromContext(ctx); ok && !principal.CanAccess(n) {
+		s.logger.Warn().Stringer("id", id).Str("principal", principal.ID).Msg("access to foreign notification denied")
+		return nil, repo.ErrNotFound
+	}
 	s.logger.Info().Msgf("Getting notification by ID: %s", id)
 	return n, nil
 }
 
-// UpdateNotification is used by the consumer to update the status after a send attempt.
+// TransitionNotification is used by the consumer to move a notification from status from
+// to n.Status, e.g. to claim it before a send attempt and to record the outcome.
+// Transitions the state machine of notifications does not allow fail with ErrInvalidTransition,
+// and those of notifications that changed since version n.Version with ErrConflict.
 // The repository decorator will handle cache invalidation.
-func (s *NotificationService) UpdateNotification(ctx context.Context, n *model.Notification) error {
-	if err := s.repo.Update(ctx, n); err != nil {
+// Terminal transitions are reported to the notification's callback URL.
+func (s *NotificationService) TransitionNotification(ctx context.Context, n *model.Notification, from model.Status) error {
+	if !from.CanTransitionTo(n.Status) {
+		return domain.ErrInvalidTransition.Withf("cannot move a %s notification to %s", from, n.Status)
+	}
+	if err := s.repo.Transition(ctx, n, from); err != nil {
+		if errors.Is(err, repo.ErrConflict) {
+			s.logger.Warn().Stringer("id", n.ID).Int("version", n.Version).Msg("notification changed concurrently")
+			return err
+		}
 		s.logger.Error().Err(err).Msgf("Failed to update notification: %s", n.ID)
 		return err
 	}
+	s.publishStatusEvent(ctx, n)
+	if n.Status.IsTerminal() {
+		s.publishCallback(ctx, n)
+	}
 	return nil
 }
 
-// CancelNotification cancels a scheduled notification.
-func (s *NotificationService) CancelNotification(ctx context.Context, id uuid.UUID) error {
-	notification, err := s.repo.GetByID(ctx, id)
+// CancelNotification cancels a scheduled notification. If version is not nil, the
+// notification is only cancelled while it still has that version; otherwise
+// ErrVersionConflict is returned.
+func (s *NotificationService) CancelNotification(ctx context.Context, id uuid.UUID, version *int) error {
+	notification, err := s.GetNotificationByID(ctx, id)
 	if err != nil {
 		s.logger.Error().Err(err).Str("notification_id", id.String()).Msg("can't get notification")
 		return err
 	}
+	if err := checkVersion(notification, version); err != nil {
+		return err
+	}
 
-	if notification.Status != model.StatusScheduled {
+	if !notification.Status.CanTransitionTo(model.StatusCancelled) {
 		s.logger.Warn().Str("notification_id", id.String()).Msg("can't cancel notification")
-		return fmt.Errorf("cannot cancel notification with status: %s", notification.Status)
+		return domain.ErrNotCancellable.Withf("cannot cancel a %s notification", notification.Status)
 	}
 
 	s.logger.Info().Str("notification_id", id.String()).Msg("cancel notification")
-	return s.repo.Delete(ctx, id)
+	cancelled, err := s.repo.Delete(ctx, notification.TenantID, id, version)
+	if err != nil {
+		if errors.Is(err, repo.ErrNotFound) && version != nil {
+			return domain.ErrVersionConflict
+		}
+		if errors.Is(err, repo.ErrNotFound) {
+			// The notification left the scheduled status after it was read.
+			return domain.ErrNotCancellable
+		}
+		return err
+	}
+
+	s.publishStatusEvent(ctx, cancelled)
+	s.publishCallback(ctx, cancelled)
+	return nil
+}
+
+// RescheduleNotification moves a scheduled notification to a new time and queues it again.
+// The message queued for the old time is skipped by the worker when it comes due.
+func (s *NotificationService) RescheduleNotification(ctx context.Context, id uuid.UUID, in RescheduleInput) (*model.Notification, error) {
+	notification, err := s.GetNotificationByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkVersion(notification, in.Version); err != nil {
+		return nil, err
+	}
+	if notification.Status != model.StatusScheduled {
+		s.logger.Warn().Stringer("id", id).Str("status", string(notification.Status)).Msg("can't reschedule notification")
+		return nil, domain.ErrNotScheduled.Withf("cannot reschedule a %s notification", notification.Status)
+	}
+	if in.Timezone == "" && notification.Timezone != nil {
+		in.Timezone = *notification.Timezone
+	}
+	if err := s.validator.validateReschedule(&in, time.Now().UTC()); err != nil {
+		return nil, err
+	}
+	var timezone *string
+	if in.Timezone != "" {
+		timezone = &in.Timezone
+	}
+
+	rescheduled, err := s.repo.Reschedule(ctx, notification.TenantID, id, in.ScheduledAt, timezone, in.Version)
+	if err != nil {
+		if errors.Is(err, repo.ErrNotFound) && in.Version != nil {
+			return nil, domain.ErrVersionConflict
+		}
+		if errors.Is(err, repo.ErrNotFound) {
+			// The notification left the scheduled status after it was read.
+			return nil, domain.ErrNotScheduled
+		}
+		return nil, err
+	}
+
+	if err := s.queue.Publish(ctx, rescheduled); err != nil {
+		s.logger.Error().Err(err).Stringer("id", id).Msg("CRITICAL: failed to publish rescheduled notification to queue")
+		return nil, fmt.Errorf("failed to schedule notification: %w", err)
+	}
+	s.logger.Info().Stringer("id", id).Time("scheduled_at", rescheduled.ScheduledAt).Msg("notification rescheduled")
+	s.publishStatusEvent(ctx, rescheduled)
+
+	return rescheduled, nil
+}
+
+// checkVersion returns ErrVersionConflict if version is set and the notification has another one.
+func checkVersion(n *model.Notification, version *int) error {
+	if version != nil && n.Version != *version {
+		return domain.ErrVersionConflict.Withf("notification has version %d, not %d", n.Version, *version)
+	}
+	return nil
+}
+
+// NextStatusEvents waits up to wait for the status events following cursor and returns
+// those the caller may see, optionally only those of one notification, together with
+// the cursor to continue from. An empty cursor starts after the newest event.
+// The caller's access to a specific notification must be checked beforehand.
+func (s *NotificationService) NextStatusEvents(ctx context.Context, notificationID *uuid.UUID, cursor string, wait time.Duration) ([]*model.StatusEvent, string, error) {
+	tenantID := auth.TenantFromContext(ctx)
+	if cursor == "" {
+		latest, err := s.events.LatestID(ctx, tenantID)
+		if err != nil {
+			return nil, "", err
+		}
+		cursor = latest
+	}
+
+	events, err := s.events.ReadAfter(ctx, tenantID, cursor, wait)
+	if err != nil {
+		return nil, cursor, err
+	}
+
+	principal, authenticated := auth.PrincipalFromContext(ctx)
+	visible := make([]*model.StatusEvent, 0, len(events))
+	for _, e := range events {
+		cursor = e.ID
+		if notificationID != nil && e.NotificationID != *notificationID {
+			continue
+		}
+		if authenticated && !principal.CanSee(e) {
+			continue
+		}
+		visible = append(visible, e)
+	}
+	return visible, cursor, nil
+}
+
+// publishStatusEvent appends the notification's current state to the live event stream.
+// Live updates are best effort, so a failure is only logged.
+func (s *NotificationService) publishStatusEvent(ctx context.Context, n *model.Notification) {
+	if err := s.events.Publish(ctx, model.NewStatusEvent(n)); err != nil {
+		s.logger.Warn().Err(err).Stringer("id", n.ID).Str("status", string(n.Status)).Msg("failed to publish status event")
+	}
+}
+
+// ListCallbackDeliveries returns the callback delivery attempts of a notification
+// the caller may access, oldest first.
+func (s *NotificationService) ListCallbackDeliveries(ctx context.Context, id uuid.UUID) ([]*model.CallbackDelivery, error) {
+	notification, err := s.GetNotificationByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.deliveries.ListByNotification(ctx, notification.TenantID, id)
+}
+
+// publishCallback queues the callback event of a terminal transition, if the notification has a callback URL.
+// The transition is already stored, so a failure is logged rather than returned.
+func (s *NotificationService) publishCallback(ctx context.Context, n *model.Notification) {
+	if n.CallbackURL == nil {
+		return
+	}
+	event := model.NewCallbackEvent(n, *n.CallbackURL)
+	if err := s.callbacks.PublishCallback(ctx, event); err != nil {
+		s.logger.Error().Err(err).Stringer("id", n.ID).Str("status", string(n.Status)).Msg("CRITICAL: failed to publish status callback")
+		return
+	}
+	s.logger.Info().Stringer("id", n.ID).Stringer("event_id", event.ID).Str("status", string(n.Status)).Msg("status callback queued")
+}
+
+// idempotentID derives the notification ID of an idempotency key, unique per tenant.
+func idempotentID(tenantID, key string) uuid.UUID {
+	return uuid.NewSHA1(idempotencyNamespace, []byte(tenantID+"\x00"+key))
+}
+
+// reserveQuota counts a new notification against the tenant's daily quota.
+// If the quota store is unavailable the notification is let through.
+func (s *NotificationService) reserveQuota(ctx context.Context, tenantID string, day time.Time) error {
+	quota := s.tenants[tenantID].DailyQuota
+	if quota <= 0 {
+		return nil
+	}
+
+	count, err := s.quotas.Increment(ctx, tenantID, day)
+	if err != nil {
+		s.logger.Warn().Err(err).Str("tenant_id", tenantID).Msg("quota store unavailable, skipping quota check")
+		return nil
+	}
+	if count > int64(quota) {
+		s.releaseQuota(ctx, tenantID, day)
+		s.logger.Warn().Str("tenant_id", tenantID).Int("quota", quota).Msg("daily quota exceeded")
+		return domain.ErrQuotaExceeded
+	}
+	return nil
+}
+
+// releaseQuota gives back a quota unit reserved for a notification that was not created.
+func (s *NotificationService) releaseQuota(ctx context.Context, tenantID string, day time.Time) {
+	if s.tenants[tenantID].DailyQuota <= 0 {
+		return
+	}
+	if err := s.quotas.Decrement(ctx, tenantID, day); err != nil {
+		s.logger.Warn().Err(err).Str("tenant_id", tenantID).Msg("failed to release quota")
+	}
+}
+
+// encodePageToken turns a list position into an opaque page token.
+func encodePageToken(c model.Cursor) string {
+	raw := strconv.FormatInt(c.CreatedAt.UnixNano(), 10) + "_" + c.ID.String()
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// decodePageToken parses a page token created by encodePageToken.
+func decodePageToken(token string) (model.Cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return model.Cursor{}, ErrInvalidPageToken
+	}
+	nanos, id, ok := strings.Cut(string(raw), "_")
+	if !ok {
+		return model.Cursor{}, ErrInvalidPageToken
+	}
+	unixNano, err := strconv.ParseInt(nanos, 10, 64)
+	if err != nil {
+		return model.Cursor{}, ErrInvalidPageToken
+	}
+	parsedID, err := uuid.Parse(id)
+	if err != nil {
+		return model.Cursor{}, ErrInvalidPageToken
+	}
+	return model.Cursor{CreatedAt: time.Unix(0, unixNano).UTC(), ID: parsedID}, nil
 }
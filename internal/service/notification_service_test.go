@@ -0,0 +1,235 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/ilindan-dev/delayed-notifier/internal/auth"
+	"github.com/ilindan-dev/delayed-notifier/internal/config"
+	"github.com/ilindan-dev/delayed-notifier/internal/domain/model"
+	repo "github.com/ilindan-dev/delayed-notifier/internal/domain/repository"
+	"github.com/rs/zerolog"
+	"testing"
+	"time"
+)
+
+// fakeNotificationRepo stores notifications in memory, scoped to their tenants like the
+// database queries. Only the methods the tests use are implemented. Saves fail with saveErr,
+// and beforeDelete, if set, changes a notification concurrently before it is cancelled.
+type fakeNotificationRepo struct {
+	repo.NotificationRepository
+	items        map[uuid.UUID]*model.Notification
+	saveErr      error
+	beforeDelete func(n *model.Notification)
+}
+
+func (r *fakeNotificationRepo) Save(_ context.Context, n *model.Notification) (*model.Notification, error) {
+	if r.saveErr != nil {
+		return nil, r.saveErr
+	}
+	saved := *n
+	saved.Version = 1
+	r.items[saved.ID] = &saved
+	result := saved
+	return &result, nil
+}
+
+func (r *fakeNotificationRepo) GetByID(_ context.Context, tenantID string, id uuid.UUID) (*model.Notification, error) {
+	n, ok := r.items[id]
+	if !ok || n.TenantID != tenantID {
+		return nil, repo.ErrNotFound
+	}
+	result := *n
+	return &result, nil
+}
+
+func (r *fakeNotificationRepo) Delete(_ context.Context, tenantID string, id uuid.UUID, version *int) (*model.Notification, error) {
+	n, ok := r.items[id]
+	if ok && r.beforeDelete != nil {
+		r.beforeDelete(n)
+	}
+	if !ok || n.TenantID != tenantID || n.Status != model.StatusScheduled || (version != nil && n.Version != *version) {
+		return nil, repo.ErrNotFound
+	}
+	n.Status = model.StatusCancelled
+	n.Version++
+	result := *n
+	return &result, nil
+}
+
+// fakeQuotaStore counts the reserved quota units per tenant.
+type fakeQuotaStore struct {
+	counts map[string]int64
+}
+
+func (q *fakeQuotaStore) Increment(_ context.Context, tenantID string, _ time.Time) (int64, error) {
+	q.counts[tenantID]++
+	return q.counts[tenantID], nil
+}
+
+func (q *fakeQuotaStore) Decrement(_ context.Context, tenantID string, _ time.Time) error {
+	q.counts[tenantID]--
+	return nil
+}
+
+type fakeQueue struct{ repo.NotificationQueue }
+
+func (fakeQueue) Publish(context.Context, *model.Notification) error { return nil }
+
+// fakeEvents records the published status events.
+type fakeEvents struct {
+	repo.StatusEventStream
+	published []*model.StatusEvent
+}
+
+func (e *fakeEvents) Publish(_ context.Context, event *model.StatusEvent) error {
+	e.published = append(e.published, event)
+	return nil
+}
+
+// fakeCallbacks records the queued callback events.
+type fakeCallbacks struct {
+	repo.CallbackQueue
+	published []*model.CallbackEvent
+}
+
+func (c *fakeCallbacks) PublishCallback(_ context.Context, event *model.CallbackEvent) error {
+	c.published = append(c.published, event)
+	return nil
+}
+
+// newTestNotificationService returns a service on top of notifications, with a daily
+// quota of 10 for the "acme" tenant.
+func newTestNotificationService(notifications *fakeNotificationRepo, quotas *fakeQuotaStore) *NotificationService {
+	logger := zerolog.Nop()
+	cfg := &config.Config{Tenants: map[string]config.TenantConfig{"acme": {DailyQuota: 10}}}
+	return NewNotificationService(cfg, notifications, fakeQueue{}, quotas, &fakeCallbacks{}, nil, &fakeEvents{}, nil, nil, &logger)
+}
+
+func TestTenantIsolation(t *testing.T) {
+	notifications := &fakeNotificationRepo{items: make(map[uuid.UUID]*model.Notification)}
+	s := newTestNotificationService(notifications, &fakeQuotaStore{counts: make(map[string]int64)})
+
+	owner := &model.Principal{ID: "billing-service", TenantID: "acme", Scopes: []model.Scope{model.ScopeCreate, model.ScopeRead, model.ScopeCancel}}
+	created, err := s.CreateNotification(auth.WithPrincipal(context.Background(), owner), CreateNotificationInput{
+		Recipient: "user@example.com",
+		Channel:   model.ChannelEmail,
+		Subject:   "Hello",
+		Delay:     "PT1H",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if created.TenantID != "acme" {
+		t.Fatalf("got tenant %q, want the caller's %q", created.TenantID, "acme")
+	}
+
+	tests := []struct {
+		name      string
+		principal *model.Principal
+	}{
+		{
+			name:      "admin of another tenant",
+			principal: &model.Principal{ID: "globex-admin", TenantID: "globex", Scopes: []model.Scope{model.ScopeAdmin}},
+		},
+		{
+			name:      "same principal ID in another tenant",
+			principal: &model.Principal{ID: owner.ID, TenantID: "globex", Scopes: owner.Scopes},
+		},
+		{
+			name:      "another author of the same tenant",
+			principal: &model.Principal{ID: "marketing-service", TenantID: "acme", Scopes: owner.Scopes},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := auth.WithPrincipal(context.Background(), tt.principal)
+			if _, err := s.GetNotificationByID(ctx, created.ID); !errors.Is(err, repo.ErrNotFound) {
+				t.Errorf("read: got %v, want %v", err, repo.ErrNotFound)
+			}
+			if err := s.CancelNotification(ctx, created.ID, nil); !errors.Is(err, repo.ErrNotFound) {
+				t.Errorf("cancel: got %v, want %v", err, repo.ErrNotFound)
+			}
+			if got := notifications.items[created.ID].Status; got != model.StatusScheduled {
+				t.Errorf("got status %s, want the notification left scheduled", got)
+			}
+		})
+	}
+
+	// The owner still has access.
+	ctx := auth.WithPrincipal(context.Background(), owner)
+	if _, err := s.GetNotificationByID(ctx, created.ID); err != nil {
+		t.Errorf("read by owner: got %v, want no error", err)
+	}
+	if err := s.CancelNotification(ctx, created.ID, nil); err != nil {
+		t.Errorf("cancel by owner: got %v, want no error", err)
+	}
+}
+
+func TestCreateNotificationReleasesQuotaOnFailure(t *testing.T) {
+	notifications := &fakeNotificationRepo{items: make(map[uuid.UUID]*model.Notification), saveErr: errors.New("connection refused")}
+	quotas := &fakeQuotaStore{counts: make(map[string]int64)}
+	s := newTestNotificationService(notifications, quotas)
+
+	ctx := auth.WithTenant(context.Background(), "acme")
+	in := CreateNotificationInput{Recipient: "user@example.com", Channel: model.ChannelEmail, Subject: "Hello", Delay: "PT1H"}
+	for range 3 {
+		if _, err := s.CreateNotification(ctx, in); !errors.Is(err, notifications.saveErr) {
+			t.Fatalf("got %v, want %v", err, notifications.saveErr)
+		}
+	}
+	if got := quotas.counts["acme"]; got != 0 {
+		t.Errorf("got %d quota units used, want failed creates to release theirs", got)
+	}
+
+	notifications.saveErr = nil
+	if _, err := s.CreateNotification(ctx, in); err != nil {
+		t.Fatal(err)
+	}
+	if got := quotas.counts["acme"]; got != 1 {
+		t.Errorf("got %d quota units used, want 1", got)
+	}
+}
+
+func TestCancelNotificationPublishesCancelledRow(t *testing.T) {
+	notifications := &fakeNotificationRepo{items: make(map[uuid.UUID]*model.Notification)}
+	s := newTestNotificationService(notifications, &fakeQuotaStore{counts: make(map[string]int64)})
+	events, callbacks := s.events.(*fakeEvents), s.callbacks.(*fakeCallbacks)
+
+	ctx := auth.WithTenant(context.Background(), "acme")
+	callbackURL := "https://example.com/hooks"
+	created, err := s.CreateNotification(ctx, CreateNotificationInput{
+		Recipient:   "user@example.com",
+		Channel:     model.ChannelEmail,
+		Subject:     "Hello",
+		Delay:       "PT1H",
+		CallbackURL: &callbackURL,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The notification is rescheduled after the service reads it, but before it is cancelled.
+	rescheduledAt := created.ScheduledAt.Add(time.Hour)
+	notifications.beforeDelete = func(n *model.Notification) {
+		n.ScheduledAt = rescheduledAt
+		n.Version++
+	}
+	if err := s.CancelNotification(ctx, created.ID, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	stored := notifications.items[created.ID]
+	if stored.Status != model.StatusCancelled || stored.Version != 3 {
+		t.Fatalf("got status %s in version %d, want %s in version 3", stored.Status, stored.Version, model.StatusCancelled)
+	}
+	if got := events.published[len(events.published)-1]; got.Status != model.StatusCancelled {
+		t.Errorf("got a %s status event, want %s", got.Status, model.StatusCancelled)
+	}
+	if len(callbacks.published) != 1 {
+		t.Fatalf("got %d callbacks, want 1", len(callbacks.published))
+	}
+	if got := callbacks.published[0]; got.Status != model.StatusCancelled || !got.ScheduledAt.Equal(rescheduledAt) {
+		t.Errorf("got callback for %s at %s, want %s at %s of the cancelled row", got.Status, got.ScheduledAt, model.StatusCancelled, rescheduledAt)
+	}
+}
@@ -0,0 +1,72 @@
+package service
+
+import (
+	"context"
+	"github.com/ilindan-dev/delayed-notifier/internal/auth"
+	"github.com/ilindan-dev/delayed-notifier/internal/domain"
+	"github.com/ilindan-dev/delayed-notifier/internal/domain/model"
+	repo "github.com/ilindan-dev/delayed-notifier/internal/domain/repository"
+	"github.com/rs/zerolog"
+)
+
+// ErrOperatorOnly is returned when a caller other than an operator inspects state shared by all tenants.
+var ErrOperatorOnly = &domain.Error{Code: domain.CodeOperatorOnly, Message: "only admins of the default tenant may inspect queues"}
+
+const (
+	// DefaultDeadLetterLimit is the number of dead letters returned if no limit is given.
+	DefaultDeadLetterLimit = 20
+	// MaxDeadLetterLimit is the maximum number of dead letters returned at once.
+	MaxDeadLetterLimit = 200
+)
+
+// QueueService lets operators inspect the message broker.
+// Queues are shared by all tenants, so only operators may use it.
+type QueueService struct {
+	inspector repo.QueueInspector
+	logger    zerolog.Logger
+}
+
+// NewQueueService creates a new instance of QueueService.
+func NewQueueService(inspector repo.QueueInspector, logger *zerolog.Logger) *QueueService {
+	return &QueueService{
+		inspector: inspector,
+		logger:    logger.With().Str("layer", "queue_service").Logger(),
+	}
+}
+
+// QueueStats returns the depth and consumer count of every queue.
+func (s *QueueService) QueueStats(ctx context.Context) ([]model.QueueStats, error) {
+	if err := checkOperator(ctx); err != nil {
+		return nil, err
+	}
+	stats, err := s.inspector.Stats(ctx)
+	if err != nil {
+		s.logger.Error().Err(err).Msg("failed to inspect queues")
+		return nil, err
+	}
+	return stats, nil
+}
+
+// DeadLetters returns up to limit of the oldest dead letters, leaving them in the queue.
+func (s *QueueService) DeadLetters(ctx context.Context, limit int) ([]model.DeadLetter, error) {
+	if err := checkOperator(ctx); err != nil {
+		return nil, err
+	}
+	if limit <= 0 {
+		limit = DefaultDeadLetterLimit
+	}
+	letters, err := s.inspector.PeekDeadLetters(ctx, min(limit, MaxDeadLetterLimit))
+	if err != nil {
+		s.logger.Error().Err(err).Msg("failed to peek dead letters")
+		return nil, err
+	}
+	return letters, nil
+}
+
+// checkOperator returns ErrOperatorOnly unless the caller is an operator or authentication is disabled.
+func checkOperator(ctx context.Context) error {
+	if principal, ok := auth.PrincipalFromContext(ctx); ok && !principal.IsOperator() {
+		return ErrOperatorOnly
+	}
+	return nil
+}
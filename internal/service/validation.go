@@ -0,0 +1,446 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/ilindan-dev/delayed-notifier/internal/config"
+	"github.com/ilindan-dev/delayed-notifier/internal/domain"
+	"github.com/ilindan-dev/delayed-notifier/internal/domain/model"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	// Embeds the time zone database, so that time zones resolve on hosts without one.
+	_ "time/tzdata"
+	"unicode/utf8"
+)
+
+// telegramMarkup is the number of characters the Telegram notifier adds around the
+// subject when it joins the subject and message into a single text.
+const telegramMarkup = len("**\n\n")
+
+// localTimeLayouts are the accepted formats of local wall-clock times: a date and time,
+// or a time of day, which refers to its next occurrence.
+var localTimeLayouts = []struct {
+	layout    string
+	timeOfDay bool
+}{
+	{layout: "2006-01-02T15:04:05"},
+	{layout: "2006-01-02T15:04"},
+	{layout: "15:04:05", timeOfDay: true},
+	{layout: "15:04", timeOfDay: true},
+}
+
+// isoDuration matches an ISO 8601 duration: years, months, weeks, days, hours, minutes and seconds.
+var isoDuration = regexp.MustCompile(`^P(?:(\d+)Y)?(?:(\d+)M)?(?:(\d+)W)?(?:(\d+)D)?(?:T(?:(\d+)H)?(?:(\d+)M)?(?:(\d+(?:[.,]\d+)?)S)?)?$`)
+
+// maxDelay bounds the time part of a delay, so that it fits into a time.Duration.
+const maxDelay = 100 * 365 * 24 * time.Hour
+
+// validator checks notifications against the configured validation rules.
+type validator struct {
+	cfg config.ValidationConfig
+}
+
+// validateCreate checks in and normalizes it in place: the recipient is brought into
+// canonical form, a send time within the past tolerance is moved to now and a missing
+// priority defaults to normal.
+// It returns the delivery window of in, if any, and reports every invalid field,
+// see domain.ValidationError.
+func (v validator) validateCreate(in *CreateNotificationInput, now time.Time) (*model.DeliveryWindow, error) {
+	var invalid domain.ValidationError
+
+	if recipient, err := normalizeRecipient(in.Channel, in.Recipient); err != nil {
+		invalid.Add(err)
+	} else {
+		in.Recipient = recipient
+	}
+
+	if strings.TrimSpace(in.Subject) == "" {
+		invalid.Add(required("subject"))
+	}
+	if v.cfg.RequireMessage && strings.TrimSpace(in.Message) == "" {
+		invalid.Add(required("message"))
+	}
+	for _, err := range v.checkLengths(in.Channel, in.Subject, in.Message) {
+		invalid.Add(err)
+	}
+	if tags, err := normalizeTags(in.Tags); err != nil {
+		invalid.Add(err)
+	} else {
+		in.Tags = tags
+	}
+	if err := checkMetadata(in.Metadata); err != nil {
+		invalid.Add(err)
+	}
+	switch {
+	case in.Priority == "":
+		in.Priority = model.PriorityNormal
+	case !slices.Contains(model.AllPriorities, in.Priority):
+		invalid.Add(domain.ErrInvalidPriority.Withf("unknown priority %q, must be low, normal, high or critical", in.Priority))
+	}
+
+	if v.resolveRelative(in, now, &invalid) {
+		v.resolveSchedule(&in.ScheduledAt, in.LocalTime, &in.Timezone, now, &invalid)
+	}
+
+	var window *model.DeliveryWindow
+	if in.DeliveryWindow != nil {
+		window = resolveWindow(*in.DeliveryWindow, in.Timezone, "delivery_window.", now, &invalid)
+	}
+
+	if in.CallbackURL != nil {
+		if err := validateCallbackURL(*in.CallbackURL); err != nil {
+			invalid.Add(err)
+		}
+	}
+	return window, invalid.Err()
+}
+
+// validateReschedule checks in and resolves its send time like validateCreate.
+func (v validator) validateReschedule(in *RescheduleInput, now time.Time) error {
+	var invalid domain.ValidationError
+	v.resolveSchedule(&in.ScheduledAt, in.LocalTime, &in.Timezone, now, &invalid)
+	return invalid.Err()
+}
+
+// resolveSchedule sets scheduledAt to the send time given by either scheduledAt itself
+// or localTime in timezone, checks it, and canonicalizes timezone. Problems are added to invalid.
+func (v validator) resolveSchedule(scheduledAt *time.Time, localTime string, timezone *string, now time.Time, invalid *domain.ValidationError) {
+	var loc *time.Location
+	if *timezone != "" {
+		var err *domain.Error
+		if loc, err = loadTimezone(*timezone); err != nil {
+			invalid.Add(err)
+			return
+		}
+		*timezone = loc.String()
+	}
+
+	switch {
+	case localTime != "" && !scheduledAt.IsZero():
+		invalid.Add(&domain.Error{Code: domain.CodeValidationFailed, Field: "local_time", Message: "scheduled_at and local_time are mutually exclusive"})
+		return
+	case localTime != "":
+		if loc == nil {
+			invalid.Add(&domain.Error{Code: domain.CodeRequired, Field: "timezone", Message: "timezone is required with local_time"})
+			return
+		}
+		resolved, err := resolveLocalTime(localTime, loc, now)
+		if err != nil {
+			invalid.Add(err)
+			return
+		}
+		*scheduledAt = resolved
+	case scheduledAt.IsZero():
+		invalid.Add(required("scheduled_at"))
+		return
+	}
+
+	resolved, err := v.checkScheduledAt(*scheduledAt, now)
+	if err != nil {
+		invalid.Add(err)
+		return
+	}
+	*scheduledAt = resolved.UTC()
+}
+
+// resolveRelative sets the send time of in from its delay or send-now mode, if any, so
+// that resolveSchedule checks it like an absolute time. It adds conflicting or invalid
+// modes to invalid and reports whether the send time can be resolved further.
+func (v validator) resolveRelative(in *CreateNotificationInput, now time.Time, invalid *domain.ValidationError) bool {
+	field := "delay"
+	if in.SendNow {
+		field = "send_now"
+	}
+	switch {
+	case in.Delay == "" && !in.SendNow:
+		return true
+	case in.Delay != "" && in.SendNow:
+		invalid.Add(&domain.Error{Code: domain.CodeValidationFailed, Field: "send_now", Message: "delay and send_now are mutually exclusive"})
+		return false
+	case !in.ScheduledAt.IsZero() || in.LocalTime != "":
+		invalid.Add(&domain.Error{Code: domain.CodeValidationFailed, Field: field, Message: field + " cannot be combined with scheduled_at or local_time"})
+		return false
+	case in.SendNow:
+		in.ScheduledAt = now
+		return true
+	}
+
+	scheduledAt, err := resolveDelay(in.Delay, now)
+	if err != nil {
+		invalid.Add(err)
+		return false
+	}
+	// A delay cannot reach into the past, so only the horizon can be exceeded.
+	if _, err := v.checkScheduledAt(scheduledAt, now); err != nil {
+		invalid.Add(inField(err.Withf("delay must be at most %s", v.cfg.MaxHorizon), "delay"))
+		return false
+	}
+	in.ScheduledAt = scheduledAt
+	return true
+}
+
+// checkScheduledAt checks a send time against the past tolerance and the horizon.
+// It returns the time to schedule for, which is now for tolerated past times.
+func (v validator) checkScheduledAt(scheduledAt, now time.Time) (time.Time, *domain.Error) {
+	switch {
+	case scheduledAt.Before(now.Add(-v.cfg.PastTolerance)):
+		return time.Time{}, domain.ErrScheduledInPast
+	case v.cfg.MaxHorizon > 0 && scheduledAt.After(now.Add(v.cfg.MaxHorizon)):
+		return time.Time{}, domain.ErrScheduledTooFar.Withf("scheduled_at must be at most %s from now", v.cfg.MaxHorizon)
+	case scheduledAt.Before(now):
+		return now, nil
+	default:
+		return scheduledAt, nil
+	}
+}
+
+// checkLengths checks the subject and message against the limits of the channel.
+func (v validator) checkLengths(channel model.Channel, subject, message string) []*domain.Error {
+	limits := v.cfg.Limits[string(channel)]
+	var errs []*domain.Error
+
+	if n := utf8.RuneCountInString(subject); limits.MaxSubjectLength > 0 && n > limits.MaxSubjectLength {
+		errs = append(errs, tooLong("subject", limits.MaxSubjectLength))
+	}
+	n := utf8.RuneCountInString(message)
+	if channel == model.ChannelTelegram {
+		n += utf8.RuneCountInString(subject) + telegramMarkup
+	}
+	if limits.MaxMessageLength > 0 && n > limits.MaxMessageLength {
+		err := tooLong("message", limits.MaxMessageLength)
+		if channel == model.ChannelTelegram {
+			err.Message = fmt.Sprintf("subject and message must be at most %d characters long together", limits.MaxMessageLength-telegramMarkup)
+		}
+		errs = append(errs, err)
+	}
+	return errs
+}
+
+// resolveWindow converts a delivery window to the domain model. Its time zone defaults
+// to defaultZone. Problems are added to invalid, with fields prefixed by prefix.
+func resolveWindow(in DeliveryWindowInput, defaultZone, prefix string, now time.Time, invalid *domain.ValidationError) *model.DeliveryWindow {
+	fields := len(invalid.Fields)
+	window := &model.DeliveryWindow{Timezone: in.Timezone}
+
+	switch {
+	case window.Timezone == "" && defaultZone == "":
+		invalid.Add(required(prefix + "timezone"))
+	case window.Timezone == "":
+		window.Timezone = defaultZone
+	default:
+		if loc, err := loadTimezone(window.Timezone); err != nil {
+			invalid.Add(inField(err, prefix+"timezone"))
+		} else {
+			window.Timezone = loc.String()
+		}
+	}
+
+	for _, name := range in.Days {
+		day, err := model.ParseWeekday(name)
+		if err != nil {
+			invalid.Add(inField(domain.ErrInvalidDeliveryWindow.Withf("%s", err), prefix+"days"))
+			continue
+		}
+		if !slices.Contains(window.Days, day) {
+			window.Days = append(window.Days, day)
+		}
+	}
+	slices.Sort(window.Days)
+
+	window.Hours = resolveClockRange(in.Hours, prefix+"hours", invalid)
+	window.QuietHours = resolveClockRange(in.QuietHours, prefix+"quiet_hours", invalid)
+
+	if len(invalid.Fields) > fields {
+		return nil
+	}
+	if _, ok := window.Next(now); !ok {
+		invalid.Add(inField(domain.ErrInvalidDeliveryWindow.Withf("delivery window never allows delivery"), strings.TrimSuffix(prefix, ".")))
+		return nil
+	}
+	return window
+}
+
+// resolveClockRange converts a range of times of day to the domain model.
+// Problems are added to invalid under field.
+func resolveClockRange(in *ClockRangeInput, field string, invalid *domain.ValidationError) *model.ClockRange {
+	if in == nil {
+		return nil
+	}
+	start, startErr := model.ParseClockTime(in.Start)
+	if startErr != nil {
+		invalid.Add(inField(domain.ErrInvalidDeliveryWindow.Withf("%s", startErr), field+".start"))
+	}
+	end, endErr := model.ParseClockTime(in.End)
+	if endErr != nil {
+		invalid.Add(inField(domain.ErrInvalidDeliveryWindow.Withf("%s", endErr), field+".end"))
+	}
+	if startErr != nil || endErr != nil {
+		return nil
+	}
+	if start == end {
+		invalid.Add(inField(domain.ErrInvalidDeliveryWindow.Withf("%s must not start and end at the same time", field), field))
+		return nil
+	}
+	return &model.ClockRange{Start: start, End: end}
+}
+
+// loadTimezone loads an IANA time zone such as "Europe/Berlin".
+func loadTimezone(name string) (*time.Location, *domain.Error) {
+	// LoadLocation also accepts "Local", the zone of the server, which means nothing to callers.
+	loc, err := time.LoadLocation(name)
+	if err != nil || name == "Local" {
+		return nil, domain.ErrInvalidTimezone.Withf("unknown time zone %q, must be an IANA name such as Europe/Berlin", name)
+	}
+	return loc, nil
+}
+
+// resolveLocalTime returns the instant of a wall-clock time in loc, see localTimeLayouts.
+// A time of day refers to its next occurrence after now.
+func resolveLocalTime(raw string, loc *time.Location, now time.Time) (time.Time, *domain.Error) {
+	for _, l := range localTimeLayouts {
+		wall, err := time.Parse(l.layout, raw)
+		if err != nil {
+			continue
+		}
+		if l.timeOfDay {
+			today := now.In(loc)
+			wall = time.Date(today.Year(), today.Month(), today.Day(), wall.Hour(), wall.Minute(), wall.Second(), 0, time.UTC)
+			if t := model.InZone(wall, loc); t.After(now) {
+				return t, nil
+			}
+			wall = wall.AddDate(0, 0, 1)
+		}
+		return model.InZone(wall, loc), nil
+	}
+	return time.Time{}, domain.ErrInvalidLocalTime.Withf("invalid local_time %q, must look like 2026-03-29T09:00 or 09:00", raw)
+}
+
+// resolveDelay returns the instant an ISO 8601 duration such as "PT15M" or "P1DT12H" after now.
+// Years, months, weeks and days are calendar units in UTC; the time part may have fractional seconds.
+func resolveDelay(raw string, now time.Time) (time.Time, *domain.Error) {
+	invalid := domain.ErrInvalidDelay.Withf("invalid delay %q, must be an ISO 8601 duration such as PT15M or P1DT12H", raw)
+	m := isoDuration.FindStringSubmatch(raw)
+	if m == nil || raw == "P" || strings.HasSuffix(raw, "T") {
+		return time.Time{}, invalid
+	}
+
+	var units [6]int
+	for i := range units {
+		if m[i+1] == "" {
+			continue
+		}
+		n, err := strconv.Atoi(m[i+1])
+		if err != nil {
+			return time.Time{}, invalid
+		}
+		units[i] = n
+	}
+	seconds := 0.0
+	if m[7] != "" {
+		seconds, _ = strconv.ParseFloat(strings.Replace(m[7], ",", ".", 1), 64)
+	}
+	clock := float64(units[4])*time.Hour.Seconds() + float64(units[5])*time.Minute.Seconds() + seconds
+	if clock > maxDelay.Seconds() {
+		return time.Time{}, invalid
+	}
+
+	years, months, weeks, days := units[0], units[1], units[2], units[3]
+	return now.UTC().AddDate(years, months, 7*weeks+days).Add(time.Duration(clock * float64(time.Second))), nil
+}
+
+// normalizeRecipient returns the canonical form of a recipient of the channel.
+func normalizeRecipient(channel model.Channel, raw string) (string, *domain.Error) {
+	switch channel {
+	case model.ChannelEmail:
+		return normalizeEmail(raw)
+	case model.ChannelTelegram:
+		return normalizeChatID(raw)
+	default:
+		return "", domain.ErrInvalidChannel.Withf("unknown channel %q, must be email or telegram", channel)
+	}
+}
+
+// normalizeEmail returns the bare address of an email recipient with a lower-case
+// domain, e.g. "Bob <bob@Example.COM>" becomes "bob@example.com".
+func normalizeEmail(raw string) (string, *domain.Error) {
+	addr, err := mail.ParseAddress(strings.TrimSpace(raw))
+	if err != nil {
+		return "", domain.ErrInvalidRecipient.Withf("invalid email address %q", raw)
+	}
+	at := strings.LastIndexByte(addr.Address, '@')
+	return addr.Address[:at] + strings.ToLower(addr.Address[at:]), nil
+}
+
+// normalizeChatID returns a Telegram chat ID in canonical decimal form.
+func normalizeChatID(raw string) (string, *domain.Error) {
+	chatID, err := strconv.ParseInt(strings.TrimSpace(raw), 10, 64)
+	if err != nil || chatID == 0 {
+		return "", domain.ErrInvalidRecipient.Withf("invalid telegram chat id %q, must be a non-zero integer", raw)
+	}
+	return strconv.FormatInt(chatID, 10), nil
+}
+
+// normalizeTags checks tags against MaxTags and MaxTagLength. It returns them trimmed
+// and without duplicates, in their original order.
+func normalizeTags(tags []string) ([]string, *domain.Error) {
+	if len(tags) > MaxTags {
+		return nil, domain.ErrInvalidTags.Withf("at most %d tags are allowed", MaxTags)
+	}
+	var normalized []string
+	for _, tag := range tags {
+		tag = strings.TrimSpace(tag)
+		switch {
+		case tag == "":
+			return nil, domain.ErrInvalidTags.Withf("tags must not be empty")
+		case utf8.RuneCountInString(tag) > MaxTagLength:
+			return nil, domain.ErrInvalidTags.Withf("tags must be at most %d characters long", MaxTagLength)
+		case !slices.Contains(normalized, tag):
+			normalized = append(normalized, tag)
+		}
+	}
+	return normalized, nil
+}
+
+// checkMetadata checks that metadata encodes to at most MaxMetadataSize bytes of JSON.
+func checkMetadata(metadata map[string]any) *domain.Error {
+	if metadata == nil {
+		return nil
+	}
+	data, err := json.Marshal(metadata)
+	if err != nil {
+		return domain.ErrInvalidMetadata.Withf("metadata must be a JSON object")
+	}
+	if len(data) > MaxMetadataSize {
+		return domain.ErrInvalidMetadata.Withf("metadata must be at most %d bytes as JSON", MaxMetadataSize)
+	}
+	return nil
+}
+
+// validateCallbackURL checks that a callback URL is an absolute http(s) URL.
+func validateCallbackURL(raw string) *domain.Error {
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return domain.ErrInvalidCallbackURL
+	}
+	return nil
+}
+
+func required(field string) *domain.Error {
+	return &domain.Error{Code: domain.CodeRequired, Field: field, Message: field + " is required"}
+}
+
+func tooLong(field string, limit int) *domain.Error {
+	return &domain.Error{Code: domain.CodeTooLong, Field: field, Message: fmt.Sprintf("%s must be at most %d characters long", field, limit)}
+}
+
+// inField returns a copy of err that refers to field.
+func inField(err *domain.Error, field string) *domain.Error {
+	copied := *err
+	copied.Field = field
+	return &copied
+}
@@ -0,0 +1,291 @@
+package service
+
+import (
+	"errors"
+	"github.com/ilindan-dev/delayed-notifier/internal/config"
+	"github.com/ilindan-dev/delayed-notifier/internal/domain"
+	"github.com/ilindan-dev/delayed-notifier/internal/domain/model"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestValidateCreate(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	v := validator{cfg: config.ValidationConfig{
+		MaxHorizon:     24 * time.Hour,
+		PastTolerance:  time.Minute,
+		RequireMessage: true,
+		Limits: map[string]config.ChannelLimitsConfig{
+			"email":    {MaxSubjectLength: 10},
+			"telegram": {MaxMessageLength: 20},
+		},
+	}}
+	valid := func() CreateNotificationInput {
+		return CreateNotificationInput{
+			Recipient:   "user@example.com",
+			Channel:     model.ChannelEmail,
+			Subject:     "Hello",
+			Message:     "World",
+			ScheduledAt: now.Add(time.Hour),
+		}
+	}
+
+	tests := []struct {
+		name        string
+		modify      func(in *CreateNotificationInput)
+		wantFields  []string
+		check       func(t *testing.T, in CreateNotificationInput)
+		checkWindow func(t *testing.T, w *model.DeliveryWindow)
+	}{
+		{
+			name: "valid",
+		},
+		{
+			name: "normalizes email recipient",
+			modify: func(in *CreateNotificationInput) {
+				in.Recipient = " Bob <Bob@Example.COM> "
+			},
+			check: func(t *testing.T, in CreateNotificationInput) {
+				if in.Recipient != "Bob@example.com" {
+					t.Errorf("got recipient %q, want Bob@example.com", in.Recipient)
+				}
+			},
+		},
+		{
+			name: "normalizes telegram chat id",
+			modify: func(in *CreateNotificationInput) {
+				in.Channel, in.Recipient, in.Subject, in.Message = model.ChannelTelegram, " +42 ", "Hi", "there"
+			},
+			check: func(t *testing.T, in CreateNotificationInput) {
+				if in.Recipient != "42" {
+					t.Errorf("got recipient %q, want 42", in.Recipient)
+				}
+			},
+		},
+		{
+			name: "moves tolerated past time to now",
+			modify: func(in *CreateNotificationInput) {
+				in.ScheduledAt = now.Add(-30 * time.Second)
+			},
+			check: func(t *testing.T, in CreateNotificationInput) {
+				if !in.ScheduledAt.Equal(now) {
+					t.Errorf("got scheduled_at %s, want %s", in.ScheduledAt, now)
+				}
+			},
+		},
+		{
+			name: "resolves next occurrence of local time of day",
+			modify: func(in *CreateNotificationInput) {
+				in.ScheduledAt, in.LocalTime, in.Timezone = time.Time{}, "09:00", "Europe/Berlin"
+			},
+			check: func(t *testing.T, in CreateNotificationInput) {
+				// It is 13:00 in Berlin, so 09:00 refers to the next day.
+				if want := time.Date(2026, 1, 2, 8, 0, 0, 0, time.UTC); !in.ScheduledAt.Equal(want) {
+					t.Errorf("got scheduled_at %s, want %s", in.ScheduledAt, want)
+				}
+			},
+		},
+		{
+			name: "requires time zone with local time",
+			modify: func(in *CreateNotificationInput) {
+				in.ScheduledAt, in.LocalTime = time.Time{}, "2026-01-01T18:00"
+			},
+			wantFields: []string{"timezone"},
+		},
+		{
+			name:       "rejects absolute and local time together",
+			modify:     func(in *CreateNotificationInput) { in.LocalTime, in.Timezone = "18:00", "UTC" },
+			wantFields: []string{"local_time"},
+		},
+		{
+			name: "resolves delay from now",
+			modify: func(in *CreateNotificationInput) {
+				in.ScheduledAt, in.Delay = time.Time{}, "PT1H30M"
+			},
+			check: func(t *testing.T, in CreateNotificationInput) {
+				if want := now.Add(90 * time.Minute); !in.ScheduledAt.Equal(want) {
+					t.Errorf("got scheduled_at %s, want %s", in.ScheduledAt, want)
+				}
+			},
+		},
+		{
+			name: "sends now",
+			modify: func(in *CreateNotificationInput) {
+				in.ScheduledAt, in.SendNow = time.Time{}, true
+			},
+			check: func(t *testing.T, in CreateNotificationInput) {
+				if !in.ScheduledAt.Equal(now) {
+					t.Errorf("got scheduled_at %s, want %s", in.ScheduledAt, now)
+				}
+			},
+		},
+		{
+			name: "defaults priority to normal",
+			check: func(t *testing.T, in CreateNotificationInput) {
+				if in.Priority != model.PriorityNormal {
+					t.Errorf("got priority %q, want normal", in.Priority)
+				}
+			},
+		},
+		{
+			name:       "rejects unknown priority",
+			modify:     func(in *CreateNotificationInput) { in.Priority = "urgent" },
+			wantFields: []string{"priority"},
+		},
+		{
+			name: "normalizes tags",
+			modify: func(in *CreateNotificationInput) {
+				in.Tags = []string{" campaign ", "spring", "campaign"}
+			},
+			check: func(t *testing.T, in CreateNotificationInput) {
+				if strings.Join(in.Tags, ",") != "campaign,spring" {
+					t.Errorf("got tags %q, want campaign and spring", in.Tags)
+				}
+			},
+		},
+		{
+			name:       "rejects empty tag",
+			modify:     func(in *CreateNotificationInput) { in.Tags = []string{"campaign", " "} },
+			wantFields: []string{"tags"},
+		},
+		{
+			name: "rejects oversized metadata",
+			modify: func(in *CreateNotificationInput) {
+				in.Metadata = map[string]any{"blob": strings.Repeat("x", MaxMetadataSize)}
+			},
+			wantFields: []string{"metadata"},
+		},
+		{
+			name:       "rejects malformed delay",
+			modify:     func(in *CreateNotificationInput) { in.ScheduledAt, in.Delay = time.Time{}, "15m" },
+			wantFields: []string{"delay"},
+		},
+		{
+			name:       "rejects delay beyond horizon",
+			modify:     func(in *CreateNotificationInput) { in.ScheduledAt, in.Delay = time.Time{}, "P1DT1S" },
+			wantFields: []string{"delay"},
+		},
+		{
+			name:       "rejects delay with absolute time",
+			modify:     func(in *CreateNotificationInput) { in.Delay = "PT15M" },
+			wantFields: []string{"delay"},
+		},
+		{
+			name: "delivery window defaults to notification time zone",
+			modify: func(in *CreateNotificationInput) {
+				in.ScheduledAt, in.LocalTime, in.Timezone = time.Time{}, "09:00", "Europe/Berlin"
+				in.DeliveryWindow = &DeliveryWindowInput{Days: []string{"fri", "Mon", "fri"}, Hours: &ClockRangeInput{Start: "09:00", End: "18:00"}}
+			},
+			checkWindow: func(t *testing.T, w *model.DeliveryWindow) {
+				if w == nil || w.Timezone != "Europe/Berlin" || len(w.Days) != 2 || w.Days[0] != time.Monday || w.Hours.End != 18*60 {
+					t.Errorf("got delivery window %+v, want Mon and Fri from 09:00 to 18:00 in Europe/Berlin", w)
+				}
+			},
+		},
+		{
+			name: "rejects malformed delivery window",
+			modify: func(in *CreateNotificationInput) {
+				in.DeliveryWindow = &DeliveryWindowInput{Days: []string{"someday"}, QuietHours: &ClockRangeInput{Start: "22:00", End: "7am"}}
+			},
+			wantFields: []string{"delivery_window.timezone", "delivery_window.days", "delivery_window.quiet_hours.end"},
+		},
+		{
+			name: "rejects delivery window that never opens",
+			modify: func(in *CreateNotificationInput) {
+				in.DeliveryWindow = &DeliveryWindowInput{
+					Timezone:   "UTC",
+					Hours:      &ClockRangeInput{Start: "09:00", End: "10:00"},
+					QuietHours: &ClockRangeInput{Start: "08:00", End: "11:00"},
+				}
+			},
+			wantFields: []string{"delivery_window"},
+		},
+		{
+			name:       "rejects past time beyond tolerance",
+			modify:     func(in *CreateNotificationInput) { in.ScheduledAt = now.Add(-2 * time.Minute) },
+			wantFields: []string{"scheduled_at"},
+		},
+		{
+			name:       "rejects time beyond horizon",
+			modify:     func(in *CreateNotificationInput) { in.ScheduledAt = now.Add(25 * time.Hour) },
+			wantFields: []string{"scheduled_at"},
+		},
+		{
+			name: "rejects overlong telegram text",
+			modify: func(in *CreateNotificationInput) {
+				in.Channel, in.Recipient, in.Message = model.ChannelTelegram, "42", "far too long for it"
+			},
+			wantFields: []string{"message"},
+		},
+		{
+			name: "reports every invalid field",
+			modify: func(in *CreateNotificationInput) {
+				in.Recipient = "not an address"
+				in.Subject = strings.Repeat("x", 11)
+				in.Message = " "
+			},
+			wantFields: []string{"recipient", "message", "subject"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			in := valid()
+			if tt.modify != nil {
+				tt.modify(&in)
+			}
+			window, err := v.validateCreate(&in, now)
+
+			var fields []string
+			var invalid *domain.ValidationError
+			var single *domain.Error
+			switch {
+			case errors.As(err, &invalid):
+				for _, f := range invalid.Fields {
+					fields = append(fields, f.Field)
+				}
+			case errors.As(err, &single):
+				fields = []string{single.Field}
+			case err != nil:
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if strings.Join(fields, ",") != strings.Join(tt.wantFields, ",") {
+				t.Fatalf("got invalid fields %v, want %v (%v)", fields, tt.wantFields, err)
+			}
+			if tt.check != nil {
+				tt.check(t, in)
+			}
+			if tt.checkWindow != nil {
+				tt.checkWindow(t, window)
+			}
+		})
+	}
+}
+
+func TestResolveDelay(t *testing.T) {
+	now := time.Date(2026, 1, 31, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		raw  string
+		want time.Time
+	}{
+		{raw: "PT15M", want: now.Add(15 * time.Minute)},
+		{raw: "PT0.5S", want: now.Add(500 * time.Millisecond)},
+		{raw: "PT1,5S", want: now.Add(1500 * time.Millisecond)},
+		{raw: "P1W", want: now.AddDate(0, 0, 7)},
+		{raw: "P1DT2H", want: now.Add(26 * time.Hour)},
+		{raw: "P1M", want: time.Date(2026, 3, 3, 12, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		got, err := resolveDelay(tt.raw, now)
+		if err != nil || !got.Equal(tt.want) {
+			t.Errorf("resolveDelay(%q) = %s, %v, want %s", tt.raw, got, err, tt.want)
+		}
+	}
+
+	for _, raw := range []string{"", "P", "PT", "P1DT", "PT-5M", "15m", "P1H", "PT1.5M"} {
+		if _, err := resolveDelay(raw, now); err == nil {
+			t.Errorf("resolveDelay(%q) succeeded, want error", raw)
+		}
+	}
+}
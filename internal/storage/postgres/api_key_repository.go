@@ -0,0 +1,140 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/ilindan-dev/delayed-notifier/internal/domain/model"
+	repo "github.com/ilindan-dev/delayed-notifier/internal/domain/repository"
+	"github.com/ilindan-dev/delayed-notifier/internal/storage/postgres/db"
+	"github.com/jackc/pgerrcode"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rs/zerolog"
+)
+
+// Ensure APIKeyRepository implements the interface
+var _ repo.APIKeyRepository = (*APIKeyRepository)(nil)
+
+// APIKeyRepository implements the domain.repository.APIKeyRepository interface
+// using PostgreSQL as a backend.
+type APIKeyRepository struct {
+	pool    *pgxpool.Pool
+	queries *db.Queries
+	logger  zerolog.Logger
+}
+
+// NewAPIKeyRepository creates a new instance of the APIKeyRepository.
+func NewAPIKeyRepository(pool *pgxpool.Pool, logger *zerolog.Logger) *APIKeyRepository {
+	return &APIKeyRepository{
+		pool:    pool,
+		queries: db.New(pool),
+		logger:  logger.With().Str("layer", "postgres_api_key_repository").Logger(),
+	}
+}
+
+// Save persists a new API key and returns it with DB-generated fields.
+func (r *APIKeyRepository) Save(ctx context.Context, key *model.APIKey) (*model.APIKey, error) {
+	scopes := make([]string, len(key.Scopes))
+	for i, s := range key.Scopes {
+		scopes[i] = string(s)
+	}
+
+	created, err := r.queries.CreateAPIKey(ctx, db.CreateAPIKeyParams{
+		Name:      key.Name,
+		KeyPrefix: key.Prefix,
+		KeyHash:   key.Hash,
+		OwnerID:   key.OwnerID,
+		Scopes:    scopes,
+		TenantID:  tenantOrDefault(key.TenantID),
+	})
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == pgerrcode.UniqueViolation {
+			return nil, repo.ErrDuplicateRecord
+		}
+		r.logger.Err(err).Msg("cannot create api key")
+		return nil, fmt.Errorf("postgres: CreateAPIKey failed: %w", err)
+	}
+
+	return toDomainAPIKey(&created), nil
+}
+
+// GetActiveByHash retrieves a non-revoked API key by the hash of its raw value.
+func (r *APIKeyRepository) GetActiveByHash(ctx context.Context, hash string) (*model.APIKey, error) {
+	key, err := r.queries.GetActiveAPIKeyByHash(ctx, hash)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, repo.ErrNotFound
+		}
+		r.logger.Err(err).Msg("cannot get api key by hash")
+		return nil, fmt.Errorf("postgres: GetActiveAPIKeyByHash failed: %w", err)
+	}
+
+	return toDomainAPIKey(&key), nil
+}
+
+// List returns all API keys of a tenant, newest first.
+func (r *APIKeyRepository) List(ctx context.Context, tenantID string) ([]*model.APIKey, error) {
+	keys, err := r.queries.ListAPIKeys(ctx, tenantID)
+	if err != nil {
+		r.logger.Err(err).Msg("cannot list api keys")
+		return nil, fmt.Errorf("postgres: ListAPIKeys failed: %w", err)
+	}
+
+	result := make([]*model.APIKey, 0, len(keys))
+	for i := range keys {
+		result = append(result, toDomainAPIKey(&keys[i]))
+	}
+	return result, nil
+}
+
+// Revoke marks an API key of a tenant as revoked.
+func (r *APIKeyRepository) Revoke(ctx context.Context, tenantID string, id uuid.UUID) error {
+	_, err := r.queries.RevokeAPIKey(ctx, db.RevokeAPIKeyParams{ID: pgtype.UUID{Bytes: id, Valid: true}, TenantID: tenantID})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			r.logger.Warn().Stringer("id", id).Msg("tried to revoke non-existent or revoked api key")
+			return repo.ErrNotFound
+		}
+		r.logger.Err(err).Stringer("id", id).Msg("cannot revoke api key")
+		return fmt.Errorf("postgres: RevokeAPIKey failed: %w", err)
+	}
+	return nil
+}
+
+// Touch records that an API key has just been used.
+func (r *APIKeyRepository) Touch(ctx context.Context, id uuid.UUID) error {
+	if err := r.queries.TouchAPIKey(ctx, pgtype.UUID{Bytes: id, Valid: true}); err != nil {
+		r.logger.Err(err).Stringer("id", id).Msg("cannot touch api key")
+		return fmt.Errorf("postgres: TouchAPIKey failed: %w", err)
+	}
+	return nil
+}
+
+// toDomainAPIKey converts a database API key to a domain model.
+func toDomainAPIKey(k *db.ApiKey) *model.APIKey {
+	key := &model.APIKey{
+		ID:        k.ID.Bytes,
+		TenantID:  k.TenantID,
+		Name:      k.Name,
+		Prefix:    k.KeyPrefix,
+		Hash:      k.KeyHash,
+		OwnerID:   k.OwnerID,
+		Scopes:    make([]model.Scope, len(k.Scopes)),
+		CreatedAt: k.CreatedAt.Time,
+	}
+	for i, s := range k.Scopes {
+		key.Scopes[i] = model.Scope(s)
+	}
+	if k.LastUsedAt.Valid {
+		key.LastUsedAt = &k.LastUsedAt.Time
+	}
+	if k.RevokedAt.Valid {
+		key.RevokedAt = &k.RevokedAt.Time
+	}
+	return key
+}
@@ -0,0 +1,102 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/ilindan-dev/delayed-notifier/internal/domain/model"
+	repo "github.com/ilindan-dev/delayed-notifier/internal/domain/repository"
+	"github.com/ilindan-dev/delayed-notifier/internal/storage/postgres/db"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rs/zerolog"
+	"time"
+)
+
+// Ensure CallbackDeliveryRepository implements the interface
+var _ repo.CallbackDeliveryRepository = (*CallbackDeliveryRepository)(nil)
+
+// CallbackDeliveryRepository implements the domain.repository.CallbackDeliveryRepository
+// interface using PostgreSQL as a backend.
+type CallbackDeliveryRepository struct {
+	pool    *pgxpool.Pool
+	queries *db.Queries
+	logger  zerolog.Logger
+}
+
+// NewCallbackDeliveryRepository creates a new instance of the CallbackDeliveryRepository.
+func NewCallbackDeliveryRepository(pool *pgxpool.Pool, logger *zerolog.Logger) *CallbackDeliveryRepository {
+	return &CallbackDeliveryRepository{
+		pool:    pool,
+		queries: db.New(pool),
+		logger:  logger.With().Str("layer", "postgres_callback_repository").Logger(),
+	}
+}
+
+// Save records a single callback delivery attempt.
+func (r *CallbackDeliveryRepository) Save(ctx context.Context, d *model.CallbackDelivery) error {
+	params := db.CreateCallbackDeliveryParams{
+		EventID:        pgtype.UUID{Bytes: d.EventID, Valid: true},
+		NotificationID: pgtype.UUID{Bytes: d.NotificationID, Valid: true},
+		TenantID:       tenantOrDefault(d.TenantID),
+		Event:          string(d.Event),
+		Url:            d.URL,
+		Attempt:        int16(d.Attempt),
+		DurationMs:     int32(d.Duration.Milliseconds()),
+		Delivered:      d.Delivered,
+	}
+	if d.StatusCode != nil {
+		params.StatusCode = pgtype.Int4{Int32: int32(*d.StatusCode), Valid: true}
+	}
+	if d.Error != nil {
+		params.Error = pgtype.Text{String: *d.Error, Valid: true}
+	}
+
+	if _, err := r.queries.CreateCallbackDelivery(ctx, params); err != nil {
+		r.logger.Err(err).Stringer("event_id", d.EventID).Msg("cannot record callback delivery")
+		return fmt.Errorf("postgres: CreateCallbackDelivery failed: %w", err)
+	}
+	return nil
+}
+
+// ListByNotification returns the delivery attempts of a tenant's notification, oldest first.
+func (r *CallbackDeliveryRepository) ListByNotification(ctx context.Context, tenantID string, notificationID uuid.UUID) ([]*model.CallbackDelivery, error) {
+	rows, err := r.queries.ListCallbackDeliveries(ctx, db.ListCallbackDeliveriesParams{
+		TenantID:       tenantID,
+		NotificationID: pgtype.UUID{Bytes: notificationID, Valid: true},
+	})
+	if err != nil {
+		r.logger.Err(err).Stringer("notification_id", notificationID).Msg("cannot list callback deliveries")
+		return nil, fmt.Errorf("postgres: ListCallbackDeliveries failed: %w", err)
+	}
+
+	deliveries := make([]*model.CallbackDelivery, 0, len(rows))
+	for i := range rows {
+		deliveries = append(deliveries, toDomainCallbackDelivery(&rows[i]))
+	}
+	return deliveries, nil
+}
+
+// toDomainCallbackDelivery converts a database row to a domain callback delivery.
+func toDomainCallbackDelivery(row *db.CallbackDelivery) *model.CallbackDelivery {
+	d := &model.CallbackDelivery{
+		ID:             row.ID.Bytes,
+		EventID:        row.EventID.Bytes,
+		NotificationID: row.NotificationID.Bytes,
+		TenantID:       row.TenantID,
+		Event:          model.Status(row.Event),
+		URL:            row.Url,
+		Attempt:        int(row.Attempt),
+		Duration:       time.Duration(row.DurationMs) * time.Millisecond,
+		Delivered:      row.Delivered,
+		CreatedAt:      row.CreatedAt.Time,
+	}
+	if row.StatusCode.Valid {
+		code := int(row.StatusCode.Int32)
+		d.StatusCode = &code
+	}
+	if row.Error.Valid {
+		d.Error = &row.Error.String
+	}
+	return d
+}
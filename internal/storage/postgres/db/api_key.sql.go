@@ -0,0 +1,170 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: api_key.sql
+
+package db
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const createAPIKey = `-- name: CreateAPIKey :one
+INSERT INTO api_keys (
+                      name,
+                      key_prefix,
+                      key_hash,
+                      owner_id,
+                      scopes,
+                      tenant_id
+) VALUES (
+          $1, $2, $3, $4, $5, $6
+         )
+RETURNING id, name, key_prefix, key_hash, owner_id, scopes, created_at, last_used_at, revoked_at, tenant_id
+`
+
+type CreateAPIKeyParams struct {
+	Name      string   `json:"name"`
+	KeyPrefix string   `json:"key_prefix"`
+	KeyHash   string   `json:"key_hash"`
+	OwnerID   string   `json:"owner_id"`
+	Scopes    []string `json:"scopes"`
+	TenantID  string   `json:"tenant_id"`
+}
+
+// This query inserts a new API key. Only the hash of the key is stored.
+func (q *Queries) CreateAPIKey(ctx context.Context, arg CreateAPIKeyParams) (ApiKey, error) {
+	row := q.db.QueryRow(ctx, createAPIKey,
+		arg.Name,
+		arg.KeyPrefix,
+		arg.KeyHash,
+		arg.OwnerID,
+		arg.Scopes,
+		arg.TenantID,
+	)
+	var i ApiKey
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.KeyPrefix,
+		&i.KeyHash,
+		&i.OwnerID,
+		&i.Scopes,
+		&i.CreatedAt,
+		&i.LastUsedAt,
+		&i.RevokedAt,
+		&i.TenantID,
+	)
+	return i, err
+}
+
+const getActiveAPIKeyByHash = `-- name: GetActiveAPIKeyByHash :one
+SELECT id, name, key_prefix, key_hash, owner_id, scopes, created_at, last_used_at, revoked_at, tenant_id FROM api_keys
+WHERE key_hash = $1 AND revoked_at IS NULL
+`
+
+// This query finds a non-revoked API key by the hash of its raw value.
+func (q *Queries) GetActiveAPIKeyByHash(ctx context.Context, keyHash string) (ApiKey, error) {
+	row := q.db.QueryRow(ctx, getActiveAPIKeyByHash, keyHash)
+	var i ApiKey
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.KeyPrefix,
+		&i.KeyHash,
+		&i.OwnerID,
+		&i.Scopes,
+		&i.CreatedAt,
+		&i.LastUsedAt,
+		&i.RevokedAt,
+		&i.TenantID,
+	)
+	return i, err
+}
+
+const listAPIKeys = `-- name: ListAPIKeys :many
+SELECT id, name, key_prefix, key_hash, owner_id, scopes, created_at, last_used_at, revoked_at, tenant_id FROM api_keys
+WHERE tenant_id = $1
+ORDER BY created_at DESC
+`
+
+// This query lists all API keys of a tenant, newest first.
+func (q *Queries) ListAPIKeys(ctx context.Context, tenantID string) ([]ApiKey, error) {
+	rows, err := q.db.Query(ctx, listAPIKeys, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ApiKey
+	for rows.Next() {
+		var i ApiKey
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.KeyPrefix,
+			&i.KeyHash,
+			&i.OwnerID,
+			&i.Scopes,
+			&i.CreatedAt,
+			&i.LastUsedAt,
+			&i.RevokedAt,
+			&i.TenantID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const revokeAPIKey = `-- name: RevokeAPIKey :one
+UPDATE api_keys
+SET
+    revoked_at = NOW()
+WHERE
+    id = $1 AND tenant_id = $2 AND revoked_at IS NULL
+RETURNING id, name, key_prefix, key_hash, owner_id, scopes, created_at, last_used_at, revoked_at, tenant_id
+`
+
+type RevokeAPIKeyParams struct {
+	ID       pgtype.UUID `json:"id"`
+	TenantID string      `json:"tenant_id"`
+}
+
+// This query revokes an API key of a tenant. Revoked keys are kept for auditing.
+func (q *Queries) RevokeAPIKey(ctx context.Context, arg RevokeAPIKeyParams) (ApiKey, error) {
+	row := q.db.QueryRow(ctx, revokeAPIKey, arg.ID, arg.TenantID)
+	var i ApiKey
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.KeyPrefix,
+		&i.KeyHash,
+		&i.OwnerID,
+		&i.Scopes,
+		&i.CreatedAt,
+		&i.LastUsedAt,
+		&i.RevokedAt,
+		&i.TenantID,
+	)
+	return i, err
+}
+
+const touchAPIKey = `-- name: TouchAPIKey :exec
+UPDATE api_keys
+SET
+    last_used_at = NOW()
+WHERE
+    id = $1
+`
+
+// This query records the last time an API key was used.
+func (q *Queries) TouchAPIKey(ctx context.Context, id pgtype.UUID) error {
+	_, err := q.db.Exec(ctx, touchAPIKey, id)
+	return err
+}
@@ -0,0 +1,120 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: callback.sql
+
+package db
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const createCallbackDelivery = `-- name: CreateCallbackDelivery :one
+INSERT INTO callback_deliveries (
+                                 event_id,
+                                 notification_id,
+                                 tenant_id,
+                                 event,
+                                 url,
+                                 attempt,
+                                 status_code,
+                                 error,
+                                 duration_ms,
+                                 delivered
+) VALUES (
+          $1, $2, $3, $4, $5, $6, $7, $8, $9, $10
+         )
+RETURNING id, event_id, notification_id, tenant_id, event, url, attempt, status_code, error, duration_ms, delivered, created_at
+`
+
+type CreateCallbackDeliveryParams struct {
+	EventID        pgtype.UUID `json:"event_id"`
+	NotificationID pgtype.UUID `json:"notification_id"`
+	TenantID       string      `json:"tenant_id"`
+	Event          string      `json:"event"`
+	Url            string      `json:"url"`
+	Attempt        int16       `json:"attempt"`
+	StatusCode     pgtype.Int4 `json:"status_code"`
+	Error          pgtype.Text `json:"error"`
+	DurationMs     int32       `json:"duration_ms"`
+	Delivered      bool        `json:"delivered"`
+}
+
+// This query records a single callback delivery attempt.
+func (q *Queries) CreateCallbackDelivery(ctx context.Context, arg CreateCallbackDeliveryParams) (CallbackDelivery, error) {
+	row := q.db.QueryRow(ctx, createCallbackDelivery,
+		arg.EventID,
+		arg.NotificationID,
+		arg.TenantID,
+		arg.Event,
+		arg.Url,
+		arg.Attempt,
+		arg.StatusCode,
+		arg.Error,
+		arg.DurationMs,
+		arg.Delivered,
+	)
+	var i CallbackDelivery
+	err := row.Scan(
+		&i.ID,
+		&i.EventID,
+		&i.NotificationID,
+		&i.TenantID,
+		&i.Event,
+		&i.Url,
+		&i.Attempt,
+		&i.StatusCode,
+		&i.Error,
+		&i.DurationMs,
+		&i.Delivered,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const listCallbackDeliveries = `-- name: ListCallbackDeliveries :many
+SELECT id, event_id, notification_id, tenant_id, event, url, attempt, status_code, error, duration_ms, delivered, created_at FROM callback_deliveries
+WHERE tenant_id = $1 AND notification_id = $2
+ORDER BY created_at
+`
+
+type ListCallbackDeliveriesParams struct {
+	TenantID       string      `json:"tenant_id"`
+	NotificationID pgtype.UUID `json:"notification_id"`
+}
+
+// This query lists the callback delivery attempts of a tenant's notification, oldest first.
+func (q *Queries) ListCallbackDeliveries(ctx context.Context, arg ListCallbackDeliveriesParams) ([]CallbackDelivery, error) {
+	rows, err := q.db.Query(ctx, listCallbackDeliveries, arg.TenantID, arg.NotificationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []CallbackDelivery
+	for rows.Next() {
+		var i CallbackDelivery
+		if err := rows.Scan(
+			&i.ID,
+			&i.EventID,
+			&i.NotificationID,
+			&i.TenantID,
+			&i.Event,
+			&i.Url,
+			&i.Attempt,
+			&i.StatusCode,
+			&i.Error,
+			&i.DurationMs,
+			&i.Delivered,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
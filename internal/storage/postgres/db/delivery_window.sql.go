@@ -0,0 +1,103 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: delivery_window.sql
+
+package db
+
+import (
+	"context"
+)
+
+const deleteRecipientDeliveryWindow = `-- name: DeleteRecipientDeliveryWindow :one
+DELETE FROM recipient_delivery_windows
+WHERE tenant_id = $1 AND channel = $2 AND recipient = $3
+RETURNING tenant_id, channel, recipient, delivery_window, updated_at
+`
+
+type DeleteRecipientDeliveryWindowParams struct {
+	TenantID  string      `json:"tenant_id"`
+	Channel   ChannelType `json:"channel"`
+	Recipient string      `json:"recipient"`
+}
+
+// This query removes the delivery window of a tenant's recipient.
+func (q *Queries) DeleteRecipientDeliveryWindow(ctx context.Context, arg DeleteRecipientDeliveryWindowParams) (RecipientDeliveryWindow, error) {
+	row := q.db.QueryRow(ctx, deleteRecipientDeliveryWindow, arg.TenantID, arg.Channel, arg.Recipient)
+	var i RecipientDeliveryWindow
+	err := row.Scan(
+		&i.TenantID,
+		&i.Channel,
+		&i.Recipient,
+		&i.DeliveryWindow,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getRecipientDeliveryWindow = `-- name: GetRecipientDeliveryWindow :one
+SELECT tenant_id, channel, recipient, delivery_window, updated_at FROM recipient_delivery_windows
+WHERE tenant_id = $1 AND channel = $2 AND recipient = $3
+`
+
+type GetRecipientDeliveryWindowParams struct {
+	TenantID  string      `json:"tenant_id"`
+	Channel   ChannelType `json:"channel"`
+	Recipient string      `json:"recipient"`
+}
+
+// This query retrieves the delivery window of a tenant's recipient.
+func (q *Queries) GetRecipientDeliveryWindow(ctx context.Context, arg GetRecipientDeliveryWindowParams) (RecipientDeliveryWindow, error) {
+	row := q.db.QueryRow(ctx, getRecipientDeliveryWindow, arg.TenantID, arg.Channel, arg.Recipient)
+	var i RecipientDeliveryWindow
+	err := row.Scan(
+		&i.TenantID,
+		&i.Channel,
+		&i.Recipient,
+		&i.DeliveryWindow,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const upsertRecipientDeliveryWindow = `-- name: UpsertRecipientDeliveryWindow :one
+INSERT INTO recipient_delivery_windows (
+                                        tenant_id,
+                                        channel,
+                                        recipient,
+                                        delivery_window
+) VALUES (
+          $1, $2, $3, $4
+         )
+ON CONFLICT (tenant_id, channel, recipient) DO UPDATE
+SET
+    delivery_window = EXCLUDED.delivery_window,
+    updated_at = NOW()
+RETURNING tenant_id, channel, recipient, delivery_window, updated_at
+`
+
+type UpsertRecipientDeliveryWindowParams struct {
+	TenantID       string      `json:"tenant_id"`
+	Channel        ChannelType `json:"channel"`
+	Recipient      string      `json:"recipient"`
+	DeliveryWindow []byte      `json:"delivery_window"`
+}
+
+// This query sets the delivery window of a tenant's recipient, replacing any previous one.
+func (q *Queries) UpsertRecipientDeliveryWindow(ctx context.Context, arg UpsertRecipientDeliveryWindowParams) (RecipientDeliveryWindow, error) {
+	row := q.db.QueryRow(ctx, upsertRecipientDeliveryWindow,
+		arg.TenantID,
+		arg.Channel,
+		arg.Recipient,
+		arg.DeliveryWindow,
+	)
+	var i RecipientDeliveryWindow
+	err := row.Scan(
+		&i.TenantID,
+		&i.Channel,
+		&i.Recipient,
+		&i.DeliveryWindow,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
@@ -53,13 +53,59 @@ func (ns NullChannelType) Value() (driver.Value, error) {
 	return string(ns.ChannelType), nil
 }
 
+type NotificationPriority string
+
+const (
+	NotificationPriorityLow      NotificationPriority = "low"
+	NotificationPriorityNormal   NotificationPriority = "normal"
+	NotificationPriorityHigh     NotificationPriority = "high"
+	NotificationPriorityCritical NotificationPriority = "critical"
+)
+
+func (e *NotificationPriority) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = NotificationPriority(s)
+	case string:
+		*e = NotificationPriority(s)
+	default:
+		return fmt.Errorf("unsupported scan type for NotificationPriority: %T", src)
+	}
+	return nil
+}
+
+type NullNotificationPriority struct {
+	NotificationPriority NotificationPriority `json:"notification_priority"`
+	Valid                bool                 `json:"valid"` // Valid is true if NotificationPriority is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullNotificationPriority) Scan(value interface{}) error {
+	if value == nil {
+		ns.NotificationPriority, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.NotificationPriority.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullNotificationPriority) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.NotificationPriority), nil
+}
+
 type NotificationStatus string
 
 const (
-	NotificationStatusScheduled NotificationStatus = "scheduled"
-	NotificationStatusSent      NotificationStatus = "sent"
-	NotificationStatusFailed    NotificationStatus = "failed"
-	NotificationStatusCancelled NotificationStatus = "cancelled"
+	NotificationStatusScheduled  NotificationStatus = "scheduled"
+	NotificationStatusProcessing NotificationStatus = "processing"
+	NotificationStatusSent       NotificationStatus = "sent"
+	NotificationStatusFailed     NotificationStatus = "failed"
+	NotificationStatusCancelled  NotificationStatus = "cancelled"
+	NotificationStatusExpired    NotificationStatus = "expired"
 )
 
 func (e *NotificationStatus) Scan(src interface{}) error {
@@ -97,20 +143,57 @@ func (ns NullNotificationStatus) Value() (driver.Value, error) {
 	return string(ns.NotificationStatus), nil
 }
 
-type Notification struct {
+type ApiKey struct {
+	ID         pgtype.UUID        `json:"id"`
+	Name       string             `json:"name"`
+	KeyPrefix  string             `json:"key_prefix"`
+	KeyHash    string             `json:"key_hash"`
+	OwnerID    string             `json:"owner_id"`
+	Scopes     []string           `json:"scopes"`
+	CreatedAt  pgtype.Timestamptz `json:"created_at"`
+	LastUsedAt pgtype.Timestamptz `json:"last_used_at"`
+	RevokedAt  pgtype.Timestamptz `json:"revoked_at"`
+	TenantID   string             `json:"tenant_id"`
+}
+
+type CallbackDelivery struct {
 	ID             pgtype.UUID        `json:"id"`
-	Subject        string             `json:"subject"`
-	Message        string             `json:"message"`
-	AuthorID       pgtype.Text        `json:"author_id"`
-	EmailTo        pgtype.Text        `json:"email_to"`
-	TelegramChatID pgtype.Int8        `json:"telegram_chat_id"`
-	Channel        ChannelType        `json:"channel"`
-	Status         NotificationStatus `json:"status"`
-	Attempts       int16              `json:"attempts"`
-	ScheduledAt    pgtype.Timestamptz `json:"scheduled_at"`
-	SentAt         pgtype.Timestamptz `json:"sent_at"`
+	EventID        pgtype.UUID        `json:"event_id"`
+	NotificationID pgtype.UUID        `json:"notification_id"`
+	TenantID       string             `json:"tenant_id"`
+	Event          string             `json:"event"`
+	Url            string             `json:"url"`
+	Attempt        int16              `json:"attempt"`
+	StatusCode     pgtype.Int4        `json:"status_code"`
+	Error          pgtype.Text        `json:"error"`
+	DurationMs     int32              `json:"duration_ms"`
+	Delivered      bool               `json:"delivered"`
 	CreatedAt      pgtype.Timestamptz `json:"created_at"`
-	UpdatedAt      pgtype.Timestamptz `json:"updated_at"`
+}
+
+type Notification struct {
+	ID             pgtype.UUID          `json:"id"`
+	Subject        string               `json:"subject"`
+	Message        string               `json:"message"`
+	AuthorID       pgtype.Text          `json:"author_id"`
+	EmailTo        pgtype.Text          `json:"email_to"`
+	TelegramChatID pgtype.Int8          `json:"telegram_chat_id"`
+	Channel        ChannelType          `json:"channel"`
+	Status         NotificationStatus   `json:"status"`
+	Attempts       int16                `json:"attempts"`
+	ScheduledAt    pgtype.Timestamptz   `json:"scheduled_at"`
+	SentAt         pgtype.Timestamptz   `json:"sent_at"`
+	CreatedAt      pgtype.Timestamptz   `json:"created_at"`
+	UpdatedAt      pgtype.Timestamptz   `json:"updated_at"`
+	TenantID       string               `json:"tenant_id"`
+	CallbackUrl    pgtype.Text          `json:"callback_url"`
+	Timezone       pgtype.Text          `json:"timezone"`
+	DeliveryWindow []byte               `json:"delivery_window"`
+	Priority       NotificationPriority `json:"priority"`
+	Tags           []string             `json:"tags"`
+	Metadata       []byte               `json:"metadata"`
+	Version        int32                `json:"version"`
+	RequestHash    pgtype.Text          `json:"request_hash"`
 }
 
 type Notifications202509 struct {
@@ -128,3 +211,11 @@ type Notifications202509 struct {
 	CreatedAt      pgtype.Timestamptz `json:"created_at"`
 	UpdatedAt      pgtype.Timestamptz `json:"updated_at"`
 }
+
+type RecipientDeliveryWindow struct {
+	TenantID       string             `json:"tenant_id"`
+	Channel        ChannelType        `json:"channel"`
+	Recipient      string             `json:"recipient"`
+	DeliveryWindow []byte             `json:"delivery_window"`
+	UpdatedAt      pgtype.Timestamptz `json:"updated_at"`
+}
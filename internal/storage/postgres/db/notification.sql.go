@@ -14,16 +14,25 @@ import (
 const cancelNotification = `-- name: CancelNotification :one
 UPDATE notifications
 SET
-    status = 'cancelled'
+    status = 'cancelled',
+    version = version + 1
 WHERE
-    id = $1
-RETURNING id, subject, message, author_id, email_to, telegram_chat_id, channel, status, attempts, scheduled_at, sent_at, created_at, updated_at
+    id = $1 AND tenant_id = $2 AND status = 'scheduled'
+    AND ($3::integer IS NULL OR version = $3)
+RETURNING id, subject, message, author_id, email_to, telegram_chat_id, channel, status, attempts, scheduled_at, sent_at, created_at, updated_at, tenant_id, callback_url, timezone, delivery_window, priority, tags, metadata, version, request_hash
 `
 
+type CancelNotificationParams struct {
+	ID       pgtype.UUID `json:"id"`
+	TenantID string      `json:"tenant_id"`
+	Version  pgtype.Int4 `json:"version"`
+}
+
 // This query performs a "soft delete" by changing the status to 'cancelled'.
-// We never truly delete data, we just change its state.
-func (q *Queries) CancelNotification(ctx context.Context, id pgtype.UUID) (Notification, error) {
-	row := q.db.QueryRow(ctx, cancelNotification, id)
+// We never truly delete data, we just change its state. Only scheduled notifications can be cancelled,
+// and only in the given version, if it is not NULL.
+func (q *Queries) CancelNotification(ctx context.Context, arg CancelNotificationParams) (Notification, error) {
+	row := q.db.QueryRow(ctx, cancelNotification, arg.ID, arg.TenantID, arg.Version)
 	var i Notification
 	err := row.Scan(
 		&i.ID,
@@ -39,12 +48,130 @@ func (q *Queries) CancelNotification(ctx context.Context, id pgtype.UUID) (Notif
 		&i.SentAt,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.TenantID,
+		&i.CallbackUrl,
+		&i.Timezone,
+		&i.DeliveryWindow,
+		&i.Priority,
+		&i.Tags,
+		&i.Metadata,
+		&i.Version,
+		&i.RequestHash,
 	)
 	return i, err
 }
 
+const cancelNotifications = `-- name: CancelNotifications :many
+UPDATE notifications
+SET
+    status = 'cancelled',
+    version = version + 1
+WHERE
+    id = ANY($1::uuid[]) AND tenant_id = $2 AND status = 'scheduled'
+RETURNING id, subject, message, author_id, email_to, telegram_chat_id, channel, status, attempts, scheduled_at, sent_at, created_at, updated_at, tenant_id, callback_url, timezone, delivery_window, priority, tags, metadata, version, request_hash
+`
+
+type CancelNotificationsParams struct {
+	Ids      []pgtype.UUID `json:"ids"`
+	TenantID string        `json:"tenant_id"`
+}
+
+// This query cancels those of the given notifications of a tenant that are still scheduled.
+func (q *Queries) CancelNotifications(ctx context.Context, arg CancelNotificationsParams) ([]Notification, error) {
+	rows, err := q.db.Query(ctx, cancelNotifications, arg.Ids, arg.TenantID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Notification
+	for rows.Next() {
+		var i Notification
+		if err := rows.Scan(
+			&i.ID,
+			&i.Subject,
+			&i.Message,
+			&i.AuthorID,
+			&i.EmailTo,
+			&i.TelegramChatID,
+			&i.Channel,
+			&i.Status,
+			&i.Attempts,
+			&i.ScheduledAt,
+			&i.SentAt,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.TenantID,
+			&i.CallbackUrl,
+			&i.Timezone,
+			&i.DeliveryWindow,
+			&i.Priority,
+			&i.Tags,
+			&i.Metadata,
+			&i.Version,
+			&i.RequestHash,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const countNotifications = `-- name: CountNotifications :one
+SELECT count(*) FROM notifications
+WHERE
+    tenant_id = $1
+    AND ($2::notification_status IS NULL OR status = $2)
+    AND ($3::channel_type IS NULL OR channel = $3)
+    AND ($4::text IS NULL OR author_id = $4)
+    AND ($5::timestamptz IS NULL OR scheduled_at >= $5)
+    AND ($6::timestamptz IS NULL OR scheduled_at < $6)
+    AND (
+        $7::text IS NULL
+        OR strpos(lower(subject), lower($7)) > 0
+        OR strpos(lower(email_to), lower($7)) > 0
+        OR telegram_chat_id::text = $7
+    )
+    AND ($8::text[] IS NULL OR tags @> $8)
+    AND ($9::jsonb IS NULL OR metadata @> $9)
+`
+
+type CountNotificationsParams struct {
+	TenantID      string                 `json:"tenant_id"`
+	Status        NullNotificationStatus `json:"status"`
+	Channel       NullChannelType        `json:"channel"`
+	AuthorID      pgtype.Text            `json:"author_id"`
+	ScheduledFrom pgtype.Timestamptz     `json:"scheduled_from"`
+	ScheduledTo   pgtype.Timestamptz     `json:"scheduled_to"`
+	Search        pgtype.Text            `json:"search"`
+	Tags          []string               `json:"tags"`
+	Metadata      []byte                 `json:"metadata"`
+}
+
+// This query counts a tenant's notifications matching the filters of ListNotifications.
+func (q *Queries) CountNotifications(ctx context.Context, arg CountNotificationsParams) (int64, error) {
+	row := q.db.QueryRow(ctx, countNotifications,
+		arg.TenantID,
+		arg.Status,
+		arg.Channel,
+		arg.AuthorID,
+		arg.ScheduledFrom,
+		arg.ScheduledTo,
+		arg.Search,
+		arg.Tags,
+		arg.Metadata,
+	)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const createNotification = `-- name: CreateNotification :one
 INSERT INTO notifications (
+                           id,
                            subject,
                            message,
                            author_id,
@@ -53,26 +180,46 @@ INSERT INTO notifications (
                            channel,
                            status,
                            attempts,
-                           scheduled_at
+                           scheduled_at,
+                           tenant_id,
+                           callback_url,
+                           timezone,
+                           delivery_window,
+                           priority,
+                           tags,
+                           metadata,
+                           request_hash
 ) VALUES (
-          $1, $2, $3, $4, $5, $6, $7, $8, $9
+          COALESCE($18::uuid, gen_random_uuid()),
+          $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17
          )
-RETURNING id, subject, message, author_id, email_to, telegram_chat_id, channel, status, attempts, scheduled_at, sent_at, created_at, updated_at
+RETURNING id, subject, message, author_id, email_to, telegram_chat_id, channel, status, attempts, scheduled_at, sent_at, created_at, updated_at, tenant_id, callback_url, timezone, delivery_window, priority, tags, metadata, version, request_hash
 `
 
 type CreateNotificationParams struct {
-	Subject        string             `json:"subject"`
-	Message        string             `json:"message"`
-	AuthorID       pgtype.Text        `json:"author_id"`
-	EmailTo        pgtype.Text        `json:"email_to"`
-	TelegramChatID pgtype.Int8        `json:"telegram_chat_id"`
-	Channel        ChannelType        `json:"channel"`
-	Status         NotificationStatus `json:"status"`
-	Attempts       int16              `json:"attempts"`
-	ScheduledAt    pgtype.Timestamptz `json:"scheduled_at"`
-}
-
-// This query inserts a new notification into the database.
+	Subject        string               `json:"subject"`
+	Message        string               `json:"message"`
+	AuthorID       pgtype.Text          `json:"author_id"`
+	EmailTo        pgtype.Text          `json:"email_to"`
+	TelegramChatID pgtype.Int8          `json:"telegram_chat_id"`
+	Channel        ChannelType          `json:"channel"`
+	Status         NotificationStatus   `json:"status"`
+	Attempts       int16                `json:"attempts"`
+	ScheduledAt    pgtype.Timestamptz   `json:"scheduled_at"`
+	TenantID       string               `json:"tenant_id"`
+	CallbackUrl    pgtype.Text          `json:"callback_url"`
+	Timezone       pgtype.Text          `json:"timezone"`
+	DeliveryWindow []byte               `json:"delivery_window"`
+	Priority       NotificationPriority `json:"priority"`
+	Tags           []string             `json:"tags"`
+	Metadata       []byte               `json:"metadata"`
+	RequestHash    pgtype.Text          `json:"request_hash"`
+	ID             pgtype.UUID          `json:"id"`
+}
+
+// This query inserts a new notification into the database. The ID is chosen by the caller,
+// e.g. derived from an idempotency key, so that a retried create hits the primary key;
+// without one the database generates it.
 func (q *Queries) CreateNotification(ctx context.Context, arg CreateNotificationParams) (Notification, error) {
 	row := q.db.QueryRow(ctx, createNotification,
 		arg.Subject,
@@ -84,6 +231,15 @@ func (q *Queries) CreateNotification(ctx context.Context, arg CreateNotification
 		arg.Status,
 		arg.Attempts,
 		arg.ScheduledAt,
+		arg.TenantID,
+		arg.CallbackUrl,
+		arg.Timezone,
+		arg.DeliveryWindow,
+		arg.Priority,
+		arg.Tags,
+		arg.Metadata,
+		arg.RequestHash,
+		arg.ID,
 	)
 	var i Notification
 	err := row.Scan(
@@ -100,18 +256,189 @@ func (q *Queries) CreateNotification(ctx context.Context, arg CreateNotification
 		&i.SentAt,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.TenantID,
+		&i.CallbackUrl,
+		&i.Timezone,
+		&i.DeliveryWindow,
+		&i.Priority,
+		&i.Tags,
+		&i.Metadata,
+		&i.Version,
+		&i.RequestHash,
 	)
 	return i, err
 }
 
 const getNotificationByID = `-- name: GetNotificationByID :one
-SELECT id, subject, message, author_id, email_to, telegram_chat_id, channel, status, attempts, scheduled_at, sent_at, created_at, updated_at FROM notifications
-WHERE id = $1
+SELECT id, subject, message, author_id, email_to, telegram_chat_id, channel, status, attempts, scheduled_at, sent_at, created_at, updated_at, tenant_id, callback_url, timezone, delivery_window, priority, tags, metadata, version, request_hash FROM notifications
+WHERE id = $1 AND tenant_id = $2
+`
+
+type GetNotificationByIDParams struct {
+	ID       pgtype.UUID `json:"id"`
+	TenantID string      `json:"tenant_id"`
+}
+
+// This query retrieves a single notification of a tenant by its unique UUID.
+func (q *Queries) GetNotificationByID(ctx context.Context, arg GetNotificationByIDParams) (Notification, error) {
+	row := q.db.QueryRow(ctx, getNotificationByID, arg.ID, arg.TenantID)
+	var i Notification
+	err := row.Scan(
+		&i.ID,
+		&i.Subject,
+		&i.Message,
+		&i.AuthorID,
+		&i.EmailTo,
+		&i.TelegramChatID,
+		&i.Channel,
+		&i.Status,
+		&i.Attempts,
+		&i.ScheduledAt,
+		&i.SentAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.TenantID,
+		&i.CallbackUrl,
+		&i.Timezone,
+		&i.DeliveryWindow,
+		&i.Priority,
+		&i.Tags,
+		&i.Metadata,
+		&i.Version,
+		&i.RequestHash,
+	)
+	return i, err
+}
+
+const listNotifications = `-- name: ListNotifications :many
+SELECT id, subject, message, author_id, email_to, telegram_chat_id, channel, status, attempts, scheduled_at, sent_at, created_at, updated_at, tenant_id, callback_url, timezone, delivery_window, priority, tags, metadata, version, request_hash FROM notifications
+WHERE
+    tenant_id = $1
+    AND ($2::notification_status IS NULL OR status = $2)
+    AND ($3::channel_type IS NULL OR channel = $3)
+    AND ($4::text IS NULL OR author_id = $4)
+    AND ($5::timestamptz IS NULL OR scheduled_at >= $5)
+    AND ($6::timestamptz IS NULL OR scheduled_at < $6)
+    AND (
+        $7::text IS NULL
+        OR strpos(lower(subject), lower($7)) > 0
+        OR strpos(lower(email_to), lower($7)) > 0
+        OR telegram_chat_id::text = $7
+    )
+    AND ($8::text[] IS NULL OR tags @> $8)
+    AND ($9::jsonb IS NULL OR metadata @> $9)
+    AND (
+        $10::timestamptz IS NULL
+        OR (created_at, id) < ($10, $11::uuid)
+    )
+ORDER BY created_at DESC, id DESC
+LIMIT $12
+`
+
+type ListNotificationsParams struct {
+	TenantID       string                 `json:"tenant_id"`
+	Status         NullNotificationStatus `json:"status"`
+	Channel        NullChannelType        `json:"channel"`
+	AuthorID       pgtype.Text            `json:"author_id"`
+	ScheduledFrom  pgtype.Timestamptz     `json:"scheduled_from"`
+	ScheduledTo    pgtype.Timestamptz     `json:"scheduled_to"`
+	Search         pgtype.Text            `json:"search"`
+	Tags           []string               `json:"tags"`
+	Metadata       []byte                 `json:"metadata"`
+	AfterCreatedAt pgtype.Timestamptz     `json:"after_created_at"`
+	AfterID        pgtype.UUID            `json:"after_id"`
+	PageLimit      int32                  `json:"page_limit"`
+}
+
+// This query lists a tenant's notifications, newest first, with optional filters.
+// Pagination is keyset-based: pass the created_at and id of the last row of the previous page.
+func (q *Queries) ListNotifications(ctx context.Context, arg ListNotificationsParams) ([]Notification, error) {
+	rows, err := q.db.Query(ctx, listNotifications,
+		arg.TenantID,
+		arg.Status,
+		arg.Channel,
+		arg.AuthorID,
+		arg.ScheduledFrom,
+		arg.ScheduledTo,
+		arg.Search,
+		arg.Tags,
+		arg.Metadata,
+		arg.AfterCreatedAt,
+		arg.AfterID,
+		arg.PageLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Notification
+	for rows.Next() {
+		var i Notification
+		if err := rows.Scan(
+			&i.ID,
+			&i.Subject,
+			&i.Message,
+			&i.AuthorID,
+			&i.EmailTo,
+			&i.TelegramChatID,
+			&i.Channel,
+			&i.Status,
+			&i.Attempts,
+			&i.ScheduledAt,
+			&i.SentAt,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.TenantID,
+			&i.CallbackUrl,
+			&i.Timezone,
+			&i.DeliveryWindow,
+			&i.Priority,
+			&i.Tags,
+			&i.Metadata,
+			&i.Version,
+			&i.RequestHash,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const rescheduleNotification = `-- name: RescheduleNotification :one
+UPDATE notifications
+SET
+    scheduled_at = $1,
+    timezone = COALESCE($2, timezone),
+    attempts = 0,
+    version = version + 1
+WHERE
+    id = $3 AND tenant_id = $4 AND status = 'scheduled'
+    AND ($5::integer IS NULL OR version = $5)
+RETURNING id, subject, message, author_id, email_to, telegram_chat_id, channel, status, attempts, scheduled_at, sent_at, created_at, updated_at, tenant_id, callback_url, timezone, delivery_window, priority, tags, metadata, version, request_hash
 `
 
-// This query retrieves a single notification by its unique UUID.
-func (q *Queries) GetNotificationByID(ctx context.Context, id pgtype.UUID) (Notification, error) {
-	row := q.db.QueryRow(ctx, getNotificationByID, id)
+type RescheduleNotificationParams struct {
+	ScheduledAt pgtype.Timestamptz `json:"scheduled_at"`
+	Timezone    pgtype.Text        `json:"timezone"`
+	ID          pgtype.UUID        `json:"id"`
+	TenantID    string             `json:"tenant_id"`
+	Version     pgtype.Int4        `json:"version"`
+}
+
+// This query moves a notification that is still scheduled to a new time and resets its attempts.
+// A NULL time zone keeps the stored one. A non-NULL version must match the stored one.
+func (q *Queries) RescheduleNotification(ctx context.Context, arg RescheduleNotificationParams) (Notification, error) {
+	row := q.db.QueryRow(ctx, rescheduleNotification,
+		arg.ScheduledAt,
+		arg.Timezone,
+		arg.ID,
+		arg.TenantID,
+		arg.Version,
+	)
 	var i Notification
 	err := row.Scan(
 		&i.ID,
@@ -127,35 +454,123 @@ func (q *Queries) GetNotificationByID(ctx context.Context, id pgtype.UUID) (Noti
 		&i.SentAt,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.TenantID,
+		&i.CallbackUrl,
+		&i.Timezone,
+		&i.DeliveryWindow,
+		&i.Priority,
+		&i.Tags,
+		&i.Metadata,
+		&i.Version,
+		&i.RequestHash,
 	)
 	return i, err
 }
 
+const rescheduleNotifications = `-- name: RescheduleNotifications :many
+UPDATE notifications
+SET
+    scheduled_at = $1,
+    timezone = COALESCE($2, timezone),
+    attempts = 0,
+    version = version + 1
+WHERE
+    id = ANY($3::uuid[]) AND tenant_id = $4 AND status = 'scheduled'
+RETURNING id, subject, message, author_id, email_to, telegram_chat_id, channel, status, attempts, scheduled_at, sent_at, created_at, updated_at, tenant_id, callback_url, timezone, delivery_window, priority, tags, metadata, version, request_hash
+`
+
+type RescheduleNotificationsParams struct {
+	ScheduledAt pgtype.Timestamptz `json:"scheduled_at"`
+	Timezone    pgtype.Text        `json:"timezone"`
+	Ids         []pgtype.UUID      `json:"ids"`
+	TenantID    string             `json:"tenant_id"`
+}
+
+// This query reschedules those of the given notifications of a tenant that are still scheduled,
+// like RescheduleNotification.
+func (q *Queries) RescheduleNotifications(ctx context.Context, arg RescheduleNotificationsParams) ([]Notification, error) {
+	rows, err := q.db.Query(ctx, rescheduleNotifications,
+		arg.ScheduledAt,
+		arg.Timezone,
+		arg.Ids,
+		arg.TenantID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Notification
+	for rows.Next() {
+		var i Notification
+		if err := rows.Scan(
+			&i.ID,
+			&i.Subject,
+			&i.Message,
+			&i.AuthorID,
+			&i.EmailTo,
+			&i.TelegramChatID,
+			&i.Channel,
+			&i.Status,
+			&i.Attempts,
+			&i.ScheduledAt,
+			&i.SentAt,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.TenantID,
+			&i.CallbackUrl,
+			&i.Timezone,
+			&i.DeliveryWindow,
+			&i.Priority,
+			&i.Tags,
+			&i.Metadata,
+			&i.Version,
+			&i.RequestHash,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const updateNotificationStatus = `-- name: UpdateNotificationStatus :one
 UPDATE notifications
 SET
-    status = $2,
-    attempts = $3,
-    sent_at = $4
+    status = $1,
+    attempts = $2,
+    sent_at = $3,
+    version = version + 1
 WHERE
-    id = $1
-RETURNING id, subject, message, author_id, email_to, telegram_chat_id, channel, status, attempts, scheduled_at, sent_at, created_at, updated_at
+    id = $4 AND tenant_id = $5 AND status = $6
+    AND version = $7
+RETURNING id, subject, message, author_id, email_to, telegram_chat_id, channel, status, attempts, scheduled_at, sent_at, created_at, updated_at, tenant_id, callback_url, timezone, delivery_window, priority, tags, metadata, version, request_hash
 `
 
 type UpdateNotificationStatusParams struct {
-	ID       pgtype.UUID        `json:"id"`
-	Status   NotificationStatus `json:"status"`
-	Attempts int16              `json:"attempts"`
-	SentAt   pgtype.Timestamptz `json:"sent_at"`
+	Status     NotificationStatus `json:"status"`
+	Attempts   int16              `json:"attempts"`
+	SentAt     pgtype.Timestamptz `json:"sent_at"`
+	ID         pgtype.UUID        `json:"id"`
+	TenantID   string             `json:"tenant_id"`
+	FromStatus NotificationStatus `json:"from_status"`
+	Version    int32              `json:"version"`
 }
 
-// This query updates the status, attempts count, and sent_at timestamp of a notification.
+// This query moves a notification to a new status and updates its attempts count and sent_at timestamp.
+// The update only applies while the notification is still in the expected status and version, so that
+// of two concurrent updates, e.g. a cancel racing with a send, only one succeeds.
 func (q *Queries) UpdateNotificationStatus(ctx context.Context, arg UpdateNotificationStatusParams) (Notification, error) {
 	row := q.db.QueryRow(ctx, updateNotificationStatus,
-		arg.ID,
 		arg.Status,
 		arg.Attempts,
 		arg.SentAt,
+		arg.ID,
+		arg.TenantID,
+		arg.FromStatus,
+		arg.Version,
 	)
 	var i Notification
 	err := row.Scan(
@@ -172,6 +587,15 @@ func (q *Queries) UpdateNotificationStatus(ctx context.Context, arg UpdateNotifi
 		&i.SentAt,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.TenantID,
+		&i.CallbackUrl,
+		&i.Timezone,
+		&i.DeliveryWindow,
+		&i.Priority,
+		&i.Tags,
+		&i.Metadata,
+		&i.Version,
+		&i.RequestHash,
 	)
 	return i, err
 }
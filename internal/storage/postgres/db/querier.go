@@ -12,14 +12,52 @@ import (
 
 type Querier interface {
 	// This query performs a "soft delete" by changing the status to 'cancelled'.
-	// We never truly delete data, we just change its state.
-	CancelNotification(ctx context.Context, id pgtype.UUID) (Notification, error)
-	// This query inserts a new notification into the database.
+	// We never truly delete data, we just change its state. Only scheduled notifications can be cancelled,
+	// and only in the given version, if it is not NULL.
+	CancelNotification(ctx context.Context, arg CancelNotificationParams) (Notification, error)
+	// This query cancels those of the given notifications of a tenant that are still scheduled.
+	CancelNotifications(ctx context.Context, arg CancelNotificationsParams) ([]Notification, error)
+	// This query counts a tenant's notifications matching the filters of ListNotifications.
+	CountNotifications(ctx context.Context, arg CountNotificationsParams) (int64, error)
+	// This query inserts a new API key. Only the hash of the key is stored.
+	CreateAPIKey(ctx context.Context, arg CreateAPIKeyParams) (ApiKey, error)
+	// This query records a single callback delivery attempt.
+	CreateCallbackDelivery(ctx context.Context, arg CreateCallbackDeliveryParams) (CallbackDelivery, error)
+	// This query inserts a new notification into the database. The ID is chosen by the caller,
+	// e.g. derived from an idempotency key, so that a retried create hits the primary key;
+	// without one the database generates it.
 	CreateNotification(ctx context.Context, arg CreateNotificationParams) (Notification, error)
-	// This query retrieves a single notification by its unique UUID.
-	GetNotificationByID(ctx context.Context, id pgtype.UUID) (Notification, error)
-	// This query updates the status, attempts count, and sent_at timestamp of a notification.
+	// This query removes the delivery window of a tenant's recipient.
+	DeleteRecipientDeliveryWindow(ctx context.Context, arg DeleteRecipientDeliveryWindowParams) (RecipientDeliveryWindow, error)
+	// This query finds a non-revoked API key by the hash of its raw value.
+	GetActiveAPIKeyByHash(ctx context.Context, keyHash string) (ApiKey, error)
+	// This query retrieves a single notification of a tenant by its unique UUID.
+	GetNotificationByID(ctx context.Context, arg GetNotificationByIDParams) (Notification, error)
+	// This query retrieves the delivery window of a tenant's recipient.
+	GetRecipientDeliveryWindow(ctx context.Context, arg GetRecipientDeliveryWindowParams) (RecipientDeliveryWindow, error)
+	// This query lists all API keys of a tenant, newest first.
+	ListAPIKeys(ctx context.Context, tenantID string) ([]ApiKey, error)
+	// This query lists the callback delivery attempts of a tenant's notification, oldest first.
+	ListCallbackDeliveries(ctx context.Context, arg ListCallbackDeliveriesParams) ([]CallbackDelivery, error)
+	// This query lists a tenant's notifications, newest first, with optional filters.
+	// Pagination is keyset-based: pass the created_at and id of the last row of the previous page.
+	ListNotifications(ctx context.Context, arg ListNotificationsParams) ([]Notification, error)
+	// This query moves a notification that is still scheduled to a new time and resets its attempts.
+	// A NULL time zone keeps the stored one. A non-NULL version must match the stored one.
+	RescheduleNotification(ctx context.Context, arg RescheduleNotificationParams) (Notification, error)
+	// This query reschedules those of the given notifications of a tenant that are still scheduled,
+	// like RescheduleNotification.
+	RescheduleNotifications(ctx context.Context, arg RescheduleNotificationsParams) ([]Notification, error)
+	// This query revokes an API key of a tenant. Revoked keys are kept for auditing.
+	RevokeAPIKey(ctx context.Context, arg RevokeAPIKeyParams) (ApiKey, error)
+	// This query records the last time an API key was used.
+	TouchAPIKey(ctx context.Context, id pgtype.UUID) error
+	// This query moves a notification to a new status and updates its attempts count and sent_at timestamp.
+	// The update only applies while the notification is still in the expected status and version, so that
+	// of two concurrent updates, e.g. a cancel racing with a send, only one succeeds.
 	UpdateNotificationStatus(ctx context.Context, arg UpdateNotificationStatusParams) (Notification, error)
+	// This query sets the delivery window of a tenant's recipient, replacing any previous one.
+	UpsertRecipientDeliveryWindow(ctx context.Context, arg UpsertRecipientDeliveryWindowParams) (RecipientDeliveryWindow, error)
 }
 
 var _ Querier = (*Queries)(nil)
@@ -0,0 +1,169 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/ilindan-dev/delayed-notifier/internal/domain/model"
+	repo "github.com/ilindan-dev/delayed-notifier/internal/domain/repository"
+	"github.com/ilindan-dev/delayed-notifier/internal/storage/postgres/db"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rs/zerolog"
+)
+
+// Ensure DeliveryWindowRepository implements the interface
+var _ repo.DeliveryWindowRepository = (*DeliveryWindowRepository)(nil)
+
+// DeliveryWindowRepository implements the domain.repository.DeliveryWindowRepository
+// interface using PostgreSQL as a backend.
+type DeliveryWindowRepository struct {
+	pool    *pgxpool.Pool
+	queries *db.Queries
+	logger  zerolog.Logger
+}
+
+// NewDeliveryWindowRepository creates a new instance of the DeliveryWindowRepository.
+func NewDeliveryWindowRepository(pool *pgxpool.Pool, logger *zerolog.Logger) *DeliveryWindowRepository {
+	return &DeliveryWindowRepository{
+		pool:    pool,
+		queries: db.New(pool),
+		logger:  logger.With().Str("layer", "postgres_delivery_window_repository").Logger(),
+	}
+}
+
+// Get returns the delivery window of a tenant's recipient.
+func (r *DeliveryWindowRepository) Get(ctx context.Context, tenantID string, channel model.Channel, recipient string) (*model.DeliveryWindow, error) {
+	row, err := r.queries.GetRecipientDeliveryWindow(ctx, db.GetRecipientDeliveryWindowParams{
+		TenantID:  tenantID,
+		Channel:   db.ChannelType(channel),
+		Recipient: recipient,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, repo.ErrNotFound
+		}
+		r.logger.Err(err).Str("channel", string(channel)).Msg("cannot get recipient delivery window")
+		return nil, fmt.Errorf("postgres: GetRecipientDeliveryWindow failed: %w", err)
+	}
+	return decodeDeliveryWindow(row.DeliveryWindow)
+}
+
+// Put sets the delivery window of a tenant's recipient, replacing any previous one.
+func (r *DeliveryWindowRepository) Put(ctx context.Context, tenantID string, channel model.Channel, recipient string, window *model.DeliveryWindow) error {
+	encoded, err := encodeDeliveryWindow(window)
+	if err != nil {
+		return err
+	}
+	_, err = r.queries.UpsertRecipientDeliveryWindow(ctx, db.UpsertRecipientDeliveryWindowParams{
+		TenantID:       tenantID,
+		Channel:        db.ChannelType(channel),
+		Recipient:      recipient,
+		DeliveryWindow: encoded,
+	})
+	if err != nil {
+		r.logger.Err(err).Str("channel", string(channel)).Msg("cannot set recipient delivery window")
+		return fmt.Errorf("postgres: UpsertRecipientDeliveryWindow failed: %w", err)
+	}
+	return nil
+}
+
+// Delete removes the delivery window of a tenant's recipient.
+func (r *DeliveryWindowRepository) Delete(ctx context.Context, tenantID string, channel model.Channel, recipient string) error {
+	_, err := r.queries.DeleteRecipientDeliveryWindow(ctx, db.DeleteRecipientDeliveryWindowParams{
+		TenantID:  tenantID,
+		Channel:   db.ChannelType(channel),
+		Recipient: recipient,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return repo.ErrNotFound
+		}
+		r.logger.Err(err).Str("channel", string(channel)).Msg("cannot delete recipient delivery window")
+		return fmt.Errorf("postgres: DeleteRecipientDeliveryWindow failed: %w", err)
+	}
+	return nil
+}
+
+// deliveryWindowJSON is the stored form of a delivery window, see migration 00007.
+type deliveryWindowJSON struct {
+	Timezone   string          `json:"timezone"`
+	Days       []string        `json:"days,omitempty"`
+	Hours      *clockRangeJSON `json:"hours,omitempty"`
+	QuietHours *clockRangeJSON `json:"quiet_hours,omitempty"`
+}
+
+type clockRangeJSON struct {
+	Start string `json:"start"`
+	End   string `json:"end"`
+}
+
+// encodeDeliveryWindow converts a delivery window to its stored form. A nil window is stored as NULL.
+func encodeDeliveryWindow(w *model.DeliveryWindow) ([]byte, error) {
+	if w == nil {
+		return nil, nil
+	}
+	stored := deliveryWindowJSON{
+		Timezone:   w.Timezone,
+		Hours:      encodeClockRange(w.Hours),
+		QuietHours: encodeClockRange(w.QuietHours),
+	}
+	for _, d := range w.Days {
+		stored.Days = append(stored.Days, model.WeekdayName(d))
+	}
+	encoded, err := json.Marshal(stored)
+	if err != nil {
+		return nil, fmt.Errorf("postgres: cannot encode delivery window: %w", err)
+	}
+	return encoded, nil
+}
+
+// decodeDeliveryWindow converts a stored delivery window to a domain model. NULL yields nil.
+func decodeDeliveryWindow(data []byte) (*model.DeliveryWindow, error) {
+	if data == nil {
+		return nil, nil
+	}
+	var stored deliveryWindowJSON
+	if err := json.Unmarshal(data, &stored); err != nil {
+		return nil, fmt.Errorf("postgres: cannot decode delivery window: %w", err)
+	}
+	w := &model.DeliveryWindow{Timezone: stored.Timezone}
+	for _, name := range stored.Days {
+		d, err := model.ParseWeekday(name)
+		if err != nil {
+			return nil, fmt.Errorf("postgres: cannot decode delivery window: %w", err)
+		}
+		w.Days = append(w.Days, d)
+	}
+	var err error
+	if w.Hours, err = decodeClockRange(stored.Hours); err != nil {
+		return nil, err
+	}
+	if w.QuietHours, err = decodeClockRange(stored.QuietHours); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+func encodeClockRange(r *model.ClockRange) *clockRangeJSON {
+	if r == nil {
+		return nil
+	}
+	return &clockRangeJSON{Start: r.Start.String(), End: r.End.String()}
+}
+
+func decodeClockRange(stored *clockRangeJSON) (*model.ClockRange, error) {
+	if stored == nil {
+		return nil, nil
+	}
+	start, err := model.ParseClockTime(stored.Start)
+	if err != nil {
+		return nil, fmt.Errorf("postgres: cannot decode delivery window: %w", err)
+	}
+	end, err := model.ParseClockTime(stored.End)
+	if err != nil {
+		return nil, fmt.Errorf("postgres: cannot decode delivery window: %w", err)
+	}
+	return &model.ClockRange{Start: start, End: end}, nil
+}
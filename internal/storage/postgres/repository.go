@@ -2,6 +2,7 @@ package postgres
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"github.com/google/uuid"
@@ -14,6 +15,7 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/rs/zerolog"
+	"time"
 )
 
 // Ensure NotificationRepository implements the interface
@@ -57,11 +59,11 @@ func (r *NotificationRepository) Save(ctx context.Context, n *model.Notification
 	return toDomainModel(&createdDB)
 }
 
-// GetByID retrieves a notification by its unique ID.
-func (r *NotificationRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.Notification, error) {
+// GetByID retrieves a notification of a tenant by its unique ID.
+func (r *NotificationRepository) GetByID(ctx context.Context, tenantID string, id uuid.UUID) (*model.Notification, error) {
 	pgUUID := pgtype.UUID{Bytes: id, Valid: true}
 
-	dbNotification, err := r.queries.GetNotificationByID(ctx, pgUUID)
+	dbNotification, err := r.queries.GetNotificationByID(ctx, db.GetNotificationByIDParams{ID: pgUUID, TenantID: tenantID})
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			r.logger.Warn().Stringer("id", id).Msg("notification not found by id")
@@ -74,43 +76,240 @@ func (r *NotificationRepository) GetByID(ctx context.Context, id uuid.UUID) (*mo
 	return toDomainModel(&dbNotification)
 }
 
-// Update updates the mutable fields of a notification.
-func (r *NotificationRepository) Update(ctx context.Context, n *model.Notification) error {
-	params, err := toDBUpdateParams(n)
+// Transition moves a notification from status from to its new status, if it is still in status from.
+func (r *NotificationRepository) Transition(ctx context.Context, n *model.Notification, from model.Status) error {
+	params, err := toDBUpdateParams(n, from)
 	if err != nil {
 		r.logger.Error().Err(err).Stringer("id", n.ID).Msg("failed to map domain model to update db params")
 		return err
 	}
 
-	_, err = r.queries.UpdateNotificationStatus(ctx, params)
+	updated, err := r.queries.UpdateNotificationStatus(ctx, params)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			r.logger.Warn().Stringer("id", n.ID).Msg("tried to update non-existent notification")
-			return repo.ErrNotFound
+			return r.transitionConflict(ctx, n, from)
 		}
 		r.logger.Err(err).Stringer("id", n.ID).Msg("cannot update notification")
 		return fmt.Errorf("postgres: UpdateNotificationStatus failed: %w", err)
 	}
+	n.Version = int(updated.Version)
 	return nil
 }
 
+// transitionConflict returns why a transition of n updated no row: ErrNotFound if the
+// notification does not exist, and ErrConflict if it changed since it was read.
+func (r *NotificationRepository) transitionConflict(ctx context.Context, n *model.Notification, from model.Status) error {
+	current, err := r.GetByID(ctx, tenantOrDefault(n.TenantID), n.ID)
+	if err != nil {
+		return err
+	}
+	r.logger.Warn().Stringer("id", n.ID).
+		Str("from", string(from)).Int("version", n.Version).
+		Str("current_status", string(current.Status)).Int("current_version", current.Version).
+		Msg("tried to update notification that changed since it was read")
+	return repo.ErrConflict
+}
+
 // Delete performs a "soft delete" on a notification by setting its status to 'cancelled'.
-func (r *NotificationRepository) Delete(ctx context.Context, id uuid.UUID) error {
-	pgUUID := pgtype.UUID{Bytes: id, Valid: true}
-	_, err := r.queries.CancelNotification(ctx, pgUUID)
+func (r *NotificationRepository) Delete(ctx context.Context, tenantID string, id uuid.UUID, version *int) (*model.Notification, error) {
+	params := db.CancelNotificationParams{
+		ID:       pgtype.UUID{Bytes: id, Valid: true},
+		TenantID: tenantID,
+		Version:  toPgVersion(version),
+	}
+	dbNotification, err := r.queries.CancelNotification(ctx, params)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			r.logger.Warn().Stringer("id", id).Msg("tried to cancel non-existent notification")
-			return repo.ErrNotFound
+			r.logger.Warn().Stringer("id", id).Msg("tried to cancel non-existent, unscheduled or changed notification")
+			return nil, repo.ErrNotFound
 		}
 		r.logger.Err(err).Stringer("id", id).Msg("cannot cancel notification")
-		return fmt.Errorf("postgres: CancelNotification failed: %w", err)
+		return nil, fmt.Errorf("postgres: CancelNotification failed: %w", err)
 	}
-	return nil
+	return toDomainModel(&dbNotification)
+}
+
+// Reschedule moves a scheduled notification to a new time and resets its attempts.
+// A non-nil timezone replaces the stored one.
+func (r *NotificationRepository) Reschedule(ctx context.Context, tenantID string, id uuid.UUID, scheduledAt time.Time, timezone *string, version *int) (*model.Notification, error) {
+	params := db.RescheduleNotificationParams{
+		ID:          pgtype.UUID{Bytes: id, Valid: true},
+		TenantID:    tenantID,
+		ScheduledAt: pgtype.Timestamptz{Time: scheduledAt, Valid: true},
+		Version:     toPgVersion(version),
+	}
+	if timezone != nil {
+		params.Timezone = pgtype.Text{String: *timezone, Valid: true}
+	}
+	dbNotification, err := r.queries.RescheduleNotification(ctx, params)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			r.logger.Warn().Stringer("id", id).Msg("tried to reschedule non-existent, unscheduled or changed notification")
+			return nil, repo.ErrNotFound
+		}
+		r.logger.Err(err).Stringer("id", id).Msg("cannot reschedule notification")
+		return nil, fmt.Errorf("postgres: RescheduleNotification failed: %w", err)
+	}
+	return toDomainModel(&dbNotification)
+}
+
+// List returns up to limit notifications of a tenant matching the filter, newest first.
+func (r *NotificationRepository) List(ctx context.Context, tenantID string, filter model.NotificationFilter, after *model.Cursor, limit int) ([]*model.Notification, error) {
+	params, err := toDBListParams(tenantID, filter, after, limit)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := r.queries.ListNotifications(ctx, params)
+	if err != nil {
+		r.logger.Err(err).Str("method", "List").Msg("cannot list notifications")
+		return nil, fmt.Errorf("postgres: ListNotifications failed: %w", err)
+	}
+
+	return toDomainModels(rows)
+}
+
+// Count returns the number of notifications of a tenant matching the filter.
+func (r *NotificationRepository) Count(ctx context.Context, tenantID string, filter model.NotificationFilter) (int, error) {
+	list, err := toDBListParams(tenantID, filter, nil, 0)
+	if err != nil {
+		return 0, err
+	}
+	count, err := r.queries.CountNotifications(ctx, db.CountNotificationsParams{
+		TenantID:      list.TenantID,
+		Status:        list.Status,
+		Channel:       list.Channel,
+		AuthorID:      list.AuthorID,
+		ScheduledFrom: list.ScheduledFrom,
+		ScheduledTo:   list.ScheduledTo,
+		Search:        list.Search,
+		Tags:          list.Tags,
+		Metadata:      list.Metadata,
+	})
+	if err != nil {
+		r.logger.Err(err).Str("method", "Count").Msg("cannot count notifications")
+		return 0, fmt.Errorf("postgres: CountNotifications failed: %w", err)
+	}
+	return int(count), nil
+}
+
+// CancelMany performs a "soft delete" on those of the notifications that are still scheduled.
+func (r *NotificationRepository) CancelMany(ctx context.Context, tenantID string, ids []uuid.UUID) ([]*model.Notification, error) {
+	rows, err := r.queries.CancelNotifications(ctx, db.CancelNotificationsParams{Ids: toPgUUIDs(ids), TenantID: tenantID})
+	if err != nil {
+		r.logger.Err(err).Int("count", len(ids)).Msg("cannot cancel notifications")
+		return nil, fmt.Errorf("postgres: CancelNotifications failed: %w", err)
+	}
+	return toDomainModels(rows)
+}
+
+// RescheduleMany moves those of the notifications that are still scheduled to a new time
+// and resets their attempts. A non-nil timezone replaces the stored ones.
+func (r *NotificationRepository) RescheduleMany(ctx context.Context, tenantID string, ids []uuid.UUID, scheduledAt time.Time, timezone *string) ([]*model.Notification, error) {
+	params := db.RescheduleNotificationsParams{
+		Ids:         toPgUUIDs(ids),
+		TenantID:    tenantID,
+		ScheduledAt: pgtype.Timestamptz{Time: scheduledAt, Valid: true},
+	}
+	if timezone != nil {
+		params.Timezone = pgtype.Text{String: *timezone, Valid: true}
+	}
+	rows, err := r.queries.RescheduleNotifications(ctx, params)
+	if err != nil {
+		r.logger.Err(err).Int("count", len(ids)).Msg("cannot reschedule notifications")
+		return nil, fmt.Errorf("postgres: RescheduleNotifications failed: %w", err)
+	}
+	return toDomainModels(rows)
 }
 
 // === Mapper Functions ===
 
+// toPgUUIDs converts notification IDs to query parameters.
+func toPgUUIDs(ids []uuid.UUID) []pgtype.UUID {
+	pgIDs := make([]pgtype.UUID, len(ids))
+	for i, id := range ids {
+		pgIDs[i] = pgtype.UUID{Bytes: id, Valid: true}
+	}
+	return pgIDs
+}
+
+// toDomainModels converts database rows to domain models.
+func toDomainModels(rows []db.Notification) ([]*model.Notification, error) {
+	notifications := make([]*model.Notification, 0, len(rows))
+	for i := range rows {
+		n, err := toDomainModel(&rows[i])
+		if err != nil {
+			return nil, err
+		}
+		notifications = append(notifications, n)
+	}
+	return notifications, nil
+}
+
+// toDBListParams converts a list filter and page position to sqlc list parameters.
+func toDBListParams(tenantID string, filter model.NotificationFilter, after *model.Cursor, limit int) (db.ListNotificationsParams, error) {
+	params := db.ListNotificationsParams{
+		TenantID:  tenantID,
+		PageLimit: int32(limit),
+	}
+	if filter.Status != nil {
+		params.Status = db.NullNotificationStatus{NotificationStatus: db.NotificationStatus(*filter.Status), Valid: true}
+	}
+	if filter.Channel != nil {
+		params.Channel = db.NullChannelType{ChannelType: db.ChannelType(*filter.Channel), Valid: true}
+	}
+	if filter.AuthorID != nil {
+		params.AuthorID = pgtype.Text{String: *filter.AuthorID, Valid: true}
+	}
+	if filter.ScheduledFrom != nil {
+		params.ScheduledFrom = pgtype.Timestamptz{Time: *filter.ScheduledFrom, Valid: true}
+	}
+	if filter.ScheduledTo != nil {
+		params.ScheduledTo = pgtype.Timestamptz{Time: *filter.ScheduledTo, Valid: true}
+	}
+	if filter.Search != nil {
+		params.Search = pgtype.Text{String: *filter.Search, Valid: true}
+	}
+	if len(filter.Tags) > 0 {
+		params.Tags = filter.Tags
+	}
+	if len(filter.Metadata) > 0 {
+		metadata, err := encodeMetadata(filter.Metadata)
+		if err != nil {
+			return db.ListNotificationsParams{}, err
+		}
+		params.Metadata = metadata
+	}
+	if after != nil {
+		params.AfterCreatedAt = pgtype.Timestamptz{Time: after.CreatedAt, Valid: true}
+		params.AfterID = pgtype.UUID{Bytes: after.ID, Valid: true}
+	}
+	return params, nil
+}
+
+// encodeMetadata converts metadata to its JSONB column value, an empty object if there is none.
+func encodeMetadata(metadata map[string]any) ([]byte, error) {
+	if metadata == nil {
+		return []byte("{}"), nil
+	}
+	data, err := json.Marshal(metadata)
+	if err != nil {
+		return nil, fmt.Errorf("postgres: failed to encode metadata: %w", err)
+	}
+	return data, nil
+}
+
+// decodeMetadata converts the JSONB column value of metadata, returning nil for an empty object.
+func decodeMetadata(data []byte) (map[string]any, error) {
+	var metadata map[string]any
+	if err := json.Unmarshal(data, &metadata); err != nil {
+		return nil, fmt.Errorf("postgres: failed to decode metadata: %w", err)
+	}
+	if len(metadata) == 0 {
+		return nil, nil
+	}
+	return metadata, nil
+}
+
 // toDBCreateParams safely converts a domain model to sqlc create parameters.
 func toDBCreateParams(n *model.Notification) (db.CreateNotificationParams, error) {
 	params := db.CreateNotificationParams{
@@ -118,12 +317,38 @@ func toDBCreateParams(n *model.Notification) (db.CreateNotificationParams, error
 		Message:     n.Message,
 		Channel:     db.ChannelType(n.Channel),
 		Status:      db.NotificationStatus(n.Status),
+		Priority:    db.NotificationPriority(n.Priority),
 		Attempts:    int16(n.Attempts),
 		ScheduledAt: pgtype.Timestamptz{Time: n.ScheduledAt, Valid: true},
+		TenantID:    tenantOrDefault(n.TenantID),
+	}
+	if n.ID != uuid.Nil {
+		params.ID = pgtype.UUID{Bytes: n.ID, Valid: true}
 	}
 	if n.AuthorID != nil {
 		params.AuthorID = pgtype.Text{String: *n.AuthorID, Valid: true}
 	}
+	if n.CallbackURL != nil {
+		params.CallbackUrl = pgtype.Text{String: *n.CallbackURL, Valid: true}
+	}
+	if n.Timezone != nil {
+		params.Timezone = pgtype.Text{String: *n.Timezone, Valid: true}
+	}
+	window, err := encodeDeliveryWindow(n.DeliveryWindow)
+	if err != nil {
+		return db.CreateNotificationParams{}, err
+	}
+	params.DeliveryWindow = window
+	if n.RequestHash != "" {
+		params.RequestHash = pgtype.Text{String: n.RequestHash, Valid: true}
+	}
+	params.Tags = n.Tags
+	if params.Tags == nil {
+		params.Tags = []string{}
+	}
+	if params.Metadata, err = encodeMetadata(n.Metadata); err != nil {
+		return db.CreateNotificationParams{}, err
+	}
 	switch n.Channel {
 	case model.ChannelEmail:
 		if n.Email == nil || n.Email.To == "" {
@@ -141,12 +366,15 @@ func toDBCreateParams(n *model.Notification) (db.CreateNotificationParams, error
 	return params, nil
 }
 
-// toDBUpdateParams converts a domain model to the sqlc-generated parameters for updating.
-func toDBUpdateParams(n *model.Notification) (db.UpdateNotificationStatusParams, error) {
+// toDBUpdateParams converts a domain model to the sqlc-generated parameters for moving it from status from.
+func toDBUpdateParams(n *model.Notification, from model.Status) (db.UpdateNotificationStatusParams, error) {
 	params := db.UpdateNotificationStatusParams{
-		ID:       pgtype.UUID{Bytes: n.ID, Valid: true},
-		Status:   db.NotificationStatus(n.Status),
-		Attempts: int16(n.Attempts),
+		ID:         pgtype.UUID{Bytes: n.ID, Valid: true},
+		Status:     db.NotificationStatus(n.Status),
+		Attempts:   int16(n.Attempts),
+		TenantID:   tenantOrDefault(n.TenantID),
+		FromStatus: db.NotificationStatus(from),
+		Version:    int32(n.Version),
 	}
 	if n.SentAt != nil {
 		params.SentAt = pgtype.Timestamptz{Time: *n.SentAt, Valid: true}
@@ -163,11 +391,14 @@ func toDomainModel(dbn *db.Notification) (*model.Notification, error) {
 	}
 	domainModel := &model.Notification{
 		ID:          dbn.ID.Bytes,
+		TenantID:    dbn.TenantID,
 		Subject:     dbn.Subject,
 		Message:     dbn.Message,
 		Channel:     model.Channel(dbn.Channel),
 		Status:      model.Status(dbn.Status),
+		Priority:    model.Priority(dbn.Priority),
 		Attempts:    int(dbn.Attempts),
+		Version:     int(dbn.Version),
 		ScheduledAt: dbn.ScheduledAt.Time,
 		CreatedAt:   dbn.CreatedAt.Time,
 		UpdatedAt:   dbn.UpdatedAt.Time,
@@ -178,6 +409,24 @@ func toDomainModel(dbn *db.Notification) (*model.Notification, error) {
 	if dbn.SentAt.Valid {
 		domainModel.SentAt = &dbn.SentAt.Time
 	}
+	if dbn.CallbackUrl.Valid {
+		domainModel.CallbackURL = &dbn.CallbackUrl.String
+	}
+	if dbn.Timezone.Valid {
+		domainModel.Timezone = &dbn.Timezone.String
+	}
+	domainModel.RequestHash = dbn.RequestHash.String
+	window, err := decodeDeliveryWindow(dbn.DeliveryWindow)
+	if err != nil {
+		return nil, err
+	}
+	domainModel.DeliveryWindow = window
+	if len(dbn.Tags) > 0 {
+		domainModel.Tags = dbn.Tags
+	}
+	if domainModel.Metadata, err = decodeMetadata(dbn.Metadata); err != nil {
+		return nil, err
+	}
 	switch domainModel.Channel {
 	case model.ChannelEmail:
 		if dbn.EmailTo.Valid {
@@ -190,3 +439,20 @@ func toDomainModel(dbn *db.Notification) (*model.Notification, error) {
 	}
 	return domainModel, nil
 }
+
+// toPgVersion converts an optional expected version to its query parameter.
+func toPgVersion(version *int) pgtype.Int4 {
+	if version == nil {
+		return pgtype.Int4{}
+	}
+	return pgtype.Int4{Int32: int32(*version), Valid: true}
+}
+
+// tenantOrDefault maps an empty tenant, e.g. from a message published before
+// tenants were introduced, to the default tenant.
+func tenantOrDefault(tenantID string) string {
+	if tenantID == "" {
+		return model.DefaultTenant
+	}
+	return tenantID
+}
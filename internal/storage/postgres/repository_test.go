@@ -0,0 +1,106 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/ilindan-dev/delayed-notifier/internal/domain/model"
+	repo "github.com/ilindan-dev/delayed-notifier/internal/domain/repository"
+	"github.com/ilindan-dev/delayed-notifier/internal/storage/postgres/db"
+	"github.com/jackc/pgerrcode"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/rs/zerolog"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// createColumns are the columns CreateNotification inserts, in the order of its arguments.
+var createColumns = []string{
+	"subject", "message", "author_id", "email_to", "telegram_chat_id", "channel", "status",
+	"attempts", "scheduled_at", "tenant_id", "callback_url", "timezone", "delivery_window",
+	"priority", "tags", "metadata", "request_hash", "id",
+}
+
+// returnedColumns are the columns of a notifications row, in the order they are scanned.
+var returnedColumns = []string{
+	"id", "subject", "message", "author_id", "email_to", "telegram_chat_id", "channel", "status",
+	"attempts", "scheduled_at", "sent_at", "created_at", "updated_at", "tenant_id", "callback_url",
+	"timezone", "delivery_window", "priority", "tags", "metadata", "version", "request_hash",
+}
+
+// fakeNotificationsTable emulates the insert of CreateNotification, including its
+// primary key and the ID generated when none is passed.
+type fakeNotificationsTable struct {
+	db.DBTX
+	ids map[uuid.UUID]bool
+}
+
+func (t *fakeNotificationsTable) QueryRow(_ context.Context, _ string, args ...any) pgx.Row {
+	values := make(map[string]any, len(args))
+	for i, column := range createColumns {
+		values[column] = args[i]
+	}
+	id := values["id"].(pgtype.UUID)
+	if !id.Valid {
+		id = pgtype.UUID{Bytes: uuid.New(), Valid: true}
+		values["id"] = id
+	}
+	if t.ids[id.Bytes] {
+		return fakeRow{err: &pgconn.PgError{Code: pgerrcode.UniqueViolation}}
+	}
+	t.ids[id.Bytes] = true
+	values["version"] = int32(1)
+	return fakeRow{values: values}
+}
+
+// fakeRow scans the values of a row by column name, leaving the others zero.
+type fakeRow struct {
+	values map[string]any
+	err    error
+}
+
+func (r fakeRow) Scan(dest ...any) error {
+	if r.err != nil {
+		return r.err
+	}
+	for i, column := range returnedColumns {
+		if value, ok := r.values[column]; ok {
+			reflect.ValueOf(dest[i]).Elem().Set(reflect.ValueOf(value))
+		}
+	}
+	return nil
+}
+
+func TestSaveKeepsNotificationID(t *testing.T) {
+	r := &NotificationRepository{
+		queries: db.New(&fakeNotificationsTable{ids: make(map[uuid.UUID]bool)}),
+		logger:  zerolog.Nop(),
+	}
+	n := model.NewEmailNotification(model.DefaultTenant, "user@example.com", "Hello", "World", time.Now(), nil)
+
+	saved, err := r.Save(context.Background(), n)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if saved.ID != n.ID {
+		t.Errorf("got ID %s, want the notification's %s", saved.ID, n.ID)
+	}
+
+	// A retried create with an idempotent ID must hit the primary key.
+	if _, err := r.Save(context.Background(), n); !errors.Is(err, repo.ErrDuplicateRecord) {
+		t.Errorf("got %v, want %v", err, repo.ErrDuplicateRecord)
+	}
+
+	// Without an ID, the database generates one.
+	n.ID = uuid.Nil
+	saved, err = r.Save(context.Background(), n)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if saved.ID == uuid.Nil {
+		t.Error("got no ID, want a generated one")
+	}
+}
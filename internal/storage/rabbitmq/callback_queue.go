@@ -0,0 +1,94 @@
+package rabbitmq
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/ilindan-dev/delayed-notifier/internal/domain/model"
+	repo "github.com/ilindan-dev/delayed-notifier/internal/domain/repository"
+	amqp "github.com/rabbitmq/amqp091-go"
+	"strconv"
+	"time"
+)
+
+// Ensure RabbitMQQueue implements the callback queue interface at compile time.
+var _ repo.CallbackQueue = (*RabbitMQQueue)(nil)
+
+// Constants for the callback delivery topology. Callback events are kept apart
+// from notifications so that slow caller endpoints cannot delay sends.
+const (
+	CallbacksExchange      = "callbacks.exchange"
+	CallbacksRetryExchange = "callbacks.retry.exchange"
+
+	CallbacksQueue      = "callbacks.queue.deliver"
+	CallbacksRetryQueue = "callbacks.queue.retry"
+)
+
+// setupCallbackTopology declares the exchanges and queues used for callback delivery.
+// Events waiting for a retry expire from the retry queue back into the delivery queue.
+func (q *RabbitMQQueue) setupCallbackTopology() error {
+	for _, exchange := range []string{CallbacksExchange, CallbacksRetryExchange} {
+		if err := q.ch.ExchangeDeclare(exchange, Direct, true, false, false, false, nil); err != nil {
+			return fmt.Errorf("failed to declare exchange %s: %w", exchange, err)
+		}
+	}
+
+	if _, err := q.ch.QueueDeclare(CallbacksQueue, true, false, false, false, nil); err != nil {
+		return fmt.Errorf("failed to declare queue %s: %w", CallbacksQueue, err)
+	}
+	retryQueueArgs := amqp.Table{"x-dead-letter-exchange": CallbacksExchange}
+	if _, err := q.ch.QueueDeclare(CallbacksRetryQueue, true, false, false, false, retryQueueArgs); err != nil {
+		return fmt.Errorf("failed to declare queue %s: %w", CallbacksRetryQueue, err)
+	}
+
+	if err := q.ch.QueueBind(CallbacksQueue, "", CallbacksExchange, false, nil); err != nil {
+		return fmt.Errorf("failed to bind queue %s to exchange %s: %w", CallbacksQueue, CallbacksExchange, err)
+	}
+	if err := q.ch.QueueBind(CallbacksRetryQueue, "", CallbacksRetryExchange, false, nil); err != nil {
+		return fmt.Errorf("failed to bind queue %s to exchange %s: %w", CallbacksRetryQueue, CallbacksRetryExchange, err)
+	}
+	return nil
+}
+
+// PublishCallback queues a callback event for immediate delivery.
+func (q *RabbitMQQueue) PublishCallback(ctx context.Context, e *model.CallbackEvent) error {
+	body, err := json.Marshal(e)
+	if err != nil {
+		q.logger.Error().Err(err).Stringer("event_id", e.ID).Msg("failed to marshal callback event")
+		return fmt.Errorf("failed to marshal callback event: %w", err)
+	}
+
+	msg := amqp.Publishing{
+		ContentType:  "application/json",
+		Body:         body,
+		DeliveryMode: amqp.Persistent,
+	}
+
+	if err := q.publishConfirmed(ctx, CallbacksExchange, "", msg); err != nil {
+		q.logger.Error().Err(err).Stringer("event_id", e.ID).Msg("failed to publish callback event")
+		return err
+	}
+	return nil
+}
+
+// PublishCallbackRetry queues a callback event for another delivery attempt after retryDelay.
+func (q *RabbitMQQueue) PublishCallbackRetry(ctx context.Context, e *model.CallbackEvent, retryDelay time.Duration) error {
+	body, err := json.Marshal(e)
+	if err != nil {
+		q.logger.Error().Err(err).Stringer("event_id", e.ID).Msg("failed to marshal callback event for retry")
+		return fmt.Errorf("failed to marshal callback event for retry: %w", err)
+	}
+
+	msg := amqp.Publishing{
+		ContentType:  "application/json",
+		Body:         body,
+		DeliveryMode: amqp.Persistent,
+		Expiration:   strconv.FormatInt(retryDelay.Milliseconds(), 10),
+	}
+
+	if err := q.publishConfirmed(ctx, CallbacksRetryExchange, "", msg); err != nil {
+		q.logger.Error().Err(err).Stringer("event_id", e.ID).Msg("failed to publish callback event for retry")
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,128 @@
+package rabbitmq
+
+import (
+	"context"
+	"fmt"
+	"github.com/ilindan-dev/delayed-notifier/internal/domain/model"
+	repo "github.com/ilindan-dev/delayed-notifier/internal/domain/repository"
+	amqp "github.com/rabbitmq/amqp091-go"
+	"time"
+)
+
+// Ensure RabbitMQQueue implements the dead letter and inspection interfaces at compile time.
+var (
+	_ repo.DeadLetterQueue = (*RabbitMQQueue)(nil)
+	_ repo.QueueInspector  = (*RabbitMQQueue)(nil)
+)
+
+// Constants for the dead letter topology. Nothing consumes the dead letter queue;
+// it keeps messages the worker gave up on until an operator looks at them.
+const (
+	DeadLetterExchange = "dead.exchange"
+	DeadLetterQueue    = "notifications.queue.dead"
+
+	// deadReasonHeader carries the reason a message was dead-lettered.
+	deadReasonHeader = "x-dead-reason"
+)
+
+// setupDeadLetterTopology declares the dead letter exchange and queue.
+func (q *RabbitMQQueue) setupDeadLetterTopology() error {
+	if err := q.ch.ExchangeDeclare(DeadLetterExchange, Direct, true, false, false, false, nil); err != nil {
+		return fmt.Errorf("failed to declare exchange %s: %w", DeadLetterExchange, err)
+	}
+	if _, err := q.ch.QueueDeclare(DeadLetterQueue, true, false, false, false, nil); err != nil {
+		return fmt.Errorf("failed to declare queue %s: %w", DeadLetterQueue, err)
+	}
+	if err := q.ch.QueueBind(DeadLetterQueue, "", DeadLetterExchange, false, nil); err != nil {
+		return fmt.Errorf("failed to bind queue %s to exchange %s: %w", DeadLetterQueue, DeadLetterExchange, err)
+	}
+	return nil
+}
+
+// PublishDeadLetter stores a copy of a message body in the dead letter queue.
+func (q *RabbitMQQueue) PublishDeadLetter(ctx context.Context, body []byte, reason string) error {
+	msg := amqp.Publishing{
+		ContentType:  "application/json",
+		Body:         body,
+		DeliveryMode: amqp.Persistent,
+		Timestamp:    time.Now().UTC(),
+		Headers:      amqp.Table{deadReasonHeader: reason},
+	}
+
+	if err := q.publishConfirmed(ctx, DeadLetterExchange, "", msg); err != nil {
+		q.logger.Error().Err(err).Str("reason", reason).Msg("failed to publish dead letter")
+		return err
+	}
+	return nil
+}
+
+// Stats returns the depth and consumer count of every queue of the service.
+// It uses a channel of its own, as the broker closes a channel on failed queue declarations.
+func (q *RabbitMQQueue) Stats(_ context.Context) ([]model.QueueStats, error) {
+	ch, err := q.conn.Channel()
+	if err != nil {
+		return nil, fmt.Errorf("rabbitmq: failed to open a channel: %w", err)
+	}
+	defer func() { _ = ch.Close() }()
+
+	names := []string{SharedQueueName(q.priorityQueues)}
+	if q.perChannelQueues {
+		for _, channel := range model.AllChannels {
+			names = append(names, ProcessQueueName(channel, q.priorityQueues))
+		}
+	}
+	names = append(names, WaitQueue, RetryQueue)
+	for _, tier := range deferTiers {
+		names = append(names, DeferQueueName(tier.name))
+	}
+	names = append(names, CallbacksQueue, CallbacksRetryQueue, DeadLetterQueue)
+
+	stats := make([]model.QueueStats, 0, len(names))
+	for _, name := range names {
+		queue, err := ch.QueueDeclarePassive(name, true, false, false, false, nil)
+		if err != nil {
+			return nil, fmt.Errorf("rabbitmq: failed to inspect queue %s: %w", name, err)
+		}
+		stats = append(stats, model.QueueStats{Name: queue.Name, Messages: queue.Messages, Consumers: queue.Consumers})
+	}
+	return stats, nil
+}
+
+// PeekDeadLetters returns up to limit of the oldest dead letters. The messages are
+// fetched without acknowledgement and requeued, so they stay in the queue.
+func (q *RabbitMQQueue) PeekDeadLetters(_ context.Context, limit int) ([]model.DeadLetter, error) {
+	ch, err := q.conn.Channel()
+	if err != nil {
+		return nil, fmt.Errorf("rabbitmq: failed to open a channel: %w", err)
+	}
+	defer func() { _ = ch.Close() }()
+
+	var (
+		letters []model.DeadLetter
+		lastTag uint64
+	)
+	for len(letters) < limit {
+		msg, ok, err := ch.Get(DeadLetterQueue, false)
+		if err != nil {
+			return nil, fmt.Errorf("rabbitmq: failed to get from queue %s: %w", DeadLetterQueue, err)
+		}
+		if !ok {
+			break
+		}
+		lastTag = msg.DeliveryTag
+		reason, _ := msg.Headers[deadReasonHeader].(string)
+		letters = append(letters, model.DeadLetter{
+			MessageID: msg.MessageId,
+			Reason:    reason,
+			DeadAt:    msg.Timestamp,
+			Body:      msg.Body,
+		})
+	}
+
+	if lastTag != 0 {
+		if err := ch.Nack(lastTag, true, true); err != nil {
+			return nil, fmt.Errorf("rabbitmq: failed to requeue dead letters: %w", err)
+		}
+	}
+	return letters, nil
+}
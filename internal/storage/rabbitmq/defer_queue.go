@@ -0,0 +1,108 @@
+package rabbitmq
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/ilindan-dev/delayed-notifier/internal/domain/model"
+	amqp "github.com/rabbitmq/amqp091-go"
+	"time"
+)
+
+// Constants for the deferral topology. Deferred notifications wait in queues of their
+// own, apart from the retry queue, since the broker only expires messages at the head of
+// a queue: an overnight deferral would hold back every short retry queued behind it.
+const (
+	DeferExchange = "defer.exchange"
+
+	Headers = "headers"
+
+	// deferTierHeader selects the deferral queue of a message on the headers exchange,
+	// so that the routing key keeps the channel for the process exchange.
+	deferTierHeader = "defer-tier"
+	// DeferredHeader marks messages that were deferred, and so were picked up late on
+	// purpose. Expired deferral messages keep their headers.
+	DeferredHeader = "x-deferred"
+)
+
+// deferTier is a deferral queue whose messages all wait for the same time, so that they
+// expire in the order they were queued.
+type deferTier struct {
+	name string
+	ttl  time.Duration
+}
+
+// deferTiers are ordered by their waiting time.
+var deferTiers = []deferTier{
+	{"1s", time.Second},
+	{"10s", 10 * time.Second},
+	{"1m", time.Minute},
+	{"10m", 10 * time.Minute},
+	{"1h", time.Hour},
+}
+
+// DeferQueueName returns the name of the deferral queue of a tier.
+func DeferQueueName(tier string) string {
+	return "defer.queue." + tier
+}
+
+// tierFor returns the longest tier not exceeding delay, or the shortest for shorter delays.
+// Longer delays take several hops, after each of which the consumer defers the
+// notification again for the rest of the delay.
+func tierFor(delay time.Duration) deferTier {
+	tier := deferTiers[0]
+	for _, t := range deferTiers[1:] {
+		if t.ttl <= delay {
+			tier = t
+		}
+	}
+	return tier
+}
+
+// setupDeferTopology declares the deferral exchange and queues. Deferred messages
+// expire from their queue back into the process exchange.
+func (q *RabbitMQQueue) setupDeferTopology() error {
+	if err := q.ch.ExchangeDeclare(DeferExchange, Headers, true, false, false, false, nil); err != nil {
+		return fmt.Errorf("failed to declare exchange %s: %w", DeferExchange, err)
+	}
+	for _, tier := range deferTiers {
+		name := DeferQueueName(tier.name)
+		args := amqp.Table{
+			"x-dead-letter-exchange": NotificationsExchange,
+			"x-message-ttl":          tier.ttl.Milliseconds(),
+		}
+		if _, err := q.ch.QueueDeclare(name, true, false, false, false, args); err != nil {
+			return fmt.Errorf("failed to declare queue %s: %w", name, err)
+		}
+		bindArgs := amqp.Table{"x-match": "all", deferTierHeader: tier.name}
+		if err := q.ch.QueueBind(name, "", DeferExchange, false, bindArgs); err != nil {
+			return fmt.Errorf("failed to bind queue %s to exchange %s: %w", name, DeferExchange, err)
+		}
+	}
+	return nil
+}
+
+// PublishDeferred queues a notification that was not attempted for another try after
+// about delay, see tierFor.
+func (q *RabbitMQQueue) PublishDeferred(ctx context.Context, n *model.Notification, delay time.Duration) error {
+	body, err := json.Marshal(n)
+	if err != nil {
+		q.logger.Error().Err(err).Stringer("id", n.ID).Msg("failed to marshal deferred notification")
+		return fmt.Errorf("failed to marshal deferred notification: %w", err)
+	}
+
+	tier := tierFor(delay)
+	msg := amqp.Publishing{
+		ContentType:  "application/json",
+		Body:         body,
+		DeliveryMode: amqp.Persistent,
+		Priority:     uint8(n.Priority.Level()),
+		Headers:      amqp.Table{deferTierHeader: tier.name, DeferredHeader: true},
+	}
+
+	if err := q.publishConfirmed(ctx, DeferExchange, string(n.Channel), msg); err != nil {
+		q.logger.Error().Err(err).Stringer("id", n.ID).Str("tier", tier.name).Msg("failed to publish deferred notification")
+		return err
+	}
+	return nil
+}
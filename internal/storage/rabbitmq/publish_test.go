@@ -0,0 +1,97 @@
+package rabbitmq
+
+import (
+	"context"
+	"errors"
+	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/rs/zerolog"
+	"testing"
+	"time"
+)
+
+// fakeConfirmation is the broker's answer to a publish. A nil acked never confirms.
+type fakeConfirmation struct {
+	acked *bool
+}
+
+func (c fakeConfirmation) WaitContext(ctx context.Context) (bool, error) {
+	if c.acked == nil {
+		<-ctx.Done()
+		return false, ctx.Err()
+	}
+	return *c.acked, nil
+}
+
+// fakePublisher answers publishes with acked, after buffering returns like the broker
+// does for unroutable mandatory messages.
+type fakePublisher struct {
+	returns    chan amqp.Return
+	publishErr error
+	acked      *bool
+	returned   bool   // Return the published message.
+	staleID    string // Return a message of an earlier publish.
+}
+
+func (p *fakePublisher) publish(_ context.Context, exchange, _ string, msg amqp.Publishing) (confirmation, error) {
+	if p.publishErr != nil {
+		return nil, p.publishErr
+	}
+	if p.staleID != "" {
+		p.returns <- amqp.Return{MessageId: p.staleID, Exchange: exchange, ReplyText: "NO_ROUTE"}
+	}
+	if p.returned {
+		p.returns <- amqp.Return{MessageId: msg.MessageId, Exchange: exchange, ReplyText: "NO_ROUTE"}
+	}
+	return fakeConfirmation{acked: p.acked}, nil
+}
+
+func TestPublishConfirmed(t *testing.T) {
+	acked, nacked := true, false
+	refused := errors.New("channel/connection is not open")
+
+	tests := []struct {
+		name      string
+		publisher fakePublisher
+		want      error
+	}{
+		{name: "acked", publisher: fakePublisher{acked: &acked}, want: nil},
+		{name: "nacked", publisher: fakePublisher{acked: &nacked}, want: ErrPublishNacked},
+		{name: "returned", publisher: fakePublisher{acked: &acked, returned: true}, want: ErrPublishUnroutable},
+		{name: "stale return", publisher: fakePublisher{acked: &acked, staleID: "earlier"}, want: nil},
+		{name: "stale and own return", publisher: fakePublisher{acked: &acked, staleID: "earlier", returned: true}, want: ErrPublishUnroutable},
+		{name: "not confirmed", publisher: fakePublisher{}, want: ErrPublishTimeout},
+		{name: "publish failed", publisher: fakePublisher{publishErr: refused}, want: refused},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			publisher := tt.publisher
+			publisher.returns = make(chan amqp.Return, returnsBufferSize)
+			q := &RabbitMQQueue{
+				publisher:      &publisher,
+				returns:        publisher.returns,
+				publishTimeout: 20 * time.Millisecond,
+				logger:         zerolog.Nop(),
+			}
+
+			err := q.publishConfirmed(context.Background(), WaitExchange, "email", amqp.Publishing{Body: []byte("{}")})
+			if !errors.Is(err, tt.want) {
+				t.Errorf("got %v, want %v", err, tt.want)
+			}
+			if n := len(q.returns); n != 0 {
+				t.Errorf("got %d returns left buffered, want them drained", n)
+			}
+		})
+	}
+}
+
+func TestPublishConfirmedCancelled(t *testing.T) {
+	publisher := &fakePublisher{returns: make(chan amqp.Return, returnsBufferSize)}
+	q := &RabbitMQQueue{publisher: publisher, returns: publisher.returns, publishTimeout: time.Hour, logger: zerolog.Nop()}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err := q.publishConfirmed(ctx, WaitExchange, "email", amqp.Publishing{Body: []byte("{}")})
+	if !errors.Is(err, context.Canceled) || errors.Is(err, ErrPublishTimeout) {
+		t.Errorf("got %v, want the caller's cancellation", err)
+	}
+}
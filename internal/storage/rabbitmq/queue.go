@@ -3,12 +3,17 @@ package rabbitmq
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"github.com/google/uuid"
+	"github.com/ilindan-dev/delayed-notifier/internal/config"
 	"github.com/ilindan-dev/delayed-notifier/internal/domain/model"
 	repo "github.com/ilindan-dev/delayed-notifier/internal/domain/repository"
 	amqp "github.com/rabbitmq/amqp091-go"
 	"github.com/rs/zerolog"
+	"slices"
 	"strconv"
+	"sync"
 	"time"
 )
 
@@ -22,23 +27,69 @@ const (
 	NotificationsExchange = "notifications.exchange"
 
 	NotificationsQueue = "notifications.queue.process"
+	PriorityQueue      = "notifications.queue.priority"
 	WaitQueue          = "wait.queue.delay"
 	RetryQueue         = "retry.queue.delay"
 
 	Direct = "direct"
+
+	// MaxPriority is the x-max-priority of the priority process queues. Messages carry
+	// the level of their notification's priority, see model.Priority.Level.
+	MaxPriority = 3
+
+	// defaultPublishTimeout bounds how long Publish waits for a broker confirmation.
+	defaultPublishTimeout = 5 * time.Second
+	// returnsBufferSize is the capacity of the channel receiving unroutable messages.
+	returnsBufferSize = 16
+)
+
+var (
+	// ErrPublishNacked is returned when the broker negatively acknowledges a published message.
+	ErrPublishNacked = errors.New("rabbitmq: message was nacked by the broker")
+	// ErrPublishUnroutable is returned when a mandatory message could not be routed to any queue.
+	ErrPublishUnroutable = errors.New("rabbitmq: message was returned as unroutable")
+	// ErrPublishTimeout is returned when the broker does not confirm a message in time.
+	ErrPublishTimeout = errors.New("rabbitmq: timed out waiting for publisher confirm")
 )
 
 // RabbitMQQueue implements the NotificationQueue interface. It acts as a PUBLISHER.
 // It uses the low-level amqp091-go library directly for reliability.
+// The channel runs in confirm mode, so every publish waits for the broker to
+// acknowledge the message before it is reported as successful.
 type RabbitMQQueue struct {
-	conn   *amqp.Connection
-	ch     *amqp.Channel
-	logger zerolog.Logger
+	conn             *amqp.Connection
+	perChannelQueues bool
+	priorityQueues   bool
+	ch               *amqp.Channel
+	publisher        confirmPublisher // Publishes on ch.
+	returns          chan amqp.Return
+	publishTimeout   time.Duration
+	mu               sync.Mutex // Serializes publishes so that confirms and returns can be correlated.
+	logger           zerolog.Logger
+}
+
+// confirmPublisher publishes mandatory messages on a channel in confirm mode.
+type confirmPublisher interface {
+	publish(ctx context.Context, exchange, key string, msg amqp.Publishing) (confirmation, error)
+}
+
+// confirmation is the pending broker confirmation of a published message.
+type confirmation interface {
+	WaitContext(ctx context.Context) (bool, error)
+}
+
+// channelPublisher publishes on an amqp channel.
+type channelPublisher struct {
+	ch *amqp.Channel
+}
+
+func (p channelPublisher) publish(ctx context.Context, exchange, key string, msg amqp.Publishing) (confirmation, error) {
+	return p.ch.PublishWithDeferredConfirmWithContext(ctx, exchange, key, true, false, msg)
 }
 
 // NewRabbitMQQueue creates a new instance of the RabbitMQQueue publisher.
 // It receives a shared amqp.Connection to create its own channel.
-func NewRabbitMQQueue(conn *amqp.Connection, logger *zerolog.Logger) (*RabbitMQQueue, error) {
+func NewRabbitMQQueue(cfg *config.Config, conn *amqp.Connection, logger *zerolog.Logger) (*RabbitMQQueue, error) {
 	channel, err := conn.Channel()
 	if err != nil {
 		// Log the error before returning
@@ -46,10 +97,25 @@ func NewRabbitMQQueue(conn *amqp.Connection, logger *zerolog.Logger) (*RabbitMQQ
 		return nil, fmt.Errorf("storage: rabbitMQ: New: Failed to open a channel: %w", err)
 	}
 
+	if err = channel.Confirm(false); err != nil {
+		logger.Error().Err(err).Msg("storage: rabbitMQ: New: Failed to enable publisher confirms")
+		return nil, fmt.Errorf("storage: rabbitMQ: New: Failed to enable publisher confirms: %w", err)
+	}
+
+	publishTimeout := cfg.RabbitMQ.PublishTimeout
+	if publishTimeout <= 0 {
+		publishTimeout = defaultPublishTimeout
+	}
+
 	queue := &RabbitMQQueue{
-		conn:   conn,
-		ch:     channel,
-		logger: logger.With().Str("component", "rabbitmq_publisher").Logger(),
+		conn:             conn,
+		perChannelQueues: cfg.Worker.PerChannelQueues,
+		priorityQueues:   cfg.Worker.PriorityQueues,
+		ch:               channel,
+		publisher:        channelPublisher{ch: channel},
+		returns:          channel.NotifyReturn(make(chan amqp.Return, returnsBufferSize)),
+		publishTimeout:   publishTimeout,
+		logger:           logger.With().Str("component", "rabbitmq_publisher").Logger(),
 	}
 
 	if err = queue.setupTopology(); err != nil {
@@ -81,8 +147,8 @@ func (q *RabbitMQQueue) setupTopology() error {
 	}
 
 	// Declare Queues
-	if _, err := q.ch.QueueDeclare(NotificationsQueue, true, false, false, false, nil); err != nil {
-		return fmt.Errorf("failed to declare queue %s: %w", NotificationsQueue, err)
+	if err := q.declareProcessQueue(SharedQueueName(q.priorityQueues)); err != nil {
+		return err
 	}
 	waitQueueArgs := amqp.Table{"x-dead-letter-exchange": NotificationsExchange}
 	if _, err := q.ch.QueueDeclare(WaitQueue, true, false, false, false, waitQueueArgs); err != nil {
@@ -92,22 +158,225 @@ func (q *RabbitMQQueue) setupTopology() error {
 	if _, err := q.ch.QueueDeclare(RetryQueue, true, false, false, false, retryQueueArgs); err != nil {
 		return fmt.Errorf("failed to declare queue %s: %w", RetryQueue, err)
 	}
+	if q.perChannelQueues {
+		for _, channel := range model.AllChannels {
+			if err := q.declareProcessQueue(ProcessQueueName(channel, q.priorityQueues)); err != nil {
+				return err
+			}
+		}
+	}
 
 	// Bind Queues
-	if err := q.ch.QueueBind(NotificationsQueue, "", NotificationsExchange, false, nil); err != nil {
-		return fmt.Errorf("failed to bind queue %s to exchange %s: %w", NotificationsQueue, NotificationsExchange, err)
+	bindings, stale := topologyBindings(q.perChannelQueues, q.priorityQueues)
+	for _, b := range bindings {
+		if err := q.ch.QueueBind(b.queue, b.key, b.exchange, false, nil); err != nil {
+			return fmt.Errorf("failed to bind queue %s to exchange %s: %w", b.queue, b.exchange, err)
+		}
 	}
-	if err := q.ch.QueueBind(WaitQueue, "", WaitExchange, false, nil); err != nil {
-		return fmt.Errorf("failed to bind queue %s to exchange %s: %w", WaitQueue, WaitExchange, err)
+	for _, b := range stale {
+		if err := q.unbind(b); err != nil {
+			return err
+		}
 	}
-	if err := q.ch.QueueBind(RetryQueue, "", RetryExchange, false, nil); err != nil {
-		return fmt.Errorf("failed to bind queue %s to exchange %s: %w", RetryQueue, RetryExchange, err)
+	for _, name := range inactiveProcessQueues(q.perChannelQueues, q.priorityQueues) {
+		if err := q.drainQueue(name); err != nil {
+			return err
+		}
+	}
+
+	if err := q.setupDeferTopology(); err != nil {
+		return err
+	}
+	if err := q.setupCallbackTopology(); err != nil {
+		return err
+	}
+	if err := q.setupDeadLetterTopology(); err != nil {
+		return err
 	}
 
 	q.logger.Info().Msg("rabbitmq topology setup successful")
 	return nil
 }
 
+// binding binds a queue to an exchange with a routing key.
+type binding struct {
+	queue    string
+	key      string
+	exchange string
+}
+
+// topologyBindings returns the bindings of the notification queues, and the stale bindings
+// of the other process queue layouts, which are removed so that a deployment can switch
+// per-channel or priority queues without routing every message to two process queues.
+// Messages are published with the channel as routing key. Dead-lettered messages keep it,
+// so the delay queues must accept every channel key, and the process exchange uses it
+// to route to per-channel queues. The empty key is kept for messages published before
+// channel routing keys were introduced.
+func topologyBindings(perChannelQueues, priorityQueues bool) (bindings, stale []binding) {
+	bindings = processBindings(perChannelQueues, priorityQueues)
+	for _, perChannel := range []bool{false, true} {
+		for _, priority := range []bool{false, true} {
+			for _, b := range processBindings(perChannel, priority) {
+				if !slices.Contains(bindings, b) && !slices.Contains(stale, b) {
+					stale = append(stale, b)
+				}
+			}
+		}
+	}
+	bindings = append(bindings,
+		binding{WaitQueue, "", WaitExchange},
+		binding{RetryQueue, "", RetryExchange},
+	)
+	for _, channel := range model.AllChannels {
+		key := string(channel)
+		bindings = append(bindings,
+			binding{WaitQueue, key, WaitExchange},
+			binding{RetryQueue, key, RetryExchange},
+		)
+	}
+	return bindings, stale
+}
+
+// processBindings returns the bindings of the process queues of a layout.
+func processBindings(perChannelQueues, priorityQueues bool) []binding {
+	shared := SharedQueueName(priorityQueues)
+	bindings := []binding{{shared, "", NotificationsExchange}}
+	for _, channel := range model.AllChannels {
+		queue := shared
+		if perChannelQueues {
+			queue = ProcessQueueName(channel, priorityQueues)
+		}
+		bindings = append(bindings, binding{queue, string(channel), NotificationsExchange})
+	}
+	return bindings
+}
+
+// inactiveProcessQueues returns the process queues that the other layouts use, but this
+// one does not. In per-channel mode the shared queue stays active, as it receives
+// messages published with the empty key.
+func inactiveProcessQueues(perChannelQueues, priorityQueues bool) []string {
+	var names []string
+	for _, priority := range []bool{false, true} {
+		if priority != priorityQueues {
+			names = append(names, SharedQueueName(priority))
+		}
+		if priority != priorityQueues || !perChannelQueues {
+			for _, channel := range model.AllChannels {
+				names = append(names, ProcessQueueName(channel, priority))
+			}
+		}
+	}
+	return names
+}
+
+// unbind removes a binding if it exists. It uses a channel of its own, since the broker
+// closes the channel when the queue does not exist, which is the case for the per-channel
+// queues of deployments that never enabled them.
+func (q *RabbitMQQueue) unbind(b binding) error {
+	ch, err := q.conn.Channel()
+	if err != nil {
+		return fmt.Errorf("failed to open channel to unbind queue %s: %w", b.queue, err)
+	}
+	err = ch.QueueUnbind(b.queue, b.key, b.exchange, nil)
+	var amqpErr *amqp.Error
+	if errors.As(err, &amqpErr) && amqpErr.Code == amqp.NotFound {
+		return nil
+	}
+	_ = ch.Close()
+	if err != nil {
+		return fmt.Errorf("failed to unbind queue %s from exchange %s: %w", b.queue, b.exchange, err)
+	}
+	return nil
+}
+
+// declareProcessQueue declares a process queue. Priority queues are declared with
+// x-max-priority, so that messages of a higher priority overtake a backlog of lower ones.
+// The arguments of an existing queue cannot change, which is why priority queues have
+// names of their own, see SharedQueueName.
+func (q *RabbitMQQueue) declareProcessQueue(name string) error {
+	var args amqp.Table
+	if q.priorityQueues {
+		args = amqp.Table{"x-max-priority": MaxPriority}
+	}
+	if _, err := q.ch.QueueDeclare(name, true, false, false, false, args); err != nil {
+		return fmt.Errorf("failed to declare queue %s: %w", name, err)
+	}
+	return nil
+}
+
+// drainQueue moves the messages of an inactive process queue back into the process
+// exchange, which routes them to the queues in use, and deletes the queue. Its bindings
+// were removed before, so no new messages arrive. While workers of an older deployment
+// still consume from it, the queue is kept and drained again on the next start.
+func (q *RabbitMQQueue) drainQueue(name string) error {
+	ch, err := q.conn.Channel()
+	if err != nil {
+		return fmt.Errorf("failed to open channel to drain queue %s: %w", name, err)
+	}
+	// The broker closes the channel when the queue does not exist or cannot be deleted.
+	defer func() { _ = ch.Close() }()
+
+	_, err = ch.QueueDeclarePassive(name, true, false, false, false, nil)
+	var amqpErr *amqp.Error
+	if errors.As(err, &amqpErr) && amqpErr.Code == amqp.NotFound {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to inspect queue %s: %w", name, err)
+	}
+
+	moved := 0
+	for {
+		msg, ok, err := ch.Get(name, false)
+		if err != nil {
+			return fmt.Errorf("failed to get message from queue %s: %w", name, err)
+		}
+		if !ok {
+			break
+		}
+		republished := amqp.Publishing{
+			Headers:      msg.Headers,
+			ContentType:  msg.ContentType,
+			DeliveryMode: msg.DeliveryMode,
+			Priority:     msg.Priority,
+			MessageId:    msg.MessageId,
+			Body:         msg.Body,
+		}
+		if err := q.publishConfirmed(context.Background(), NotificationsExchange, msg.RoutingKey, republished); err != nil {
+			_ = msg.Nack(false, true)
+			return fmt.Errorf("failed to move message from queue %s: %w", name, err)
+		}
+		if err := msg.Ack(false); err != nil {
+			return fmt.Errorf("failed to acknowledge message moved from queue %s: %w", name, err)
+		}
+		moved++
+	}
+
+	log := q.logger.With().Str("queue", name).Int("moved", moved).Logger()
+	if _, err := ch.QueueDelete(name, true, true, false); err != nil {
+		log.Warn().Err(err).Msg("inactive process queue is still in use, keeping it")
+		return nil
+	}
+	log.Info().Msg("drained and deleted inactive process queue")
+	return nil
+}
+
+// SharedQueueName returns the name of the shared process queue. Priority queues have
+// names of their own, so that enabling them does not conflict with the arguments of the
+// existing queues, which are drained into the new ones on startup.
+func SharedQueueName(priorityQueues bool) string {
+	if priorityQueues {
+		return PriorityQueue
+	}
+	return NotificationsQueue
+}
+
+// ProcessQueueName returns the name of the dedicated process queue for a channel,
+// used when per-channel queues are enabled.
+func ProcessQueueName(channel model.Channel, priorityQueues bool) string {
+	return SharedQueueName(priorityQueues) + "." + string(channel)
+}
+
 // Publish schedules a notification for delayed processing.
 func (q *RabbitMQQueue) Publish(ctx context.Context, n *model.Notification) error {
 	body, err := json.Marshal(n)
@@ -124,10 +393,15 @@ func (q *RabbitMQQueue) Publish(ctx context.Context, n *model.Notification) erro
 		ContentType:  "application/json",
 		Body:         body,
 		DeliveryMode: amqp.Persistent,
+		Priority:     uint8(n.Priority.Level()),
 		Expiration:   strconv.FormatInt(delay.Milliseconds(), 10),
 	}
 
-	return q.ch.PublishWithContext(ctx, WaitExchange, "", false, false, msg)
+	if err := q.publishConfirmed(ctx, WaitExchange, string(n.Channel), msg); err != nil {
+		q.logger.Error().Err(err).Stringer("id", n.ID).Msg("failed to publish notification")
+		return err
+	}
+	return nil
 }
 
 // PublishRetry schedules a notification for a retry attempt.
@@ -142,10 +416,98 @@ func (q *RabbitMQQueue) PublishRetry(ctx context.Context, n *model.Notification,
 		ContentType:  "application/json",
 		Body:         body,
 		DeliveryMode: amqp.Persistent,
+		Priority:     uint8(n.Priority.Level()),
 		Expiration:   fmt.Sprintf("%d", retryDelay.Milliseconds()),
 	}
 
-	return q.ch.PublishWithContext(ctx, RetryExchange, "", false, false, msg)
+	if err := q.publishConfirmed(ctx, RetryExchange, string(n.Channel), msg); err != nil {
+		q.logger.Error().Err(err).Stringer("id", n.ID).Msg("failed to publish notification for retry")
+		return err
+	}
+	return nil
+}
+
+// PublishNow sends a notification straight to the process exchange, skipping the wait queue.
+func (q *RabbitMQQueue) PublishNow(ctx context.Context, n *model.Notification) error {
+	body, err := json.Marshal(n)
+	if err != nil {
+		q.logger.Error().Err(err).Stringer("id", n.ID).Msg("failed to marshal notification")
+		return fmt.Errorf("failed to marshal notification: %w", err)
+	}
+
+	msg := amqp.Publishing{
+		ContentType:  "application/json",
+		Body:         body,
+		DeliveryMode: amqp.Persistent,
+		Priority:     uint8(n.Priority.Level()),
+	}
+
+	if err := q.publishConfirmed(ctx, NotificationsExchange, string(n.Channel), msg); err != nil {
+		q.logger.Error().Err(err).Stringer("id", n.ID).Msg("failed to publish notification for immediate processing")
+		return err
+	}
+	return nil
+}
+
+// publishConfirmed publishes a mandatory message and blocks until the broker confirms it.
+// It returns an error if the message is nacked, returned as unroutable, or not confirmed
+// within the configured publish timeout.
+func (q *RabbitMQQueue) publishConfirmed(ctx context.Context, exchange, key string, msg amqp.Publishing) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if msg.MessageId == "" {
+		msg.MessageId = uuid.NewString()
+	}
+
+	confirmation, err := q.publisher.publish(ctx, exchange, key, msg)
+	if err != nil {
+		return fmt.Errorf("rabbitmq: failed to publish to exchange %s: %w", exchange, err)
+	}
+
+	waitCtx, cancel := context.WithTimeout(ctx, q.publishTimeout)
+	defer cancel()
+
+	acked, err := confirmation.WaitContext(waitCtx)
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) && ctx.Err() == nil {
+			return ErrPublishTimeout
+		}
+		return fmt.Errorf("rabbitmq: waiting for publisher confirm: %w", err)
+	}
+	if !acked {
+		return ErrPublishNacked
+	}
+
+	// The broker sends basic.return before basic.ack for an unroutable mandatory
+	// message, so any return for this message is already buffered at this point.
+	if q.drainReturns(msg.MessageId) {
+		return ErrPublishUnroutable
+	}
+	return nil
+}
+
+// drainReturns empties the returns buffer and reports whether it contained
+// the message with the given ID. Returns for other messages are stale leftovers
+// from publishes that timed out and are only logged.
+func (q *RabbitMQQueue) drainReturns(messageID string) bool {
+	found := false
+	for {
+		select {
+		case ret := <-q.returns:
+			if ret.MessageId == messageID {
+				found = true
+				continue
+			}
+			q.logger.Warn().
+				Str("message_id", ret.MessageId).
+				Str("exchange", ret.Exchange).
+				Str("reason", ret.ReplyText).
+				Msg("discarding stale returned message")
+		default:
+			return found
+		}
+	}
 }
 
 // Close gracefully shuts down the channel. The connection is managed by Fx.
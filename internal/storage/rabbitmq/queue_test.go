@@ -0,0 +1,131 @@
+package rabbitmq
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestTopologyBindings(t *testing.T) {
+	layout := func(queue, email, telegram string) []binding {
+		return []binding{
+			{queue, "", NotificationsExchange},
+			{email, "email", NotificationsExchange},
+			{telegram, "telegram", NotificationsExchange},
+		}
+	}
+	shared := layout(NotificationsQueue, NotificationsQueue, NotificationsQueue)
+	perChannel := layout(NotificationsQueue, NotificationsQueue+".email", NotificationsQueue+".telegram")
+	priorityShared := layout(PriorityQueue, PriorityQueue, PriorityQueue)
+	priorityPerChannel := layout(PriorityQueue, PriorityQueue+".email", PriorityQueue+".telegram")
+	layouts := [][]binding{shared, perChannel, priorityShared, priorityPerChannel}
+
+	tests := []struct {
+		perChannelQueues, priorityQueues bool
+		bound                            []binding
+	}{
+		{perChannelQueues: false, priorityQueues: false, bound: shared},
+		{perChannelQueues: true, priorityQueues: false, bound: perChannel},
+		{perChannelQueues: false, priorityQueues: true, bound: priorityShared},
+		{perChannelQueues: true, priorityQueues: true, bound: priorityPerChannel},
+	}
+	for _, tt := range tests {
+		name := fmt.Sprintf("per-channel %v, priority %v", tt.perChannelQueues, tt.priorityQueues)
+		bindings, stale := topologyBindings(tt.perChannelQueues, tt.priorityQueues)
+		for _, b := range tt.bound {
+			if !slices.Contains(bindings, b) {
+				t.Errorf("%s: %+v is not bound", name, b)
+			}
+		}
+		// Every process binding of the other layouts must be removed.
+		for _, other := range layouts {
+			for _, b := range other {
+				if !slices.Contains(tt.bound, b) && !slices.Contains(stale, b) {
+					t.Errorf("%s: stale %+v is not unbound", name, b)
+				}
+			}
+		}
+		for _, b := range stale {
+			if slices.Contains(bindings, b) {
+				t.Errorf("%s: %+v is both bound and unbound", name, b)
+			}
+		}
+
+		// Every channel key must reach exactly one process queue.
+		for _, key := range []string{"", "email", "telegram"} {
+			routed := 0
+			for _, b := range bindings {
+				if b.exchange == NotificationsExchange && b.key == key {
+					routed++
+				}
+			}
+			if routed != 1 {
+				t.Errorf("%s: key %q is routed to %d process queues, want 1", name, key, routed)
+			}
+		}
+	}
+}
+
+func TestInactiveProcessQueues(t *testing.T) {
+	tests := []struct {
+		perChannelQueues, priorityQueues bool
+		want                             []string
+	}{
+		{
+			perChannelQueues: false, priorityQueues: false,
+			want: []string{
+				NotificationsQueue + ".email", NotificationsQueue + ".telegram",
+				PriorityQueue, PriorityQueue + ".email", PriorityQueue + ".telegram",
+			},
+		},
+		{
+			perChannelQueues: true, priorityQueues: false,
+			want: []string{PriorityQueue, PriorityQueue + ".email", PriorityQueue + ".telegram"},
+		},
+		{
+			perChannelQueues: false, priorityQueues: true,
+			want: []string{
+				NotificationsQueue, NotificationsQueue + ".email", NotificationsQueue + ".telegram",
+				PriorityQueue + ".email", PriorityQueue + ".telegram",
+			},
+		},
+		{
+			perChannelQueues: true, priorityQueues: true,
+			want: []string{NotificationsQueue, NotificationsQueue + ".email", NotificationsQueue + ".telegram"},
+		},
+	}
+	for _, tt := range tests {
+		got := inactiveProcessQueues(tt.perChannelQueues, tt.priorityQueues)
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("per-channel %v, priority %v: got %v, want %v", tt.perChannelQueues, tt.priorityQueues, got, tt.want)
+		}
+		// Queues still bound must never be drained and deleted.
+		bindings, _ := topologyBindings(tt.perChannelQueues, tt.priorityQueues)
+		for _, b := range bindings {
+			if slices.Contains(got, b.queue) {
+				t.Errorf("per-channel %v, priority %v: bound queue %s is inactive", tt.perChannelQueues, tt.priorityQueues, b.queue)
+			}
+		}
+	}
+}
+
+func TestTierFor(t *testing.T) {
+	tests := []struct {
+		delay time.Duration
+		want  string
+	}{
+		{0, "1s"},
+		{500 * time.Millisecond, "1s"},
+		{9 * time.Second, "1s"},
+		{10 * time.Second, "10s"},
+		{5 * time.Minute, "1m"},
+		{59 * time.Minute, "10m"},
+		{10 * time.Hour, "1h"},
+	}
+	for _, tt := range tests {
+		if got := tierFor(tt.delay); got.name != tt.want {
+			t.Errorf("%s: got tier %s, want %s", tt.delay, got.name, tt.want)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/ilindan-dev/delayed-notifier/internal/domain/model"
+	repo "github.com/ilindan-dev/delayed-notifier/internal/domain/repository"
+	"github.com/ilindan-dev/delayed-notifier/pkg/keybuilder"
+	goredis "github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog"
+	"time"
+)
+
+// bulkJobTTL is how long a job can be looked up after its last progress update.
+const bulkJobTTL = 7 * 24 * time.Hour
+
+// Ensure BulkJobStore implements the interface
+var _ repo.BulkJobStore = (*BulkJobStore)(nil)
+
+// BulkJobStore implements the domain.BulkJobStore interface
+// by storing every job as an expiring JSON value.
+type BulkJobStore struct {
+	redis  *goredis.Client
+	logger zerolog.Logger
+}
+
+// NewBulkJobStore creates a new instance of the BulkJobStore.
+func NewBulkJobStore(logger *zerolog.Logger, redis *goredis.Client) *BulkJobStore {
+	return &BulkJobStore{
+		redis:  redis,
+		logger: logger.With().Str("layer", "redis_bulk_jobs").Logger(),
+	}
+}
+
+// Save stores a job, replacing any previous state of it, and renews its expiry.
+func (s *BulkJobStore) Save(ctx context.Context, job *model.BulkJob) error {
+	key := keybuilder.RedisBulkJobKeyBuild(job.TenantID, job.ID)
+	data, err := json.Marshal(job)
+	if err != nil {
+		return fmt.Errorf("failed to marshal bulk job: %w", err)
+	}
+
+	if err := s.redis.Set(ctx, key, data, bulkJobTTL).Err(); err != nil {
+		s.logger.Error().Err(err).Str("key", key).Msg("failed to save bulk job")
+		return fmt.Errorf("redis: bulk job save failed: %w", err)
+	}
+	return nil
+}
+
+// Get returns a job of a tenant, or repo.ErrNotFound if there is no such job or it has expired.
+func (s *BulkJobStore) Get(ctx context.Context, tenantID string, id uuid.UUID) (*model.BulkJob, error) {
+	key := keybuilder.RedisBulkJobKeyBuild(tenantID, id)
+	data, err := s.redis.Get(ctx, key).Bytes()
+	if err != nil {
+		if errors.Is(err, goredis.Nil) {
+			return nil, repo.ErrNotFound
+		}
+		s.logger.Error().Err(err).Str("key", key).Msg("failed to get bulk job")
+		return nil, fmt.Errorf("redis: bulk job get failed: %w", err)
+	}
+
+	var job model.BulkJob
+	if err := json.Unmarshal(data, &job); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal bulk job: %w", err)
+	}
+	return &job, nil
+}
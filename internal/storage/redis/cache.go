@@ -32,9 +32,9 @@ func NewNotificationCache(logger *zerolog.Logger, redis *goredis.Client) *Notifi
 	}
 }
 
-// Get retrieves an item from the cache.
-func (c *NotificationCache) Get(ctx context.Context, id uuid.UUID) (*model.Notification, error) {
-	key := keybuilder.RedisNotificationKeyBuild(id)
+// Get retrieves an item of a tenant from the cache.
+func (c *NotificationCache) Get(ctx context.Context, tenantID string, id uuid.UUID) (*model.Notification, error) {
+	key := keybuilder.RedisNotificationKeyBuild(tenantID, id)
 	val, err := c.redis.Get(ctx, key).Result()
 	if err != nil {
 		if errors.Is(err, goredis.Nil) {
@@ -50,6 +50,11 @@ func (c *NotificationCache) Get(ctx context.Context, id uuid.UUID) (*model.Notif
 		c.logger.Error().Err(err).Str("key", key).Msg("failed to unmarshal notification from cache")
 		return nil, fmt.Errorf("failed to unmarshal cached data: %w", err)
 	}
+	if notification.Version == 0 {
+		// Cached before notifications had versions, so updates based on it would fail.
+		c.logger.Info().Str("key", key).Str("cache", "miss").Msg("notification cached without version")
+		return nil, repo.ErrNotFound
+	}
 
 	c.logger.Info().Str("key", key).Str("cache", "hit").Msg("notification found in cache")
 	return &notification, nil
@@ -57,7 +62,7 @@ func (c *NotificationCache) Get(ctx context.Context, id uuid.UUID) (*model.Notif
 
 // Set adds an item to the cache for a specified duration.
 func (c *NotificationCache) Set(ctx context.Context, n *model.Notification, expiration time.Duration) error {
-	key := keybuilder.RedisNotificationKeyBuild(n.ID)
+	key := keybuilder.RedisNotificationKeyBuild(n.TenantID, n.ID)
 	nBytes, err := json.Marshal(n)
 	if err != nil {
 		c.logger.Error().Err(err).Stringer("id", n.ID).Msg("failed to marshal notification for cache")
@@ -73,9 +78,9 @@ func (c *NotificationCache) Set(ctx context.Context, n *model.Notification, expi
 	return nil
 }
 
-// Delete removes an item from the cache.
-func (c *NotificationCache) Delete(ctx context.Context, id uuid.UUID) error {
-	key := keybuilder.RedisNotificationKeyBuild(id)
+// Delete removes an item of a tenant from the cache.
+func (c *NotificationCache) Delete(ctx context.Context, tenantID string, id uuid.UUID) error {
+	key := keybuilder.RedisNotificationKeyBuild(tenantID, id)
 	if err := c.redis.Del(ctx, key).Err(); err != nil {
 		c.logger.Error().Err(err).Str("key", key).Msg("failed to delete key from redis")
 		return err
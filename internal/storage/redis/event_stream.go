@@ -0,0 +1,290 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/ilindan-dev/delayed-notifier/internal/config"
+	"github.com/ilindan-dev/delayed-notifier/internal/domain/model"
+	repo "github.com/ilindan-dev/delayed-notifier/internal/domain/repository"
+	"github.com/ilindan-dev/delayed-notifier/pkg/keybuilder"
+	goredis "github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog"
+	"go.uber.org/fx"
+	"regexp"
+	"sync"
+	"time"
+)
+
+const (
+	// defaultStreamMaxLen is the default approximate number of events kept per tenant.
+	defaultStreamMaxLen = 10000
+	// eventField is the stream entry field holding the JSON-encoded event.
+	eventField = "event"
+	// streamStart is the ID preceding every stream entry.
+	streamStart = "0-0"
+	// defaultWatchConnections is the default number of connections for watching tenant streams.
+	defaultWatchConnections = 16
+	// watchBlock bounds a single blocking read of a tenant watch, so that it notices
+	// when its readers are gone.
+	watchBlock = 5 * time.Second
+	// watchIdleTimeout is how long a tenant watch outlives its last reader, so that
+	// readers polling in a loop do not restart it every time.
+	watchIdleTimeout = time.Minute
+	// watchRetryDelay is how long a tenant watch waits after a failed read.
+	watchRetryDelay = time.Second
+)
+
+// streamIDPattern matches Redis stream entry IDs, e.g. "1700000000000-0".
+var streamIDPattern = regexp.MustCompile(`^\d+-\d+$`)
+
+// Ensure StatusEventStream implements the interface
+var _ repo.StatusEventStream = (*StatusEventStream)(nil)
+
+// StatusEventStream implements the domain.StatusEventStream interface using one
+// capped Redis stream per tenant. Stream entry IDs serve as event IDs, so readers
+// can resume after the last event they saw.
+// Readers waiting for new events do not block connections of the shared client:
+// a single blocking read per tenant, on a client of its own, wakes them up.
+type StatusEventStream struct {
+	redis  *goredis.Client
+	maxLen int64
+	logger zerolog.Logger
+
+	watchClient *goredis.Client
+	watchCtx    context.Context
+	stopWatches context.CancelFunc
+	mu          sync.Mutex
+	watches     map[string]*tenantWatch
+}
+
+// tenantWatch wakes the readers waiting for new events of a tenant.
+type tenantWatch struct {
+	changed  chan struct{} // Closed and replaced when new events arrive.
+	readers  int
+	lastRead time.Time
+}
+
+// NewStatusEventStream creates a new instance of the StatusEventStream.
+func NewStatusEventStream(lc fx.Lifecycle, cfg *config.Config, logger *zerolog.Logger, redis *goredis.Client) *StatusEventStream {
+	maxLen := cfg.Events.StreamMaxLen
+	if maxLen <= 0 {
+		maxLen = defaultStreamMaxLen
+	}
+	watchConnections := cfg.Events.WatchConnections
+	if watchConnections <= 0 {
+		watchConnections = defaultWatchConnections
+	}
+
+	opts := *redis.Options()
+	opts.PoolSize = watchConnections
+	opts.MinIdleConns = 0
+	watchCtx, stopWatches := context.WithCancel(context.Background())
+	s := &StatusEventStream{
+		redis:       redis,
+		maxLen:      maxLen,
+		logger:      logger.With().Str("layer", "redis_event_stream").Logger(),
+		watchClient: goredis.NewClient(&opts),
+		watchCtx:    watchCtx,
+		stopWatches: stopWatches,
+		watches:     make(map[string]*tenantWatch),
+	}
+
+	lc.Append(fx.Hook{
+		OnStop: func(context.Context) error {
+			s.stopWatches()
+			return s.watchClient.Close()
+		},
+	})
+	return s
+}
+
+// Publish appends an event to its tenant's stream and sets the event's ID.
+func (s *StatusEventStream) Publish(ctx context.Context, e *model.StatusEvent) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("failed to marshal status event: %w", err)
+	}
+
+	key := keybuilder.RedisEventsKeyBuild(e.TenantID)
+	id, err := s.redis.XAdd(ctx, &goredis.XAddArgs{
+		Stream: key,
+		MaxLen: s.maxLen,
+		Approx: true,
+		Values: map[string]any{eventField: data},
+	}).Result()
+	if err != nil {
+		s.logger.Error().Err(err).Str("key", key).Msg("failed to publish status event")
+		return fmt.Errorf("redis: status event publish failed: %w", err)
+	}
+
+	e.ID = id
+	return nil
+}
+
+// LatestID returns the ID of the newest event of the tenant, or the stream start if there is none.
+func (s *StatusEventStream) LatestID(ctx context.Context, tenantID string) (string, error) {
+	key := keybuilder.RedisEventsKeyBuild(tenantID)
+	entries, err := s.redis.XRevRangeN(ctx, key, "+", "-", 1).Result()
+	if err != nil {
+		s.logger.Error().Err(err).Str("key", key).Msg("failed to get latest status event")
+		return "", fmt.Errorf("redis: latest status event lookup failed: %w", err)
+	}
+	if len(entries) == 0 {
+		return streamStart, nil
+	}
+	return entries[0].ID, nil
+}
+
+// ReadAfter returns the tenant's events following afterID, waiting up to block for new ones.
+// A non-positive block returns immediately. Events trimmed from the stream are skipped silently.
+func (s *StatusEventStream) ReadAfter(ctx context.Context, tenantID, afterID string, block time.Duration) ([]*model.StatusEvent, error) {
+	if !streamIDPattern.MatchString(afterID) {
+		return nil, repo.ErrInvalidEventID
+	}
+	if block <= 0 {
+		return s.read(ctx, tenantID, afterID)
+	}
+
+	// Events published after the watch started wake it up, and earlier ones are read
+	// right away, so that none is missed between the two.
+	changed, done, err := s.watch(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	defer done()
+
+	events, err := s.read(ctx, tenantID, afterID)
+	if err != nil || len(events) > 0 {
+		return events, err
+	}
+
+	timer := time.NewTimer(block)
+	defer timer.Stop()
+	select {
+	case <-changed:
+		return s.read(ctx, tenantID, afterID)
+	case <-timer.C:
+		return nil, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// read returns the tenant's events following afterID without waiting for new ones.
+func (s *StatusEventStream) read(ctx context.Context, tenantID, afterID string) ([]*model.StatusEvent, error) {
+	key := keybuilder.RedisEventsKeyBuild(tenantID)
+	streams, err := s.redis.XRead(ctx, &goredis.XReadArgs{
+		Streams: []string{key, afterID},
+		Block:   -1, // Omits BLOCK; zero would block forever.
+	}).Result()
+	if err != nil {
+		if errors.Is(err, goredis.Nil) {
+			return nil, nil
+		}
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		s.logger.Error().Err(err).Str("key", key).Msg("failed to read status events")
+		return nil, fmt.Errorf("redis: status event read failed: %w", err)
+	}
+
+	var events []*model.StatusEvent
+	for _, stream := range streams {
+		for _, msg := range stream.Messages {
+			raw, _ := msg.Values[eventField].(string)
+			var event model.StatusEvent
+			if err := json.Unmarshal([]byte(raw), &event); err != nil {
+				s.logger.Warn().Err(err).Str("id", msg.ID).Msg("skipping malformed status event")
+				continue
+			}
+			event.ID = msg.ID
+			events = append(events, &event)
+		}
+	}
+	return events, nil
+}
+
+// watch registers a reader waiting for new events of a tenant, starting the tenant's
+// watch if it has none. It returns a channel closed when new events arrive, and a
+// function to call once the reader stops waiting.
+func (s *StatusEventStream) watch(ctx context.Context, tenantID string) (<-chan struct{}, func(), error) {
+	s.mu.Lock()
+	w, ok := s.watches[tenantID]
+	if !ok {
+		s.mu.Unlock()
+		latest, err := s.LatestID(ctx, tenantID)
+		if err != nil {
+			return nil, nil, err
+		}
+		s.mu.Lock()
+		if w, ok = s.watches[tenantID]; !ok {
+			w = &tenantWatch{changed: make(chan struct{})}
+			s.watches[tenantID] = w
+			go s.runWatch(tenantID, w, latest)
+		}
+	}
+	defer s.mu.Unlock()
+
+	w.readers++
+	done := func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		w.readers--
+		w.lastRead = time.Now()
+	}
+	return w.changed, done, nil
+}
+
+// runWatch blocks on the tenant's stream for events following lastID and wakes the
+// waiting readers whenever some arrive. It stops once the watch has been idle for
+// watchIdleTimeout. On errors the readers are woken up too, so that they poll.
+func (s *StatusEventStream) runWatch(tenantID string, w *tenantWatch, lastID string) {
+	key := keybuilder.RedisEventsKeyBuild(tenantID)
+	for {
+		s.mu.Lock()
+		if w.readers == 0 && time.Since(w.lastRead) > watchIdleTimeout {
+			delete(s.watches, tenantID)
+			s.mu.Unlock()
+			return
+		}
+		s.mu.Unlock()
+
+		streams, err := s.watchClient.XRead(s.watchCtx, &goredis.XReadArgs{
+			Streams: []string{key, lastID},
+			Block:   watchBlock,
+		}).Result()
+		switch {
+		case errors.Is(err, goredis.Nil):
+			continue
+		case err != nil:
+			if s.watchCtx.Err() != nil {
+				return
+			}
+			s.logger.Warn().Err(err).Str("key", key).Msg("failed to watch status events")
+			s.wake(w)
+			select {
+			case <-time.After(watchRetryDelay):
+			case <-s.watchCtx.Done():
+				return
+			}
+			continue
+		}
+
+		for _, stream := range streams {
+			if n := len(stream.Messages); n > 0 {
+				lastID = stream.Messages[n-1].ID
+			}
+		}
+		s.wake(w)
+	}
+}
+
+// wake wakes the readers waiting on a tenant watch.
+func (s *StatusEventStream) wake(w *tenantWatch) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	close(w.changed)
+	w.changed = make(chan struct{})
+}
@@ -0,0 +1,58 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	repo "github.com/ilindan-dev/delayed-notifier/internal/domain/repository"
+	"github.com/ilindan-dev/delayed-notifier/pkg/keybuilder"
+	goredis "github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog"
+	"time"
+)
+
+// quotaKeyTTL keeps a daily counter around a little longer than its day,
+// so late requests around midnight in other time zones still see it.
+const quotaKeyTTL = 48 * time.Hour
+
+// Ensure QuotaStore implements the interface
+var _ repo.QuotaStore = (*QuotaStore)(nil)
+
+// QuotaStore implements the domain.QuotaStore interface
+// using one expiring Redis counter per tenant and day.
+type QuotaStore struct {
+	redis  *goredis.Client
+	logger zerolog.Logger
+}
+
+// NewQuotaStore creates a new instance of the QuotaStore.
+func NewQuotaStore(logger *zerolog.Logger, redis *goredis.Client) *QuotaStore {
+	return &QuotaStore{
+		redis:  redis,
+		logger: logger.With().Str("layer", "redis_quota").Logger(),
+	}
+}
+
+// Increment adds one to the tenant's counter for the given day and returns the new count.
+func (q *QuotaStore) Increment(ctx context.Context, tenantID string, day time.Time) (int64, error) {
+	key := keybuilder.RedisQuotaKeyBuild(tenantID, day.UTC().Format(time.DateOnly))
+
+	pipe := q.redis.TxPipeline()
+	incr := pipe.Incr(ctx, key)
+	pipe.Expire(ctx, key, quotaKeyTTL)
+	if _, err := pipe.Exec(ctx); err != nil {
+		q.logger.Error().Err(err).Str("key", key).Msg("failed to increment quota counter")
+		return 0, fmt.Errorf("redis: quota increment failed: %w", err)
+	}
+
+	return incr.Val(), nil
+}
+
+// Decrement gives back one unit of the tenant's counter for the given day.
+func (q *QuotaStore) Decrement(ctx context.Context, tenantID string, day time.Time) error {
+	key := keybuilder.RedisQuotaKeyBuild(tenantID, day.UTC().Format(time.DateOnly))
+	if err := q.redis.Decr(ctx, key).Err(); err != nil {
+		q.logger.Error().Err(err).Str("key", key).Msg("failed to decrement quota counter")
+		return fmt.Errorf("redis: quota decrement failed: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	repo "github.com/ilindan-dev/delayed-notifier/internal/domain/repository"
+	goredis "github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog"
+	"time"
+)
+
+// Ensure RateLimiter implements the interface
+var _ repo.RateLimiter = (*RateLimiter)(nil)
+
+// tokenBucketScript atomically refills the buckets stored as hashes under KEYS, with the
+// rate and burst of each given in pairs in ARGV, and takes a token from every bucket if
+// none is empty. It uses the Redis server clock so that all worker replicas share the
+// same time source. It returns 0 if the tokens were taken, or the number of milliseconds
+// until the slowest empty bucket has a token again and that bucket's position in KEYS.
+var tokenBucketScript = goredis.NewScript(`
+local t = redis.call('TIME')
+local now = t[1] * 1000 + math.floor(t[2] / 1000)
+
+local tokens = {}
+local wait, limited = 0, 0
+for i, key in ipairs(KEYS) do
+	local rate = tonumber(ARGV[2 * i - 1])
+	local burst = tonumber(ARGV[2 * i])
+	local state = redis.call('HMGET', key, 'tokens', 'ts')
+	local ts = tonumber(state[2]) or now
+	tokens[i] = math.min(burst, (tonumber(state[1]) or burst) + math.max(0, now - ts) / 1000 * rate)
+	if tokens[i] < 1 then
+		local w = math.ceil((1 - tokens[i]) / rate * 1000)
+		if w > wait then
+			wait, limited = w, i
+		end
+	end
+end
+
+for i, key in ipairs(KEYS) do
+	local rate = tonumber(ARGV[2 * i - 1])
+	local burst = tonumber(ARGV[2 * i])
+	if wait == 0 then
+		tokens[i] = tokens[i] - 1
+	end
+	redis.call('HSET', key, 'tokens', tostring(tokens[i]), 'ts', now)
+	redis.call('PEXPIRE', key, math.ceil(burst / rate * 1000) + 1000)
+end
+return {wait, limited}
+`)
+
+// RateLimiter implements the domain.RateLimiter interface
+// using a token bucket stored in Redis.
+type RateLimiter struct {
+	redis  *goredis.Client
+	logger zerolog.Logger
+}
+
+// NewRateLimiter creates a new instance of the RateLimiter.
+func NewRateLimiter(logger *zerolog.Logger, redis *goredis.Client) *RateLimiter {
+	return &RateLimiter{
+		redis:  redis,
+		logger: logger.With().Str("layer", "redis_rate_limiter").Logger(),
+	}
+}
+
+// Take tries to take one token from each of the buckets at once.
+// Buckets without a positive rate are not limited.
+func (l *RateLimiter) Take(ctx context.Context, buckets []repo.RateLimitBucket) (time.Duration, string, error) {
+	keys := make([]string, 0, len(buckets))
+	args := make([]any, 0, 2*len(buckets))
+	for _, b := range buckets {
+		if b.Rate <= 0 {
+			continue
+		}
+		keys = append(keys, b.Key)
+		args = append(args, b.Rate, max(b.Burst, 1))
+	}
+	if len(keys) == 0 {
+		return 0, "", nil
+	}
+
+	result, err := tokenBucketScript.Run(ctx, l.redis, keys, args...).Int64Slice()
+	if err != nil {
+		l.logger.Error().Err(err).Strs("keys", keys).Msg("failed to run token bucket script")
+		return 0, "", fmt.Errorf("redis: rate limiter take failed: %w", err)
+	}
+
+	wait, limited := time.Duration(result[0])*time.Millisecond, result[1]
+	if wait <= 0 {
+		return 0, "", nil
+	}
+	return wait, keys[limited-1], nil
+}
@@ -55,8 +55,8 @@ func (r *CachedNotificationRepository) Save(ctx context.Context, n *model.Notifi
 // GetByID implements the cache-aside pattern.
 // It first tries to fetch the data from the cache. If it's a miss,
 // it fetches from the primary repository, caches the result, and then returns it.
-func (r *CachedNotificationRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.Notification, error) {
-	cached, err := r.cache.Get(ctx, id)
+func (r *CachedNotificationRepository) GetByID(ctx context.Context, tenantID string, id uuid.UUID) (*model.Notification, error) {
+	cached, err := r.cache.Get(ctx, tenantID, id)
 	if err == nil {
 		r.logger.Info().Stringer("id", id).Msg("cache hit")
 		return cached, nil
@@ -68,7 +68,7 @@ func (r *CachedNotificationRepository) GetByID(ctx context.Context, id uuid.UUID
 		r.logger.Info().Stringer("id", id).Msg("cache miss")
 	}
 
-	primary, err := r.primaryRepo.GetByID(ctx, id)
+	primary, err := r.primaryRepo.GetByID(ctx, tenantID, id)
 	if err != nil {
 		return nil, err
 	}
@@ -80,14 +80,20 @@ func (r *CachedNotificationRepository) GetByID(ctx context.Context, id uuid.UUID
 	return primary, nil
 }
 
-// Update first updates the data in the primary repository,
-// then invalidates the corresponding cache entry.
-func (r *CachedNotificationRepository) Update(ctx context.Context, n *model.Notification) error {
-	if err := r.primaryRepo.Update(ctx, n); err != nil {
+// Transition first updates the data in the primary repository,
+// then invalidates the corresponding cache entry. The entry is also invalidated
+// on ErrConflict, as the version the update was based on may have come from it.
+func (r *CachedNotificationRepository) Transition(ctx context.Context, n *model.Notification, from model.Status) error {
+	if err := r.primaryRepo.Transition(ctx, n, from); err != nil {
+		if errors.Is(err, repo.ErrConflict) {
+			if err := r.cache.Delete(ctx, n.TenantID, n.ID); err != nil {
+				r.logger.Error().Err(err).Stringer("id", n.ID).Msg("failed to invalidate cache after conflict")
+			}
+		}
 		return err
 	}
 
-	if err := r.cache.Delete(ctx, n.ID); err != nil {
+	if err := r.cache.Delete(ctx, n.TenantID, n.ID); err != nil {
 		r.logger.Error().Err(err).Stringer("id", n.ID).Msg("failed to invalidate cache after update")
 	}
 
@@ -96,14 +102,73 @@ func (r *CachedNotificationRepository) Update(ctx context.Context, n *model.Noti
 
 // Delete first deletes the data from the primary repository,
 // then invalidates the cache.
-func (r *CachedNotificationRepository) Delete(ctx context.Context, id uuid.UUID) error {
-	if err := r.primaryRepo.Delete(ctx, id); err != nil {
-		return err
+func (r *CachedNotificationRepository) Delete(ctx context.Context, tenantID string, id uuid.UUID, version *int) (*model.Notification, error) {
+	n, err := r.primaryRepo.Delete(ctx, tenantID, id, version)
+	if err != nil {
+		return nil, err
 	}
 
-	if err := r.cache.Delete(ctx, id); err != nil {
+	if err := r.cache.Delete(ctx, tenantID, id); err != nil {
 		r.logger.Error().Err(err).Stringer("id", id).Msg("failed to invalidate cache after delete")
 	}
 
-	return nil
+	return n, nil
+}
+
+// Reschedule first reschedules the notification in the primary repository,
+// then invalidates the cache.
+func (r *CachedNotificationRepository) Reschedule(ctx context.Context, tenantID string, id uuid.UUID, scheduledAt time.Time, timezone *string, version *int) (*model.Notification, error) {
+	n, err := r.primaryRepo.Reschedule(ctx, tenantID, id, scheduledAt, timezone, version)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.cache.Delete(ctx, tenantID, id); err != nil {
+		r.logger.Error().Err(err).Stringer("id", id).Msg("failed to invalidate cache after reschedule")
+	}
+
+	return n, nil
+}
+
+// List bypasses the cache, as list results cannot be invalidated per notification.
+func (r *CachedNotificationRepository) List(ctx context.Context, tenantID string, filter model.NotificationFilter, after *model.Cursor, limit int) ([]*model.Notification, error) {
+	return r.primaryRepo.List(ctx, tenantID, filter, after, limit)
+}
+
+// Count bypasses the cache, like List.
+func (r *CachedNotificationRepository) Count(ctx context.Context, tenantID string, filter model.NotificationFilter) (int, error) {
+	return r.primaryRepo.Count(ctx, tenantID, filter)
+}
+
+// CancelMany first cancels the notifications in the primary repository,
+// then invalidates the cache entries of those it cancelled.
+func (r *CachedNotificationRepository) CancelMany(ctx context.Context, tenantID string, ids []uuid.UUID) ([]*model.Notification, error) {
+	cancelled, err := r.primaryRepo.CancelMany(ctx, tenantID, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	r.invalidate(ctx, cancelled, "failed to invalidate cache after bulk cancel")
+	return cancelled, nil
+}
+
+// RescheduleMany first reschedules the notifications in the primary repository,
+// then invalidates the cache entries of those it rescheduled.
+func (r *CachedNotificationRepository) RescheduleMany(ctx context.Context, tenantID string, ids []uuid.UUID, scheduledAt time.Time, timezone *string) ([]*model.Notification, error) {
+	rescheduled, err := r.primaryRepo.RescheduleMany(ctx, tenantID, ids, scheduledAt, timezone)
+	if err != nil {
+		return nil, err
+	}
+
+	r.invalidate(ctx, rescheduled, "failed to invalidate cache after bulk reschedule")
+	return rescheduled, nil
+}
+
+// invalidate removes the cache entries of the notifications, logging failures with msg.
+func (r *CachedNotificationRepository) invalidate(ctx context.Context, notifications []*model.Notification, msg string) {
+	for _, n := range notifications {
+		if err := r.cache.Delete(ctx, n.TenantID, n.ID); err != nil {
+			r.logger.Error().Err(err).Stringer("id", n.ID).Msg(msg)
+		}
+	}
 }
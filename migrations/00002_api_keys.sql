@@ -0,0 +1,30 @@
+-- +goose Up
+-- This migration adds API keys used to authenticate callers of the HTTP API.
+
+-- Only a SHA-256 hash of each key is stored. The raw key is shown once, on creation.
+-- key_prefix keeps the first characters of the raw key so operators can tell keys apart.
+CREATE TABLE api_keys (
+                          id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+                          name TEXT NOT NULL,
+                          key_prefix TEXT NOT NULL,
+                          key_hash TEXT NOT NULL UNIQUE,
+
+    -- The principal the key acts as. It is recorded as author_id of created notifications.
+                          owner_id TEXT NOT NULL,
+    -- Granted scopes: 'create', 'read', 'cancel', 'admin'.
+                          scopes TEXT[] NOT NULL DEFAULT '{}',
+
+    -- Timestamps
+                          created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+                          last_used_at TIMESTAMPTZ,
+                          revoked_at TIMESTAMPTZ
+);
+
+CREATE INDEX idx_api_keys_owner_id ON api_keys (owner_id);
+
+-- Owners can only see their own notifications, so lookups by author become common.
+CREATE INDEX idx_notifications_author_id ON notifications (author_id);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_notifications_author_id;
+DROP TABLE IF EXISTS api_keys;
@@ -0,0 +1,18 @@
+-- +goose Up
+-- This migration scopes notifications and API keys to tenants.
+-- Existing rows are assigned to the 'default' tenant.
+
+ALTER TABLE notifications ADD COLUMN tenant_id TEXT NOT NULL DEFAULT 'default';
+ALTER TABLE api_keys ADD COLUMN tenant_id TEXT NOT NULL DEFAULT 'default';
+
+-- Every notification lookup is now filtered by tenant.
+CREATE INDEX idx_notifications_tenant_id_author_id ON notifications (tenant_id, author_id);
+DROP INDEX IF EXISTS idx_notifications_author_id;
+CREATE INDEX idx_api_keys_tenant_id ON api_keys (tenant_id);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_api_keys_tenant_id;
+DROP INDEX IF EXISTS idx_notifications_tenant_id_author_id;
+CREATE INDEX idx_notifications_author_id ON notifications (author_id);
+ALTER TABLE api_keys DROP COLUMN IF EXISTS tenant_id;
+ALTER TABLE notifications DROP COLUMN IF EXISTS tenant_id;
@@ -0,0 +1,36 @@
+-- +goose NO TRANSACTION
+-- +goose Up
+-- This migration adds status change callbacks and the 'expired' status.
+-- It runs outside a transaction because ALTER TYPE ... ADD VALUE may not run inside one.
+
+-- Notifications picked up too long after their scheduled time are expired instead of sent.
+ALTER TYPE notification_status ADD VALUE IF NOT EXISTS 'expired';
+
+-- An optional URL that receives a signed POST after each terminal status transition.
+ALTER TABLE notifications ADD COLUMN callback_url TEXT;
+
+-- The delivery log keeps one row per callback delivery attempt.
+CREATE TABLE callback_deliveries (
+                                     id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+                                     event_id UUID NOT NULL,
+                                     notification_id UUID NOT NULL,
+                                     tenant_id TEXT NOT NULL,
+                                     event TEXT NOT NULL,
+                                     url TEXT NOT NULL,
+                                     attempt SMALLINT NOT NULL,
+
+    -- Outcome of the attempt. status_code is NULL if no response was received.
+                                     status_code INTEGER,
+                                     error TEXT,
+                                     duration_ms INTEGER NOT NULL,
+                                     delivered BOOLEAN NOT NULL,
+
+                                     created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_callback_deliveries_notification ON callback_deliveries (tenant_id, notification_id, created_at);
+
+-- +goose Down
+-- Enum values cannot be removed; 'expired' stays in notification_status.
+DROP TABLE IF EXISTS callback_deliveries;
+ALTER TABLE notifications DROP COLUMN IF EXISTS callback_url;
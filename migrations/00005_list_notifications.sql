@@ -0,0 +1,7 @@
+-- +goose Up
+-- This migration supports listing a tenant's notifications, newest first,
+-- with keyset pagination on (created_at, id).
+CREATE INDEX idx_notifications_tenant_id_created_at ON notifications (tenant_id, created_at DESC, id DESC);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_notifications_tenant_id_created_at;
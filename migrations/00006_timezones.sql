@@ -0,0 +1,8 @@
+-- +goose Up
+-- This migration stores the IANA time zone a notification was scheduled in, e.g.
+-- 'Europe/Berlin', so that reschedules keep local-time semantics. NULL means the
+-- notification was scheduled with an absolute timestamp only.
+ALTER TABLE notifications ADD COLUMN timezone TEXT;
+
+-- +goose Down
+ALTER TABLE notifications DROP COLUMN IF EXISTS timezone;
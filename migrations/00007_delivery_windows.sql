@@ -0,0 +1,21 @@
+-- +goose Up
+-- This migration adds delivery windows: when a notification may be delivered, e.g.
+-- Monday to Friday from 09:00 to 18:00 in the recipient's time zone, and quiet hours.
+-- Windows are stored as JSON objects with the keys timezone, days, hours and quiet_hours.
+
+-- A window given with the notification itself. NULL falls back to the recipient's window.
+ALTER TABLE notifications ADD COLUMN delivery_window JSONB;
+
+-- The windows of recipients, applied to all their notifications without a window of their own.
+CREATE TABLE recipient_delivery_windows (
+                                            tenant_id TEXT NOT NULL,
+                                            channel channel_type NOT NULL,
+                                            recipient TEXT NOT NULL,
+                                            delivery_window JSONB NOT NULL,
+                                            updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+                                            PRIMARY KEY (tenant_id, channel, recipient)
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS recipient_delivery_windows;
+ALTER TABLE notifications DROP COLUMN IF EXISTS delivery_window;
@@ -0,0 +1,10 @@
+-- +goose Up
+-- This migration adds notification priorities. Higher priorities overtake lower ones
+-- waiting in the process queue; existing notifications keep the normal priority.
+CREATE TYPE notification_priority AS ENUM ('low', 'normal', 'high', 'critical');
+
+ALTER TABLE notifications ADD COLUMN priority notification_priority NOT NULL DEFAULT 'normal';
+
+-- +goose Down
+ALTER TABLE notifications DROP COLUMN IF EXISTS priority;
+DROP TYPE IF EXISTS notification_priority;
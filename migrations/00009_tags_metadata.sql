@@ -0,0 +1,15 @@
+-- +goose Up
+-- This migration adds tags and metadata, which callers use to correlate notifications
+-- with their own entities, e.g. a campaign tag or {"order_id": "A-1001"}.
+ALTER TABLE notifications ADD COLUMN tags TEXT[] NOT NULL DEFAULT '{}';
+ALTER TABLE notifications ADD COLUMN metadata JSONB NOT NULL DEFAULT '{}';
+
+-- List filters match tags and metadata by containment (@>), which GIN indexes support.
+CREATE INDEX idx_notifications_tags ON notifications USING GIN (tags);
+CREATE INDEX idx_notifications_metadata ON notifications USING GIN (metadata jsonb_path_ops);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_notifications_metadata;
+DROP INDEX IF EXISTS idx_notifications_tags;
+ALTER TABLE notifications DROP COLUMN IF EXISTS metadata;
+ALTER TABLE notifications DROP COLUMN IF EXISTS tags;
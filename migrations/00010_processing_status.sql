@@ -0,0 +1,11 @@
+-- +goose NO TRANSACTION
+-- +goose Up
+-- This migration adds the 'processing' status, which a worker claims a notification with
+-- before sending it, so that it can no longer be cancelled or rescheduled meanwhile.
+-- It runs outside a transaction because ALTER TYPE ... ADD VALUE may not run inside one.
+ALTER TYPE notification_status ADD VALUE IF NOT EXISTS 'processing' AFTER 'scheduled';
+
+-- +goose Down
+-- Enum values cannot be removed; 'processing' stays in notification_status.
+-- Notifications claimed by a worker are handed back to the scheduler.
+UPDATE notifications SET status = 'scheduled' WHERE status = 'processing';
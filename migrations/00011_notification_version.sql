@@ -0,0 +1,7 @@
+-- +goose Up
+-- This migration adds a version to notifications, incremented by every update, so that
+-- updates can require the version they read and fail instead of overwriting newer changes.
+ALTER TABLE notifications ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
+
+-- +goose Down
+ALTER TABLE notifications DROP COLUMN IF EXISTS version;
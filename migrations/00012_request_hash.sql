@@ -0,0 +1,7 @@
+-- +goose Up
+-- This migration stores a hash of the request that created a notification with an
+-- idempotency key, so that reusing the key for a different request can be rejected.
+ALTER TABLE notifications ADD COLUMN request_hash TEXT;
+
+-- +goose Down
+ALTER TABLE notifications DROP COLUMN IF EXISTS request_hash;
@@ -0,0 +1,2363 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: notifier/v1/notifier.proto
+
+package notifierv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	structpb "google.golang.org/protobuf/types/known/structpb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Channel is the delivery channel of a notification.
+type Channel int32
+
+const (
+	Channel_CHANNEL_UNSPECIFIED Channel = 0
+	Channel_CHANNEL_EMAIL       Channel = 1
+	Channel_CHANNEL_TELEGRAM    Channel = 2
+)
+
+// Enum value maps for Channel.
+var (
+	Channel_name = map[int32]string{
+		0: "CHANNEL_UNSPECIFIED",
+		1: "CHANNEL_EMAIL",
+		2: "CHANNEL_TELEGRAM",
+	}
+	Channel_value = map[string]int32{
+		"CHANNEL_UNSPECIFIED": 0,
+		"CHANNEL_EMAIL":       1,
+		"CHANNEL_TELEGRAM":    2,
+	}
+)
+
+func (x Channel) Enum() *Channel {
+	p := new(Channel)
+	*p = x
+	return p
+}
+
+func (x Channel) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Channel) Descriptor() protoreflect.EnumDescriptor {
+	return file_notifier_v1_notifier_proto_enumTypes[0].Descriptor()
+}
+
+func (Channel) Type() protoreflect.EnumType {
+	return &file_notifier_v1_notifier_proto_enumTypes[0]
+}
+
+func (x Channel) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Channel.Descriptor instead.
+func (Channel) EnumDescriptor() ([]byte, []int) {
+	return file_notifier_v1_notifier_proto_rawDescGZIP(), []int{0}
+}
+
+// Status is the state of a notification.
+type Status int32
+
+const (
+	Status_STATUS_UNSPECIFIED Status = 0
+	Status_STATUS_SCHEDULED   Status = 1
+	Status_STATUS_SENT        Status = 2
+	Status_STATUS_FAILED      Status = 3
+	Status_STATUS_CANCELLED   Status = 4
+	Status_STATUS_EXPIRED     Status = 5
+	// A worker is sending the notification; it can no longer be cancelled or rescheduled.
+	Status_STATUS_PROCESSING Status = 6
+)
+
+// Enum value maps for Status.
+var (
+	Status_name = map[int32]string{
+		0: "STATUS_UNSPECIFIED",
+		1: "STATUS_SCHEDULED",
+		2: "STATUS_SENT",
+		3: "STATUS_FAILED",
+		4: "STATUS_CANCELLED",
+		5: "STATUS_EXPIRED",
+		6: "STATUS_PROCESSING",
+	}
+	Status_value = map[string]int32{
+		"STATUS_UNSPECIFIED": 0,
+		"STATUS_SCHEDULED":   1,
+		"STATUS_SENT":        2,
+		"STATUS_FAILED":      3,
+		"STATUS_CANCELLED":   4,
+		"STATUS_EXPIRED":     5,
+		"STATUS_PROCESSING":  6,
+	}
+)
+
+func (x Status) Enum() *Status {
+	p := new(Status)
+	*p = x
+	return p
+}
+
+func (x Status) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Status) Descriptor() protoreflect.EnumDescriptor {
+	return file_notifier_v1_notifier_proto_enumTypes[1].Descriptor()
+}
+
+func (Status) Type() protoreflect.EnumType {
+	return &file_notifier_v1_notifier_proto_enumTypes[1]
+}
+
+func (x Status) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Status.Descriptor instead.
+func (Status) EnumDescriptor() ([]byte, []int) {
+	return file_notifier_v1_notifier_proto_rawDescGZIP(), []int{1}
+}
+
+// Priority orders notifications waiting to be processed: higher priorities go first.
+type Priority int32
+
+const (
+	Priority_PRIORITY_UNSPECIFIED Priority = 0
+	Priority_PRIORITY_LOW         Priority = 1
+	Priority_PRIORITY_NORMAL      Priority = 2
+	Priority_PRIORITY_HIGH        Priority = 3
+	Priority_PRIORITY_CRITICAL    Priority = 4
+)
+
+// Enum value maps for Priority.
+var (
+	Priority_name = map[int32]string{
+		0: "PRIORITY_UNSPECIFIED",
+		1: "PRIORITY_LOW",
+		2: "PRIORITY_NORMAL",
+		3: "PRIORITY_HIGH",
+		4: "PRIORITY_CRITICAL",
+	}
+	Priority_value = map[string]int32{
+		"PRIORITY_UNSPECIFIED": 0,
+		"PRIORITY_LOW":         1,
+		"PRIORITY_NORMAL":      2,
+		"PRIORITY_HIGH":        3,
+		"PRIORITY_CRITICAL":    4,
+	}
+)
+
+func (x Priority) Enum() *Priority {
+	p := new(Priority)
+	*p = x
+	return p
+}
+
+func (x Priority) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Priority) Descriptor() protoreflect.EnumDescriptor {
+	return file_notifier_v1_notifier_proto_enumTypes[2].Descriptor()
+}
+
+func (Priority) Type() protoreflect.EnumType {
+	return &file_notifier_v1_notifier_proto_enumTypes[2]
+}
+
+func (x Priority) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Priority.Descriptor instead.
+func (Priority) EnumDescriptor() ([]byte, []int) {
+	return file_notifier_v1_notifier_proto_rawDescGZIP(), []int{2}
+}
+
+// BulkAction is the change a bulk job applies to every notification it selects.
+type BulkAction int32
+
+const (
+	BulkAction_BULK_ACTION_UNSPECIFIED BulkAction = 0
+	BulkAction_BULK_ACTION_CANCEL      BulkAction = 1
+	BulkAction_BULK_ACTION_RESCHEDULE  BulkAction = 2
+)
+
+// Enum value maps for BulkAction.
+var (
+	BulkAction_name = map[int32]string{
+		0: "BULK_ACTION_UNSPECIFIED",
+		1: "BULK_ACTION_CANCEL",
+		2: "BULK_ACTION_RESCHEDULE",
+	}
+	BulkAction_value = map[string]int32{
+		"BULK_ACTION_UNSPECIFIED": 0,
+		"BULK_ACTION_CANCEL":      1,
+		"BULK_ACTION_RESCHEDULE":  2,
+	}
+)
+
+func (x BulkAction) Enum() *BulkAction {
+	p := new(BulkAction)
+	*p = x
+	return p
+}
+
+func (x BulkAction) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (BulkAction) Descriptor() protoreflect.EnumDescriptor {
+	return file_notifier_v1_notifier_proto_enumTypes[3].Descriptor()
+}
+
+func (BulkAction) Type() protoreflect.EnumType {
+	return &file_notifier_v1_notifier_proto_enumTypes[3]
+}
+
+func (x BulkAction) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use BulkAction.Descriptor instead.
+func (BulkAction) EnumDescriptor() ([]byte, []int) {
+	return file_notifier_v1_notifier_proto_rawDescGZIP(), []int{3}
+}
+
+// BulkJobStatus is the state of a bulk job.
+type BulkJobStatus int32
+
+const (
+	BulkJobStatus_BULK_JOB_STATUS_UNSPECIFIED BulkJobStatus = 0
+	BulkJobStatus_BULK_JOB_STATUS_RUNNING     BulkJobStatus = 1
+	BulkJobStatus_BULK_JOB_STATUS_COMPLETED   BulkJobStatus = 2
+	BulkJobStatus_BULK_JOB_STATUS_FAILED      BulkJobStatus = 3
+)
+
+// Enum value maps for BulkJobStatus.
+var (
+	BulkJobStatus_name = map[int32]string{
+		0: "BULK_JOB_STATUS_UNSPECIFIED",
+		1: "BULK_JOB_STATUS_RUNNING",
+		2: "BULK_JOB_STATUS_COMPLETED",
+		3: "BULK_JOB_STATUS_FAILED",
+	}
+	BulkJobStatus_value = map[string]int32{
+		"BULK_JOB_STATUS_UNSPECIFIED": 0,
+		"BULK_JOB_STATUS_RUNNING":     1,
+		"BULK_JOB_STATUS_COMPLETED":   2,
+		"BULK_JOB_STATUS_FAILED":      3,
+	}
+)
+
+func (x BulkJobStatus) Enum() *BulkJobStatus {
+	p := new(BulkJobStatus)
+	*p = x
+	return p
+}
+
+func (x BulkJobStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (BulkJobStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_notifier_v1_notifier_proto_enumTypes[4].Descriptor()
+}
+
+func (BulkJobStatus) Type() protoreflect.EnumType {
+	return &file_notifier_v1_notifier_proto_enumTypes[4]
+}
+
+func (x BulkJobStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use BulkJobStatus.Descriptor instead.
+func (BulkJobStatus) EnumDescriptor() ([]byte, []int) {
+	return file_notifier_v1_notifier_proto_rawDescGZIP(), []int{4}
+}
+
+type Notification struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Id          string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Status      Status                 `protobuf:"varint,2,opt,name=status,proto3,enum=notifier.v1.Status" json:"status,omitempty"`
+	Channel     Channel                `protobuf:"varint,3,opt,name=channel,proto3,enum=notifier.v1.Channel" json:"channel,omitempty"`
+	Subject     string                 `protobuf:"bytes,4,opt,name=subject,proto3" json:"subject,omitempty"`
+	ScheduledAt *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=scheduled_at,json=scheduledAt,proto3" json:"scheduled_at,omitempty"`
+	CreatedAt   *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	CallbackUrl *string                `protobuf:"bytes,7,opt,name=callback_url,json=callbackUrl,proto3,oneof" json:"callback_url,omitempty"`
+	// The email address or Telegram chat ID, depending on the channel.
+	Recipient string `protobuf:"bytes,8,opt,name=recipient,proto3" json:"recipient,omitempty"`
+	// Delivery attempts made so far.
+	Attempts int32 `protobuf:"varint,9,opt,name=attempts,proto3" json:"attempts,omitempty"`
+	// Set once the notification has been sent.
+	SentAt *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=sent_at,json=sentAt,proto3" json:"sent_at,omitempty"`
+	// The IANA time zone the notification was scheduled in, unset for absolute schedules.
+	Timezone *string `protobuf:"bytes,11,opt,name=timezone,proto3,oneof" json:"timezone,omitempty"`
+	// The scheduled time on the clocks of the time zone, e.g. 2026-03-29T09:00:00.
+	LocalTime string `protobuf:"bytes,12,opt,name=local_time,json=localTime,proto3" json:"local_time,omitempty"`
+	// The notification's own delivery window, if any.
+	DeliveryWindow *DeliveryWindow  `protobuf:"bytes,13,opt,name=delivery_window,json=deliveryWindow,proto3" json:"delivery_window,omitempty"`
+	Priority       Priority         `protobuf:"varint,14,opt,name=priority,proto3,enum=notifier.v1.Priority" json:"priority,omitempty"`
+	Tags           []string         `protobuf:"bytes,15,rep,name=tags,proto3" json:"tags,omitempty"`
+	Metadata       *structpb.Struct `protobuf:"bytes,16,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	// Incremented by every change of the notification. Pass it as the version of a cancel or
+	// reschedule request to make the change fail with ABORTED if the notification changed since.
+	Version       int32 `protobuf:"varint,17,opt,name=version,proto3" json:"version,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Notification) Reset() {
+	*x = Notification{}
+	mi := &file_notifier_v1_notifier_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Notification) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Notification) ProtoMessage() {}
+
+func (x *Notification) ProtoReflect() protoreflect.Message {
+	mi := &file_notifier_v1_notifier_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Notification.ProtoReflect.Descriptor instead.
+func (*Notification) Descriptor() ([]byte, []int) {
+	return file_notifier_v1_notifier_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Notification) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Notification) GetStatus() Status {
+	if x != nil {
+		return x.Status
+	}
+	return Status_STATUS_UNSPECIFIED
+}
+
+func (x *Notification) GetChannel() Channel {
+	if x != nil {
+		return x.Channel
+	}
+	return Channel_CHANNEL_UNSPECIFIED
+}
+
+func (x *Notification) GetSubject() string {
+	if x != nil {
+		return x.Subject
+	}
+	return ""
+}
+
+func (x *Notification) GetScheduledAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ScheduledAt
+	}
+	return nil
+}
+
+func (x *Notification) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Notification) GetCallbackUrl() string {
+	if x != nil && x.CallbackUrl != nil {
+		return *x.CallbackUrl
+	}
+	return ""
+}
+
+func (x *Notification) GetRecipient() string {
+	if x != nil {
+		return x.Recipient
+	}
+	return ""
+}
+
+func (x *Notification) GetAttempts() int32 {
+	if x != nil {
+		return x.Attempts
+	}
+	return 0
+}
+
+func (x *Notification) GetSentAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.SentAt
+	}
+	return nil
+}
+
+func (x *Notification) GetTimezone() string {
+	if x != nil && x.Timezone != nil {
+		return *x.Timezone
+	}
+	return ""
+}
+
+func (x *Notification) GetLocalTime() string {
+	if x != nil {
+		return x.LocalTime
+	}
+	return ""
+}
+
+func (x *Notification) GetDeliveryWindow() *DeliveryWindow {
+	if x != nil {
+		return x.DeliveryWindow
+	}
+	return nil
+}
+
+func (x *Notification) GetPriority() Priority {
+	if x != nil {
+		return x.Priority
+	}
+	return Priority_PRIORITY_UNSPECIFIED
+}
+
+func (x *Notification) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *Notification) GetMetadata() *structpb.Struct {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+func (x *Notification) GetVersion() int32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+// DeliveryWindow restricts when a notification may be delivered, e.g. on weekdays from
+// 09:00 to 18:00, or outside quiet hours from 22:00 to 07:00. Notifications falling
+// outside are deferred to the next opening of the window without using up an attempt.
+type DeliveryWindow struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// An IANA time zone such as Europe/Berlin. Defaults to the notification's time zone.
+	Timezone string `protobuf:"bytes,1,opt,name=timezone,proto3" json:"timezone,omitempty"`
+	// Weekdays on which delivery is allowed, as short names such as mon; empty means every day.
+	Days []string `protobuf:"bytes,2,rep,name=days,proto3" json:"days,omitempty"`
+	// The daily range of times during which delivery is allowed.
+	Hours *ClockRange `protobuf:"bytes,3,opt,name=hours,proto3" json:"hours,omitempty"`
+	// The daily range of times during which delivery is not allowed.
+	QuietHours    *ClockRange `protobuf:"bytes,4,opt,name=quiet_hours,json=quietHours,proto3" json:"quiet_hours,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeliveryWindow) Reset() {
+	*x = DeliveryWindow{}
+	mi := &file_notifier_v1_notifier_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeliveryWindow) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeliveryWindow) ProtoMessage() {}
+
+func (x *DeliveryWindow) ProtoReflect() protoreflect.Message {
+	mi := &file_notifier_v1_notifier_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeliveryWindow.ProtoReflect.Descriptor instead.
+func (*DeliveryWindow) Descriptor() ([]byte, []int) {
+	return file_notifier_v1_notifier_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *DeliveryWindow) GetTimezone() string {
+	if x != nil {
+		return x.Timezone
+	}
+	return ""
+}
+
+func (x *DeliveryWindow) GetDays() []string {
+	if x != nil {
+		return x.Days
+	}
+	return nil
+}
+
+func (x *DeliveryWindow) GetHours() *ClockRange {
+	if x != nil {
+		return x.Hours
+	}
+	return nil
+}
+
+func (x *DeliveryWindow) GetQuietHours() *ClockRange {
+	if x != nil {
+		return x.QuietHours
+	}
+	return nil
+}
+
+// ClockRange is a daily range of times of day such as 09:00, from start up to end.
+// It wraps past midnight if end is not after start.
+type ClockRange struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Start         string                 `protobuf:"bytes,1,opt,name=start,proto3" json:"start,omitempty"`
+	End           string                 `protobuf:"bytes,2,opt,name=end,proto3" json:"end,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ClockRange) Reset() {
+	*x = ClockRange{}
+	mi := &file_notifier_v1_notifier_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ClockRange) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ClockRange) ProtoMessage() {}
+
+func (x *ClockRange) ProtoReflect() protoreflect.Message {
+	mi := &file_notifier_v1_notifier_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ClockRange.ProtoReflect.Descriptor instead.
+func (*ClockRange) Descriptor() ([]byte, []int) {
+	return file_notifier_v1_notifier_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ClockRange) GetStart() string {
+	if x != nil {
+		return x.Start
+	}
+	return ""
+}
+
+func (x *ClockRange) GetEnd() string {
+	if x != nil {
+		return x.End
+	}
+	return ""
+}
+
+type CreateNotificationRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The email address or Telegram chat ID, depending on the channel.
+	Recipient string  `protobuf:"bytes,1,opt,name=recipient,proto3" json:"recipient,omitempty"`
+	Channel   Channel `protobuf:"varint,2,opt,name=channel,proto3,enum=notifier.v1.Channel" json:"channel,omitempty"`
+	Subject   string  `protobuf:"bytes,3,opt,name=subject,proto3" json:"subject,omitempty"`
+	Message   string  `protobuf:"bytes,4,opt,name=message,proto3" json:"message,omitempty"`
+	// The absolute send time. Alternatively, set local_time and timezone, delay or send_now.
+	ScheduledAt *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=scheduled_at,json=scheduledAt,proto3" json:"scheduled_at,omitempty"`
+	// Ignored when authentication is enabled; the authenticated principal is used instead.
+	AuthorId *string `protobuf:"bytes,6,opt,name=author_id,json=authorId,proto3,oneof" json:"author_id,omitempty"`
+	// Receives a signed POST after the notification reaches a terminal status.
+	CallbackUrl *string `protobuf:"bytes,7,opt,name=callback_url,json=callbackUrl,proto3,oneof" json:"callback_url,omitempty"`
+	// A wall-clock date and time such as 2026-03-29T09:00, or a time of day such as 09:00
+	// for its next occurrence, in timezone. Resolved across daylight saving transitions.
+	LocalTime string `protobuf:"bytes,8,opt,name=local_time,json=localTime,proto3" json:"local_time,omitempty"`
+	// An IANA time zone such as Europe/Berlin. Stored with the notification, so that
+	// local-time reschedules default to it.
+	Timezone string `protobuf:"bytes,9,opt,name=timezone,proto3" json:"timezone,omitempty"`
+	// Restricts when the notification may be delivered. Without one, the window set
+	// for the recipient over the HTTP API applies, if any.
+	DeliveryWindow *DeliveryWindow `protobuf:"bytes,10,opt,name=delivery_window,json=deliveryWindow,proto3" json:"delivery_window,omitempty"`
+	// An ISO 8601 duration such as PT15M or P1DT12H after which to send, resolved on the
+	// server's clock. The resolved time is returned as scheduled_at.
+	Delay string `protobuf:"bytes,11,opt,name=delay,proto3" json:"delay,omitempty"`
+	// Sends the notification immediately, skipping the delay queue.
+	SendNow bool `protobuf:"varint,12,opt,name=send_now,json=sendNow,proto3" json:"send_now,omitempty"`
+	// Defaults to normal when unspecified.
+	Priority Priority `protobuf:"varint,13,opt,name=priority,proto3,enum=notifier.v1.Priority" json:"priority,omitempty"`
+	// Labels correlating the notification with the caller's own entities, e.g. a campaign.
+	// At most 20 of at most 64 characters each.
+	Tags []string `protobuf:"bytes,14,rep,name=tags,proto3" json:"tags,omitempty"`
+	// An arbitrary object of at most 4096 bytes as JSON, e.g. the ID of an order.
+	Metadata      *structpb.Struct `protobuf:"bytes,15,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateNotificationRequest) Reset() {
+	*x = CreateNotificationRequest{}
+	mi := &file_notifier_v1_notifier_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateNotificationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateNotificationRequest) ProtoMessage() {}
+
+func (x *CreateNotificationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_notifier_v1_notifier_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateNotificationRequest.ProtoReflect.Descriptor instead.
+func (*CreateNotificationRequest) Descriptor() ([]byte, []int) {
+	return file_notifier_v1_notifier_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *CreateNotificationRequest) GetRecipient() string {
+	if x != nil {
+		return x.Recipient
+	}
+	return ""
+}
+
+func (x *CreateNotificationRequest) GetChannel() Channel {
+	if x != nil {
+		return x.Channel
+	}
+	return Channel_CHANNEL_UNSPECIFIED
+}
+
+func (x *CreateNotificationRequest) GetSubject() string {
+	if x != nil {
+		return x.Subject
+	}
+	return ""
+}
+
+func (x *CreateNotificationRequest) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *CreateNotificationRequest) GetScheduledAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ScheduledAt
+	}
+	return nil
+}
+
+func (x *CreateNotificationRequest) GetAuthorId() string {
+	if x != nil && x.AuthorId != nil {
+		return *x.AuthorId
+	}
+	return ""
+}
+
+func (x *CreateNotificationRequest) GetCallbackUrl() string {
+	if x != nil && x.CallbackUrl != nil {
+		return *x.CallbackUrl
+	}
+	return ""
+}
+
+func (x *CreateNotificationRequest) GetLocalTime() string {
+	if x != nil {
+		return x.LocalTime
+	}
+	return ""
+}
+
+func (x *CreateNotificationRequest) GetTimezone() string {
+	if x != nil {
+		return x.Timezone
+	}
+	return ""
+}
+
+func (x *CreateNotificationRequest) GetDeliveryWindow() *DeliveryWindow {
+	if x != nil {
+		return x.DeliveryWindow
+	}
+	return nil
+}
+
+func (x *CreateNotificationRequest) GetDelay() string {
+	if x != nil {
+		return x.Delay
+	}
+	return ""
+}
+
+func (x *CreateNotificationRequest) GetSendNow() bool {
+	if x != nil {
+		return x.SendNow
+	}
+	return false
+}
+
+func (x *CreateNotificationRequest) GetPriority() Priority {
+	if x != nil {
+		return x.Priority
+	}
+	return Priority_PRIORITY_UNSPECIFIED
+}
+
+func (x *CreateNotificationRequest) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *CreateNotificationRequest) GetMetadata() *structpb.Struct {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+type GetNotificationRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetNotificationRequest) Reset() {
+	*x = GetNotificationRequest{}
+	mi := &file_notifier_v1_notifier_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetNotificationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetNotificationRequest) ProtoMessage() {}
+
+func (x *GetNotificationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_notifier_v1_notifier_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetNotificationRequest.ProtoReflect.Descriptor instead.
+func (*GetNotificationRequest) Descriptor() ([]byte, []int) {
+	return file_notifier_v1_notifier_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *GetNotificationRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type CancelNotificationRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// Only cancel the notification while it still has this version.
+	Version       *int32 `protobuf:"varint,2,opt,name=version,proto3,oneof" json:"version,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CancelNotificationRequest) Reset() {
+	*x = CancelNotificationRequest{}
+	mi := &file_notifier_v1_notifier_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelNotificationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelNotificationRequest) ProtoMessage() {}
+
+func (x *CancelNotificationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_notifier_v1_notifier_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelNotificationRequest.ProtoReflect.Descriptor instead.
+func (*CancelNotificationRequest) Descriptor() ([]byte, []int) {
+	return file_notifier_v1_notifier_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *CancelNotificationRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *CancelNotificationRequest) GetVersion() int32 {
+	if x != nil && x.Version != nil {
+		return *x.Version
+	}
+	return 0
+}
+
+type CancelNotificationResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CancelNotificationResponse) Reset() {
+	*x = CancelNotificationResponse{}
+	mi := &file_notifier_v1_notifier_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelNotificationResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelNotificationResponse) ProtoMessage() {}
+
+func (x *CancelNotificationResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_notifier_v1_notifier_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelNotificationResponse.ProtoReflect.Descriptor instead.
+func (*CancelNotificationResponse) Descriptor() ([]byte, []int) {
+	return file_notifier_v1_notifier_proto_rawDescGZIP(), []int{6}
+}
+
+type RescheduleNotificationRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// The absolute send time. Alternatively, set local_time.
+	ScheduledAt *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=scheduled_at,json=scheduledAt,proto3" json:"scheduled_at,omitempty"`
+	// Like CreateNotificationRequest.local_time; read in timezone, which defaults to the
+	// notification's time zone and replaces it if set.
+	LocalTime string `protobuf:"bytes,3,opt,name=local_time,json=localTime,proto3" json:"local_time,omitempty"`
+	Timezone  string `protobuf:"bytes,4,opt,name=timezone,proto3" json:"timezone,omitempty"`
+	// Only reschedule the notification while it still has this version.
+	Version       *int32 `protobuf:"varint,5,opt,name=version,proto3,oneof" json:"version,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RescheduleNotificationRequest) Reset() {
+	*x = RescheduleNotificationRequest{}
+	mi := &file_notifier_v1_notifier_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RescheduleNotificationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RescheduleNotificationRequest) ProtoMessage() {}
+
+func (x *RescheduleNotificationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_notifier_v1_notifier_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RescheduleNotificationRequest.ProtoReflect.Descriptor instead.
+func (*RescheduleNotificationRequest) Descriptor() ([]byte, []int) {
+	return file_notifier_v1_notifier_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *RescheduleNotificationRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *RescheduleNotificationRequest) GetScheduledAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ScheduledAt
+	}
+	return nil
+}
+
+func (x *RescheduleNotificationRequest) GetLocalTime() string {
+	if x != nil {
+		return x.LocalTime
+	}
+	return ""
+}
+
+func (x *RescheduleNotificationRequest) GetTimezone() string {
+	if x != nil {
+		return x.Timezone
+	}
+	return ""
+}
+
+func (x *RescheduleNotificationRequest) GetVersion() int32 {
+	if x != nil && x.Version != nil {
+		return *x.Version
+	}
+	return 0
+}
+
+type ListNotificationsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Filters; unset fields match everything.
+	Status        Status                 `protobuf:"varint,1,opt,name=status,proto3,enum=notifier.v1.Status" json:"status,omitempty"`
+	Channel       Channel                `protobuf:"varint,2,opt,name=channel,proto3,enum=notifier.v1.Channel" json:"channel,omitempty"`
+	AuthorId      *string                `protobuf:"bytes,3,opt,name=author_id,json=authorId,proto3,oneof" json:"author_id,omitempty"`
+	ScheduledFrom *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=scheduled_from,json=scheduledFrom,proto3" json:"scheduled_from,omitempty"`
+	ScheduledTo   *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=scheduled_to,json=scheduledTo,proto3" json:"scheduled_to,omitempty"`
+	// Page size, 50 by default and at most 500.
+	PageSize int32 `protobuf:"varint,6,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	// The next_page_token of the previous page.
+	PageToken string `protobuf:"bytes,7,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	// Case-insensitive substring of the subject or email recipient, or an exact Telegram chat ID.
+	Search *string `protobuf:"bytes,8,opt,name=search,proto3,oneof" json:"search,omitempty"`
+	// Only notifications carrying all of the tags.
+	Tags []string `protobuf:"bytes,9,rep,name=tags,proto3" json:"tags,omitempty"`
+	// Only notifications whose metadata contains this object, i.e. has all of its keys with equal values.
+	Metadata      *structpb.Struct `protobuf:"bytes,10,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListNotificationsRequest) Reset() {
+	*x = ListNotificationsRequest{}
+	mi := &file_notifier_v1_notifier_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListNotificationsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListNotificationsRequest) ProtoMessage() {}
+
+func (x *ListNotificationsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_notifier_v1_notifier_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListNotificationsRequest.ProtoReflect.Descriptor instead.
+func (*ListNotificationsRequest) Descriptor() ([]byte, []int) {
+	return file_notifier_v1_notifier_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ListNotificationsRequest) GetStatus() Status {
+	if x != nil {
+		return x.Status
+	}
+	return Status_STATUS_UNSPECIFIED
+}
+
+func (x *ListNotificationsRequest) GetChannel() Channel {
+	if x != nil {
+		return x.Channel
+	}
+	return Channel_CHANNEL_UNSPECIFIED
+}
+
+func (x *ListNotificationsRequest) GetAuthorId() string {
+	if x != nil && x.AuthorId != nil {
+		return *x.AuthorId
+	}
+	return ""
+}
+
+func (x *ListNotificationsRequest) GetScheduledFrom() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ScheduledFrom
+	}
+	return nil
+}
+
+func (x *ListNotificationsRequest) GetScheduledTo() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ScheduledTo
+	}
+	return nil
+}
+
+func (x *ListNotificationsRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListNotificationsRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+func (x *ListNotificationsRequest) GetSearch() string {
+	if x != nil && x.Search != nil {
+		return *x.Search
+	}
+	return ""
+}
+
+func (x *ListNotificationsRequest) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *ListNotificationsRequest) GetMetadata() *structpb.Struct {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+type ListNotificationsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Notifications []*Notification        `protobuf:"bytes,1,rep,name=notifications,proto3" json:"notifications,omitempty"`
+	// Empty on the last page.
+	NextPageToken string `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListNotificationsResponse) Reset() {
+	*x = ListNotificationsResponse{}
+	mi := &file_notifier_v1_notifier_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListNotificationsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListNotificationsResponse) ProtoMessage() {}
+
+func (x *ListNotificationsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_notifier_v1_notifier_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListNotificationsResponse.ProtoReflect.Descriptor instead.
+func (*ListNotificationsResponse) Descriptor() ([]byte, []int) {
+	return file_notifier_v1_notifier_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *ListNotificationsResponse) GetNotifications() []*Notification {
+	if x != nil {
+		return x.Notifications
+	}
+	return nil
+}
+
+func (x *ListNotificationsResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+type BatchCreateNotificationsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// At most 100 notifications.
+	Notifications []*CreateNotificationRequest `protobuf:"bytes,1,rep,name=notifications,proto3" json:"notifications,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchCreateNotificationsRequest) Reset() {
+	*x = BatchCreateNotificationsRequest{}
+	mi := &file_notifier_v1_notifier_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchCreateNotificationsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchCreateNotificationsRequest) ProtoMessage() {}
+
+func (x *BatchCreateNotificationsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_notifier_v1_notifier_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchCreateNotificationsRequest.ProtoReflect.Descriptor instead.
+func (*BatchCreateNotificationsRequest) Descriptor() ([]byte, []int) {
+	return file_notifier_v1_notifier_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *BatchCreateNotificationsRequest) GetNotifications() []*CreateNotificationRequest {
+	if x != nil {
+		return x.Notifications
+	}
+	return nil
+}
+
+type BatchCreateNotificationsResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The outcome of each request, in request order.
+	Results       []*BatchCreateResult `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchCreateNotificationsResponse) Reset() {
+	*x = BatchCreateNotificationsResponse{}
+	mi := &file_notifier_v1_notifier_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchCreateNotificationsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchCreateNotificationsResponse) ProtoMessage() {}
+
+func (x *BatchCreateNotificationsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_notifier_v1_notifier_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchCreateNotificationsResponse.ProtoReflect.Descriptor instead.
+func (*BatchCreateNotificationsResponse) Descriptor() ([]byte, []int) {
+	return file_notifier_v1_notifier_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *BatchCreateNotificationsResponse) GetResults() []*BatchCreateResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+type BatchCreateResult struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Result:
+	//
+	//	*BatchCreateResult_Notification
+	//	*BatchCreateResult_Error
+	Result        isBatchCreateResult_Result `protobuf_oneof:"result"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchCreateResult) Reset() {
+	*x = BatchCreateResult{}
+	mi := &file_notifier_v1_notifier_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchCreateResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchCreateResult) ProtoMessage() {}
+
+func (x *BatchCreateResult) ProtoReflect() protoreflect.Message {
+	mi := &file_notifier_v1_notifier_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchCreateResult.ProtoReflect.Descriptor instead.
+func (*BatchCreateResult) Descriptor() ([]byte, []int) {
+	return file_notifier_v1_notifier_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *BatchCreateResult) GetResult() isBatchCreateResult_Result {
+	if x != nil {
+		return x.Result
+	}
+	return nil
+}
+
+func (x *BatchCreateResult) GetNotification() *Notification {
+	if x != nil {
+		if x, ok := x.Result.(*BatchCreateResult_Notification); ok {
+			return x.Notification
+		}
+	}
+	return nil
+}
+
+func (x *BatchCreateResult) GetError() *BatchCreateError {
+	if x != nil {
+		if x, ok := x.Result.(*BatchCreateResult_Error); ok {
+			return x.Error
+		}
+	}
+	return nil
+}
+
+type isBatchCreateResult_Result interface {
+	isBatchCreateResult_Result()
+}
+
+type BatchCreateResult_Notification struct {
+	Notification *Notification `protobuf:"bytes,1,opt,name=notification,proto3,oneof"`
+}
+
+type BatchCreateResult_Error struct {
+	Error *BatchCreateError `protobuf:"bytes,2,opt,name=error,proto3,oneof"`
+}
+
+func (*BatchCreateResult_Notification) isBatchCreateResult_Result() {}
+
+func (*BatchCreateResult_Error) isBatchCreateResult_Result() {}
+
+// BatchCreateError is the error a single request of a batch would have failed with.
+type BatchCreateError struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// A google.rpc.Code value.
+	Code    int32  `protobuf:"varint,1,opt,name=code,proto3" json:"code,omitempty"`
+	Message string `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	// The stable error code, e.g. invalid_recipient, also found in the ErrorInfo
+	// details of regular errors.
+	Reason        string `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchCreateError) Reset() {
+	*x = BatchCreateError{}
+	mi := &file_notifier_v1_notifier_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchCreateError) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchCreateError) ProtoMessage() {}
+
+func (x *BatchCreateError) ProtoReflect() protoreflect.Message {
+	mi := &file_notifier_v1_notifier_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchCreateError.ProtoReflect.Descriptor instead.
+func (*BatchCreateError) Descriptor() ([]byte, []int) {
+	return file_notifier_v1_notifier_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *BatchCreateError) GetCode() int32 {
+	if x != nil {
+		return x.Code
+	}
+	return 0
+}
+
+func (x *BatchCreateError) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *BatchCreateError) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+type WatchStatusRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Only streams the notification's events if set.
+	NotificationId *string `protobuf:"bytes,1,opt,name=notification_id,json=notificationId,proto3,oneof" json:"notification_id,omitempty"`
+	// Resumes after the given event ID instead of starting with the next event.
+	LastEventId   string `protobuf:"bytes,2,opt,name=last_event_id,json=lastEventId,proto3" json:"last_event_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchStatusRequest) Reset() {
+	*x = WatchStatusRequest{}
+	mi := &file_notifier_v1_notifier_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchStatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchStatusRequest) ProtoMessage() {}
+
+func (x *WatchStatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_notifier_v1_notifier_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchStatusRequest.ProtoReflect.Descriptor instead.
+func (*WatchStatusRequest) Descriptor() ([]byte, []int) {
+	return file_notifier_v1_notifier_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *WatchStatusRequest) GetNotificationId() string {
+	if x != nil && x.NotificationId != nil {
+		return *x.NotificationId
+	}
+	return ""
+}
+
+func (x *WatchStatusRequest) GetLastEventId() string {
+	if x != nil {
+		return x.LastEventId
+	}
+	return ""
+}
+
+type StatusEvent struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Id             string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	NotificationId string                 `protobuf:"bytes,2,opt,name=notification_id,json=notificationId,proto3" json:"notification_id,omitempty"`
+	Status         Status                 `protobuf:"varint,3,opt,name=status,proto3,enum=notifier.v1.Status" json:"status,omitempty"`
+	Attempts       int32                  `protobuf:"varint,4,opt,name=attempts,proto3" json:"attempts,omitempty"`
+	OccurredAt     *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=occurred_at,json=occurredAt,proto3" json:"occurred_at,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *StatusEvent) Reset() {
+	*x = StatusEvent{}
+	mi := &file_notifier_v1_notifier_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StatusEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StatusEvent) ProtoMessage() {}
+
+func (x *StatusEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_notifier_v1_notifier_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StatusEvent.ProtoReflect.Descriptor instead.
+func (*StatusEvent) Descriptor() ([]byte, []int) {
+	return file_notifier_v1_notifier_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *StatusEvent) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *StatusEvent) GetNotificationId() string {
+	if x != nil {
+		return x.NotificationId
+	}
+	return ""
+}
+
+func (x *StatusEvent) GetStatus() Status {
+	if x != nil {
+		return x.Status
+	}
+	return Status_STATUS_UNSPECIFIED
+}
+
+func (x *StatusEvent) GetAttempts() int32 {
+	if x != nil {
+		return x.Attempts
+	}
+	return 0
+}
+
+func (x *StatusEvent) GetOccurredAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.OccurredAt
+	}
+	return nil
+}
+
+// NotificationFilter selects the notifications of a bulk job, like the filters of
+// ListNotificationsRequest. Only scheduled notifications are changed, and at least one
+// criterion besides the status is required.
+type NotificationFilter struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Status        Status                 `protobuf:"varint,1,opt,name=status,proto3,enum=notifier.v1.Status" json:"status,omitempty"`
+	Channel       Channel                `protobuf:"varint,2,opt,name=channel,proto3,enum=notifier.v1.Channel" json:"channel,omitempty"`
+	AuthorId      *string                `protobuf:"bytes,3,opt,name=author_id,json=authorId,proto3,oneof" json:"author_id,omitempty"`
+	ScheduledFrom *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=scheduled_from,json=scheduledFrom,proto3" json:"scheduled_from,omitempty"`
+	ScheduledTo   *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=scheduled_to,json=scheduledTo,proto3" json:"scheduled_to,omitempty"`
+	Search        *string                `protobuf:"bytes,6,opt,name=search,proto3,oneof" json:"search,omitempty"`
+	Tags          []string               `protobuf:"bytes,7,rep,name=tags,proto3" json:"tags,omitempty"`
+	Metadata      *structpb.Struct       `protobuf:"bytes,8,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NotificationFilter) Reset() {
+	*x = NotificationFilter{}
+	mi := &file_notifier_v1_notifier_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NotificationFilter) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NotificationFilter) ProtoMessage() {}
+
+func (x *NotificationFilter) ProtoReflect() protoreflect.Message {
+	mi := &file_notifier_v1_notifier_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NotificationFilter.ProtoReflect.Descriptor instead.
+func (*NotificationFilter) Descriptor() ([]byte, []int) {
+	return file_notifier_v1_notifier_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *NotificationFilter) GetStatus() Status {
+	if x != nil {
+		return x.Status
+	}
+	return Status_STATUS_UNSPECIFIED
+}
+
+func (x *NotificationFilter) GetChannel() Channel {
+	if x != nil {
+		return x.Channel
+	}
+	return Channel_CHANNEL_UNSPECIFIED
+}
+
+func (x *NotificationFilter) GetAuthorId() string {
+	if x != nil && x.AuthorId != nil {
+		return *x.AuthorId
+	}
+	return ""
+}
+
+func (x *NotificationFilter) GetScheduledFrom() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ScheduledFrom
+	}
+	return nil
+}
+
+func (x *NotificationFilter) GetScheduledTo() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ScheduledTo
+	}
+	return nil
+}
+
+func (x *NotificationFilter) GetSearch() string {
+	if x != nil && x.Search != nil {
+		return *x.Search
+	}
+	return ""
+}
+
+func (x *NotificationFilter) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *NotificationFilter) GetMetadata() *structpb.Struct {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+type CancelNotificationsRequest struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Filter *NotificationFilter    `protobuf:"bytes,1,opt,name=filter,proto3" json:"filter,omitempty"`
+	// Only counts the notifications that would be cancelled.
+	DryRun        bool `protobuf:"varint,2,opt,name=dry_run,json=dryRun,proto3" json:"dry_run,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CancelNotificationsRequest) Reset() {
+	*x = CancelNotificationsRequest{}
+	mi := &file_notifier_v1_notifier_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelNotificationsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelNotificationsRequest) ProtoMessage() {}
+
+func (x *CancelNotificationsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_notifier_v1_notifier_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelNotificationsRequest.ProtoReflect.Descriptor instead.
+func (*CancelNotificationsRequest) Descriptor() ([]byte, []int) {
+	return file_notifier_v1_notifier_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *CancelNotificationsRequest) GetFilter() *NotificationFilter {
+	if x != nil {
+		return x.Filter
+	}
+	return nil
+}
+
+func (x *CancelNotificationsRequest) GetDryRun() bool {
+	if x != nil {
+		return x.DryRun
+	}
+	return false
+}
+
+type RescheduleNotificationsRequest struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Filter *NotificationFilter    `protobuf:"bytes,1,opt,name=filter,proto3" json:"filter,omitempty"`
+	// The absolute send time. Alternatively, set local_time.
+	ScheduledAt *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=scheduled_at,json=scheduledAt,proto3" json:"scheduled_at,omitempty"`
+	// Like CreateNotificationRequest.local_time; read in timezone, which is then required
+	// and replaces the time zones of the notifications.
+	LocalTime string `protobuf:"bytes,3,opt,name=local_time,json=localTime,proto3" json:"local_time,omitempty"`
+	Timezone  string `protobuf:"bytes,4,opt,name=timezone,proto3" json:"timezone,omitempty"`
+	// Only counts the notifications that would be rescheduled.
+	DryRun        bool `protobuf:"varint,5,opt,name=dry_run,json=dryRun,proto3" json:"dry_run,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RescheduleNotificationsRequest) Reset() {
+	*x = RescheduleNotificationsRequest{}
+	mi := &file_notifier_v1_notifier_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RescheduleNotificationsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RescheduleNotificationsRequest) ProtoMessage() {}
+
+func (x *RescheduleNotificationsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_notifier_v1_notifier_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RescheduleNotificationsRequest.ProtoReflect.Descriptor instead.
+func (*RescheduleNotificationsRequest) Descriptor() ([]byte, []int) {
+	return file_notifier_v1_notifier_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *RescheduleNotificationsRequest) GetFilter() *NotificationFilter {
+	if x != nil {
+		return x.Filter
+	}
+	return nil
+}
+
+func (x *RescheduleNotificationsRequest) GetScheduledAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ScheduledAt
+	}
+	return nil
+}
+
+func (x *RescheduleNotificationsRequest) GetLocalTime() string {
+	if x != nil {
+		return x.LocalTime
+	}
+	return ""
+}
+
+func (x *RescheduleNotificationsRequest) GetTimezone() string {
+	if x != nil {
+		return x.Timezone
+	}
+	return ""
+}
+
+func (x *RescheduleNotificationsRequest) GetDryRun() bool {
+	if x != nil {
+		return x.DryRun
+	}
+	return false
+}
+
+type StartBulkJobResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The number of scheduled notifications matching the filter.
+	Matched int32 `protobuf:"varint,1,opt,name=matched,proto3" json:"matched,omitempty"`
+	// The started job; unset for a dry run.
+	Job           *BulkJob `protobuf:"bytes,2,opt,name=job,proto3" json:"job,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StartBulkJobResponse) Reset() {
+	*x = StartBulkJobResponse{}
+	mi := &file_notifier_v1_notifier_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StartBulkJobResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StartBulkJobResponse) ProtoMessage() {}
+
+func (x *StartBulkJobResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_notifier_v1_notifier_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StartBulkJobResponse.ProtoReflect.Descriptor instead.
+func (*StartBulkJobResponse) Descriptor() ([]byte, []int) {
+	return file_notifier_v1_notifier_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *StartBulkJobResponse) GetMatched() int32 {
+	if x != nil {
+		return x.Matched
+	}
+	return 0
+}
+
+func (x *StartBulkJobResponse) GetJob() *BulkJob {
+	if x != nil {
+		return x.Job
+	}
+	return nil
+}
+
+type GetBulkJobRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetBulkJobRequest) Reset() {
+	*x = GetBulkJobRequest{}
+	mi := &file_notifier_v1_notifier_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetBulkJobRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetBulkJobRequest) ProtoMessage() {}
+
+func (x *GetBulkJobRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_notifier_v1_notifier_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetBulkJobRequest.ProtoReflect.Descriptor instead.
+func (*GetBulkJobRequest) Descriptor() ([]byte, []int) {
+	return file_notifier_v1_notifier_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *GetBulkJobRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+// BulkJob cancels or reschedules notifications in the background. Jobs are kept for
+// 7 days after their last update.
+type BulkJob struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Id     string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Action BulkAction             `protobuf:"varint,2,opt,name=action,proto3,enum=notifier.v1.BulkAction" json:"action,omitempty"`
+	Status BulkJobStatus          `protobuf:"varint,3,opt,name=status,proto3,enum=notifier.v1.BulkJobStatus" json:"status,omitempty"`
+	// The new send time of a reschedule job.
+	ScheduledAt *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=scheduled_at,json=scheduledAt,proto3" json:"scheduled_at,omitempty"`
+	// The new time zone of a reschedule job, if it replaces those of the notifications.
+	Timezone *string `protobuf:"bytes,5,opt,name=timezone,proto3,oneof" json:"timezone,omitempty"`
+	// The number of notifications matching the filter when the job started.
+	Total int32 `protobuf:"varint,6,opt,name=total,proto3" json:"total,omitempty"`
+	// The number of notifications changed so far.
+	Processed int32 `protobuf:"varint,7,opt,name=processed,proto3" json:"processed,omitempty"`
+	// The number of rescheduled notifications that could not be queued again.
+	Failed int32 `protobuf:"varint,8,opt,name=failed,proto3" json:"failed,omitempty"`
+	// Why a failed job stopped early.
+	Error         string                 `protobuf:"bytes,9,opt,name=error,proto3" json:"error,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,11,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	FinishedAt    *timestamppb.Timestamp `protobuf:"bytes,12,opt,name=finished_at,json=finishedAt,proto3" json:"finished_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BulkJob) Reset() {
+	*x = BulkJob{}
+	mi := &file_notifier_v1_notifier_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BulkJob) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BulkJob) ProtoMessage() {}
+
+func (x *BulkJob) ProtoReflect() protoreflect.Message {
+	mi := &file_notifier_v1_notifier_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BulkJob.ProtoReflect.Descriptor instead.
+func (*BulkJob) Descriptor() ([]byte, []int) {
+	return file_notifier_v1_notifier_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *BulkJob) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *BulkJob) GetAction() BulkAction {
+	if x != nil {
+		return x.Action
+	}
+	return BulkAction_BULK_ACTION_UNSPECIFIED
+}
+
+func (x *BulkJob) GetStatus() BulkJobStatus {
+	if x != nil {
+		return x.Status
+	}
+	return BulkJobStatus_BULK_JOB_STATUS_UNSPECIFIED
+}
+
+func (x *BulkJob) GetScheduledAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ScheduledAt
+	}
+	return nil
+}
+
+func (x *BulkJob) GetTimezone() string {
+	if x != nil && x.Timezone != nil {
+		return *x.Timezone
+	}
+	return ""
+}
+
+func (x *BulkJob) GetTotal() int32 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+func (x *BulkJob) GetProcessed() int32 {
+	if x != nil {
+		return x.Processed
+	}
+	return 0
+}
+
+func (x *BulkJob) GetFailed() int32 {
+	if x != nil {
+		return x.Failed
+	}
+	return 0
+}
+
+func (x *BulkJob) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *BulkJob) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *BulkJob) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+func (x *BulkJob) GetFinishedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.FinishedAt
+	}
+	return nil
+}
+
+var File_notifier_v1_notifier_proto protoreflect.FileDescriptor
+
+const file_notifier_v1_notifier_proto_rawDesc = "" +
+	"\n" +
+	"\x1anotifier/v1/notifier.proto\x12\vnotifier.v1\x1a\x1cgoogle/protobuf/struct.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\xe0\x05\n" +
+	"\fNotification\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12+\n" +
+	"\x06status\x18\x02 \x01(\x0e2\x13.notifier.v1.StatusR\x06status\x12.\n" +
+	"\achannel\x18\x03 \x01(\x0e2\x14.notifier.v1.ChannelR\achannel\x12\x18\n" +
+	"\asubject\x18\x04 \x01(\tR\asubject\x12=\n" +
+	"\fscheduled_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\vscheduledAt\x129\n" +
+	"\n" +
+	"created_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x12&\n" +
+	"\fcallback_url\x18\a \x01(\tH\x00R\vcallbackUrl\x88\x01\x01\x12\x1c\n" +
+	"\trecipient\x18\b \x01(\tR\trecipient\x12\x1a\n" +
+	"\battempts\x18\t \x01(\x05R\battempts\x123\n" +
+	"\asent_at\x18\n" +
+	" \x01(\v2\x1a.google.protobuf.TimestampR\x06sentAt\x12\x1f\n" +
+	"\btimezone\x18\v \x01(\tH\x01R\btimezone\x88\x01\x01\x12\x1d\n" +
+	"\n" +
+	"local_time\x18\f \x01(\tR\tlocalTime\x12D\n" +
+	"\x0fdelivery_window\x18\r \x01(\v2\x1b.notifier.v1.DeliveryWindowR\x0edeliveryWindow\x121\n" +
+	"\bpriority\x18\x0e \x01(\x0e2\x15.notifier.v1.PriorityR\bpriority\x12\x12\n" +
+	"\x04tags\x18\x0f \x03(\tR\x04tags\x123\n" +
+	"\bmetadata\x18\x10 \x01(\v2\x17.google.protobuf.StructR\bmetadata\x12\x18\n" +
+	"\aversion\x18\x11 \x01(\x05R\aversionB\x0f\n" +
+	"\r_callback_urlB\v\n" +
+	"\t_timezone\"\xa9\x01\n" +
+	"\x0eDeliveryWindow\x12\x1a\n" +
+	"\btimezone\x18\x01 \x01(\tR\btimezone\x12\x12\n" +
+	"\x04days\x18\x02 \x03(\tR\x04days\x12-\n" +
+	"\x05hours\x18\x03 \x01(\v2\x17.notifier.v1.ClockRangeR\x05hours\x128\n" +
+	"\vquiet_hours\x18\x04 \x01(\v2\x17.notifier.v1.ClockRangeR\n" +
+	"quietHours\"4\n" +
+	"\n" +
+	"ClockRange\x12\x14\n" +
+	"\x05start\x18\x01 \x01(\tR\x05start\x12\x10\n" +
+	"\x03end\x18\x02 \x01(\tR\x03end\"\xf3\x04\n" +
+	"\x19CreateNotificationRequest\x12\x1c\n" +
+	"\trecipient\x18\x01 \x01(\tR\trecipient\x12.\n" +
+	"\achannel\x18\x02 \x01(\x0e2\x14.notifier.v1.ChannelR\achannel\x12\x18\n" +
+	"\asubject\x18\x03 \x01(\tR\asubject\x12\x18\n" +
+	"\amessage\x18\x04 \x01(\tR\amessage\x12=\n" +
+	"\fscheduled_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\vscheduledAt\x12 \n" +
+	"\tauthor_id\x18\x06 \x01(\tH\x00R\bauthorId\x88\x01\x01\x12&\n" +
+	"\fcallback_url\x18\a \x01(\tH\x01R\vcallbackUrl\x88\x01\x01\x12\x1d\n" +
+	"\n" +
+	"local_time\x18\b \x01(\tR\tlocalTime\x12\x1a\n" +
+	"\btimezone\x18\t \x01(\tR\btimezone\x12D\n" +
+	"\x0fdelivery_window\x18\n" +
+	" \x01(\v2\x1b.notifier.v1.DeliveryWindowR\x0edeliveryWindow\x12\x14\n" +
+	"\x05delay\x18\v \x01(\tR\x05delay\x12\x19\n" +
+	"\bsend_now\x18\f \x01(\bR\asendNow\x121\n" +
+	"\bpriority\x18\r \x01(\x0e2\x15.notifier.v1.PriorityR\bpriority\x12\x12\n" +
+	"\x04tags\x18\x0e \x03(\tR\x04tags\x123\n" +
+	"\bmetadata\x18\x0f \x01(\v2\x17.google.protobuf.StructR\bmetadataB\f\n" +
+	"\n" +
+	"_author_idB\x0f\n" +
+	"\r_callback_url\"(\n" +
+	"\x16GetNotificationRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"V\n" +
+	"\x19CancelNotificationRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1d\n" +
+	"\aversion\x18\x02 \x01(\x05H\x00R\aversion\x88\x01\x01B\n" +
+	"\n" +
+	"\b_version\"\x1c\n" +
+	"\x1aCancelNotificationResponse\"\xd4\x01\n" +
+	"\x1dRescheduleNotificationRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12=\n" +
+	"\fscheduled_at\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\vscheduledAt\x12\x1d\n" +
+	"\n" +
+	"local_time\x18\x03 \x01(\tR\tlocalTime\x12\x1a\n" +
+	"\btimezone\x18\x04 \x01(\tR\btimezone\x12\x1d\n" +
+	"\aversion\x18\x05 \x01(\x05H\x00R\aversion\x88\x01\x01B\n" +
+	"\n" +
+	"\b_version\"\xd6\x03\n" +
+	"\x18ListNotificationsRequest\x12+\n" +
+	"\x06status\x18\x01 \x01(\x0e2\x13.notifier.v1.StatusR\x06status\x12.\n" +
+	"\achannel\x18\x02 \x01(\x0e2\x14.notifier.v1.ChannelR\achannel\x12 \n" +
+	"\tauthor_id\x18\x03 \x01(\tH\x00R\bauthorId\x88\x01\x01\x12A\n" +
+	"\x0escheduled_from\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\rscheduledFrom\x12=\n" +
+	"\fscheduled_to\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\vscheduledTo\x12\x1b\n" +
+	"\tpage_size\x18\x06 \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\a \x01(\tR\tpageToken\x12\x1b\n" +
+	"\x06search\x18\b \x01(\tH\x01R\x06search\x88\x01\x01\x12\x12\n" +
+	"\x04tags\x18\t \x03(\tR\x04tags\x123\n" +
+	"\bmetadata\x18\n" +
+	" \x01(\v2\x17.google.protobuf.StructR\bmetadataB\f\n" +
+	"\n" +
+	"_author_idB\t\n" +
+	"\a_search\"\x84\x01\n" +
+	"\x19ListNotificationsResponse\x12?\n" +
+	"\rnotifications\x18\x01 \x03(\v2\x19.notifier.v1.NotificationR\rnotifications\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\"o\n" +
+	"\x1fBatchCreateNotificationsRequest\x12L\n" +
+	"\rnotifications\x18\x01 \x03(\v2&.notifier.v1.CreateNotificationRequestR\rnotifications\"\\\n" +
+	" BatchCreateNotificationsResponse\x128\n" +
+	"\aresults\x18\x01 \x03(\v2\x1e.notifier.v1.BatchCreateResultR\aresults\"\x95\x01\n" +
+	"\x11BatchCreateResult\x12?\n" +
+	"\fnotification\x18\x01 \x01(\v2\x19.notifier.v1.NotificationH\x00R\fnotification\x125\n" +
+	"\x05error\x18\x02 \x01(\v2\x1d.notifier.v1.BatchCreateErrorH\x00R\x05errorB\b\n" +
+	"\x06result\"X\n" +
+	"\x10BatchCreateError\x12\x12\n" +
+	"\x04code\x18\x01 \x01(\x05R\x04code\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12\x16\n" +
+	"\x06reason\x18\x03 \x01(\tR\x06reason\"z\n" +
+	"\x12WatchStatusRequest\x12,\n" +
+	"\x0fnotification_id\x18\x01 \x01(\tH\x00R\x0enotificationId\x88\x01\x01\x12\"\n" +
+	"\rlast_event_id\x18\x02 \x01(\tR\vlastEventIdB\x12\n" +
+	"\x10_notification_id\"\xcc\x01\n" +
+	"\vStatusEvent\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12'\n" +
+	"\x0fnotification_id\x18\x02 \x01(\tR\x0enotificationId\x12+\n" +
+	"\x06status\x18\x03 \x01(\x0e2\x13.notifier.v1.StatusR\x06status\x12\x1a\n" +
+	"\battempts\x18\x04 \x01(\x05R\battempts\x12;\n" +
+	"\voccurred_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"occurredAt\"\x94\x03\n" +
+	"\x12NotificationFilter\x12+\n" +
+	"\x06status\x18\x01 \x01(\x0e2\x13.notifier.v1.StatusR\x06status\x12.\n" +
+	"\achannel\x18\x02 \x01(\x0e2\x14.notifier.v1.ChannelR\achannel\x12 \n" +
+	"\tauthor_id\x18\x03 \x01(\tH\x00R\bauthorId\x88\x01\x01\x12A\n" +
+	"\x0escheduled_from\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\rscheduledFrom\x12=\n" +
+	"\fscheduled_to\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\vscheduledTo\x12\x1b\n" +
+	"\x06search\x18\x06 \x01(\tH\x01R\x06search\x88\x01\x01\x12\x12\n" +
+	"\x04tags\x18\a \x03(\tR\x04tags\x123\n" +
+	"\bmetadata\x18\b \x01(\v2\x17.google.protobuf.StructR\bmetadataB\f\n" +
+	"\n" +
+	"_author_idB\t\n" +
+	"\a_search\"n\n" +
+	"\x1aCancelNotificationsRequest\x127\n" +
+	"\x06filter\x18\x01 \x01(\v2\x1f.notifier.v1.NotificationFilterR\x06filter\x12\x17\n" +
+	"\adry_run\x18\x02 \x01(\bR\x06dryRun\"\xec\x01\n" +
+	"\x1eRescheduleNotificationsRequest\x127\n" +
+	"\x06filter\x18\x01 \x01(\v2\x1f.notifier.v1.NotificationFilterR\x06filter\x12=\n" +
+	"\fscheduled_at\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\vscheduledAt\x12\x1d\n" +
+	"\n" +
+	"local_time\x18\x03 \x01(\tR\tlocalTime\x12\x1a\n" +
+	"\btimezone\x18\x04 \x01(\tR\btimezone\x12\x17\n" +
+	"\adry_run\x18\x05 \x01(\bR\x06dryRun\"X\n" +
+	"\x14StartBulkJobResponse\x12\x18\n" +
+	"\amatched\x18\x01 \x01(\x05R\amatched\x12&\n" +
+	"\x03job\x18\x02 \x01(\v2\x14.notifier.v1.BulkJobR\x03job\"#\n" +
+	"\x11GetBulkJobRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\x80\x04\n" +
+	"\aBulkJob\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12/\n" +
+	"\x06action\x18\x02 \x01(\x0e2\x17.notifier.v1.BulkActionR\x06action\x122\n" +
+	"\x06status\x18\x03 \x01(\x0e2\x1a.notifier.v1.BulkJobStatusR\x06status\x12=\n" +
+	"\fscheduled_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\vscheduledAt\x12\x1f\n" +
+	"\btimezone\x18\x05 \x01(\tH\x00R\btimezone\x88\x01\x01\x12\x14\n" +
+	"\x05total\x18\x06 \x01(\x05R\x05total\x12\x1c\n" +
+	"\tprocessed\x18\a \x01(\x05R\tprocessed\x12\x16\n" +
+	"\x06failed\x18\b \x01(\x05R\x06failed\x12\x14\n" +
+	"\x05error\x18\t \x01(\tR\x05error\x129\n" +
+	"\n" +
+	"created_at\x18\n" +
+	" \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\v \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x12;\n" +
+	"\vfinished_at\x18\f \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"finishedAtB\v\n" +
+	"\t_timezone*K\n" +
+	"\aChannel\x12\x17\n" +
+	"\x13CHANNEL_UNSPECIFIED\x10\x00\x12\x11\n" +
+	"\rCHANNEL_EMAIL\x10\x01\x12\x14\n" +
+	"\x10CHANNEL_TELEGRAM\x10\x02*\x9b\x01\n" +
+	"\x06Status\x12\x16\n" +
+	"\x12STATUS_UNSPECIFIED\x10\x00\x12\x14\n" +
+	"\x10STATUS_SCHEDULED\x10\x01\x12\x0f\n" +
+	"\vSTATUS_SENT\x10\x02\x12\x11\n" +
+	"\rSTATUS_FAILED\x10\x03\x12\x14\n" +
+	"\x10STATUS_CANCELLED\x10\x04\x12\x12\n" +
+	"\x0eSTATUS_EXPIRED\x10\x05\x12\x15\n" +
+	"\x11STATUS_PROCESSING\x10\x06*u\n" +
+	"\bPriority\x12\x18\n" +
+	"\x14PRIORITY_UNSPECIFIED\x10\x00\x12\x10\n" +
+	"\fPRIORITY_LOW\x10\x01\x12\x13\n" +
+	"\x0fPRIORITY_NORMAL\x10\x02\x12\x11\n" +
+	"\rPRIORITY_HIGH\x10\x03\x12\x15\n" +
+	"\x11PRIORITY_CRITICAL\x10\x04*]\n" +
+	"\n" +
+	"BulkAction\x12\x1b\n" +
+	"\x17BULK_ACTION_UNSPECIFIED\x10\x00\x12\x16\n" +
+	"\x12BULK_ACTION_CANCEL\x10\x01\x12\x1a\n" +
+	"\x16BULK_ACTION_RESCHEDULE\x10\x02*\x88\x01\n" +
+	"\rBulkJobStatus\x12\x1f\n" +
+	"\x1bBULK_JOB_STATUS_UNSPECIFIED\x10\x00\x12\x1b\n" +
+	"\x17BULK_JOB_STATUS_RUNNING\x10\x01\x12\x1d\n" +
+	"\x19BULK_JOB_STATUS_COMPLETED\x10\x02\x12\x1a\n" +
+	"\x16BULK_JOB_STATUS_FAILED\x10\x032\xc4\a\n" +
+	"\x13NotificationService\x12W\n" +
+	"\x12CreateNotification\x12&.notifier.v1.CreateNotificationRequest\x1a\x19.notifier.v1.Notification\x12Q\n" +
+	"\x0fGetNotification\x12#.notifier.v1.GetNotificationRequest\x1a\x19.notifier.v1.Notification\x12e\n" +
+	"\x12CancelNotification\x12&.notifier.v1.CancelNotificationRequest\x1a'.notifier.v1.CancelNotificationResponse\x12_\n" +
+	"\x16RescheduleNotification\x12*.notifier.v1.RescheduleNotificationRequest\x1a\x19.notifier.v1.Notification\x12b\n" +
+	"\x11ListNotifications\x12%.notifier.v1.ListNotificationsRequest\x1a&.notifier.v1.ListNotificationsResponse\x12w\n" +
+	"\x18BatchCreateNotifications\x12,.notifier.v1.BatchCreateNotificationsRequest\x1a-.notifier.v1.BatchCreateNotificationsResponse\x12J\n" +
+	"\vWatchStatus\x12\x1f.notifier.v1.WatchStatusRequest\x1a\x18.notifier.v1.StatusEvent0\x01\x12a\n" +
+	"\x13CancelNotifications\x12'.notifier.v1.CancelNotificationsRequest\x1a!.notifier.v1.StartBulkJobResponse\x12i\n" +
+	"\x17RescheduleNotifications\x12+.notifier.v1.RescheduleNotificationsRequest\x1a!.notifier.v1.StartBulkJobResponse\x12B\n" +
+	"\n" +
+	"GetBulkJob\x12\x1e.notifier.v1.GetBulkJobRequest\x1a\x14.notifier.v1.BulkJobBHZFgithub.com/ilindan-dev/delayed-notifier/pkg/api/notifier/v1;notifierv1b\x06proto3"
+
+var (
+	file_notifier_v1_notifier_proto_rawDescOnce sync.Once
+	file_notifier_v1_notifier_proto_rawDescData []byte
+)
+
+func file_notifier_v1_notifier_proto_rawDescGZIP() []byte {
+	file_notifier_v1_notifier_proto_rawDescOnce.Do(func() {
+		file_notifier_v1_notifier_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_notifier_v1_notifier_proto_rawDesc), len(file_notifier_v1_notifier_proto_rawDesc)))
+	})
+	return file_notifier_v1_notifier_proto_rawDescData
+}
+
+var file_notifier_v1_notifier_proto_enumTypes = make([]protoimpl.EnumInfo, 5)
+var file_notifier_v1_notifier_proto_msgTypes = make([]protoimpl.MessageInfo, 22)
+var file_notifier_v1_notifier_proto_goTypes = []any{
+	(Channel)(0),                             // 0: notifier.v1.Channel
+	(Status)(0),                              // 1: notifier.v1.Status
+	(Priority)(0),                            // 2: notifier.v1.Priority
+	(BulkAction)(0),                          // 3: notifier.v1.BulkAction
+	(BulkJobStatus)(0),                       // 4: notifier.v1.BulkJobStatus
+	(*Notification)(nil),                     // 5: notifier.v1.Notification
+	(*DeliveryWindow)(nil),                   // 6: notifier.v1.DeliveryWindow
+	(*ClockRange)(nil),                       // 7: notifier.v1.ClockRange
+	(*CreateNotificationRequest)(nil),        // 8: notifier.v1.CreateNotificationRequest
+	(*GetNotificationRequest)(nil),           // 9: notifier.v1.GetNotificationRequest
+	(*CancelNotificationRequest)(nil),        // 10: notifier.v1.CancelNotificationRequest
+	(*CancelNotificationResponse)(nil),       // 11: notifier.v1.CancelNotificationResponse
+	(*RescheduleNotificationRequest)(nil),    // 12: notifier.v1.RescheduleNotificationRequest
+	(*ListNotificationsRequest)(nil),         // 13: notifier.v1.ListNotificationsRequest
+	(*ListNotificationsResponse)(nil),        // 14: notifier.v1.ListNotificationsResponse
+	(*BatchCreateNotificationsRequest)(nil),  // 15: notifier.v1.BatchCreateNotificationsRequest
+	(*BatchCreateNotificationsResponse)(nil), // 16: notifier.v1.BatchCreateNotificationsResponse
+	(*BatchCreateResult)(nil),                // 17: notifier.v1.BatchCreateResult
+	(*BatchCreateError)(nil),                 // 18: notifier.v1.BatchCreateError
+	(*WatchStatusRequest)(nil),               // 19: notifier.v1.WatchStatusRequest
+	(*StatusEvent)(nil),                      // 20: notifier.v1.StatusEvent
+	(*NotificationFilter)(nil),               // 21: notifier.v1.NotificationFilter
+	(*CancelNotificationsRequest)(nil),       // 22: notifier.v1.CancelNotificationsRequest
+	(*RescheduleNotificationsRequest)(nil),   // 23: notifier.v1.RescheduleNotificationsRequest
+	(*StartBulkJobResponse)(nil),             // 24: notifier.v1.StartBulkJobResponse
+	(*GetBulkJobRequest)(nil),                // 25: notifier.v1.GetBulkJobRequest
+	(*BulkJob)(nil),                          // 26: notifier.v1.BulkJob
+	(*timestamppb.Timestamp)(nil),            // 27: google.protobuf.Timestamp
+	(*structpb.Struct)(nil),                  // 28: google.protobuf.Struct
+}
+var file_notifier_v1_notifier_proto_depIdxs = []int32{
+	1,  // 0: notifier.v1.Notification.status:type_name -> notifier.v1.Status
+	0,  // 1: notifier.v1.Notification.channel:type_name -> notifier.v1.Channel
+	27, // 2: notifier.v1.Notification.scheduled_at:type_name -> google.protobuf.Timestamp
+	27, // 3: notifier.v1.Notification.created_at:type_name -> google.protobuf.Timestamp
+	27, // 4: notifier.v1.Notification.sent_at:type_name -> google.protobuf.Timestamp
+	6,  // 5: notifier.v1.Notification.delivery_window:type_name -> notifier.v1.DeliveryWindow
+	2,  // 6: notifier.v1.Notification.priority:type_name -> notifier.v1.Priority
+	28, // 7: notifier.v1.Notification.metadata:type_name -> google.protobuf.Struct
+	7,  // 8: notifier.v1.DeliveryWindow.hours:type_name -> notifier.v1.ClockRange
+	7,  // 9: notifier.v1.DeliveryWindow.quiet_hours:type_name -> notifier.v1.ClockRange
+	0,  // 10: notifier.v1.CreateNotificationRequest.channel:type_name -> notifier.v1.Channel
+	27, // 11: notifier.v1.CreateNotificationRequest.scheduled_at:type_name -> google.protobuf.Timestamp
+	6,  // 12: notifier.v1.CreateNotificationRequest.delivery_window:type_name -> notifier.v1.DeliveryWindow
+	2,  // 13: notifier.v1.CreateNotificationRequest.priority:type_name -> notifier.v1.Priority
+	28, // 14: notifier.v1.CreateNotificationRequest.metadata:type_name -> google.protobuf.Struct
+	27, // 15: notifier.v1.RescheduleNotificationRequest.scheduled_at:type_name -> google.protobuf.Timestamp
+	1,  // 16: notifier.v1.ListNotificationsRequest.status:type_name -> notifier.v1.Status
+	0,  // 17: notifier.v1.ListNotificationsRequest.channel:type_name -> notifier.v1.Channel
+	27, // 18: notifier.v1.ListNotificationsRequest.scheduled_from:type_name -> google.protobuf.Timestamp
+	27, // 19: notifier.v1.ListNotificationsRequest.scheduled_to:type_name -> google.protobuf.Timestamp
+	28, // 20: notifier.v1.ListNotificationsRequest.metadata:type_name -> google.protobuf.Struct
+	5,  // 21: notifier.v1.ListNotificationsResponse.notifications:type_name -> notifier.v1.Notification
+	8,  // 22: notifier.v1.BatchCreateNotificationsRequest.notifications:type_name -> notifier.v1.CreateNotificationRequest
+	17, // 23: notifier.v1.BatchCreateNotificationsResponse.results:type_name -> notifier.v1.BatchCreateResult
+	5,  // 24: notifier.v1.BatchCreateResult.notification:type_name -> notifier.v1.Notification
+	18, // 25: notifier.v1.BatchCreateResult.error:type_name -> notifier.v1.BatchCreateError
+	1,  // 26: notifier.v1.StatusEvent.status:type_name -> notifier.v1.Status
+	27, // 27: notifier.v1.StatusEvent.occurred_at:type_name -> google.protobuf.Timestamp
+	1,  // 28: notifier.v1.NotificationFilter.status:type_name -> notifier.v1.Status
+	0,  // 29: notifier.v1.NotificationFilter.channel:type_name -> notifier.v1.Channel
+	27, // 30: notifier.v1.NotificationFilter.scheduled_from:type_name -> google.protobuf.Timestamp
+	27, // 31: notifier.v1.NotificationFilter.scheduled_to:type_name -> google.protobuf.Timestamp
+	28, // 32: notifier.v1.NotificationFilter.metadata:type_name -> google.protobuf.Struct
+	21, // 33: notifier.v1.CancelNotificationsRequest.filter:type_name -> notifier.v1.NotificationFilter
+	21, // 34: notifier.v1.RescheduleNotificationsRequest.filter:type_name -> notifier.v1.NotificationFilter
+	27, // 35: notifier.v1.RescheduleNotificationsRequest.scheduled_at:type_name -> google.protobuf.Timestamp
+	26, // 36: notifier.v1.StartBulkJobResponse.job:type_name -> notifier.v1.BulkJob
+	3,  // 37: notifier.v1.BulkJob.action:type_name -> notifier.v1.BulkAction
+	4,  // 38: notifier.v1.BulkJob.status:type_name -> notifier.v1.BulkJobStatus
+	27, // 39: notifier.v1.BulkJob.scheduled_at:type_name -> google.protobuf.Timestamp
+	27, // 40: notifier.v1.BulkJob.created_at:type_name -> google.protobuf.Timestamp
+	27, // 41: notifier.v1.BulkJob.updated_at:type_name -> google.protobuf.Timestamp
+	27, // 42: notifier.v1.BulkJob.finished_at:type_name -> google.protobuf.Timestamp
+	8,  // 43: notifier.v1.NotificationService.CreateNotification:input_type -> notifier.v1.CreateNotificationRequest
+	9,  // 44: notifier.v1.NotificationService.GetNotification:input_type -> notifier.v1.GetNotificationRequest
+	10, // 45: notifier.v1.NotificationService.CancelNotification:input_type -> notifier.v1.CancelNotificationRequest
+	12, // 46: notifier.v1.NotificationService.RescheduleNotification:input_type -> notifier.v1.RescheduleNotificationRequest
+	13, // 47: notifier.v1.NotificationService.ListNotifications:input_type -> notifier.v1.ListNotificationsRequest
+	15, // 48: notifier.v1.NotificationService.BatchCreateNotifications:input_type -> notifier.v1.BatchCreateNotificationsRequest
+	19, // 49: notifier.v1.NotificationService.WatchStatus:input_type -> notifier.v1.WatchStatusRequest
+	22, // 50: notifier.v1.NotificationService.CancelNotifications:input_type -> notifier.v1.CancelNotificationsRequest
+	23, // 51: notifier.v1.NotificationService.RescheduleNotifications:input_type -> notifier.v1.RescheduleNotificationsRequest
+	25, // 52: notifier.v1.NotificationService.GetBulkJob:input_type -> notifier.v1.GetBulkJobRequest
+	5,  // 53: notifier.v1.NotificationService.CreateNotification:output_type -> notifier.v1.Notification
+	5,  // 54: notifier.v1.NotificationService.GetNotification:output_type -> notifier.v1.Notification
+	11, // 55: notifier.v1.NotificationService.CancelNotification:output_type -> notifier.v1.CancelNotificationResponse
+	5,  // 56: notifier.v1.NotificationService.RescheduleNotification:output_type -> notifier.v1.Notification
+	14, // 57: notifier.v1.NotificationService.ListNotifications:output_type -> notifier.v1.ListNotificationsResponse
+	16, // 58: notifier.v1.NotificationService.BatchCreateNotifications:output_type -> notifier.v1.BatchCreateNotificationsResponse
+	20, // 59: notifier.v1.NotificationService.WatchStatus:output_type -> notifier.v1.StatusEvent
+	24, // 60: notifier.v1.NotificationService.CancelNotifications:output_type -> notifier.v1.StartBulkJobResponse
+	24, // 61: notifier.v1.NotificationService.RescheduleNotifications:output_type -> notifier.v1.StartBulkJobResponse
+	26, // 62: notifier.v1.NotificationService.GetBulkJob:output_type -> notifier.v1.BulkJob
+	53, // [53:63] is the sub-list for method output_type
+	43, // [43:53] is the sub-list for method input_type
+	43, // [43:43] is the sub-list for extension type_name
+	43, // [43:43] is the sub-list for extension extendee
+	0,  // [0:43] is the sub-list for field type_name
+}
+
+func init() { file_notifier_v1_notifier_proto_init() }
+func file_notifier_v1_notifier_proto_init() {
+	if File_notifier_v1_notifier_proto != nil {
+		return
+	}
+	file_notifier_v1_notifier_proto_msgTypes[0].OneofWrappers = []any{}
+	file_notifier_v1_notifier_proto_msgTypes[3].OneofWrappers = []any{}
+	file_notifier_v1_notifier_proto_msgTypes[5].OneofWrappers = []any{}
+	file_notifier_v1_notifier_proto_msgTypes[7].OneofWrappers = []any{}
+	file_notifier_v1_notifier_proto_msgTypes[8].OneofWrappers = []any{}
+	file_notifier_v1_notifier_proto_msgTypes[12].OneofWrappers = []any{
+		(*BatchCreateResult_Notification)(nil),
+		(*BatchCreateResult_Error)(nil),
+	}
+	file_notifier_v1_notifier_proto_msgTypes[14].OneofWrappers = []any{}
+	file_notifier_v1_notifier_proto_msgTypes[16].OneofWrappers = []any{}
+	file_notifier_v1_notifier_proto_msgTypes[21].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_notifier_v1_notifier_proto_rawDesc), len(file_notifier_v1_notifier_proto_rawDesc)),
+			NumEnums:      5,
+			NumMessages:   22,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_notifier_v1_notifier_proto_goTypes,
+		DependencyIndexes: file_notifier_v1_notifier_proto_depIdxs,
+		EnumInfos:         file_notifier_v1_notifier_proto_enumTypes,
+		MessageInfos:      file_notifier_v1_notifier_proto_msgTypes,
+	}.Build()
+	File_notifier_v1_notifier_proto = out.File
+	file_notifier_v1_notifier_proto_goTypes = nil
+	file_notifier_v1_notifier_proto_depIdxs = nil
+}
@@ -0,0 +1,497 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: notifier/v1/notifier.proto
+
+package notifierv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	NotificationService_CreateNotification_FullMethodName       = "/notifier.v1.NotificationService/CreateNotification"
+	NotificationService_GetNotification_FullMethodName          = "/notifier.v1.NotificationService/GetNotification"
+	NotificationService_CancelNotification_FullMethodName       = "/notifier.v1.NotificationService/CancelNotification"
+	NotificationService_RescheduleNotification_FullMethodName   = "/notifier.v1.NotificationService/RescheduleNotification"
+	NotificationService_ListNotifications_FullMethodName        = "/notifier.v1.NotificationService/ListNotifications"
+	NotificationService_BatchCreateNotifications_FullMethodName = "/notifier.v1.NotificationService/BatchCreateNotifications"
+	NotificationService_WatchStatus_FullMethodName              = "/notifier.v1.NotificationService/WatchStatus"
+	NotificationService_CancelNotifications_FullMethodName      = "/notifier.v1.NotificationService/CancelNotifications"
+	NotificationService_RescheduleNotifications_FullMethodName  = "/notifier.v1.NotificationService/RescheduleNotifications"
+	NotificationService_GetBulkJob_FullMethodName               = "/notifier.v1.NotificationService/GetBulkJob"
+)
+
+// NotificationServiceClient is the client API for NotificationService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// NotificationService schedules delayed notifications. It mirrors the REST API under /api/v1.
+// Callers authenticate with an "x-api-key" or a bearer "authorization" metadata entry.
+type NotificationServiceClient interface {
+	// CreateNotification schedules a new notification. Requires the create scope.
+	CreateNotification(ctx context.Context, in *CreateNotificationRequest, opts ...grpc.CallOption) (*Notification, error)
+	// GetNotification returns a notification by ID. Requires the read scope.
+	GetNotification(ctx context.Context, in *GetNotificationRequest, opts ...grpc.CallOption) (*Notification, error)
+	// CancelNotification cancels a scheduled notification. Requires the cancel scope.
+	CancelNotification(ctx context.Context, in *CancelNotificationRequest, opts ...grpc.CallOption) (*CancelNotificationResponse, error)
+	// RescheduleNotification moves a scheduled notification to a new time. Requires the create scope.
+	RescheduleNotification(ctx context.Context, in *RescheduleNotificationRequest, opts ...grpc.CallOption) (*Notification, error)
+	// ListNotifications lists notifications, newest first. Requires the read scope.
+	ListNotifications(ctx context.Context, in *ListNotificationsRequest, opts ...grpc.CallOption) (*ListNotificationsResponse, error)
+	// BatchCreateNotifications creates several notifications independently. Requires the create scope.
+	BatchCreateNotifications(ctx context.Context, in *BatchCreateNotificationsRequest, opts ...grpc.CallOption) (*BatchCreateNotificationsResponse, error)
+	// WatchStatus streams status changes as they happen. Requires the read scope.
+	WatchStatus(ctx context.Context, in *WatchStatusRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[StatusEvent], error)
+	// CancelNotifications starts a background job cancelling every scheduled notification
+	// matching a filter. Requires the cancel scope.
+	CancelNotifications(ctx context.Context, in *CancelNotificationsRequest, opts ...grpc.CallOption) (*StartBulkJobResponse, error)
+	// RescheduleNotifications starts a background job moving every scheduled notification
+	// matching a filter to a new time. Requires the create scope.
+	RescheduleNotifications(ctx context.Context, in *RescheduleNotificationsRequest, opts ...grpc.CallOption) (*StartBulkJobResponse, error)
+	// GetBulkJob returns a bulk job and its progress. Requires the read scope.
+	GetBulkJob(ctx context.Context, in *GetBulkJobRequest, opts ...grpc.CallOption) (*BulkJob, error)
+}
+
+type notificationServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewNotificationServiceClient(cc grpc.ClientConnInterface) NotificationServiceClient {
+	return &notificationServiceClient{cc}
+}
+
+func (c *notificationServiceClient) CreateNotification(ctx context.Context, in *CreateNotificationRequest, opts ...grpc.CallOption) (*Notification, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Notification)
+	err := c.cc.Invoke(ctx, NotificationService_CreateNotification_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *notificationServiceClient) GetNotification(ctx context.Context, in *GetNotificationRequest, opts ...grpc.CallOption) (*Notification, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Notification)
+	err := c.cc.Invoke(ctx, NotificationService_GetNotification_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *notificationServiceClient) CancelNotification(ctx context.Context, in *CancelNotificationRequest, opts ...grpc.CallOption) (*CancelNotificationResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CancelNotificationResponse)
+	err := c.cc.Invoke(ctx, NotificationService_CancelNotification_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *notificationServiceClient) RescheduleNotification(ctx context.Context, in *RescheduleNotificationRequest, opts ...grpc.CallOption) (*Notification, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Notification)
+	err := c.cc.Invoke(ctx, NotificationService_RescheduleNotification_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *notificationServiceClient) ListNotifications(ctx context.Context, in *ListNotificationsRequest, opts ...grpc.CallOption) (*ListNotificationsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListNotificationsResponse)
+	err := c.cc.Invoke(ctx, NotificationService_ListNotifications_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *notificationServiceClient) BatchCreateNotifications(ctx context.Context, in *BatchCreateNotificationsRequest, opts ...grpc.CallOption) (*BatchCreateNotificationsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BatchCreateNotificationsResponse)
+	err := c.cc.Invoke(ctx, NotificationService_BatchCreateNotifications_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *notificationServiceClient) WatchStatus(ctx context.Context, in *WatchStatusRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[StatusEvent], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &NotificationService_ServiceDesc.Streams[0], NotificationService_WatchStatus_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchStatusRequest, StatusEvent]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type NotificationService_WatchStatusClient = grpc.ServerStreamingClient[StatusEvent]
+
+func (c *notificationServiceClient) CancelNotifications(ctx context.Context, in *CancelNotificationsRequest, opts ...grpc.CallOption) (*StartBulkJobResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(StartBulkJobResponse)
+	err := c.cc.Invoke(ctx, NotificationService_CancelNotifications_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *notificationServiceClient) RescheduleNotifications(ctx context.Context, in *RescheduleNotificationsRequest, opts ...grpc.CallOption) (*StartBulkJobResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(StartBulkJobResponse)
+	err := c.cc.Invoke(ctx, NotificationService_RescheduleNotifications_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *notificationServiceClient) GetBulkJob(ctx context.Context, in *GetBulkJobRequest, opts ...grpc.CallOption) (*BulkJob, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BulkJob)
+	err := c.cc.Invoke(ctx, NotificationService_GetBulkJob_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// NotificationServiceServer is the server API for NotificationService service.
+// All implementations must embed UnimplementedNotificationServiceServer
+// for forward compatibility.
+//
+// NotificationService schedules delayed notifications. It mirrors the REST API under /api/v1.
+// Callers authenticate with an "x-api-key" or a bearer "authorization" metadata entry.
+type NotificationServiceServer interface {
+	// CreateNotification schedules a new notification. Requires the create scope.
+	CreateNotification(context.Context, *CreateNotificationRequest) (*Notification, error)
+	// GetNotification returns a notification by ID. Requires the read scope.
+	GetNotification(context.Context, *GetNotificationRequest) (*Notification, error)
+	// CancelNotification cancels a scheduled notification. Requires the cancel scope.
+	CancelNotification(context.Context, *CancelNotificationRequest) (*CancelNotificationResponse, error)
+	// RescheduleNotification moves a scheduled notification to a new time. Requires the create scope.
+	RescheduleNotification(context.Context, *RescheduleNotificationRequest) (*Notification, error)
+	// ListNotifications lists notifications, newest first. Requires the read scope.
+	ListNotifications(context.Context, *ListNotificationsRequest) (*ListNotificationsResponse, error)
+	// BatchCreateNotifications creates several notifications independently. Requires the create scope.
+	BatchCreateNotifications(context.Context, *BatchCreateNotificationsRequest) (*BatchCreateNotificationsResponse, error)
+	// WatchStatus streams status changes as they happen. Requires the read scope.
+	WatchStatus(*WatchStatusRequest, grpc.ServerStreamingServer[StatusEvent]) error
+	// CancelNotifications starts a background job cancelling every scheduled notification
+	// matching a filter. Requires the cancel scope.
+	CancelNotifications(context.Context, *CancelNotificationsRequest) (*StartBulkJobResponse, error)
+	// RescheduleNotifications starts a background job moving every scheduled notification
+	// matching a filter to a new time. Requires the create scope.
+	RescheduleNotifications(context.Context, *RescheduleNotificationsRequest) (*StartBulkJobResponse, error)
+	// GetBulkJob returns a bulk job and its progress. Requires the read scope.
+	GetBulkJob(context.Context, *GetBulkJobRequest) (*BulkJob, error)
+	mustEmbedUnimplementedNotificationServiceServer()
+}
+
+// UnimplementedNotificationServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedNotificationServiceServer struct{}
+
+func (UnimplementedNotificationServiceServer) CreateNotification(context.Context, *CreateNotificationRequest) (*Notification, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateNotification not implemented")
+}
+func (UnimplementedNotificationServiceServer) GetNotification(context.Context, *GetNotificationRequest) (*Notification, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetNotification not implemented")
+}
+func (UnimplementedNotificationServiceServer) CancelNotification(context.Context, *CancelNotificationRequest) (*CancelNotificationResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CancelNotification not implemented")
+}
+func (UnimplementedNotificationServiceServer) RescheduleNotification(context.Context, *RescheduleNotificationRequest) (*Notification, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RescheduleNotification not implemented")
+}
+func (UnimplementedNotificationServiceServer) ListNotifications(context.Context, *ListNotificationsRequest) (*ListNotificationsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListNotifications not implemented")
+}
+func (UnimplementedNotificationServiceServer) BatchCreateNotifications(context.Context, *BatchCreateNotificationsRequest) (*BatchCreateNotificationsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BatchCreateNotifications not implemented")
+}
+func (UnimplementedNotificationServiceServer) WatchStatus(*WatchStatusRequest, grpc.ServerStreamingServer[StatusEvent]) error {
+	return status.Errorf(codes.Unimplemented, "method WatchStatus not implemented")
+}
+func (UnimplementedNotificationServiceServer) CancelNotifications(context.Context, *CancelNotificationsRequest) (*StartBulkJobResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CancelNotifications not implemented")
+}
+func (UnimplementedNotificationServiceServer) RescheduleNotifications(context.Context, *RescheduleNotificationsRequest) (*StartBulkJobResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RescheduleNotifications not implemented")
+}
+func (UnimplementedNotificationServiceServer) GetBulkJob(context.Context, *GetBulkJobRequest) (*BulkJob, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetBulkJob not implemented")
+}
+func (UnimplementedNotificationServiceServer) mustEmbedUnimplementedNotificationServiceServer() {}
+func (UnimplementedNotificationServiceServer) testEmbeddedByValue()                             {}
+
+// UnsafeNotificationServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to NotificationServiceServer will
+// result in compilation errors.
+type UnsafeNotificationServiceServer interface {
+	mustEmbedUnimplementedNotificationServiceServer()
+}
+
+func RegisterNotificationServiceServer(s grpc.ServiceRegistrar, srv NotificationServiceServer) {
+	// If the following call pancis, it indicates UnimplementedNotificationServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&NotificationService_ServiceDesc, srv)
+}
+
+func _NotificationService_CreateNotification_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateNotificationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NotificationServiceServer).CreateNotification(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NotificationService_CreateNotification_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NotificationServiceServer).CreateNotification(ctx, req.(*CreateNotificationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NotificationService_GetNotification_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetNotificationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NotificationServiceServer).GetNotification(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NotificationService_GetNotification_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NotificationServiceServer).GetNotification(ctx, req.(*GetNotificationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NotificationService_CancelNotification_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CancelNotificationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NotificationServiceServer).CancelNotification(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NotificationService_CancelNotification_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NotificationServiceServer).CancelNotification(ctx, req.(*CancelNotificationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NotificationService_RescheduleNotification_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RescheduleNotificationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NotificationServiceServer).RescheduleNotification(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NotificationService_RescheduleNotification_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NotificationServiceServer).RescheduleNotification(ctx, req.(*RescheduleNotificationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NotificationService_ListNotifications_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListNotificationsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NotificationServiceServer).ListNotifications(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NotificationService_ListNotifications_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NotificationServiceServer).ListNotifications(ctx, req.(*ListNotificationsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NotificationService_BatchCreateNotifications_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchCreateNotificationsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NotificationServiceServer).BatchCreateNotifications(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NotificationService_BatchCreateNotifications_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NotificationServiceServer).BatchCreateNotifications(ctx, req.(*BatchCreateNotificationsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NotificationService_WatchStatus_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchStatusRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(NotificationServiceServer).WatchStatus(m, &grpc.GenericServerStream[WatchStatusRequest, StatusEvent]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type NotificationService_WatchStatusServer = grpc.ServerStreamingServer[StatusEvent]
+
+func _NotificationService_CancelNotifications_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CancelNotificationsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NotificationServiceServer).CancelNotifications(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NotificationService_CancelNotifications_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NotificationServiceServer).CancelNotifications(ctx, req.(*CancelNotificationsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NotificationService_RescheduleNotifications_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RescheduleNotificationsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NotificationServiceServer).RescheduleNotifications(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NotificationService_RescheduleNotifications_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NotificationServiceServer).RescheduleNotifications(ctx, req.(*RescheduleNotificationsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NotificationService_GetBulkJob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetBulkJobRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NotificationServiceServer).GetBulkJob(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NotificationService_GetBulkJob_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NotificationServiceServer).GetBulkJob(ctx, req.(*GetBulkJobRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// NotificationService_ServiceDesc is the grpc.ServiceDesc for NotificationService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var NotificationService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "notifier.v1.NotificationService",
+	HandlerType: (*NotificationServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CreateNotification",
+			Handler:    _NotificationService_CreateNotification_Handler,
+		},
+		{
+			MethodName: "GetNotification",
+			Handler:    _NotificationService_GetNotification_Handler,
+		},
+		{
+			MethodName: "CancelNotification",
+			Handler:    _NotificationService_CancelNotification_Handler,
+		},
+		{
+			MethodName: "RescheduleNotification",
+			Handler:    _NotificationService_RescheduleNotification_Handler,
+		},
+		{
+			MethodName: "ListNotifications",
+			Handler:    _NotificationService_ListNotifications_Handler,
+		},
+		{
+			MethodName: "BatchCreateNotifications",
+			Handler:    _NotificationService_BatchCreateNotifications_Handler,
+		},
+		{
+			MethodName: "CancelNotifications",
+			Handler:    _NotificationService_CancelNotifications_Handler,
+		},
+		{
+			MethodName: "RescheduleNotifications",
+			Handler:    _NotificationService_RescheduleNotifications_Handler,
+		},
+		{
+			MethodName: "GetBulkJob",
+			Handler:    _NotificationService_GetBulkJob_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchStatus",
+			Handler:       _NotificationService_WatchStatus_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "notifier/v1/notifier.proto",
+}
@@ -0,0 +1,48 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// QueueStats describes the current state of a broker queue.
+type QueueStats struct {
+	Name      string `json:"name"`
+	Messages  int    `json:"messages"`
+	Consumers int    `json:"consumers"`
+}
+
+// DeadLetter is a message the worker gave up on.
+type DeadLetter struct {
+	MessageID string    `json:"message_id"`
+	Reason    string    `json:"reason"`
+	DeadAt    time.Time `json:"dead_at"`
+	// Body is the original message body, which may not be valid JSON.
+	Body string `json:"body"`
+}
+
+// Queues returns the depth of every broker queue. It requires an admin of the default tenant.
+func (c *Client) Queues(ctx context.Context) ([]QueueStats, error) {
+	var stats []QueueStats
+	if err := c.do(ctx, request{method: http.MethodGet, path: "/admin/queues"}, &stats); err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+// DeadLetters returns up to limit of the oldest dead letters, leaving them in the queue.
+// The server default applies if limit is zero. It requires an admin of the default tenant.
+func (c *Client) DeadLetters(ctx context.Context, limit int) ([]DeadLetter, error) {
+	query := url.Values{}
+	if limit > 0 {
+		query.Set("limit", strconv.Itoa(limit))
+	}
+	var letters []DeadLetter
+	if err := c.do(ctx, request{method: http.MethodGet, path: "/admin/dead-letters", query: query}, &letters); err != nil {
+		return nil, err
+	}
+	return letters, nil
+}
@@ -0,0 +1,254 @@
+// Package client is the Go SDK of the delayed-notifier REST API.
+//
+// A Client retries requests that fail with a network error or a transient server
+// status. Create and Batch send an Idempotency-Key header, generated unless the
+// request sets one, so a retried create never schedules a notification twice.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultMaxRetries = 3
+	defaultMinBackoff = 200 * time.Millisecond
+	defaultMaxBackoff = 5 * time.Second
+	defaultTimeout    = 30 * time.Second
+	userAgent         = "delayed-notifier-go-client"
+)
+
+// Client calls the delayed-notifier REST API. It is safe for concurrent use.
+type Client struct {
+	baseURL     *url.URL
+	httpClient  *http.Client
+	apiKey      string
+	bearerToken string
+	maxRetries  int
+	minBackoff  time.Duration
+	maxBackoff  time.Duration
+}
+
+// Option configures a Client.
+type Option func(*Client)
+
+// WithHTTPClient sets the HTTP client used to send requests.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) { c.httpClient = httpClient }
+}
+
+// WithTimeout sets the timeout of each attempt of a request. Event streams are not limited.
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Client) {
+		httpClient := *c.httpClient
+		httpClient.Timeout = timeout
+		c.httpClient = &httpClient
+	}
+}
+
+// WithAPIKey authenticates requests with an API key.
+func WithAPIKey(key string) Option {
+	return func(c *Client) { c.apiKey = key }
+}
+
+// WithBearerToken authenticates requests with a JWT bearer token.
+func WithBearerToken(token string) Option {
+	return func(c *Client) { c.bearerToken = token }
+}
+
+// WithRetries sets how many times a failed request is retried and the bounds of the
+// exponential backoff between attempts. Zero maxRetries disables retries.
+func WithRetries(maxRetries int, minBackoff, maxBackoff time.Duration) Option {
+	return func(c *Client) {
+		c.maxRetries = maxRetries
+		c.minBackoff = minBackoff
+		c.maxBackoff = maxBackoff
+	}
+}
+
+// New creates a client for the API served at baseURL, e.g. "https://notifier.example.com".
+// The /api/v1 prefix is added by the client.
+func New(baseURL string, opts ...Option) (*Client, error) {
+	u, err := url.Parse(strings.TrimSuffix(baseURL, "/"))
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("client: invalid base url %q", baseURL)
+	}
+
+	c := &Client{
+		baseURL:    u,
+		httpClient: &http.Client{Timeout: defaultTimeout},
+		maxRetries: defaultMaxRetries,
+		minBackoff: defaultMinBackoff,
+		maxBackoff: defaultMaxBackoff,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c, nil
+}
+
+// request describes a single API call.
+type request struct {
+	method         string
+	path           string
+	query          url.Values
+	body           any
+	idempotencyKey string
+	// ifMatch is the entity tag sent as If-Match, if not empty.
+	ifMatch string
+}
+
+// do sends the request, retrying transient failures, and decodes a successful
+// response body into out, if out is not nil.
+func (c *Client) do(ctx context.Context, req request, out any) error {
+	var payload []byte
+	if req.body != nil {
+		var err error
+		if payload, err = json.Marshal(req.body); err != nil {
+			return fmt.Errorf("client: failed to encode request: %w", err)
+		}
+	}
+
+	for attempt := 0; ; attempt++ {
+		resp, err := c.send(ctx, req, payload)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			if attempt >= c.maxRetries {
+				return fmt.Errorf("client: %s %s: %w", req.method, req.path, err)
+			}
+			if err := c.wait(ctx, attempt, 0); err != nil {
+				return err
+			}
+			continue
+		}
+
+		retryAfter, retryable := retryDelay(resp)
+		if retryable && attempt < c.maxRetries {
+			_, _ = io.Copy(io.Discard, resp.Body)
+			_ = resp.Body.Close()
+			if err := c.wait(ctx, attempt, retryAfter); err != nil {
+				return err
+			}
+			continue
+		}
+		return decodeResponse(resp, out)
+	}
+}
+
+// send performs a single attempt of the request.
+func (c *Client) send(ctx context.Context, req request, payload []byte) (*http.Response, error) {
+	httpReq, err := c.newRequest(ctx, req, payload)
+	if err != nil {
+		return nil, err
+	}
+	return c.httpClient.Do(httpReq)
+}
+
+// newRequest builds the HTTP request of an API call, including authentication headers.
+func (c *Client) newRequest(ctx context.Context, req request, payload []byte) (*http.Request, error) {
+	u := c.baseURL.JoinPath("/api/v1", req.path)
+	u.RawQuery = req.query.Encode()
+
+	var body io.Reader
+	if payload != nil {
+		body = bytes.NewReader(payload)
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, req.method, u.String(), body)
+	if err != nil {
+		return nil, err
+	}
+
+	httpReq.Header.Set("Accept", "application/json, application/problem+json")
+	httpReq.Header.Set("User-Agent", userAgent)
+	if payload != nil {
+		httpReq.Header.Set("Content-Type", "application/json")
+	}
+	if req.idempotencyKey != "" {
+		httpReq.Header.Set("Idempotency-Key", req.idempotencyKey)
+	}
+	if req.ifMatch != "" {
+		httpReq.Header.Set("If-Match", req.ifMatch)
+	}
+	if c.apiKey != "" {
+		httpReq.Header.Set("X-API-Key", c.apiKey)
+	}
+	if c.bearerToken != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+c.bearerToken)
+	}
+	return httpReq, nil
+}
+
+// wait sleeps before the next attempt: retryAfter if the server asked for it,
+// otherwise an exponential backoff with full jitter.
+func (c *Client) wait(ctx context.Context, attempt int, retryAfter time.Duration) error {
+	delay := retryAfter
+	if delay <= 0 {
+		backoff := min(c.minBackoff<<attempt, c.maxBackoff)
+		if backoff > 0 {
+			delay = rand.N(backoff) + 1
+		}
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// retryDelay reports whether the response is worth retrying and how long the
+// server asked to wait first, if it did.
+// Internal server errors are not retried: the request may have partly succeeded.
+func retryDelay(resp *http.Response) (time.Duration, bool) {
+	var retryAfter time.Duration
+	if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && seconds > 0 {
+		retryAfter = time.Duration(seconds) * time.Second
+	}
+
+	switch resp.StatusCode {
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return retryAfter, true
+	case http.StatusTooManyRequests:
+		// A used-up daily quota comes without Retry-After and will not recover soon.
+		return retryAfter, retryAfter > 0
+	default:
+		return 0, false
+	}
+}
+
+// decodeResponse decodes a successful response into out, or turns an error response into an *APIError.
+func decodeResponse(resp *http.Response, out any) error {
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		var body problem
+		data, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+		if err := json.Unmarshal(data, &body); err == nil && (body.Detail != "" || body.Title != "") {
+			return body.apiError(resp.StatusCode)
+		}
+		return &APIError{StatusCode: resp.StatusCode, Message: strings.TrimSpace(string(data))}
+	}
+
+	if out == nil || resp.StatusCode == http.StatusNoContent {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("client: failed to decode response: %w", err)
+	}
+	return nil
+}
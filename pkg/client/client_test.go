@@ -0,0 +1,334 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/ilindan-dev/delayed-notifier/pkg/client"
+	"github.com/ilindan-dev/delayed-notifier/pkg/client/clienttest"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func newRequest() client.CreateRequest {
+	return client.CreateRequest{
+		Recipient:   "user@example.com",
+		Channel:     client.ChannelEmail,
+		Subject:     "Hello",
+		Message:     "World",
+		ScheduledAt: time.Now().Add(time.Hour),
+	}
+}
+
+func TestCreateGetCancel(t *testing.T) {
+	srv := clienttest.NewServer()
+	defer srv.Close()
+	c := srv.Client()
+	ctx := context.Background()
+
+	created, err := c.Create(ctx, newRequest())
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if created.Status != client.StatusScheduled {
+		t.Errorf("got status %q, want %q", created.Status, client.StatusScheduled)
+	}
+
+	got, err := c.Get(ctx, created.ID)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if got.ID != created.ID || got.Subject != "Hello" {
+		t.Errorf("Get returned %+v, want %+v", got, created)
+	}
+
+	later := created.ScheduledAt.Add(time.Hour)
+	rescheduled, err := c.Reschedule(ctx, created.ID, later)
+	if err != nil {
+		t.Fatalf("Reschedule: %v", err)
+	}
+	if !rescheduled.ScheduledAt.Equal(later) {
+		t.Errorf("got scheduled_at %s after reschedule, want %s", rescheduled.ScheduledAt, later)
+	}
+	local, err := c.RescheduleLocal(ctx, created.ID, "2030-06-01T09:00", "Europe/Berlin")
+	if err != nil {
+		t.Fatalf("RescheduleLocal: %v", err)
+	}
+	if want := time.Date(2030, 6, 1, 7, 0, 0, 0, time.UTC); !local.ScheduledAt.Equal(want) || local.Timezone != "Europe/Berlin" {
+		t.Errorf("got scheduled_at %s in %q after local reschedule, want %s in Europe/Berlin", local.ScheduledAt, local.Timezone, want)
+	}
+
+	if err := c.Cancel(ctx, created.ID); err != nil {
+		t.Fatalf("Cancel: %v", err)
+	}
+	if got, _ := c.Get(ctx, created.ID); got.Status != client.StatusCancelled {
+		t.Errorf("got status %q after cancel, want %q", got.Status, client.StatusCancelled)
+	}
+	if _, err := c.Reschedule(ctx, created.ID, later); !errors.Is(err, client.ErrConflict) {
+		t.Errorf("Reschedule of cancelled notification: got %v, want ErrConflict", err)
+	}
+	var apiErr *client.APIError
+	if err := c.Cancel(ctx, created.ID); !errors.As(err, &apiErr) || apiErr.Code != "not_cancellable" {
+		t.Errorf("Cancel of cancelled notification: got %v, want a not_cancellable error", err)
+	}
+}
+
+func TestConditionalChanges(t *testing.T) {
+	srv := clienttest.NewServer()
+	defer srv.Close()
+	c := srv.Client()
+	ctx := context.Background()
+
+	created, err := c.Create(ctx, newRequest())
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	rescheduled, err := c.RescheduleIfVersion(ctx, created.ID, created.ScheduledAt.Add(time.Hour), created.Version)
+	if err != nil {
+		t.Fatalf("RescheduleIfVersion: %v", err)
+	}
+	if rescheduled.Version != created.Version+1 {
+		t.Errorf("got version %d after reschedule, want %d", rescheduled.Version, created.Version+1)
+	}
+
+	var apiErr *client.APIError
+	if err := c.CancelIfVersion(ctx, created.ID, created.Version); !errors.Is(err, client.ErrConflict) ||
+		!errors.As(err, &apiErr) || apiErr.Code != "version_conflict" {
+		t.Errorf("CancelIfVersion with a stale version: got %v, want a version_conflict error", err)
+	}
+	if err := c.CancelIfVersion(ctx, created.ID, rescheduled.Version); err != nil {
+		t.Fatalf("CancelIfVersion: %v", err)
+	}
+}
+
+func TestRetriesReuseIdempotencyKey(t *testing.T) {
+	srv := clienttest.NewServer()
+	defer srv.Close()
+	c := srv.Client()
+
+	srv.FailNext(http.StatusServiceUnavailable, http.StatusBadGateway)
+	if _, err := c.Create(context.Background(), newRequest()); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if got := srv.Requests(); got != 3 {
+		t.Errorf("got %d requests, want 3", got)
+	}
+
+	req := newRequest()
+	req.IdempotencyKey = "order-42"
+	first, err := c.Create(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	second, err := c.Create(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if first.ID != second.ID {
+		t.Errorf("repeated idempotency key created %s and %s", first.ID, second.ID)
+	}
+	if got := len(srv.Notifications()); got != 2 {
+		t.Errorf("got %d stored notifications, want 2", got)
+	}
+
+	req.Subject = "Goodbye"
+	if _, err := c.Create(context.Background(), req); !errors.Is(err, client.ErrConflict) {
+		t.Errorf("Create with a reused idempotency key: got %v, want ErrConflict", err)
+	}
+}
+
+func TestTypedErrors(t *testing.T) {
+	srv := clienttest.NewServer()
+	defer srv.Close()
+	ctx := context.Background()
+
+	_, err := srv.Client().Get(ctx, uuid.New())
+	var apiErr *client.APIError
+	if !errors.Is(err, client.ErrNotFound) || !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotFound {
+		t.Errorf("Get of unknown notification: got %v, want a 404 APIError", err)
+	}
+
+	srv.FailNext(http.StatusInternalServerError)
+	if _, err := srv.Client().Create(ctx, newRequest()); !errors.Is(err, client.ErrServer) {
+		t.Errorf("got %v, want ErrServer without retrying", err)
+	}
+
+	srv.RequireAPIKey("secret")
+	if _, err := srv.Client().List(ctx, client.ListOptions{}); !errors.Is(err, client.ErrUnauthorized) {
+		t.Errorf("got %v, want ErrUnauthorized", err)
+	}
+	if _, err := srv.Client(client.WithAPIKey("secret")).List(ctx, client.ListOptions{}); err != nil {
+		t.Errorf("List with api key: %v", err)
+	}
+}
+
+func TestRelativeSchedule(t *testing.T) {
+	srv := clienttest.NewServer()
+	defer srv.Close()
+	c := srv.Client()
+	ctx := context.Background()
+
+	if got := client.ISODuration(90*time.Minute + 1500*time.Millisecond); got != "PT1H30M1.5S" {
+		t.Errorf("ISODuration = %q, want PT1H30M1.5S", got)
+	}
+
+	req := newRequest()
+	req.ScheduledAt, req.Delay = time.Time{}, client.ISODuration(15*time.Minute)
+	before := time.Now()
+	created, err := c.Create(ctx, req)
+	if err != nil {
+		t.Fatalf("Create with delay: %v", err)
+	}
+	if created.ScheduledAt.Before(before.Add(15 * time.Minute)) {
+		t.Errorf("got scheduled_at %s, want 15 minutes from now", created.ScheduledAt)
+	}
+
+	req = newRequest()
+	req.ScheduledAt, req.SendNow, req.Priority = time.Time{}, true, client.PriorityCritical
+	if created, err = c.Create(ctx, req); err != nil || created.Priority != client.PriorityCritical {
+		t.Fatalf("Create with send_now: got %+v, %v, want a critical notification", created, err)
+	}
+
+	req = newRequest()
+	req.SendNow = true
+	if _, err := c.Create(ctx, req); !errors.Is(err, client.ErrBadRequest) {
+		t.Errorf("Create with scheduled_at and send_now: got %v, want ErrBadRequest", err)
+	}
+}
+
+func TestDeliveryWindows(t *testing.T) {
+	srv := clienttest.NewServer()
+	defer srv.Close()
+	c := srv.Client()
+	ctx := context.Background()
+
+	req := newRequest()
+	req.ScheduledAt, req.LocalTime, req.Timezone = time.Time{}, "09:00", "Europe/Berlin"
+	req.DeliveryWindow = &client.DeliveryWindow{Days: []string{"mon", "fri"}, Hours: &client.ClockRange{Start: "09:00", End: "18:00"}}
+	created, err := c.Create(ctx, req)
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if w := created.DeliveryWindow; w == nil || w.Timezone != "Europe/Berlin" || len(w.Days) != 2 {
+		t.Errorf("got delivery window %+v, want the requested one in Europe/Berlin", w)
+	}
+
+	if _, err := c.GetRecipientWindow(ctx, client.ChannelTelegram, "42"); !errors.Is(err, client.ErrNotFound) {
+		t.Fatalf("GetRecipientWindow without a window: got %v, want ErrNotFound", err)
+	}
+	quiet := client.DeliveryWindow{Timezone: "Europe/Berlin", QuietHours: &client.ClockRange{Start: "22:00", End: "07:00"}}
+	if _, err := c.SetRecipientWindow(ctx, client.ChannelTelegram, "42", quiet); err != nil {
+		t.Fatalf("SetRecipientWindow: %v", err)
+	}
+	got, err := c.GetRecipientWindow(ctx, client.ChannelTelegram, "42")
+	if err != nil || got.QuietHours == nil || got.QuietHours.Start != "22:00" {
+		t.Fatalf("GetRecipientWindow: got %+v, %v, want quiet hours from 22:00", got, err)
+	}
+	if err := c.DeleteRecipientWindow(ctx, client.ChannelTelegram, "42"); err != nil {
+		t.Fatalf("DeleteRecipientWindow: %v", err)
+	}
+	if _, err := c.SetRecipientWindow(ctx, client.ChannelTelegram, "42", client.DeliveryWindow{}); !errors.Is(err, client.ErrBadRequest) {
+		t.Errorf("SetRecipientWindow without a time zone: got %v, want ErrBadRequest", err)
+	}
+}
+
+func TestBatchAndList(t *testing.T) {
+	srv := clienttest.NewServer()
+	defer srv.Close()
+	c := srv.Client()
+	ctx := context.Background()
+
+	invalid := newRequest()
+	invalid.Channel = "sms"
+	results, err := c.Batch(ctx, client.BatchRequest{Notifications: []client.CreateRequest{newRequest(), invalid, newRequest()}})
+	if err != nil {
+		t.Fatalf("Batch: %v", err)
+	}
+	if results[0].Notification == nil || results[2].Notification == nil || !errors.Is(results[1].Err, client.ErrBadRequest) {
+		t.Fatalf("unexpected batch results: %+v", results)
+	}
+	var apiErr *client.APIError
+	if !errors.As(results[1].Err, &apiErr) || apiErr.Code != "invalid_channel" || len(apiErr.Fields) != 1 || apiErr.Fields[0].Field != "channel" {
+		t.Errorf("got batch item error %+v, want an invalid_channel error about the channel field", results[1].Err)
+	}
+
+	first, err := c.List(ctx, client.ListOptions{Limit: 1})
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(first.Notifications) != 1 || first.Notifications[0].ID != results[2].Notification.ID || first.NextPageToken == "" {
+		t.Fatalf("unexpected first page: %+v", first)
+	}
+	second, err := c.List(ctx, client.ListOptions{Limit: 1, PageToken: first.NextPageToken})
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(second.Notifications) != 1 || second.Notifications[0].ID != results[0].Notification.ID || second.NextPageToken != "" {
+		t.Fatalf("unexpected last page: %+v", second)
+	}
+
+	tagged := newRequest()
+	tagged.Tags, tagged.Metadata = []string{"campaign-spring"}, map[string]any{"order_id": "A-1001"}
+	created, err := c.Create(ctx, tagged)
+	if err != nil {
+		t.Fatalf("Create with tags: %v", err)
+	}
+	page, err := c.List(ctx, client.ListOptions{Tags: []string{"campaign-spring"}, Metadata: map[string]any{"order_id": "A-1001"}})
+	if err != nil {
+		t.Fatalf("List by tag and metadata: %v", err)
+	}
+	if len(page.Notifications) != 1 || page.Notifications[0].ID != created.ID || page.Notifications[0].Metadata["order_id"] != "A-1001" {
+		t.Errorf("List by tag and metadata: got %+v, want only the tagged notification", page.Notifications)
+	}
+}
+
+func TestBulkJobs(t *testing.T) {
+	srv := clienttest.NewServer()
+	defer srv.Close()
+	c := srv.Client()
+	ctx := context.Background()
+
+	tagged := newRequest()
+	tagged.Tags = []string{"campaign-spring"}
+	var ids []uuid.UUID
+	for _, req := range []client.CreateRequest{tagged, tagged, newRequest()} {
+		n, err := c.Create(ctx, req)
+		if err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+		ids = append(ids, n.ID)
+	}
+
+	filter := client.Filter{Tags: []string{"campaign-spring"}}
+	if matched, err := c.CountMatching(ctx, filter); err != nil || matched != 2 {
+		t.Fatalf("CountMatching: got %d, %v, want 2", matched, err)
+	}
+	if _, err := c.BulkCancel(ctx, client.Filter{}); !errors.Is(err, client.ErrBadRequest) {
+		t.Errorf("BulkCancel without criteria: got %v, want ErrBadRequest", err)
+	}
+
+	job, err := c.BulkCancel(ctx, filter)
+	if err != nil {
+		t.Fatalf("BulkCancel: %v", err)
+	}
+	job, err = c.WaitJob(ctx, job.ID, time.Millisecond)
+	if err != nil {
+		t.Fatalf("WaitJob: %v", err)
+	}
+	if job.Status != client.JobCompleted || job.Total != 2 || job.Processed != 2 {
+		t.Errorf("got job %+v, want 2 of 2 notifications processed", job)
+	}
+	for i, want := range []client.Status{client.StatusCancelled, client.StatusCancelled, client.StatusScheduled} {
+		n, err := c.Get(ctx, ids[i])
+		if err != nil {
+			t.Fatalf("Get: %v", err)
+		}
+		if n.Status != want {
+			t.Errorf("notification %d: got status %s, want %s", i, n.Status, want)
+		}
+	}
+	if _, err := c.GetJob(ctx, uuid.New()); !errors.Is(err, client.ErrNotFound) {
+		t.Errorf("GetJob of an unknown job: got %v, want ErrNotFound", err)
+	}
+}
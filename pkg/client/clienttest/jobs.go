@@ -0,0 +1,142 @@
+package clienttest
+
+import (
+	"encoding/json"
+	"github.com/google/uuid"
+	"github.com/ilindan-dev/delayed-notifier/pkg/client"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// bulkRequest is the body of the bulk cancel and reschedule endpoints.
+type bulkRequest struct {
+	Filter      client.Filter `json:"filter"`
+	ScheduledAt time.Time     `json:"scheduled_at"`
+	LocalTime   string        `json:"local_time"`
+	Timezone    string        `json:"timezone"`
+	DryRun      bool          `json:"dry_run"`
+}
+
+// bulkCancel and bulkReschedule change the matching notifications before they answer,
+// so the jobs they return are already completed.
+func (s *Server) bulkCancel(w http.ResponseWriter, r *http.Request) {
+	s.bulk(w, r, client.JobActionCancel)
+}
+
+func (s *Server) bulkReschedule(w http.ResponseWriter, r *http.Request) {
+	s.bulk(w, r, client.JobActionReschedule)
+}
+
+func (s *Server) bulk(w http.ResponseWriter, r *http.Request, action client.JobAction) {
+	var req bulkRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, newProblem(http.StatusBadRequest, "malformed_request", err.Error()))
+		return
+	}
+	q, p := filterValues(req.Filter)
+	if p != nil {
+		writeError(w, p)
+		return
+	}
+	var scheduledAt time.Time
+	if action == client.JobActionReschedule {
+		if req.ScheduledAt.IsZero() && req.LocalTime == "" {
+			writeError(w, fieldProblem("required", "scheduled_at", "scheduled_at is required"))
+			return
+		}
+		if scheduledAt, p = schedule(req.ScheduledAt, req.LocalTime, req.Timezone); p != nil {
+			writeError(w, p)
+			return
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var matched []*client.Notification
+	for _, id := range s.order {
+		if n := s.notifications[id]; matches(n, q) {
+			matched = append(matched, n)
+		}
+	}
+	if req.DryRun {
+		writeJSON(w, http.StatusOK, map[string]any{"action": action, "matched": len(matched)})
+		return
+	}
+
+	now := time.Now().UTC()
+	job := &client.Job{
+		ID:         uuid.New(),
+		Action:     action,
+		Status:     client.JobCompleted,
+		Timezone:   req.Timezone,
+		Total:      len(matched),
+		Processed:  len(matched),
+		CreatedAt:  now,
+		UpdatedAt:  now,
+		FinishedAt: &now,
+	}
+	for _, n := range matched {
+		switch action {
+		case client.JobActionCancel:
+			n.Status = client.StatusCancelled
+		case client.JobActionReschedule:
+			setSchedule(n, scheduledAt, req.Timezone)
+		}
+		n.Version++
+	}
+	if action == client.JobActionReschedule {
+		job.ScheduledAt = &scheduledAt
+	}
+	s.jobs[job.ID] = job
+	w.Header().Set("Location", "/api/v1/jobs/"+job.ID.String())
+	writeJSON(w, http.StatusAccepted, job)
+}
+
+func (s *Server) getJob(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		writeError(w, fieldProblem("invalid_id", "id", "invalid job ID format"))
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	job, ok := s.jobs[id]
+	if !ok {
+		writeError(w, newProblem(http.StatusNotFound, "not_found", "record not found"))
+		return
+	}
+	writeJSON(w, http.StatusOK, job)
+}
+
+// filterValues returns the list filters equivalent to the filter of a bulk job, or the
+// problem the API would reject it with. Unlike the API, the author is not filtered on.
+func filterValues(f client.Filter) (url.Values, *problem) {
+	if f.Status != "" && f.Status != client.StatusScheduled {
+		return nil, fieldProblem("invalid_filter", "filter", "only scheduled notifications can be changed, not "+string(f.Status)+" ones")
+	}
+	if f.Channel == "" && f.AuthorID == "" && f.ScheduledFrom == nil && f.ScheduledTo == nil && f.Search == "" &&
+		len(f.Tags) == 0 && len(f.Metadata) == 0 {
+		return nil, fieldProblem("invalid_filter", "filter", "the filter must select notifications by at least one criterion besides the status")
+	}
+
+	q := url.Values{"status": {string(client.StatusScheduled)}, "tag": f.Tags}
+	if f.Channel != "" {
+		q.Set("channel", string(f.Channel))
+	}
+	if f.ScheduledFrom != nil {
+		q.Set("scheduled_from", f.ScheduledFrom.Format(time.RFC3339))
+	}
+	if f.ScheduledTo != nil {
+		q.Set("scheduled_to", f.ScheduledTo.Format(time.RFC3339))
+	}
+	if f.Search != "" {
+		q.Set("search", f.Search)
+	}
+	if len(f.Metadata) > 0 {
+		metadata, _ := json.Marshal(f.Metadata)
+		q.Set("metadata", string(metadata))
+	}
+	return q, nil
+}
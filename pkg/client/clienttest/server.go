@@ -0,0 +1,676 @@
+// Package clienttest provides an in-memory fake of the delayed-notifier REST API
+// for unit tests of code that uses package client.
+//
+//	srv := clienttest.NewServer()
+//	defer srv.Close()
+//	c := srv.Client()
+package clienttest
+
+import (
+	"encoding/json"
+	"github.com/google/uuid"
+	"github.com/ilindan-dev/delayed-notifier/pkg/client"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Server is a fake notifier API listening on a local address. It stores
+// notifications in memory and honours idempotency keys and If-Match headers like the real API.
+// It serves the notification, bulk job and recipient delivery window endpoints only; event
+// streams and admin endpoints answer 404. Delivery windows are stored, but not enforced.
+type Server struct {
+	*httptest.Server
+
+	mu            sync.Mutex
+	apiKey        string
+	notifications map[uuid.UUID]*client.Notification
+	order         []uuid.UUID
+	keys          map[string]keyedRequest
+	windows       map[string]client.DeliveryWindow
+	jobs          map[uuid.UUID]*client.Job
+	failures      []int
+	requests      int
+}
+
+// keyedRequest is a creation request made with an idempotency key, and the notification it created.
+type keyedRequest struct {
+	id  uuid.UUID
+	req client.CreateRequest
+}
+
+// NewServer starts a fake server. It must be closed with Close.
+func NewServer() *Server {
+	s := &Server{
+		notifications: make(map[uuid.UUID]*client.Notification),
+		keys:          make(map[string]keyedRequest),
+		windows:       make(map[string]client.DeliveryWindow),
+		jobs:          make(map[uuid.UUID]*client.Job),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /api/v1/notifications", s.create)
+	mux.HandleFunc("POST /api/v1/notifications/batch", s.batch)
+	mux.HandleFunc("GET /api/v1/notifications", s.list)
+	mux.HandleFunc("GET /api/v1/notifications/{id}", s.get)
+	mux.HandleFunc("PATCH /api/v1/notifications/{id}", s.reschedule)
+	mux.HandleFunc("DELETE /api/v1/notifications/{id}", s.cancel)
+	mux.HandleFunc("POST /api/v1/notifications:cancel", s.bulkCancel)
+	mux.HandleFunc("POST /api/v1/notifications:reschedule", s.bulkReschedule)
+	mux.HandleFunc("GET /api/v1/jobs/{id}", s.getJob)
+	mux.HandleFunc("GET /api/v1/recipients/{channel}/{recipient}/delivery-window", s.getWindow)
+	mux.HandleFunc("PUT /api/v1/recipients/{channel}/{recipient}/delivery-window", s.setWindow)
+	mux.HandleFunc("DELETE /api/v1/recipients/{channel}/{recipient}/delivery-window", s.deleteWindow)
+
+	s.Server = httptest.NewServer(s.intercept(mux))
+	return s
+}
+
+// Client returns a client for the server. Retries happen without delay unless opts override it.
+func (s *Server) Client(opts ...client.Option) *client.Client {
+	defaults := []client.Option{
+		client.WithHTTPClient(s.Server.Client()),
+		client.WithRetries(3, 0, 0),
+	}
+	c, err := client.New(s.URL, append(defaults, opts...)...)
+	if err != nil {
+		panic(err)
+	}
+	return c
+}
+
+// RequireAPIKey makes the server reject requests without the given X-API-Key.
+func (s *Server) RequireAPIKey(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.apiKey = key
+}
+
+// FailNext makes the server answer the next requests with the given statuses, in order,
+// without handling them. Use it to exercise retries and error handling.
+func (s *Server) FailNext(statuses ...int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failures = append(s.failures, statuses...)
+}
+
+// Requests returns the number of requests the server has received.
+func (s *Server) Requests() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.requests
+}
+
+// Notifications returns the stored notifications, oldest first.
+func (s *Server) Notifications() []client.Notification {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	result := make([]client.Notification, 0, len(s.order))
+	for _, id := range s.order {
+		result = append(result, *s.notifications[id])
+	}
+	return result
+}
+
+// SetStatus changes the status of a stored notification, e.g. to simulate a delivery.
+// It reports whether the notification exists.
+func (s *Server) SetStatus(id uuid.UUID, status client.Status) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	n, ok := s.notifications[id]
+	if ok {
+		n.Status = status
+		n.Version++
+	}
+	return ok
+}
+
+// intercept counts requests, answers queued failures and checks the API key.
+func (s *Server) intercept(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		s.requests++
+		var failure int
+		if len(s.failures) > 0 {
+			failure, s.failures = s.failures[0], s.failures[1:]
+		}
+		apiKey := s.apiKey
+		s.mu.Unlock()
+
+		switch {
+		case failure != 0:
+			writeError(w, newProblem(failure, failureCode(failure), http.StatusText(failure)))
+		case apiKey != "" && r.Header.Get("X-API-Key") != apiKey:
+			writeError(w, newProblem(http.StatusUnauthorized, "unauthenticated", "invalid api key"))
+		default:
+			next.ServeHTTP(w, r)
+		}
+	})
+}
+
+func (s *Server) create(w http.ResponseWriter, r *http.Request) {
+	var req client.CreateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, newProblem(http.StatusBadRequest, "malformed_request", err.Error()))
+		return
+	}
+	if p := validate(req); p != nil {
+		writeError(w, p)
+		return
+	}
+
+	s.mu.Lock()
+	n, p := s.store(req, r.Header.Get("Idempotency-Key"))
+	s.mu.Unlock()
+	if p != nil {
+		writeError(w, p)
+		return
+	}
+	setETag(w, n)
+	writeJSON(w, http.StatusCreated, n)
+}
+
+func (s *Server) batch(w http.ResponseWriter, r *http.Request) {
+	var req client.BatchRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, newProblem(http.StatusBadRequest, "malformed_request", err.Error()))
+		return
+	}
+	if len(req.Notifications) == 0 {
+		writeError(w, fieldProblem("validation_failed", "notifications", "notifications must hold at least 1 items"))
+		return
+	}
+	if len(req.Notifications) > 100 {
+		writeError(w, newProblem(http.StatusBadRequest, "batch_too_large", "a batch may hold at most 100 notifications"))
+		return
+	}
+
+	type item struct {
+		Status       int                  `json:"status"`
+		Notification *client.Notification `json:"notification,omitempty"`
+		Error        *problem             `json:"error,omitempty"`
+	}
+	results := make([]item, len(req.Notifications))
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key := r.Header.Get("Idempotency-Key")
+	for i, n := range req.Notifications {
+		if p := validate(n); p != nil {
+			results[i] = item{Status: p.Status, Error: p}
+			continue
+		}
+		itemKey := ""
+		if key != "" {
+			itemKey = key + "/" + strconv.Itoa(i)
+		}
+		if stored, p := s.store(n, itemKey); p != nil {
+			results[i] = item{Status: p.Status, Error: p}
+		} else {
+			results[i] = item{Status: http.StatusCreated, Notification: stored}
+		}
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"results": results})
+}
+
+func (s *Server) list(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	limit := 50
+	if raw := q.Get("limit"); raw != "" {
+		l, err := strconv.Atoi(raw)
+		if err != nil || l < 1 || l > 500 {
+			writeError(w, fieldProblem("validation_failed", "limit", "limit must be between 1 and 500"))
+			return
+		}
+		limit = l
+	}
+	// Page tokens are positions in the newest-first list.
+	offset := 0
+	if raw := q.Get("page_token"); raw != "" {
+		o, err := strconv.Atoi(raw)
+		if err != nil || o < 0 {
+			writeError(w, newProblem(http.StatusBadRequest, "invalid_page_token", "invalid page token"))
+			return
+		}
+		offset = o
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var matched []client.Notification
+	for i := len(s.order) - 1; i >= 0; i-- {
+		n := s.notifications[s.order[i]]
+		if matches(n, q) {
+			matched = append(matched, *n)
+		}
+	}
+
+	result := client.ListResult{Notifications: []client.Notification{}}
+	if offset < len(matched) {
+		end := min(offset+limit, len(matched))
+		result.Notifications = matched[offset:end]
+		if end < len(matched) {
+			result.NextPageToken = strconv.Itoa(end)
+		}
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+func (s *Server) get(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		writeError(w, fieldProblem("invalid_id", "id", "invalid notification ID format"))
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	n, ok := s.notifications[id]
+	if !ok {
+		writeError(w, newProblem(http.StatusNotFound, "not_found", "record not found"))
+		return
+	}
+	setETag(w, n)
+	writeJSON(w, http.StatusOK, n)
+}
+
+func (s *Server) reschedule(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		writeError(w, fieldProblem("invalid_id", "id", "invalid notification ID format"))
+		return
+	}
+	var req struct {
+		ScheduledAt time.Time `json:"scheduled_at"`
+		LocalTime   string    `json:"local_time"`
+		Timezone    string    `json:"timezone"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, newProblem(http.StatusBadRequest, "malformed_request", err.Error()))
+		return
+	}
+	if req.ScheduledAt.IsZero() && req.LocalTime == "" {
+		writeError(w, fieldProblem("required", "scheduled_at", "scheduled_at is required"))
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	n, ok := s.notifications[id]
+	if !ok {
+		writeError(w, newProblem(http.StatusNotFound, "not_found", "record not found"))
+		return
+	}
+	if p := checkIfMatch(r, n); p != nil {
+		writeError(w, p)
+		return
+	}
+	if n.Status != client.StatusScheduled {
+		writeError(w, newProblem(http.StatusConflict, "not_scheduled", "cannot reschedule a "+string(n.Status)+" notification"))
+		return
+	}
+	if req.Timezone == "" {
+		req.Timezone = n.Timezone
+	}
+	scheduledAt, p := schedule(req.ScheduledAt, req.LocalTime, req.Timezone)
+	if p != nil {
+		writeError(w, p)
+		return
+	}
+	setSchedule(n, scheduledAt, req.Timezone)
+	if n.Priority == "" {
+		n.Priority = client.PriorityNormal
+	}
+	n.Version++
+	setETag(w, n)
+	writeJSON(w, http.StatusOK, n)
+}
+
+func (s *Server) cancel(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		writeError(w, fieldProblem("invalid_id", "id", "invalid notification ID format"))
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	n, ok := s.notifications[id]
+	if !ok {
+		writeError(w, newProblem(http.StatusNotFound, "not_found", "record not found"))
+		return
+	}
+	if p := checkIfMatch(r, n); p != nil {
+		writeError(w, p)
+		return
+	}
+	if n.Status != client.StatusScheduled {
+		writeError(w, newProblem(http.StatusConflict, "not_cancellable", "cannot cancel a "+string(n.Status)+" notification"))
+		return
+	}
+	n.Status = client.StatusCancelled
+	n.Version++
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) getWindow(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	window, ok := s.windows[windowKey(r)]
+	if !ok {
+		writeError(w, newProblem(http.StatusNotFound, "not_found", "record not found"))
+		return
+	}
+	writeJSON(w, http.StatusOK, window)
+}
+
+func (s *Server) setWindow(w http.ResponseWriter, r *http.Request) {
+	var window client.DeliveryWindow
+	if err := json.NewDecoder(r.Body).Decode(&window); err != nil {
+		writeError(w, newProblem(http.StatusBadRequest, "malformed_request", err.Error()))
+		return
+	}
+	if p := validateWindow(window, "", ""); p != nil {
+		writeError(w, p)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.windows[windowKey(r)] = window
+	writeJSON(w, http.StatusOK, window)
+}
+
+func (s *Server) deleteWindow(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key := windowKey(r)
+	if _, ok := s.windows[key]; !ok {
+		writeError(w, newProblem(http.StatusNotFound, "not_found", "record not found"))
+		return
+	}
+	delete(s.windows, key)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// windowKey identifies the recipient of a delivery window request.
+func windowKey(r *http.Request) string {
+	return r.PathValue("channel") + "/" + r.PathValue("recipient")
+}
+
+// validateWindow returns the problem the API would reject a delivery window with, or nil.
+// Only its time zone is checked, which defaults to defaultZone. Fields are prefixed by prefix.
+func validateWindow(window client.DeliveryWindow, defaultZone, prefix string) *problem {
+	switch {
+	case window.Timezone == "" && defaultZone == "":
+		return fieldProblem("required", prefix+"timezone", "timezone is required")
+	case window.Timezone != "":
+		if _, err := time.LoadLocation(window.Timezone); err != nil {
+			return fieldProblem("invalid_timezone", prefix+"timezone", "unknown time zone "+strconv.Quote(window.Timezone))
+		}
+	}
+	return nil
+}
+
+// store saves a new notification, or returns the one created earlier with the same
+// idempotency key, or the problem the API answers with if the key was used for a
+// different request. The caller must hold s.mu.
+func (s *Server) store(req client.CreateRequest, key string) (*client.Notification, *problem) {
+	if keyed, ok := s.keys[key]; ok && key != "" {
+		if !reflect.DeepEqual(keyed.req, req) {
+			return nil, newProblem(http.StatusConflict, "duplicate", "idempotency key was already used for a different request")
+		}
+		return s.notifications[keyed.id], nil
+	}
+
+	n := &client.Notification{
+		ID:          uuid.New(),
+		Status:      client.StatusScheduled,
+		Channel:     req.Channel,
+		Priority:    req.Priority,
+		Recipient:   req.Recipient,
+		Tags:        req.Tags,
+		Metadata:    req.Metadata,
+		Subject:     req.Subject,
+		Version:     1,
+		CreatedAt:   time.Now().UTC(),
+		CallbackURL: req.CallbackURL,
+	}
+	// The request was validated, so the schedule resolves.
+	scheduledAt, _ := resolveSchedule(req)
+	setSchedule(n, scheduledAt, req.Timezone)
+	if req.DeliveryWindow != nil {
+		window := *req.DeliveryWindow
+		if window.Timezone == "" {
+			window.Timezone = req.Timezone
+		}
+		n.DeliveryWindow = &window
+	}
+	s.notifications[n.ID] = n
+	s.order = append(s.order, n.ID)
+	if key != "" {
+		s.keys[key] = keyedRequest{id: n.ID, req: req}
+	}
+	return n, nil
+}
+
+// validate returns the problem the API would reject req with, or nil.
+func validate(req client.CreateRequest) *problem {
+	var missing []client.FieldError
+	for field, empty := range map[string]bool{
+		"recipient":    req.Recipient == "",
+		"subject":      req.Subject == "",
+		"scheduled_at": req.ScheduledAt.IsZero() && req.LocalTime == "" && req.Delay == "" && !req.SendNow,
+	} {
+		if empty {
+			missing = append(missing, client.FieldError{Field: field, Code: "required", Message: field + " is required"})
+		}
+	}
+	if len(missing) > 0 {
+		sort.Slice(missing, func(i, j int) bool { return missing[i].Field < missing[j].Field })
+		p := newProblem(http.StatusBadRequest, "validation_failed", "the request has invalid fields")
+		p.Errors = missing
+		return p
+	}
+
+	if _, p := resolveSchedule(req); p != nil {
+		return p
+	}
+	if req.DeliveryWindow != nil {
+		if p := validateWindow(*req.DeliveryWindow, req.Timezone, "delivery_window."); p != nil {
+			return p
+		}
+	}
+	switch {
+	case req.Channel != client.ChannelEmail && req.Channel != client.ChannelTelegram:
+		return fieldProblem("invalid_channel", "channel", "unknown channel: "+string(req.Channel))
+	case !slices.Contains([]client.Priority{"", client.PriorityLow, client.PriorityNormal, client.PriorityHigh, client.PriorityCritical}, req.Priority):
+		return fieldProblem("invalid_priority", "priority", "unknown priority "+strconv.Quote(string(req.Priority)))
+	case req.CallbackURL != nil:
+		u, err := url.Parse(*req.CallbackURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fieldProblem("invalid_callback_url", "callback_url", "callback url must be an absolute http or https url")
+		}
+	}
+	return nil
+}
+
+// resolveSchedule returns the send time of req. Unlike the API, it only accepts delays
+// without a date part, such as PT1H30M.
+func resolveSchedule(req client.CreateRequest) (time.Time, *problem) {
+	field := "delay"
+	if req.SendNow {
+		field = "send_now"
+	}
+	switch {
+	case req.Delay == "" && !req.SendNow:
+		return schedule(req.ScheduledAt, req.LocalTime, req.Timezone)
+	case req.Delay != "" && req.SendNow:
+		return time.Time{}, fieldProblem("validation_failed", "send_now", "delay and send_now are mutually exclusive")
+	case !req.ScheduledAt.IsZero() || req.LocalTime != "":
+		return time.Time{}, fieldProblem("validation_failed", field, field+" cannot be combined with scheduled_at or local_time")
+	case req.SendNow:
+		return time.Now().UTC(), nil
+	}
+
+	clock, ok := strings.CutPrefix(req.Delay, "PT")
+	d, err := time.ParseDuration(strings.ToLower(clock))
+	if !ok || err != nil || d < 0 {
+		return time.Time{}, fieldProblem("invalid_delay", "delay", "invalid delay "+strconv.Quote(req.Delay))
+	}
+	return time.Now().Add(d).UTC(), nil
+}
+
+// schedule returns the send time given by either scheduledAt or localTime in timezone.
+// Unlike the API, it leaves the resolution of local times around daylight saving
+// transitions to time.Date.
+func schedule(scheduledAt time.Time, localTime, timezone string) (time.Time, *problem) {
+	var loc *time.Location
+	if timezone != "" {
+		var err error
+		if loc, err = time.LoadLocation(timezone); err != nil {
+			return time.Time{}, fieldProblem("invalid_timezone", "timezone", "unknown time zone "+strconv.Quote(timezone))
+		}
+	}
+	switch {
+	case localTime == "":
+		return scheduledAt.UTC(), nil
+	case !scheduledAt.IsZero():
+		return time.Time{}, fieldProblem("validation_failed", "local_time", "scheduled_at and local_time are mutually exclusive")
+	case loc == nil:
+		return time.Time{}, fieldProblem("required", "timezone", "timezone is required with local_time")
+	}
+
+	for _, layout := range []string{"2006-01-02T15:04:05", "2006-01-02T15:04"} {
+		if t, err := time.ParseInLocation(layout, localTime, loc); err == nil {
+			return t.UTC(), nil
+		}
+	}
+	for _, layout := range []string{"15:04:05", "15:04"} {
+		clock, err := time.Parse(layout, localTime)
+		if err != nil {
+			continue
+		}
+		now := time.Now().In(loc)
+		t := time.Date(now.Year(), now.Month(), now.Day(), clock.Hour(), clock.Minute(), clock.Second(), 0, loc)
+		if !t.After(now) {
+			t = t.AddDate(0, 0, 1)
+		}
+		return t.UTC(), nil
+	}
+	return time.Time{}, fieldProblem("invalid_local_time", "local_time", "invalid local_time "+strconv.Quote(localTime))
+}
+
+// setSchedule sets the send time of n and, if timezone is set, its local-time fields.
+func setSchedule(n *client.Notification, scheduledAt time.Time, timezone string) {
+	n.ScheduledAt = scheduledAt
+	if loc, err := time.LoadLocation(timezone); err == nil && timezone != "" {
+		n.Timezone = timezone
+		n.LocalTime = scheduledAt.In(loc).Format("2006-01-02T15:04:05")
+	}
+}
+
+// setETag sets the ETag header of a response to the version of n.
+func setETag(w http.ResponseWriter, n *client.Notification) {
+	w.Header().Set("ETag", strconv.Quote(strconv.Itoa(n.Version)))
+}
+
+// checkIfMatch returns the problem the API answers with if the If-Match header of r
+// does not match the version of n, or nil.
+func checkIfMatch(r *http.Request, n *client.Notification) *problem {
+	tag := r.Header.Get("If-Match")
+	if tag == "" || tag == "*" || tag == strconv.Quote(strconv.Itoa(n.Version)) {
+		return nil
+	}
+	return newProblem(http.StatusPreconditionFailed, "version_conflict", "notification has version "+strconv.Itoa(n.Version)+", not "+tag)
+}
+
+// matches reports whether n passes the list filters of q. Unlike the API, metadata
+// filters compare top-level values only.
+func matches(n *client.Notification, q url.Values) bool {
+	if status := q.Get("status"); status != "" && string(n.Status) != status {
+		return false
+	}
+	if channel := q.Get("channel"); channel != "" && string(n.Channel) != channel {
+		return false
+	}
+	if search := q.Get("search"); search != "" && !strings.Contains(strings.ToLower(n.Subject), strings.ToLower(search)) &&
+		!strings.Contains(strings.ToLower(n.Recipient), strings.ToLower(search)) {
+		return false
+	}
+	if from, err := time.Parse(time.RFC3339, q.Get("scheduled_from")); err == nil && n.ScheduledAt.Before(from) {
+		return false
+	}
+	if to, err := time.Parse(time.RFC3339, q.Get("scheduled_to")); err == nil && !n.ScheduledAt.Before(to) {
+		return false
+	}
+	for _, tag := range q["tag"] {
+		if !slices.Contains(n.Tags, tag) {
+			return false
+		}
+	}
+	var metadata map[string]any
+	_ = json.Unmarshal([]byte(q.Get("metadata")), &metadata)
+	for key, value := range metadata {
+		if !reflect.DeepEqual(n.Metadata[key], value) {
+			return false
+		}
+	}
+	return true
+}
+
+// problem is an RFC 7807 error response body, as sent by the API.
+type problem struct {
+	Type   string              `json:"type"`
+	Title  string              `json:"title"`
+	Status int                 `json:"status"`
+	Detail string              `json:"detail,omitempty"`
+	Code   string              `json:"code"`
+	Errors []client.FieldError `json:"errors,omitempty"`
+}
+
+func newProblem(status int, code, detail string) *problem {
+	return &problem{Type: "about:blank", Title: http.StatusText(status), Status: status, Detail: detail, Code: code}
+}
+
+// fieldProblem returns a 400 problem about a single invalid field.
+func fieldProblem(code, field, detail string) *problem {
+	p := newProblem(http.StatusBadRequest, code, detail)
+	p.Errors = []client.FieldError{{Field: field, Code: code, Message: detail}}
+	return p
+}
+
+// failureCode returns the error code the API reports with status.
+func failureCode(status int) string {
+	switch status {
+	case http.StatusUnauthorized:
+		return "unauthenticated"
+	case http.StatusNotFound:
+		return "not_found"
+	case http.StatusTooManyRequests:
+		return "quota_exceeded"
+	case http.StatusBadRequest:
+		return "validation_failed"
+	default:
+		return "internal"
+	}
+}
+
+func writeError(w http.ResponseWriter, p *problem) {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(p.Status)
+	_ = json.NewEncoder(w).Encode(p)
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
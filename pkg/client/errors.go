@@ -0,0 +1,88 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// Sentinel errors matched by an *APIError with the corresponding status, e.g.
+//
+//	if errors.Is(err, client.ErrNotFound) { ... }
+//
+// ErrConflict also matches the failed precondition of a version mismatch.
+var (
+	ErrBadRequest    = errors.New("bad request")
+	ErrUnauthorized  = errors.New("unauthorized")
+	ErrForbidden     = errors.New("forbidden")
+	ErrNotFound      = errors.New("not found")
+	ErrConflict      = errors.New("conflict")
+	ErrQuotaExceeded = errors.New("quota exceeded")
+	ErrServer        = errors.New("server error")
+)
+
+// problem is the RFC 7807 body of an API error response.
+type problem struct {
+	Title  string       `json:"title"`
+	Detail string       `json:"detail"`
+	Code   string       `json:"code"`
+	Errors []FieldError `json:"errors"`
+}
+
+// apiError converts p to an *APIError with the given status.
+func (p *problem) apiError(status int) *APIError {
+	msg := p.Detail
+	if msg == "" {
+		msg = p.Title
+	}
+	return &APIError{StatusCode: status, Code: p.Code, Message: msg, Fields: p.Errors}
+}
+
+// FieldError describes an invalid request field.
+type FieldError struct {
+	// Field is the path of the field, e.g. "notifications[1].recipient".
+	Field string `json:"field"`
+	// Code is the failed rule, e.g. "required", or an error code.
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// APIError is returned when the API answers with an error status.
+type APIError struct {
+	StatusCode int
+	// Code is the stable, machine-readable error code, e.g. "invalid_recipient"
+	// or "not_cancellable". It is empty if the response carried none.
+	Code    string
+	Message string
+	// Fields lists the invalid request fields of a validation error.
+	Fields []FieldError
+}
+
+func (e *APIError) Error() string {
+	if e.Code == "" {
+		return fmt.Sprintf("notifier api: %d %s: %s", e.StatusCode, http.StatusText(e.StatusCode), e.Message)
+	}
+	return fmt.Sprintf("notifier api: %d %s: %s (%s)", e.StatusCode, http.StatusText(e.StatusCode), e.Message, e.Code)
+}
+
+// Unwrap returns the sentinel error of the status code, if there is one.
+func (e *APIError) Unwrap() error {
+	switch {
+	case e.StatusCode == http.StatusBadRequest:
+		return ErrBadRequest
+	case e.StatusCode == http.StatusUnauthorized:
+		return ErrUnauthorized
+	case e.StatusCode == http.StatusForbidden:
+		return ErrForbidden
+	case e.StatusCode == http.StatusNotFound:
+		return ErrNotFound
+	case e.StatusCode == http.StatusConflict, e.StatusCode == http.StatusPreconditionFailed:
+		return ErrConflict
+	case e.StatusCode == http.StatusTooManyRequests:
+		return ErrQuotaExceeded
+	case e.StatusCode >= http.StatusInternalServerError:
+		return ErrServer
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,142 @@
+package client
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// StatusEvent is a status change of a notification.
+type StatusEvent struct {
+	// ID identifies the event in the stream; pass it as LastEventID to resume after it.
+	ID             string    `json:"id"`
+	NotificationID uuid.UUID `json:"notification_id"`
+	Status         Status    `json:"status"`
+	Attempts       int       `json:"attempts"`
+	OccurredAt     time.Time `json:"occurred_at"`
+}
+
+// StreamOptions selects the events of StreamEvents.
+type StreamOptions struct {
+	// NotificationID limits the stream to a single notification.
+	NotificationID *uuid.UUID
+	// LastEventID resumes the stream after this event. The stream starts with
+	// events that happen after the call if it is empty.
+	LastEventID string
+}
+
+// errStreamEnded is reported when the server closes the event stream.
+var errStreamEnded = errors.New("event stream ended")
+
+// StreamEvents calls fn with each status event, oldest first, until ctx is done or fn
+// returns an error, which is then returned. Dropped connections are resumed after the
+// last received event, giving up after the client's retry limit of consecutive failures.
+func (c *Client) StreamEvents(ctx context.Context, opts StreamOptions, fn func(StatusEvent) error) error {
+	path := "/notifications/events"
+	if opts.NotificationID != nil {
+		path = "/notifications/" + opts.NotificationID.String() + "/events"
+	}
+
+	// The stream outlives any request timeout of the configured HTTP client.
+	httpClient := *c.httpClient
+	httpClient.Timeout = 0
+
+	lastEventID := opts.LastEventID
+	var fnErr error
+	for failures := 0; ; failures++ {
+		received := false
+		err := c.stream(ctx, &httpClient, path, lastEventID, func(e StatusEvent) error {
+			received = true
+			lastEventID = e.ID
+			if err := fn(e); err != nil {
+				fnErr = err
+				return err
+			}
+			return nil
+		})
+		switch {
+		case fnErr != nil:
+			return fnErr
+		case ctx.Err() != nil:
+			return ctx.Err()
+		}
+
+		var apiErr *APIError
+		if errors.As(err, &apiErr) {
+			if _, retryable := retryDelay(&http.Response{StatusCode: apiErr.StatusCode}); !retryable {
+				return err
+			}
+		}
+		if received {
+			failures = 0
+		}
+		if failures >= c.maxRetries {
+			return fmt.Errorf("client: streaming events: %w", err)
+		}
+		if err := c.wait(ctx, failures, 0); err != nil {
+			return err
+		}
+	}
+}
+
+// stream reads a single connection of the event stream.
+func (c *Client) stream(ctx context.Context, httpClient *http.Client, path, lastEventID string, fn func(StatusEvent) error) error {
+	req, err := c.newRequest(ctx, request{method: http.MethodGet, path: path}, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "text/event-stream")
+	if lastEventID != "" {
+		req.Header.Set("Last-Event-ID", lastEventID)
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return decodeResponse(resp, nil)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	// Server-sent events are blocks of "field: value" lines separated by a blank line.
+	// Lines starting with a colon are comments, sent as keep-alives.
+	var event, data string
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case line == "":
+			if event == "status" && data != "" {
+				var e StatusEvent
+				if err := json.Unmarshal([]byte(data), &e); err != nil {
+					return fmt.Errorf("client: failed to decode event: %w", err)
+				}
+				if err := fn(e); err != nil {
+					return err
+				}
+			}
+			event, data = "", ""
+		case strings.HasPrefix(line, ":"):
+		default:
+			field, value, _ := strings.Cut(line, ":")
+			value = strings.TrimPrefix(value, " ")
+			switch field {
+			case "event":
+				event = value
+			case "data":
+				data += value
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	return errStreamEnded
+}
@@ -0,0 +1,139 @@
+package client
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"net/http"
+	"time"
+)
+
+// Filter selects the notifications of a bulk job, like ListOptions. Only scheduled
+// notifications are changed, and at least one criterion besides the status is required.
+type Filter struct {
+	Status        Status         `json:"status,omitempty"`
+	Channel       Channel        `json:"channel,omitempty"`
+	AuthorID      string         `json:"author_id,omitempty"`
+	ScheduledFrom *time.Time     `json:"scheduled_from,omitempty"`
+	ScheduledTo   *time.Time     `json:"scheduled_to,omitempty"`
+	Search        string         `json:"search,omitempty"`
+	Tags          []string       `json:"tags,omitempty"`
+	Metadata      map[string]any `json:"metadata,omitempty"`
+}
+
+// BulkRescheduleRequest describes the new send time of the notifications of a bulk
+// reschedule: either ScheduledAt, or LocalTime in Timezone, which then replaces the
+// time zones of the notifications.
+type BulkRescheduleRequest struct {
+	Filter      Filter    `json:"filter"`
+	ScheduledAt time.Time `json:"scheduled_at,omitzero"`
+	LocalTime   string    `json:"local_time,omitempty"`
+	Timezone    string    `json:"timezone,omitempty"`
+}
+
+// JobAction is the change a bulk job applies to every notification it selects.
+type JobAction string
+
+const (
+	JobActionCancel     JobAction = "cancel"
+	JobActionReschedule JobAction = "reschedule"
+)
+
+// JobStatus is the status of a bulk job.
+type JobStatus string
+
+const (
+	JobRunning   JobStatus = "running"
+	JobCompleted JobStatus = "completed"
+	JobFailed    JobStatus = "failed"
+)
+
+// Job is a bulk job cancelling or rescheduling notifications in the background.
+type Job struct {
+	ID     uuid.UUID `json:"id"`
+	Action JobAction `json:"action"`
+	Status JobStatus `json:"status"`
+	// ScheduledAt and Timezone are the new send time of a reschedule job.
+	ScheduledAt *time.Time `json:"scheduled_at,omitempty"`
+	Timezone    string     `json:"timezone,omitempty"`
+	// Total is the number of notifications matching the filter when the job started,
+	// Processed the number changed so far, and Failed the number of rescheduled
+	// notifications that could not be queued again.
+	Total      int        `json:"total"`
+	Processed  int        `json:"processed"`
+	Failed     int        `json:"failed"`
+	Error      string     `json:"error,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+}
+
+// Done reports whether the job has finished, successfully or not.
+func (j *Job) Done() bool {
+	return j.Status != JobRunning
+}
+
+// BulkCancel starts a job cancelling every scheduled notification matching filter.
+func (c *Client) BulkCancel(ctx context.Context, filter Filter) (*Job, error) {
+	var job Job
+	err := c.do(ctx, request{
+		method: http.MethodPost,
+		path:   "/notifications:cancel",
+		body:   map[string]Filter{"filter": filter},
+	}, &job)
+	if err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+// BulkReschedule starts a job moving every scheduled notification matching req.Filter to a new time.
+func (c *Client) BulkReschedule(ctx context.Context, req BulkRescheduleRequest) (*Job, error) {
+	var job Job
+	if err := c.do(ctx, request{method: http.MethodPost, path: "/notifications:reschedule", body: req}, &job); err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+// CountMatching returns the number of scheduled notifications a bulk job with filter
+// would change, without changing any.
+func (c *Client) CountMatching(ctx context.Context, filter Filter) (int, error) {
+	var result struct {
+		Matched int `json:"matched"`
+	}
+	err := c.do(ctx, request{
+		method: http.MethodPost,
+		path:   "/notifications:cancel",
+		body:   map[string]any{"filter": filter, "dry_run": true},
+	}, &result)
+	if err != nil {
+		return 0, err
+	}
+	return result.Matched, nil
+}
+
+// GetJob returns a bulk job and its progress.
+func (c *Client) GetJob(ctx context.Context, id uuid.UUID) (*Job, error) {
+	var job Job
+	if err := c.do(ctx, request{method: http.MethodGet, path: "/jobs/" + id.String()}, &job); err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+// WaitJob polls a bulk job every interval until it has finished, and returns it.
+func (c *Client) WaitJob(ctx context.Context, id uuid.UUID, interval time.Duration) (*Job, error) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		job, err := c.GetJob(ctx, id)
+		if err != nil || job.Done() {
+			return job, err
+		}
+		select {
+		case <-ctx.Done():
+			return job, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,379 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/google/uuid"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// Channel is the delivery channel of a notification.
+type Channel string
+
+const (
+	ChannelEmail    Channel = "email"
+	ChannelTelegram Channel = "telegram"
+)
+
+// Status is the status of a notification.
+type Status string
+
+const (
+	StatusScheduled Status = "scheduled"
+	// StatusProcessing means a worker is sending the notification; it can no longer be
+	// cancelled or rescheduled.
+	StatusProcessing Status = "processing"
+	StatusSent       Status = "sent"
+	StatusFailed     Status = "failed"
+	StatusCancelled  Status = "cancelled"
+	StatusExpired    Status = "expired"
+)
+
+// Priority orders notifications waiting to be processed: higher priorities go first.
+type Priority string
+
+const (
+	PriorityLow      Priority = "low"
+	PriorityNormal   Priority = "normal"
+	PriorityHigh     Priority = "high"
+	PriorityCritical Priority = "critical"
+)
+
+// Notification is a scheduled notification as returned by the API.
+type Notification struct {
+	ID        uuid.UUID `json:"id"`
+	Status    Status    `json:"status"`
+	Channel   Channel   `json:"channel"`
+	Priority  Priority  `json:"priority"`
+	Recipient string    `json:"recipient"`
+	Subject   string    `json:"subject"`
+	Attempts  int       `json:"attempts"`
+	// Version is incremented by every change of the notification. RescheduleIfVersion
+	// and CancelIfVersion only change the notification while it has a given version.
+	Version     int       `json:"version"`
+	ScheduledAt time.Time `json:"scheduled_at"`
+	// Timezone is the time zone the notification was scheduled in, and LocalTime the
+	// scheduled time on the clocks there. Both are empty for absolute schedules.
+	Timezone    string     `json:"timezone,omitempty"`
+	LocalTime   string     `json:"local_time,omitempty"`
+	SentAt      *time.Time `json:"sent_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	CallbackURL *string    `json:"callback_url,omitempty"`
+	// DeliveryWindow is the notification's own delivery window, if any.
+	DeliveryWindow *DeliveryWindow `json:"delivery_window,omitempty"`
+	Tags           []string        `json:"tags,omitempty"`
+	Metadata       map[string]any  `json:"metadata,omitempty"`
+}
+
+// DeliveryWindow restricts when a notification may be delivered, e.g. on weekdays from
+// 09:00 to 18:00, or outside quiet hours from 22:00 to 07:00. Notifications falling
+// outside are deferred to the next opening of the window.
+type DeliveryWindow struct {
+	// Timezone is an IANA time zone such as "Europe/Berlin". For a notification's own
+	// window it defaults to CreateRequest.Timezone.
+	Timezone string `json:"timezone,omitempty"`
+	// Days are short weekday names such as "mon"; empty means every day.
+	Days []string `json:"days,omitempty"`
+	// Hours is the daily range of times during which delivery is allowed.
+	Hours *ClockRange `json:"hours,omitempty"`
+	// QuietHours is the daily range of times during which delivery is not allowed.
+	QuietHours *ClockRange `json:"quiet_hours,omitempty"`
+}
+
+// ClockRange is a daily range of times of day such as "09:00", from Start up to End.
+// It wraps past midnight if End is not after Start.
+type ClockRange struct {
+	Start string `json:"start"`
+	End   string `json:"end"`
+}
+
+// CreateRequest describes a notification to schedule.
+type CreateRequest struct {
+	// Recipient is an email address or a Telegram chat ID, depending on Channel.
+	Recipient string  `json:"recipient"`
+	Channel   Channel `json:"channel"`
+	Subject   string  `json:"subject"`
+	Message   string  `json:"message"`
+	// ScheduledAt is the absolute send time. Alternatively, set LocalTime and Timezone,
+	// Delay or SendNow.
+	ScheduledAt time.Time `json:"scheduled_at,omitzero"`
+	// LocalTime is a wall-clock date and time such as "2026-03-29T09:00", or a time of day
+	// such as "09:00" for its next occurrence, in Timezone.
+	LocalTime string `json:"local_time,omitempty"`
+	// Timezone is an IANA time zone such as "Europe/Berlin". It is stored with the
+	// notification, so that RescheduleLocal defaults to it.
+	Timezone string `json:"timezone,omitempty"`
+	// Delay is an ISO 8601 duration such as "PT15M" to send after, resolved on the
+	// server's clock so that the client's clock does not matter, see ISODuration.
+	Delay string `json:"delay,omitempty"`
+	// SendNow sends the notification immediately, skipping the delay queue.
+	SendNow bool `json:"send_now,omitempty"`
+	// Priority defaults to PriorityNormal.
+	Priority Priority `json:"priority,omitempty"`
+	// Tags and Metadata correlate the notification with your own entities, e.g. a campaign
+	// tag or {"order_id": "A-1001"}. They are returned with the notification, filterable
+	// with ListOptions and included in callbacks. Metadata must encode to at most 4096 bytes.
+	Tags     []string       `json:"tags,omitempty"`
+	Metadata map[string]any `json:"metadata,omitempty"`
+	// AuthorID is ignored by servers with authentication enabled.
+	AuthorID *string `json:"author_id,omitempty"`
+	// CallbackURL optionally receives a signed POST when the notification reaches a terminal status.
+	CallbackURL *string `json:"callback_url,omitempty"`
+	// DeliveryWindow optionally restricts when the notification may be delivered.
+	// Without one, the window of the recipient applies, see Client.SetRecipientWindow.
+	DeliveryWindow *DeliveryWindow `json:"delivery_window,omitempty"`
+	// IdempotencyKey identifies the create across retries. A random key is used if empty;
+	// set it to stay safe across process restarts too. Ignored for batch items.
+	IdempotencyKey string `json:"-"`
+}
+
+// ISODuration formats d as an ISO 8601 duration for CreateRequest.Delay, e.g. "PT1H30M".
+// Negative durations format as "PT0S".
+func ISODuration(d time.Duration) string {
+	if d <= 0 {
+		return "PT0S"
+	}
+	s := "PT"
+	if h := d / time.Hour; h > 0 {
+		s += strconv.FormatInt(int64(h), 10) + "H"
+		d -= h * time.Hour
+	}
+	if m := d / time.Minute; m > 0 {
+		s += strconv.FormatInt(int64(m), 10) + "M"
+		d -= m * time.Minute
+	}
+	if d > 0 {
+		s += strconv.FormatFloat(d.Seconds(), 'f', -1, 64) + "S"
+	}
+	return s
+}
+
+// BatchRequest describes several notifications to schedule at once.
+type BatchRequest struct {
+	Notifications []CreateRequest `json:"notifications"`
+	// IdempotencyKey identifies the batch across retries. A random key is used if empty.
+	IdempotencyKey string `json:"-"`
+}
+
+// BatchResult is the outcome of a single batch item.
+// Exactly one of Notification and Err is set.
+type BatchResult struct {
+	Notification *Notification
+	Err          error
+}
+
+type batchItemResponse struct {
+	Status       int           `json:"status"`
+	Notification *Notification `json:"notification,omitempty"`
+	Error        *problem      `json:"error,omitempty"`
+}
+
+type batchResponse struct {
+	Results []batchItemResponse `json:"results"`
+}
+
+// ListOptions filters and pages the notification list. Zero values are ignored.
+type ListOptions struct {
+	Status        Status
+	Channel       Channel
+	AuthorID      string
+	ScheduledFrom *time.Time
+	ScheduledTo   *time.Time
+	// Search matches a case-insensitive substring of the subject or email recipient,
+	// or a Telegram chat ID exactly.
+	Search string
+	// Tags matches notifications carrying all of the tags.
+	Tags []string
+	// Metadata matches notifications whose metadata contains the object, i.e. has all
+	// of its keys with equal values.
+	Metadata map[string]any
+	// Limit is the page size; the server default applies if zero.
+	Limit int
+	// PageToken is the NextPageToken of the previous page.
+	PageToken string
+}
+
+func (o ListOptions) values() (url.Values, error) {
+	q := url.Values{}
+	if o.Status != "" {
+		q.Set("status", string(o.Status))
+	}
+	if o.Channel != "" {
+		q.Set("channel", string(o.Channel))
+	}
+	if o.AuthorID != "" {
+		q.Set("author_id", o.AuthorID)
+	}
+	if o.ScheduledFrom != nil {
+		q.Set("scheduled_from", o.ScheduledFrom.Format(time.RFC3339))
+	}
+	if o.ScheduledTo != nil {
+		q.Set("scheduled_to", o.ScheduledTo.Format(time.RFC3339))
+	}
+	if o.Search != "" {
+		q.Set("search", o.Search)
+	}
+	for _, tag := range o.Tags {
+		q.Add("tag", tag)
+	}
+	if len(o.Metadata) > 0 {
+		metadata, err := json.Marshal(o.Metadata)
+		if err != nil {
+			return nil, fmt.Errorf("client: failed to encode metadata filter: %w", err)
+		}
+		q.Set("metadata", string(metadata))
+	}
+	if o.Limit > 0 {
+		q.Set("limit", strconv.Itoa(o.Limit))
+	}
+	if o.PageToken != "" {
+		q.Set("page_token", o.PageToken)
+	}
+	return q, nil
+}
+
+// ListResult is a page of notifications, newest first.
+type ListResult struct {
+	Notifications []Notification `json:"notifications"`
+	// NextPageToken is empty on the last page.
+	NextPageToken string `json:"next_page_token,omitempty"`
+}
+
+// Create schedules a notification.
+func (c *Client) Create(ctx context.Context, req CreateRequest) (*Notification, error) {
+	var n Notification
+	err := c.do(ctx, request{
+		method:         http.MethodPost,
+		path:           "/notifications",
+		body:           req,
+		idempotencyKey: idempotencyKey(req.IdempotencyKey),
+	}, &n)
+	if err != nil {
+		return nil, err
+	}
+	return &n, nil
+}
+
+// Batch schedules several notifications. Items are created independently; the
+// results are in request order and report each item's error separately.
+func (c *Client) Batch(ctx context.Context, req BatchRequest) ([]BatchResult, error) {
+	var resp batchResponse
+	err := c.do(ctx, request{
+		method:         http.MethodPost,
+		path:           "/notifications/batch",
+		body:           req,
+		idempotencyKey: idempotencyKey(req.IdempotencyKey),
+	}, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]BatchResult, len(resp.Results))
+	for i, item := range resp.Results {
+		if item.Error != nil {
+			results[i].Err = item.Error.apiError(item.Status)
+			continue
+		}
+		results[i].Notification = item.Notification
+	}
+	return results, nil
+}
+
+// Get returns a notification by ID.
+func (c *Client) Get(ctx context.Context, id uuid.UUID) (*Notification, error) {
+	var n Notification
+	if err := c.do(ctx, request{method: http.MethodGet, path: "/notifications/" + id.String()}, &n); err != nil {
+		return nil, err
+	}
+	return &n, nil
+}
+
+// Reschedule moves a scheduled notification to a new time.
+func (c *Client) Reschedule(ctx context.Context, id uuid.UUID, scheduledAt time.Time) (*Notification, error) {
+	return c.reschedule(ctx, id, scheduledAt, "")
+}
+
+// RescheduleIfVersion is like Reschedule, but fails with ErrConflict if the notification
+// no longer has the given version, e.g. because it was changed since it was read.
+func (c *Client) RescheduleIfVersion(ctx context.Context, id uuid.UUID, scheduledAt time.Time, version int) (*Notification, error) {
+	return c.reschedule(ctx, id, scheduledAt, entityTag(version))
+}
+
+func (c *Client) reschedule(ctx context.Context, id uuid.UUID, scheduledAt time.Time, ifMatch string) (*Notification, error) {
+	var n Notification
+	err := c.do(ctx, request{
+		method:  http.MethodPatch,
+		path:    "/notifications/" + id.String(),
+		body:    map[string]time.Time{"scheduled_at": scheduledAt},
+		ifMatch: ifMatch,
+	}, &n)
+	if err != nil {
+		return nil, err
+	}
+	return &n, nil
+}
+
+// RescheduleLocal moves a scheduled notification to a local wall-clock time, see
+// CreateRequest.LocalTime. An empty timezone uses the notification's time zone;
+// otherwise timezone replaces it.
+func (c *Client) RescheduleLocal(ctx context.Context, id uuid.UUID, localTime, timezone string) (*Notification, error) {
+	body := map[string]string{"local_time": localTime}
+	if timezone != "" {
+		body["timezone"] = timezone
+	}
+	var n Notification
+	err := c.do(ctx, request{
+		method: http.MethodPatch,
+		path:   "/notifications/" + id.String(),
+		body:   body,
+	}, &n)
+	if err != nil {
+		return nil, err
+	}
+	return &n, nil
+}
+
+// Cancel cancels a scheduled notification.
+func (c *Client) Cancel(ctx context.Context, id uuid.UUID) error {
+	return c.do(ctx, request{method: http.MethodDelete, path: "/notifications/" + id.String()}, nil)
+}
+
+// CancelIfVersion is like Cancel, but fails with ErrConflict if the notification no
+// longer has the given version.
+func (c *Client) CancelIfVersion(ctx context.Context, id uuid.UUID, version int) error {
+	return c.do(ctx, request{
+		method:  http.MethodDelete,
+		path:    "/notifications/" + id.String(),
+		ifMatch: entityTag(version),
+	}, nil)
+}
+
+// List returns a page of notifications matching opts, newest first.
+func (c *Client) List(ctx context.Context, opts ListOptions) (*ListResult, error) {
+	query, err := opts.values()
+	if err != nil {
+		return nil, err
+	}
+	var result ListResult
+	if err := c.do(ctx, request{method: http.MethodGet, path: "/notifications", query: query}, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// entityTag returns the entity tag of a notification version, as sent in ETag headers.
+func entityTag(version int) string {
+	return strconv.Quote(strconv.Itoa(version))
+}
+
+// idempotencyKey returns key, or a random key if it is empty.
+func idempotencyKey(key string) string {
+	if key != "" {
+		return key
+	}
+	return uuid.NewString()
+}
@@ -0,0 +1,37 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
+
+// GetRecipientWindow returns the delivery window of a recipient. It returns an error
+// matching ErrNotFound if the recipient has none.
+func (c *Client) GetRecipientWindow(ctx context.Context, channel Channel, recipient string) (*DeliveryWindow, error) {
+	var w DeliveryWindow
+	if err := c.do(ctx, request{method: http.MethodGet, path: recipientWindowPath(channel, recipient)}, &w); err != nil {
+		return nil, err
+	}
+	return &w, nil
+}
+
+// SetRecipientWindow sets the delivery window of a recipient, which applies to all their
+// notifications without a window of their own. The window's time zone is required.
+func (c *Client) SetRecipientWindow(ctx context.Context, channel Channel, recipient string, window DeliveryWindow) (*DeliveryWindow, error) {
+	var w DeliveryWindow
+	err := c.do(ctx, request{method: http.MethodPut, path: recipientWindowPath(channel, recipient), body: window}, &w)
+	if err != nil {
+		return nil, err
+	}
+	return &w, nil
+}
+
+// DeleteRecipientWindow removes the delivery window of a recipient.
+func (c *Client) DeleteRecipientWindow(ctx context.Context, channel Channel, recipient string) error {
+	return c.do(ctx, request{method: http.MethodDelete, path: recipientWindowPath(channel, recipient)}, nil)
+}
+
+func recipientWindowPath(channel Channel, recipient string) string {
+	return "/recipients/" + string(channel) + "/" + url.PathEscape(recipient) + "/delivery-window"
+}
@@ -3,13 +3,39 @@ package keybuilder
 import (
 	"fmt"
 	"github.com/google/uuid"
+	"strings"
 )
 
 const (
 	Redis        string = "redis"
 	Notification string = "notification"
+	RateLimit    string = "ratelimit"
+	Quota        string = "quota"
+	Events       string = "events"
+	BulkJob      string = "bulkjob"
 )
 
-func RedisNotificationKeyBuild(id uuid.UUID) string {
-	return fmt.Sprintf("%s:%s:%s", Redis, Notification, id)
+// RedisNotificationKeyBuild builds the cache key of a tenant's notification, e.g. "redis:notification:default:<id>".
+func RedisNotificationKeyBuild(tenantID string, id uuid.UUID) string {
+	return fmt.Sprintf("%s:%s:%s:%s", Redis, Notification, tenantID, id)
+}
+
+// RedisRateLimitKeyBuild builds the key of a rate limit bucket, e.g. "redis:ratelimit:default:telegram:42".
+func RedisRateLimitKeyBuild(parts ...string) string {
+	return fmt.Sprintf("%s:%s:%s", Redis, RateLimit, strings.Join(parts, ":"))
+}
+
+// RedisQuotaKeyBuild builds the key of a tenant's daily quota counter, e.g. "redis:quota:default:2025-09-30".
+func RedisQuotaKeyBuild(tenantID string, day string) string {
+	return fmt.Sprintf("%s:%s:%s:%s", Redis, Quota, tenantID, day)
+}
+
+// RedisEventsKeyBuild builds the key of a tenant's status event stream, e.g. "redis:events:default".
+func RedisEventsKeyBuild(tenantID string) string {
+	return fmt.Sprintf("%s:%s:%s", Redis, Events, tenantID)
+}
+
+// RedisBulkJobKeyBuild builds the key of a tenant's bulk job, e.g. "redis:bulkjob:default:<id>".
+func RedisBulkJobKeyBuild(tenantID string, id uuid.UUID) string {
+	return fmt.Sprintf("%s:%s:%s:%s", Redis, BulkJob, tenantID, id)
 }
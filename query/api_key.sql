@@ -0,0 +1,41 @@
+-- name: CreateAPIKey :one
+-- This query inserts a new API key. Only the hash of the key is stored.
+INSERT INTO api_keys (
+                      name,
+                      key_prefix,
+                      key_hash,
+                      owner_id,
+                      scopes,
+                      tenant_id
+) VALUES (
+          $1, $2, $3, $4, $5, $6
+         )
+RETURNING *;
+
+-- name: GetActiveAPIKeyByHash :one
+-- This query finds a non-revoked API key by the hash of its raw value.
+SELECT * FROM api_keys
+WHERE key_hash = $1 AND revoked_at IS NULL;
+
+-- name: ListAPIKeys :many
+-- This query lists all API keys of a tenant, newest first.
+SELECT * FROM api_keys
+WHERE tenant_id = $1
+ORDER BY created_at DESC;
+
+-- name: RevokeAPIKey :one
+-- This query revokes an API key of a tenant. Revoked keys are kept for auditing.
+UPDATE api_keys
+SET
+    revoked_at = NOW()
+WHERE
+    id = $1 AND tenant_id = $2 AND revoked_at IS NULL
+RETURNING *;
+
+-- name: TouchAPIKey :exec
+-- This query records the last time an API key was used.
+UPDATE api_keys
+SET
+    last_used_at = NOW()
+WHERE
+    id = $1;
@@ -0,0 +1,23 @@
+-- name: CreateCallbackDelivery :one
+-- This query records a single callback delivery attempt.
+INSERT INTO callback_deliveries (
+                                 event_id,
+                                 notification_id,
+                                 tenant_id,
+                                 event,
+                                 url,
+                                 attempt,
+                                 status_code,
+                                 error,
+                                 duration_ms,
+                                 delivered
+) VALUES (
+          $1, $2, $3, $4, $5, $6, $7, $8, $9, $10
+         )
+RETURNING *;
+
+-- name: ListCallbackDeliveries :many
+-- This query lists the callback delivery attempts of a tenant's notification, oldest first.
+SELECT * FROM callback_deliveries
+WHERE tenant_id = $1 AND notification_id = $2
+ORDER BY created_at;
@@ -0,0 +1,26 @@
+-- name: GetRecipientDeliveryWindow :one
+-- This query retrieves the delivery window of a tenant's recipient.
+SELECT * FROM recipient_delivery_windows
+WHERE tenant_id = $1 AND channel = $2 AND recipient = $3;
+
+-- name: UpsertRecipientDeliveryWindow :one
+-- This query sets the delivery window of a tenant's recipient, replacing any previous one.
+INSERT INTO recipient_delivery_windows (
+                                        tenant_id,
+                                        channel,
+                                        recipient,
+                                        delivery_window
+) VALUES (
+          $1, $2, $3, $4
+         )
+ON CONFLICT (tenant_id, channel, recipient) DO UPDATE
+SET
+    delivery_window = EXCLUDED.delivery_window,
+    updated_at = NOW()
+RETURNING *;
+
+-- name: DeleteRecipientDeliveryWindow :one
+-- This query removes the delivery window of a tenant's recipient.
+DELETE FROM recipient_delivery_windows
+WHERE tenant_id = $1 AND channel = $2 AND recipient = $3
+RETURNING *;
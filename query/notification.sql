@@ -1,6 +1,9 @@
 -- name: CreateNotification :one
--- This query inserts a new notification into the database.
+-- This query inserts a new notification into the database. The ID is chosen by the caller,
+-- e.g. derived from an idempotency key, so that a retried create hits the primary key;
+-- without one the database generates it.
 INSERT INTO notifications (
+                           id,
                            subject,
                            message,
                            author_id,
@@ -9,35 +12,133 @@ INSERT INTO notifications (
                            channel,
                            status,
                            attempts,
-                           scheduled_at
+                           scheduled_at,
+                           tenant_id,
+                           callback_url,
+                           timezone,
+                           delivery_window,
+                           priority,
+                           tags,
+                           metadata,
+                           request_hash
 ) VALUES (
-          $1, $2, $3, $4, $5, $6, $7, $8, $9
+          COALESCE(sqlc.narg(id)::uuid, gen_random_uuid()),
+          $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17
          )
 RETURNING *;
 
 -- name: GetNotificationByID :one
--- This query retrieves a single notification by its unique UUID.
+-- This query retrieves a single notification of a tenant by its unique UUID.
 SELECT * FROM notifications
-WHERE id = $1;
+WHERE id = $1 AND tenant_id = $2;
 
 
 -- name: UpdateNotificationStatus :one
--- This query updates the status, attempts count, and sent_at timestamp of a notification.
+-- This query moves a notification to a new status and updates its attempts count and sent_at timestamp.
+-- The update only applies while the notification is still in the expected status and version, so that
+-- of two concurrent updates, e.g. a cancel racing with a send, only one succeeds.
 UPDATE notifications
 SET
-    status = $2,
-    attempts = $3,
-    sent_at = $4
+    status = sqlc.arg(status),
+    attempts = sqlc.arg(attempts),
+    sent_at = sqlc.arg(sent_at),
+    version = version + 1
 WHERE
-    id = $1
+    id = sqlc.arg(id) AND tenant_id = sqlc.arg(tenant_id) AND status = sqlc.arg(from_status)
+    AND version = sqlc.arg(version)
 RETURNING *;
 
 -- name: CancelNotification :one
 -- This query performs a "soft delete" by changing the status to 'cancelled'.
--- We never truly delete data, we just change its state.
+-- We never truly delete data, we just change its state. Only scheduled notifications can be cancelled,
+-- and only in the given version, if it is not NULL.
 UPDATE notifications
 SET
-    status = 'cancelled'
+    status = 'cancelled',
+    version = version + 1
 WHERE
-    id = $1
-RETURNING *;
\ No newline at end of file
+    id = sqlc.arg(id) AND tenant_id = sqlc.arg(tenant_id) AND status = 'scheduled'
+    AND (sqlc.narg(version)::integer IS NULL OR version = sqlc.narg(version))
+RETURNING *;
+
+-- name: RescheduleNotification :one
+-- This query moves a notification that is still scheduled to a new time and resets its attempts.
+-- A NULL time zone keeps the stored one. A non-NULL version must match the stored one.
+UPDATE notifications
+SET
+    scheduled_at = sqlc.arg(scheduled_at),
+    timezone = COALESCE(sqlc.narg(timezone), timezone),
+    attempts = 0,
+    version = version + 1
+WHERE
+    id = sqlc.arg(id) AND tenant_id = sqlc.arg(tenant_id) AND status = 'scheduled'
+    AND (sqlc.narg(version)::integer IS NULL OR version = sqlc.narg(version))
+RETURNING *;
+
+-- name: CancelNotifications :many
+-- This query cancels those of the given notifications of a tenant that are still scheduled.
+UPDATE notifications
+SET
+    status = 'cancelled',
+    version = version + 1
+WHERE
+    id = ANY(sqlc.arg(ids)::uuid[]) AND tenant_id = sqlc.arg(tenant_id) AND status = 'scheduled'
+RETURNING *;
+
+-- name: RescheduleNotifications :many
+-- This query reschedules those of the given notifications of a tenant that are still scheduled,
+-- like RescheduleNotification.
+UPDATE notifications
+SET
+    scheduled_at = sqlc.arg(scheduled_at),
+    timezone = COALESCE(sqlc.narg(timezone), timezone),
+    attempts = 0,
+    version = version + 1
+WHERE
+    id = ANY(sqlc.arg(ids)::uuid[]) AND tenant_id = sqlc.arg(tenant_id) AND status = 'scheduled'
+RETURNING *;
+
+-- name: ListNotifications :many
+-- This query lists a tenant's notifications, newest first, with optional filters.
+-- Pagination is keyset-based: pass the created_at and id of the last row of the previous page.
+SELECT * FROM notifications
+WHERE
+    tenant_id = sqlc.arg(tenant_id)
+    AND (sqlc.narg(status)::notification_status IS NULL OR status = sqlc.narg(status))
+    AND (sqlc.narg(channel)::channel_type IS NULL OR channel = sqlc.narg(channel))
+    AND (sqlc.narg(author_id)::text IS NULL OR author_id = sqlc.narg(author_id))
+    AND (sqlc.narg(scheduled_from)::timestamptz IS NULL OR scheduled_at >= sqlc.narg(scheduled_from))
+    AND (sqlc.narg(scheduled_to)::timestamptz IS NULL OR scheduled_at < sqlc.narg(scheduled_to))
+    AND (
+        sqlc.narg(search)::text IS NULL
+        OR strpos(lower(subject), lower(sqlc.narg(search))) > 0
+        OR strpos(lower(email_to), lower(sqlc.narg(search))) > 0
+        OR telegram_chat_id::text = sqlc.narg(search)
+    )
+    AND (sqlc.narg(tags)::text[] IS NULL OR tags @> sqlc.narg(tags))
+    AND (sqlc.narg(metadata)::jsonb IS NULL OR metadata @> sqlc.narg(metadata))
+    AND (
+        sqlc.narg(after_created_at)::timestamptz IS NULL
+        OR (created_at, id) < (sqlc.narg(after_created_at), sqlc.narg(after_id)::uuid)
+    )
+ORDER BY created_at DESC, id DESC
+LIMIT sqlc.arg(page_limit);
+
+-- name: CountNotifications :one
+-- This query counts a tenant's notifications matching the filters of ListNotifications.
+SELECT count(*) FROM notifications
+WHERE
+    tenant_id = sqlc.arg(tenant_id)
+    AND (sqlc.narg(status)::notification_status IS NULL OR status = sqlc.narg(status))
+    AND (sqlc.narg(channel)::channel_type IS NULL OR channel = sqlc.narg(channel))
+    AND (sqlc.narg(author_id)::text IS NULL OR author_id = sqlc.narg(author_id))
+    AND (sqlc.narg(scheduled_from)::timestamptz IS NULL OR scheduled_at >= sqlc.narg(scheduled_from))
+    AND (sqlc.narg(scheduled_to)::timestamptz IS NULL OR scheduled_at < sqlc.narg(scheduled_to))
+    AND (
+        sqlc.narg(search)::text IS NULL
+        OR strpos(lower(subject), lower(sqlc.narg(search))) > 0
+        OR strpos(lower(email_to), lower(sqlc.narg(search))) > 0
+        OR telegram_chat_id::text = sqlc.narg(search)
+    )
+    AND (sqlc.narg(tags)::text[] IS NULL OR tags @> sqlc.narg(tags))
+    AND (sqlc.narg(metadata)::jsonb IS NULL OR metadata @> sqlc.narg(metadata));
@@ -0,0 +1,570 @@
+// Web admin UI of the delayed-notifier API. It needs no build step: it calls the
+// /api/v1 REST API with the credential saved in localStorage and follows status
+// changes through the server-sent event streams.
+"use strict";
+
+const API = "/api/v1";
+const PAGE_SIZE = 50;
+const QUEUE_REFRESH_MS = 5000;
+const RECONNECT_MS = 3000;
+const DEAD_LETTER_LIMIT = 20;
+
+const $ = (selector) => document.querySelector(selector);
+
+const state = {
+  filters: {},
+  nextPageToken: "",
+  // The notification shown in the detail panel.
+  selected: null,
+  // AbortControllers of the open event streams.
+  listStream: null,
+  detailStream: null,
+  queueTimer: 0,
+};
+
+// === API access ===
+
+class APIError extends Error {
+  // problem is the RFC 7807 body of the error response, if it had one.
+  constructor(status, problem, fallback) {
+    const fields = ((problem && problem.errors) || []).map((e) => e.message);
+    super(fields.length ? fields.join("; ") : (problem && (problem.detail || problem.title)) || fallback);
+    this.status = status;
+    this.code = problem && problem.code;
+  }
+}
+
+function authHeaders() {
+  const secret = localStorage.getItem("notifier.secret");
+  if (!secret) {
+    return {};
+  }
+  if (localStorage.getItem("notifier.kind") === "token") {
+    return { Authorization: "Bearer " + secret };
+  }
+  return { "X-API-Key": secret };
+}
+
+// api sends a request to the API. A notification passed as ifMatch makes the request
+// conditional on its version, so that it fails if the notification changed meanwhile.
+async function api(method, path, body, ifMatch) {
+  const init = { method, headers: authHeaders() };
+  if (ifMatch) {
+    init.headers["If-Match"] = '"' + ifMatch.version + '"';
+  }
+  if (body !== undefined) {
+    init.headers["Content-Type"] = "application/json";
+    init.body = JSON.stringify(body);
+  }
+  const resp = await fetch(API + path, init);
+  if (resp.status === 204) {
+    return null;
+  }
+  const data = await resp.json().catch(() => null);
+  if (!resp.ok) {
+    throw new APIError(resp.status, data, resp.statusText);
+  }
+  return data;
+}
+
+// streamEvents calls onEvent for every status event of the server-sent event stream
+// at path, reconnecting with the last seen event ID until signal is aborted.
+// It uses fetch because EventSource cannot send the credential headers.
+async function streamEvents(path, lastEventID, signal, onEvent) {
+  while (!signal.aborted) {
+    try {
+      const query = lastEventID ? "?last_event_id=" + encodeURIComponent(lastEventID) : "";
+      const resp = await fetch(API + path + query, { headers: authHeaders(), signal });
+      if (!resp.ok) {
+        const data = await resp.json().catch(() => null);
+        throw new APIError(resp.status, data, resp.statusText);
+      }
+      const reader = resp.body.pipeThrough(new TextDecoderStream()).getReader();
+      let buffer = "";
+      for (;;) {
+        const { value, done } = await reader.read();
+        if (done) {
+          break;
+        }
+        buffer += value;
+        let end;
+        while ((end = buffer.indexOf("\n\n")) >= 0) {
+          const event = parseEvent(buffer.slice(0, end));
+          buffer = buffer.slice(end + 2);
+          if (event) {
+            lastEventID = event.id;
+            onEvent(event);
+          }
+        }
+      }
+    } catch (err) {
+      if (signal.aborted) {
+        return;
+      }
+      if (err instanceof APIError && err.status < 500) {
+        // Retrying cannot help, e.g. with a missing scope.
+        showError(err);
+        return;
+      }
+    }
+    await sleep(RECONNECT_MS);
+  }
+}
+
+// parseEvent returns the JSON data of a server-sent event, or null for comments.
+function parseEvent(frame) {
+  let data = "";
+  for (const line of frame.split("\n")) {
+    if (line.startsWith("data:")) {
+      data += line.slice(5).trim();
+    }
+  }
+  return data ? JSON.parse(data) : null;
+}
+
+// === Rendering helpers ===
+
+// el creates an element. Attributes starting with "on" become event listeners;
+// string children become text nodes, so user data is never parsed as HTML.
+function el(tag, attrs, ...children) {
+  const node = document.createElement(tag);
+  for (const [name, value] of Object.entries(attrs || {})) {
+    if (name.startsWith("on")) {
+      node.addEventListener(name.slice(2), value);
+    } else {
+      node.setAttribute(name, value);
+    }
+  }
+  node.append(...children.filter((child) => child !== null && child !== undefined));
+  return node;
+}
+
+function badge(status) {
+  return el("span", { class: "badge " + status }, status);
+}
+
+function formatTime(value) {
+  return value ? new Date(value).toLocaleString() : "—";
+}
+
+// browserZone is the IANA time zone of the browser.
+const browserZone = Intl.DateTimeFormat().resolvedOptions().timeZone;
+
+// toLocalInput formats a time for a datetime-local input, as wall-clock time in zone.
+function toLocalInput(value, zone) {
+  const parts = Object.fromEntries(new Intl.DateTimeFormat("en-CA", {
+    timeZone: zone || browserZone,
+    year: "numeric", month: "2-digit", day: "2-digit",
+    hour: "2-digit", minute: "2-digit", hourCycle: "h23",
+  }).formatToParts(new Date(value)).map((p) => [p.type, p.value]));
+  return `${parts.year}-${parts.month}-${parts.day}T${parts.hour}:${parts.minute}`;
+}
+
+function sleep(ms) {
+  return new Promise((resolve) => setTimeout(resolve, ms));
+}
+
+let flashTimer = 0;
+
+function flash(message, kind) {
+  const box = $("#flash");
+  box.textContent = message;
+  box.className = kind || "info";
+  box.hidden = false;
+  clearTimeout(flashTimer);
+  flashTimer = setTimeout(() => {
+    box.hidden = true;
+  }, 6000);
+}
+
+function showError(err) {
+  if (err instanceof APIError && err.status === 401) {
+    flash("Authentication required: enter an API key or token.", "error");
+    $("#credentials [name=secret]").focus();
+    return;
+  }
+  if (err instanceof APIError && err.code === "version_conflict") {
+    flash("The notification was changed meanwhile. Check its new state and try again.", "error");
+    return;
+  }
+  flash(err.message, "error");
+}
+
+// === Notification list ===
+
+function notificationRow(n) {
+  const cancel = n.status === "scheduled"
+    ? el("button", {
+      type: "button",
+      class: "danger small",
+      onclick: (e) => {
+        e.stopPropagation();
+        cancelNotification(n);
+      },
+    }, "Cancel")
+    : null;
+
+  return el("tr", { "data-id": n.id, class: "clickable", onclick: () => showDetail(n.id) },
+    el("td", null, badge(n.status)),
+    el("td", null, n.channel),
+    el("td", null, n.recipient),
+    el("td", { class: "subject" }, n.subject),
+    el("td", null, formatTime(n.scheduled_at)),
+    el("td", { class: "num" }, String(n.attempts)),
+    el("td", { class: "actions" }, cancel),
+  );
+}
+
+async function loadNotifications(more) {
+  const params = new URLSearchParams({ limit: PAGE_SIZE });
+  for (const [name, value] of Object.entries(state.filters)) {
+    if (value) {
+      params.set(name, value);
+    }
+  }
+  if (more) {
+    params.set("page_token", state.nextPageToken);
+  }
+
+  let page;
+  try {
+    page = await api("GET", "/notifications?" + params);
+  } catch (err) {
+    showError(err);
+    return;
+  }
+
+  const body = $("#notifications tbody");
+  if (!more) {
+    body.replaceChildren();
+  }
+  body.append(...page.notifications.map(notificationRow));
+  state.nextPageToken = page.next_page_token || "";
+  $("#load-more").hidden = !state.nextPageToken;
+  $("#no-notifications").hidden = body.children.length > 0;
+}
+
+// refreshNotification shows the current state of n in the list and the detail panel.
+function refreshNotification(n) {
+  const row = document.querySelector(`#notifications tr[data-id="${n.id}"]`);
+  if (row) {
+    row.replaceWith(notificationRow(n));
+  }
+  if (state.selected && state.selected.id === n.id) {
+    state.selected = n;
+    renderDetail();
+  }
+}
+
+// applyEvent reloads a listed or selected notification after a status event,
+// since events do not carry every field, e.g. sent_at.
+function applyEvent(event) {
+  const id = event.notification_id;
+  const shown = (state.selected && state.selected.id === id) ||
+    document.querySelector(`#notifications tr[data-id="${id}"]`);
+  if (shown) {
+    api("GET", "/notifications/" + id).then(refreshNotification, () => {});
+  }
+}
+
+function followList() {
+  if (state.listStream) {
+    state.listStream.abort();
+  }
+  state.listStream = new AbortController();
+  streamEvents("/notifications/events", "", state.listStream.signal, applyEvent);
+}
+
+async function cancelNotification(n) {
+  if (!confirm("Cancel this notification? It will not be sent.")) {
+    return;
+  }
+  try {
+    await api("DELETE", "/notifications/" + n.id, undefined, n);
+    flash("Notification cancelled.");
+  } catch (err) {
+    showError(err);
+  }
+  api("GET", "/notifications/" + n.id).then(refreshNotification, () => {});
+}
+
+// === Detail panel ===
+
+async function showDetail(id) {
+  closeDetail();
+  let n;
+  try {
+    n = await api("GET", "/notifications/" + id);
+  } catch (err) {
+    showError(err);
+    return;
+  }
+
+  state.selected = n;
+  renderDetail();
+  $("#history tbody").replaceChildren();
+  $("#detail").hidden = false;
+  loadCallbacks(id);
+
+  // Replay the notification's events from the start of the stream, then follow it live.
+  state.detailStream = new AbortController();
+  $("#history-live").hidden = false;
+  streamEvents("/notifications/" + id + "/events", "0-0", state.detailStream.signal, (event) => {
+    $("#history tbody").append(el("tr", null,
+      el("td", null, formatTime(event.occurred_at)),
+      el("td", null, badge(event.status)),
+      el("td", { class: "num" }, String(event.attempts)),
+    ));
+  }).finally(() => {
+    $("#history-live").hidden = true;
+  });
+}
+
+// formatWindow describes a delivery window in one line, e.g. "mon,tue 09:00–18:00 Europe/Berlin".
+function formatWindow(w) {
+  if (!w) {
+    return "—";
+  }
+  const parts = [];
+  if (w.days && w.days.length) {
+    parts.push(w.days.join(","));
+  }
+  if (w.hours) {
+    parts.push(w.hours.start + "–" + w.hours.end);
+  }
+  if (w.quiet_hours) {
+    parts.push("quiet " + w.quiet_hours.start + "–" + w.quiet_hours.end);
+  }
+  parts.push(w.timezone);
+  return parts.join(" ");
+}
+
+function renderDetail() {
+  const n = state.selected;
+  $("#detail-title").textContent = n.subject;
+
+  const fields = [
+    ["ID", n.id],
+    ["Status", badge(n.status)],
+    ["Channel", n.channel],
+    ["Priority", n.priority],
+    ["Recipient", n.recipient],
+    ["Scheduled at", formatTime(n.scheduled_at)],
+    ["Local time", n.timezone ? n.local_time + " " + n.timezone : "—"],
+    ["Delivery window", formatWindow(n.delivery_window)],
+    ["Tags", n.tags ? n.tags.join(", ") : "—"],
+    ["Metadata", n.metadata ? JSON.stringify(n.metadata) : "—"],
+    ["Sent at", formatTime(n.sent_at)],
+    ["Attempts", String(n.attempts)],
+    ["Version", String(n.version)],
+    ["Created at", formatTime(n.created_at)],
+    ["Callback URL", n.callback_url || "—"],
+  ];
+  $("#detail-fields").replaceChildren(...fields.flatMap(([name, value]) => [el("dt", null, name), el("dd", null, value)]));
+
+  $("#detail-actions").hidden = n.status !== "scheduled";
+  const zone = n.timezone || browserZone;
+  $("#reschedule-form [name=local_time]").value = toLocalInput(n.scheduled_at, zone);
+  $("#reschedule-form [name=timezone]").value = zone;
+}
+
+async function loadCallbacks(id) {
+  let deliveries;
+  try {
+    deliveries = await api("GET", "/notifications/" + id + "/callbacks");
+  } catch (err) {
+    showError(err);
+    return;
+  }
+  $("#callbacks tbody").replaceChildren(...deliveries.map((d) => el("tr", null,
+    el("td", null, formatTime(d.created_at)),
+    el("td", null, d.event),
+    el("td", { class: "num" }, String(d.attempt)),
+    el("td", { class: d.delivered ? "ok" : "failed" }, d.delivered ? "delivered" : d.error || "HTTP " + d.status_code),
+    el("td", { class: "num" }, d.duration_ms + " ms"),
+  )));
+  $("#no-callbacks").hidden = deliveries.length > 0;
+}
+
+function closeDetail() {
+  if (state.detailStream) {
+    state.detailStream.abort();
+    state.detailStream = null;
+  }
+  state.selected = null;
+  $("#detail").hidden = true;
+}
+
+async function reschedule(e) {
+  e.preventDefault();
+  const { local_time, timezone } = e.target.elements;
+  try {
+    const n = await api("PATCH", "/notifications/" + state.selected.id, {
+      local_time: local_time.value,
+      timezone: timezone.value.trim(),
+    }, state.selected);
+    refreshNotification(n);
+    flash("Notification rescheduled to " + formatTime(n.scheduled_at) + ".");
+  } catch (err) {
+    showError(err);
+    if (err instanceof APIError && err.code === "version_conflict") {
+      api("GET", "/notifications/" + state.selected.id).then(refreshNotification, () => {});
+    }
+  }
+}
+
+// === Create dialog ===
+
+function openCreate() {
+  const form = $("#create-form");
+  form.reset();
+  form.elements.local_time.disabled = false;
+  form.elements.local_time.value = toLocalInput(Date.now() + 3600 * 1000);
+  form.elements.timezone.value = browserZone;
+  $("#create-dialog").showModal();
+}
+
+async function create(e) {
+  e.preventDefault();
+  const form = e.target;
+  const fields = Object.fromEntries(new FormData(form));
+  const body = {
+    channel: fields.channel,
+    priority: fields.priority,
+    recipient: fields.recipient.trim(),
+    subject: fields.subject,
+    message: fields.message,
+    timezone: fields.timezone.trim(),
+  };
+  // Disabled fields are not submitted, so local_time is missing when sending now.
+  if (fields.send_now) {
+    body.send_now = true;
+  } else {
+    body.local_time = fields.local_time;
+  }
+  const tags = fields.tags.split(",").map((tag) => tag.trim()).filter(Boolean);
+  if (tags.length) {
+    body.tags = tags;
+  }
+  if (fields.callback_url) {
+    body.callback_url = fields.callback_url;
+  }
+
+  const submit = form.querySelector("[type=submit]");
+  submit.disabled = true;
+  try {
+    const n = await api("POST", "/notifications", body);
+    $("#create-dialog").close();
+    flash(body.send_now ? "Notification queued for sending now." : "Notification scheduled for " + formatTime(n.scheduled_at) + ".");
+    await loadNotifications(false);
+  } catch (err) {
+    showError(err);
+  } finally {
+    submit.disabled = false;
+  }
+}
+
+// === Queues ===
+
+async function loadQueues() {
+  try {
+    const [queues, letters] = await Promise.all([
+      api("GET", "/admin/queues"),
+      api("GET", "/admin/dead-letters?limit=" + DEAD_LETTER_LIMIT),
+    ]);
+    $("#queues tbody").replaceChildren(...queues.map((q) => el("tr", null,
+      el("td", null, q.name),
+      el("td", { class: "num" }, String(q.messages)),
+      el("td", { class: "num" + (q.consumers === 0 ? " failed" : "") }, String(q.consumers)),
+    )));
+    $("#dead-letters tbody").replaceChildren(...letters.map((d) => el("tr", null,
+      el("td", null, formatTime(d.dead_at)),
+      el("td", null, d.reason),
+      el("td", null, d.message_id),
+      el("td", null, el("code", { title: d.body }, d.body.length > 120 ? d.body.slice(0, 120) + "…" : d.body)),
+    )));
+    $("#no-dead-letters").hidden = letters.length > 0;
+    $("#queues-updated").textContent = "updated " + new Date().toLocaleTimeString();
+  } catch (err) {
+    stopQueues();
+    showError(err);
+  }
+}
+
+function startQueues() {
+  stopQueues();
+  loadQueues();
+  state.queueTimer = setInterval(loadQueues, QUEUE_REFRESH_MS);
+}
+
+function stopQueues() {
+  clearInterval(state.queueTimer);
+  state.queueTimer = 0;
+}
+
+// === Wiring ===
+
+function showView(name) {
+  for (const tab of document.querySelectorAll(".tab")) {
+    tab.classList.toggle("active", tab.dataset.view === name);
+  }
+  $("#notifications-view").hidden = name !== "notifications";
+  $("#queues-view").hidden = name !== "queues";
+  if (name === "queues") {
+    closeDetail();
+    startQueues();
+  } else {
+    stopQueues();
+  }
+}
+
+function reload() {
+  closeDetail();
+  loadNotifications(false);
+  followList();
+  if (!$("#queues-view").hidden) {
+    startQueues();
+  }
+}
+
+function init() {
+  const credentials = $("#credentials");
+  credentials.elements.kind.value = localStorage.getItem("notifier.kind") || "api-key";
+  credentials.elements.secret.value = localStorage.getItem("notifier.secret") || "";
+  credentials.addEventListener("submit", (e) => {
+    e.preventDefault();
+    localStorage.setItem("notifier.kind", credentials.elements.kind.value);
+    localStorage.setItem("notifier.secret", credentials.elements.secret.value.trim());
+    reload();
+  });
+
+  for (const tab of document.querySelectorAll(".tab")) {
+    tab.addEventListener("click", () => showView(tab.dataset.view));
+  }
+
+  $("#filters").addEventListener("submit", (e) => {
+    e.preventDefault();
+    state.filters = Object.fromEntries(new FormData(e.target));
+    loadNotifications(false);
+  });
+  $("#load-more").addEventListener("click", () => loadNotifications(true));
+  $("#new-notification").addEventListener("click", openCreate);
+  $("#create-form").addEventListener("submit", create);
+  $("#create-form [name=send_now]").addEventListener("change", (e) => {
+    e.target.form.elements.local_time.disabled = e.target.checked;
+  });
+  $("#close-create").addEventListener("click", () => $("#create-dialog").close());
+  $("#close-detail").addEventListener("click", closeDetail);
+  $("#reschedule-form").addEventListener("submit", reschedule);
+  $("#cancel-notification").addEventListener("click", () => cancelNotification(state.selected));
+  document.addEventListener("keydown", (e) => {
+    if (e.key === "Escape" && !$("#detail").hidden) {
+      closeDetail();
+    }
+  });
+
+  loadNotifications(false);
+  followList();
+}
+
+init();
@@ -0,0 +1,9 @@
+// Package static embeds the web admin UI, a single page that talks to the /api/v1 REST API.
+package static
+
+import "embed"
+
+// Files holds index.html with its stylesheets and scripts.
+//
+//go:embed index.html css js
+var Files embed.FS